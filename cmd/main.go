@@ -17,18 +17,28 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 	"time"
 
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/StarRocks/starrocks-kubernetes-operator/cmd/config"
 	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	srapiv2 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v2"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/controllers"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/metricsadapter"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/webhooks"
 )
 
 var (
@@ -37,6 +47,11 @@ var (
 	_probeAddr            string
 	_namespace            string
 	_denyList             string
+	_enableWebhooks       bool
+	_webhookCertDir       string
+	_webhookService       string
+	_enableMetricsAdapter bool
+	_sqlOptions           = sqlclient.DefaultOptions()
 )
 
 func main() {
@@ -50,6 +65,24 @@ func main() {
 	flag.StringVar(&config.DNSDomainSuffix, "dns-domain-suffix", "cluster.local", "The suffix of the dns domain in k8s")
 	flag.BoolVar(&config.VolumeNameWithHash, "volume-name-with-hash", true, "Add a hash to the volume name")
 	flag.StringVar(&_denyList, "deny-list", "", "Comma-separated list of namespaces to exclude from reconciliation")
+	flag.BoolVar(&_enableWebhooks, "enable-webhooks", false,
+		"Enable the admission webhooks of StarRocks CRDs. The serving certificate must be mounted to webhook-cert-dir.")
+	flag.StringVar(&_webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs",
+		"The directory that contains the tls.crt, tls.key and ca.crt of the webhook server")
+	flag.StringVar(&_webhookService, "webhook-service", "",
+		"The namespace/name of the service of webhook server. If specified, the operator configures the conversion "+
+			"webhook of StarRocks CRDs and serves the v2 version of them.")
+	flag.BoolVar(&_enableMetricsAdapter, "enable-metrics-adapter", false,
+		"Serve the metrics of StarRocks warehouses by the external metrics API on the webhook server, which are used "+
+			"by the starrocksMetrics of autoScalingPolicy. The serving certificate must be mounted to webhook-cert-dir.")
+	flag.DurationVar(&_sqlOptions.Timeout, "sql-timeout", sqlclient.DefaultTimeout,
+		"The timeout of SQL statements executed by the operator in FE, and of the HTTP requests to BE and CN")
+	flag.StringVar(&_sqlOptions.TLSMode, "sql-tls-mode", sqlclient.TLSDisabled,
+		"The TLS mode to connect to FE, one of disabled, preferred, skip-verify and verify")
+	flag.StringVar(&_sqlOptions.TLSCAFile, "sql-tls-ca-file", "",
+		"The CA certificate to verify FE when sql-tls-mode is verify. Defaults to the system CAs.")
+	flag.IntVar(&_sqlOptions.MaxRetries, "sql-max-retries", sqlclient.DefaultMaxRetries,
+		"The max number of retries when FE can not execute a SQL statement for now, e.g. the FE leader is changing")
 
 	// Set up logger.
 	opts := zap.Options{}
@@ -58,25 +91,41 @@ func main() {
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 	logger := ctrl.Log.WithName("main")
 
+	if err := sqlclient.Configure(_sqlOptions); err != nil {
+		logger.Error(err, "invalid options of SQL client")
+		os.Exit(1)
+	}
+
 	// Register CRD to SchemeBuilder
 	srapi.Register()
+	utilruntime.Must(srapiv2.AddToScheme(srapi.Scheme))
 
 	duration := 2 * time.Minute
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 srapi.Scheme,
 		MetricsBindAddress:     _metricsAddr,
 		Port:                   9443,
+		CertDir:                _webhookCertDir,
 		SyncPeriod:             &duration,
 		HealthProbeBindAddress: _probeAddr,
 		LeaderElection:         _enableLeaderElection,
 		LeaderElectionID:       "c6c79638.starrocks.com",
 		Namespace:              _namespace,
+		// storage classes are only read when the storage size is changed, and they are cluster scoped, which can not
+		// be read if the operator is only granted a role in its namespace.
+		ClientDisableCacheFor: []client.Object{&storagev1.StorageClass{}},
 	})
 	if err != nil {
 		logger.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// the kubernetes version decides which version of HPA is watched by the reconcilers
+	if err := k8sutils.GetKubernetesVersion(); err != nil {
+		logger.Error(err, "unable to get kubernetes version, the version of HPA is chosen by the API server, "+
+			"continue to start manager")
+	}
+
 	// setup all reconciles
 	if err := controllers.SetupClusterReconciler(mgr, _denyList); err != nil {
 		logger.Error(err, "unable to set up cluster reconciler")
@@ -88,6 +137,44 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := controllers.SetupBackupReconcilers(mgr, _namespace, _denyList); err != nil {
+		logger.Error(err, "unable to set up backup and restore reconcilers")
+		os.Exit(1)
+	}
+
+	if err := controllers.SetupStorageVolumeReconciler(mgr, _namespace, _denyList); err != nil {
+		logger.Error(err, "unable to set up storage volume reconciler")
+		os.Exit(1)
+	}
+
+	if err := controllers.SetupAccessControlReconcilers(mgr, _namespace, _denyList); err != nil {
+		logger.Error(err, "unable to set up user and role reconcilers")
+		os.Exit(1)
+	}
+
+	if _enableWebhooks {
+		if err := webhooks.SetupWebhooks(mgr); err != nil {
+			logger.Error(err, "unable to set up webhooks")
+			os.Exit(1)
+		}
+		if _webhookService != "" {
+			namespace, name, err := cache.SplitMetaNamespaceKey(_webhookService)
+			if err != nil || namespace == "" {
+				logger.Error(err, "invalid webhook service, it should be namespace/name", "service", _webhookService)
+				os.Exit(1)
+			}
+			service := types.NamespacedName{Namespace: namespace, Name: name}
+			if err := webhooks.SetupConversion(context.Background(), mgr, service, _webhookCertDir); err != nil {
+				logger.Error(err, "unable to set up conversion webhook")
+				os.Exit(1)
+			}
+		}
+	}
+
+	if _enableMetricsAdapter {
+		metricsadapter.Setup(mgr)
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -99,10 +186,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := k8sutils.GetKubernetesVersion(); err != nil {
-		logger.Error(err, "unable to get kubernetes version, continue to start manager")
-	}
-
 	logger.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		logger.Error(err, "problem running manager")
@@ -22,6 +22,7 @@ import (
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -30,6 +31,11 @@ type AutoScalingPolicy struct {
 	// the policy of autoscaling. operator use autoscaling v2.
 	HPAPolicy *HPAPolicy `json:"hpaPolicy,omitempty"`
 
+	// StarRocksMetrics scales CN by the metrics of the warehouse in StarRocks, which are served by the external
+	// metrics API of operator. They are added to the metrics of hpaPolicy. Not supported by autoscaling v1.
+	// +optional
+	StarRocksMetrics []StarRocksMetric `json:"starrocksMetrics,omitempty"`
+
 	// version represents the autoscaler version for cn service. only support v1,v2beta2,v2
 	// +optional
 	Version AutoScalerVersion `json:"version,omitempty"`
@@ -60,6 +66,50 @@ type HPAPolicy struct {
 	Behavior *autoscalingv2beta2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
 }
 
+// StarRocksMetric is a metric of StarRocks used to scale CN.
+type StarRocksMetric struct {
+	// Name is the name of metric.
+	// pending_queries: the number of queries waiting in the query queue, divided by the number of CN pods.
+	// running_queries: the number of running queries, divided by the number of CN pods.
+	// slot_usage: the number of resource group slots used by running queries, divided by the number of CN pods.
+	// queue_wait_seconds: the longest time a pending query has been waiting in the query queue.
+	// +kubebuilder:validation:Enum=pending_queries;running_queries;slot_usage;queue_wait_seconds
+	Name StarRocksMetricName `json:"name"`
+
+	// Target is the target value of metric.
+	Target resource.Quantity `json:"target"`
+}
+
+type StarRocksMetricName string
+
+const (
+	PendingQueries   StarRocksMetricName = "pending_queries"
+	RunningQueries   StarRocksMetricName = "running_queries"
+	SlotUsage        StarRocksMetricName = "slot_usage"
+	QueueWaitSeconds StarRocksMetricName = "queue_wait_seconds"
+)
+
+// StarRocksMetricNames are all the metrics of StarRocks which can be used to scale CN.
+var StarRocksMetricNames = []StarRocksMetricName{PendingQueries, RunningQueries, SlotUsage, QueueWaitSeconds}
+
+const (
+	// StarRocksMetricClusterLabel and StarRocksMetricWarehouseLabel are the labels of the metrics served by the
+	// external metrics API of operator. They select the StarRocksCluster and the warehouse in FE of a metric.
+	StarRocksMetricClusterLabel   = "cluster"
+	StarRocksMetricWarehouseLabel = "warehouse"
+)
+
+// ExternalMetricName returns the name of metric in the external metrics API.
+func (name StarRocksMetricName) ExternalMetricName() string {
+	return "starrocks_" + string(name)
+}
+
+// IsAverageValue returns true if the metric is divided by the number of CN pods to compare with the target, i.e. the
+// metric is the total load of a warehouse, and is used as the AverageValue target of HPA.
+func (name StarRocksMetricName) IsAverageValue() bool {
+	return name != QueueWaitSeconds
+}
+
 type AutoScalerVersion string
 
 const (
@@ -23,6 +23,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ SpecInterface = &StarRocksComponentSpec{}
@@ -41,6 +42,12 @@ type StarRocksComponentSpec struct {
 	// +optional
 	Capabilities *corev1.Capabilities `json:"capabilities,omitempty"`
 
+	// Config is the configuration of the component, e.g. sys_log_level: INFO. The operator renders it with the default
+	// ports into fe.conf, be.conf or cn.conf in a configMap owned by the operator. It can not be used together with
+	// configMapInfo, or a configMap mounted on the config directory.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+
 	// the reference for configMap which allow users to mount any files to container.
 	// +optional
 	ConfigMaps []ConfigMapReference `json:"configMaps,omitempty"`
@@ -160,6 +167,37 @@ type StarRocksComponentStatus struct {
 
 	// Reason represents the reason of not running.
 	Reason string `json:"reason,omitempty"`
+
+	// ObservedGeneration is the generation of StarRocksCluster or StarRocksWarehouse observed by the component.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the component, the possible types are:
+	// Ready, Progressing.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// VolumeExpansion represents the progress of expanding the persistent volume claims after the storage size is
+	// increased. It is removed after all the persistent volume claims have been expanded.
+	// +optional
+	VolumeExpansion *VolumeExpansionStatus `json:"volumeExpansion,omitempty"`
+
+	// Config represents how the last change of spec.config is applied.
+	// +optional
+	Config *ConfigStatus `json:"config,omitempty"`
+}
+
+// ConfigStatus represents the keys of spec.config changed last time.
+type ConfigStatus struct {
+	// HotAppliedKeys are applied without restarting pods.
+	// +optional
+	HotAppliedKeys []string `json:"hotAppliedKeys,omitempty"`
+
+	// PendingRestartKeys take effect after pods are restarted.
+	// +optional
+	PendingRestartKeys []string `json:"pendingRestartKeys,omitempty"`
 }
 
 type ConfigMapInfo struct {
@@ -168,11 +206,62 @@ type ConfigMapInfo struct {
 
 	// the config response key in configmap.
 	ResolveKey string `json:"resolveKey,omitempty"`
+
+	// DisableRollout disables restarting the pods when the configMap is changed.
+	// +optional
+	DisableRollout bool `json:"disableRollout,omitempty"`
+}
+
+// ConfigMapReference mounts a configMap to the pods. The fields except DisableRollout are the same as MountInfo,
+// because MountInfo is used to calculate the volume name, and adding a field to it will restart the pods.
+type ConfigMapReference struct {
+	// This must match the Name of a ConfigMap in the same namespace, and
+	// the length of name must not more than 50 characters.
+	Name string `json:"name,omitempty"`
+
+	// Path within the container at which the volume should be mounted.  Must
+	// not contain ':'.
+	MountPath string `json:"mountPath,omitempty"`
+
+	// SubPath within the volume from which the container's volume should be mounted.
+	// Defaults to "" (volume's root).
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+
+	// DisableRollout disables restarting the pods when the configMap is changed.
+	// +optional
+	DisableRollout bool `json:"disableRollout,omitempty"`
+}
+
+// SecretReference mounts a secret to the pods, see ConfigMapReference.
+type SecretReference struct {
+	// This must match the Name of a Secret in the same namespace, and
+	// the length of name must not more than 50 characters.
+	Name string `json:"name,omitempty"`
+
+	// Path within the container at which the volume should be mounted.  Must
+	// not contain ':'.
+	MountPath string `json:"mountPath,omitempty"`
+
+	// SubPath within the volume from which the container's volume should be mounted.
+	// Defaults to "" (volume's root).
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+
+	// DisableRollout disables restarting the pods when the secret is changed.
+	// +optional
+	DisableRollout bool `json:"disableRollout,omitempty"`
 }
 
-type ConfigMapReference MountInfo
+// MountInfo returns the MountInfo of the reference, which is used to calculate the volume name.
+func (reference ConfigMapReference) MountInfo() MountInfo {
+	return MountInfo{Name: reference.Name, MountPath: reference.MountPath, SubPath: reference.SubPath}
+}
 
-type SecretReference MountInfo
+// MountInfo returns the MountInfo of the reference, which is used to calculate the volume name.
+func (reference SecretReference) MountInfo() MountInfo {
+	return MountInfo{Name: reference.Name, MountPath: reference.MountPath, SubPath: reference.SubPath}
+}
 
 // MountInfo
 // The reason why we do not support defaultMode is that we use hash.HashObject to
@@ -300,12 +389,71 @@ type DisasterRecovery struct {
 	// Generation records the generation of disaster recovery. If you want to trigger disaster recovery, you should
 	// increase the generation.
 	Generation int64 `json:"generation,omitempty"`
+
+	// TimeoutSeconds is the maximum duration of disaster recovery. If FE is still not ready after the timeout, the
+	// phase becomes failed and FE is deployed as normal. Zero means no timeout.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+
+	// Abort aborts the running disaster recovery of the current generation, the phase becomes aborted and FE is
+	// deployed as normal. You should set it back to false before increasing the generation.
+	// +optional
+	Abort bool `json:"abort,omitempty"`
+
+	// ClusterSnapshot is used to generate cluster_snapshot.yaml. If it is set, the operator renders the file into a
+	// secret and mounts it to FE, you do not need to mount cluster_snapshot.yaml through configMaps.
+	// +optional
+	ClusterSnapshot *ClusterSnapshot `json:"clusterSnapshot,omitempty"`
+}
+
+// ClusterSnapshot describes the cluster snapshot to be downloaded and restored.
+type ClusterSnapshot struct {
+	// Path is the path of the cluster snapshot, e.g.
+	// s3://bucket/data/7351ce6a-f4a4-4937-a876-cb8801085aea/meta/image/automated_cluster_snapshot_1739858235830.
+	Path string `json:"path"`
+
+	// StorageVolume is the storage volume where the cluster snapshot is stored.
+	StorageVolume SnapshotStorageVolume `json:"storageVolume"`
+}
+
+// SnapshotStorageVolume is a storage volume in cluster_snapshot.yaml.
+type SnapshotStorageVolume struct {
+	// Name is the name of the storage volume. The default is builtin_storage_volume.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the storage volume, the possible values are: S3, HDFS, AZBLOB, ADLS2 and GS.
+	// +kubebuilder:validation:Enum=S3;HDFS;AZBLOB;ADLS2;GS
+	Type string `json:"type"`
+
+	// Location is the location of the storage volume, e.g. s3://bucket/data. The path of cluster snapshot must be
+	// under it.
+	Location string `json:"location"`
+
+	// Endpoint is the endpoint of the object storage, e.g. https://s3.us-west-2.amazonaws.com.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Region is the region of the object storage.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// CredentialsSecretRef is a secret in the same namespace. For S3, the keys access_key and secret_key are used.
+	// For HDFS, the keys username and password are used.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// Properties are the extra properties of the storage volume, they override the properties generated from the
+	// fields above.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
 }
 
 // DisasterRecoveryStatus represents the status of disaster recovery.
 // Note: you should create a new instance of DisasterRecoveryStatus by NewDisasterRecoveryStatus.
 type DisasterRecoveryStatus struct {
-	// the available phase include: todo, doing, done
+	// the available phase include: todo, doing, done, failed, aborted
 	Phase DRPhase `json:"phase,omitempty"`
 
 	// the reason of disaster recovery.
@@ -320,6 +468,32 @@ type DisasterRecoveryStatus struct {
 	// the observed generation of disaster recovery.
 	// If the observed generation is less than the generation, it will trigger disaster recovery.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// History records the finished disaster recoveries, the latest one is the last. At most
+	// MaxDisasterRecoveryHistory records are kept.
+	// +optional
+	History []DisasterRecoveryRecord `json:"history,omitempty"`
+}
+
+// MaxDisasterRecoveryHistory is the max number of records in the history of disaster recovery.
+const MaxDisasterRecoveryHistory = 10
+
+// DisasterRecoveryRecord is a finished disaster recovery.
+type DisasterRecoveryRecord struct {
+	// the generation of disaster recovery.
+	Generation int64 `json:"generation"`
+
+	// the final phase, include: done, failed, aborted
+	Phase DRPhase `json:"phase"`
+
+	// the reason of the final phase.
+	Reason string `json:"reason,omitempty"`
+
+	// the unix time of starting disaster recovery.
+	StartTimestamp int64 `json:"startTimestamp,omitempty"`
+
+	// the unix time of ending disaster recovery.
+	EndTimestamp int64 `json:"endTimestamp,omitempty"`
 }
 
 // NewDisasterRecoveryStatus creates a new disaster recovery status which the phase is todo.
@@ -331,10 +505,36 @@ func NewDisasterRecoveryStatus(generation int64) *DisasterRecoveryStatus {
 	}
 }
 
+// Finish sets the final phase of disaster recovery and appends it to the history.
+func (status *DisasterRecoveryStatus) Finish(phase DRPhase, reason string) {
+	status.Phase = phase
+	status.Reason = reason
+	status.EndTimestamp = time.Now().Unix()
+	status.History = append(status.History, DisasterRecoveryRecord{
+		Generation:     status.ObservedGeneration,
+		Phase:          phase,
+		Reason:         reason,
+		StartTimestamp: status.StartTimestamp,
+		EndTimestamp:   status.EndTimestamp,
+	})
+	if len(status.History) > MaxDisasterRecoveryHistory {
+		status.History = status.History[len(status.History)-MaxDisasterRecoveryHistory:]
+	}
+}
+
 type DRPhase string
 
 const (
 	DRPhaseTodo  DRPhase = "todo"
 	DRPhaseDoing DRPhase = "doing"
 	DRPhaseDone  DRPhase = "done"
+	// DRPhaseFailed means FE is not ready before the timeout.
+	DRPhaseFailed DRPhase = "failed"
+	// DRPhaseAborted means the disaster recovery is aborted by user, or it is replaced by a newer generation.
+	DRPhaseAborted DRPhase = "aborted"
 )
+
+// IsFinished returns true if the disaster recovery will not make any progress.
+func (phase DRPhase) IsFinished() bool {
+	return phase == DRPhaseDone || phase == DRPhaseFailed || phase == DRPhaseAborted
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// the condition types of StarRocksCluster and StarRocksWarehouse.
+const (
+	// ConditionAvailable means all the deployed components have running instances, and the cluster can serve requests.
+	ConditionAvailable = "Available"
+
+	// ConditionProgressing means the operator is rolling out the changes, e.g. creating or updating pods.
+	ConditionProgressing = "Progressing"
+
+	// ConditionDegraded means some component is failed, or the operator failed to reconcile the spec.
+	ConditionDegraded = "Degraded"
+
+	// ConditionFeReady means all the FE pods are running and updated.
+	ConditionFeReady = "FeReady"
+
+	// ConditionBeReady means all the BE pods are running and updated.
+	ConditionBeReady = "BeReady"
+
+	// ConditionCnReady means all the CN pods are running and updated.
+	ConditionCnReady = "CnReady"
+
+	// ConditionDisasterRecoveryInProgress means the cluster is in disaster recovery mode.
+	ConditionDisasterRecoveryInProgress = "DisasterRecoveryInProgress"
+
+	// ConditionSuspended means the cluster is suspended, or it is being suspended or resumed.
+	ConditionSuspended = "Suspended"
+)
+
+// the condition types of components, e.g. StarRocksFeStatus.
+const (
+	// ConditionReady means all the pods of the component are running and updated.
+	ConditionReady = "Ready"
+)
+
+// the reasons of conditions.
+const (
+	// ReasonAsExpected means the condition is in the expected state, e.g. the cluster is not degraded.
+	ReasonAsExpected = "AsExpected"
+
+	// ReasonComponentsRunning means all the pods of the components are running and updated.
+	ReasonComponentsRunning = "ComponentsRunning"
+
+	// ReasonRunningInstancesAvailable means every deployed component has at least one running instance.
+	ReasonRunningInstancesAvailable = "RunningInstancesAvailable"
+
+	// ReasonComponentReconciling means some component is reconciling.
+	ReasonComponentReconciling = "ComponentReconciling"
+
+	// ReasonComponentFailed means some component is failed.
+	ReasonComponentFailed = "ComponentFailed"
+
+	// ReasonSyncFailed means the operator failed to reconcile the spec.
+	ReasonSyncFailed = "SyncFailed"
+
+	// ReasonNoRunningInstances means the component has no running instances.
+	ReasonNoRunningInstances = "NoRunningInstances"
+
+	// ReasonDisasterRecoveryTodo means the disaster recovery is going to start.
+	ReasonDisasterRecoveryTodo = "DisasterRecoveryTodo"
+
+	// ReasonDisasterRecoveryDoing means the disaster recovery is running.
+	ReasonDisasterRecoveryDoing = "DisasterRecoveryDoing"
+
+	// ReasonDisasterRecoveryDone means the disaster recovery is finished.
+	ReasonDisasterRecoveryDone = "DisasterRecoveryDone"
+
+	// ReasonDisasterRecoveryFailed means the disaster recovery is timed out.
+	ReasonDisasterRecoveryFailed = "DisasterRecoveryFailed"
+
+	// ReasonDisasterRecoveryAborted means the disaster recovery is aborted.
+	ReasonDisasterRecoveryAborted = "DisasterRecoveryAborted"
+
+	// ReasonDisasterRecoveryNotRequested means the cluster is not in disaster recovery mode.
+	ReasonDisasterRecoveryNotRequested = "DisasterRecoveryNotRequested"
+
+	// ReasonSuspending means the components are being scaled to zero.
+	ReasonSuspending = "Suspending"
+
+	// ReasonSuspended means all the components have been scaled to zero.
+	ReasonSuspended = "Suspended"
+
+	// ReasonResuming means the components are being scaled back.
+	ReasonResuming = "Resuming"
+
+	// ReasonNotSuspended means the cluster is not suspended.
+	ReasonNotSuspended = "NotSuspended"
+)
@@ -0,0 +1,26 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// v1 is the storage version and the hub of conversion, the other versions convert to and from v1.
+// See https://book.kubebuilder.io/multiversion-tutorial/conversion-concepts.html for more details.
+
+// Hub marks StarRocksCluster as a conversion hub.
+func (*StarRocksCluster) Hub() {}
+
+// Hub marks StarRocksWarehouse as a conversion hub.
+func (*StarRocksWarehouse) Hub() {}
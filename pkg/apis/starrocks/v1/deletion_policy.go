@@ -0,0 +1,104 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// DeletionPolicy defines what the operator should do before the StarRocksCluster is deleted.
+// The steps are executed in the order of: final snapshot, drop warehouses, clear the resources of components,
+// and delete persistent volume claims.
+type DeletionPolicy struct {
+	// PersistentVolumeClaims determines whether the persistent volume claims of FE, BE and CN are deleted
+	// after the StarRocksCluster is deleted. The possible values are: Retain, Delete. The default is Retain.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	PersistentVolumeClaims PersistentVolumeClaimDeletionPolicy `json:"persistentVolumeClaims,omitempty"`
+
+	// DropWarehouses determines whether the StarRocksWarehouse objects which belong to the StarRocksCluster are
+	// deleted before the StarRocksCluster is deleted. When it is true, the warehouses will be dropped from
+	// StarRocks while FE is still running.
+	// +optional
+	DropWarehouses bool `json:"dropWarehouses,omitempty"`
+
+	// FinalSnapshot is used to back up the databases by BACKUP SNAPSHOT before the StarRocksCluster is deleted.
+	// The deletion will wait until the backup is finished. If the backup can not be finished, e.g. the repository
+	// does not exist, see FinalSnapshot.OnFailure, or remove this field to continue the deletion.
+	// +optional
+	FinalSnapshot *FinalSnapshot `json:"finalSnapshot,omitempty"`
+}
+
+// PersistentVolumeClaimDeletionPolicy represents whether the persistent volume claims are deleted.
+type PersistentVolumeClaimDeletionPolicy string
+
+const (
+	// RetainPersistentVolumeClaims means the persistent volume claims are retained after the cluster is deleted.
+	RetainPersistentVolumeClaims PersistentVolumeClaimDeletionPolicy = "Retain"
+
+	// DeletePersistentVolumeClaims means the persistent volume claims are deleted after the cluster is deleted.
+	DeletePersistentVolumeClaims PersistentVolumeClaimDeletionPolicy = "Delete"
+)
+
+// FinalSnapshot defines how to back up the databases before the StarRocksCluster is deleted.
+type FinalSnapshot struct {
+	// Repository is the name of the repository in StarRocks, which is created by CREATE REPOSITORY.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	Repository string `json:"repository"`
+
+	// Databases is the list of databases to back up.
+	// +kubebuilder:validation:MinItems=1
+	Databases []DatabaseName `json:"databases"`
+
+	// Timeout is how long to wait for the backup since the StarRocksCluster is deleted, e.g. 2h. No timeout if unset.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// OnFailure is what to do when the backup fails, times out, or the cluster is suspended: Block or Continue.
+	// +kubebuilder:validation:Enum=Block;Continue
+	// +optional
+	OnFailure FinalSnapshotFailurePolicy `json:"onFailure,omitempty"`
+}
+
+// DatabaseName is the name of a database in StarRocks.
+// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+type DatabaseName string
+
+// FinalSnapshotFailurePolicy represents what the operator should do when the final snapshot can not be taken.
+type FinalSnapshotFailurePolicy string
+
+const (
+	// BlockOnFinalSnapshotFailure means the deletion is blocked until the final snapshot is taken, it is the default.
+	BlockOnFinalSnapshotFailure FinalSnapshotFailurePolicy = "Block"
+
+	// ContinueOnFinalSnapshotFailure means the final snapshot is skipped and the deletion continues.
+	ContinueOnFinalSnapshotFailure FinalSnapshotFailurePolicy = "Continue"
+)
+
+// GetPersistentVolumeClaims returns the deletion policy of persistent volume claims, the default is Retain.
+func (policy *DeletionPolicy) GetPersistentVolumeClaims() PersistentVolumeClaimDeletionPolicy {
+	if policy == nil || policy.PersistentVolumeClaims == "" {
+		return RetainPersistentVolumeClaims
+	}
+	return policy.PersistentVolumeClaims
+}
+
+// GetOnFailure returns the failure policy of the final snapshot, the default is Block.
+func (snapshot *FinalSnapshot) GetOnFailure() FinalSnapshotFailurePolicy {
+	if snapshot == nil || snapshot.OnFailure == "" {
+		return BlockOnFinalSnapshotFailure
+	}
+	return snapshot.OnFailure
+}
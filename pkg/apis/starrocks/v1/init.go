@@ -13,6 +13,12 @@ var (
 func Register() {
 	SchemeBuilder.Register(&StarRocksCluster{}, &StarRocksClusterList{})
 	SchemeBuilder.Register(&StarRocksWarehouse{}, &StarRocksWarehouseList{})
+	SchemeBuilder.Register(&StarRocksBackup{}, &StarRocksBackupList{})
+	SchemeBuilder.Register(&StarRocksBackupSchedule{}, &StarRocksBackupScheduleList{})
+	SchemeBuilder.Register(&StarRocksRestore{}, &StarRocksRestoreList{})
+	SchemeBuilder.Register(&StarRocksStorageVolume{}, &StarRocksStorageVolumeList{})
+	SchemeBuilder.Register(&StarRocksUser{}, &StarRocksUserList{})
+	SchemeBuilder.Register(&StarRocksRole{}, &StarRocksRoleList{})
 
 	utilruntime.Must(clientgoscheme.AddToScheme(Scheme))
 	utilruntime.Must(AddToScheme(Scheme))
@@ -26,6 +26,25 @@ const (
 
 	// ComponentResourceHash the component hash
 	ComponentResourceHash string = "app.starrocks.components/hash"
+
+	// ConfigHashAnnotation is the hash of the config file rendered from spec.config, the pods are restarted when it
+	// is changed.
+	ConfigHashAnnotation string = "app.starrocks.components/config-hash"
+
+	// ConfigMapHashAnnotationPrefix and SecretHashAnnotationPrefix are the prefixes of the annotations of pods which
+	// store the hashes of the referenced configMaps and secrets, the pods are restarted when they are changed.
+	ConfigMapHashAnnotationPrefix string = "app.starrocks.components/configmap-"
+	SecretHashAnnotationPrefix    string = "app.starrocks.components/secret-"
+
+	// FeRoleLabelKey represents the live role of a FE pod in the cluster, it is synced from SHOW FRONTENDS.
+	FeRoleLabelKey string = "app.starrocks.fe/role"
+)
+
+// the values of FeRoleLabelKey
+const (
+	FeRoleLeader   = "leader"
+	FeRoleFollower = "follower"
+	FeRoleObserver = "observer"
 )
 
 // the labels value. default statefulset name
@@ -40,4 +59,6 @@ const (
 const (
 	COMPONENT_NAME  = "COMPONENT_NAME"
 	FE_SERVICE_NAME = "FE_SERVICE_NAME"
+	// FE_ROLE is OBSERVER for the pods of FE observer groups, so that a custom entrypoint can tell them apart.
+	FE_ROLE = "FE_ROLE"
 )
@@ -0,0 +1,188 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StarRocksBackupSpec defines the desired state of StarRocksBackup.
+type StarRocksBackupSpec struct {
+	// StarRocksCluster is the name of a StarRocksCluster in the same namespace, whose database is backed up.
+	StarRocksCluster string `json:"starRocksCluster"`
+
+	// Repository is the repository where the snapshot is stored. It will be created by CREATE REPOSITORY if it
+	// does not exist in StarRocks.
+	Repository BackupRepository `json:"repository"`
+
+	// Database is the name of the database to back up.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	Database string `json:"database"`
+
+	// Tables is the list of tables to back up. If it is empty, all the tables of the database are backed up.
+	// +optional
+	Tables []TableName `json:"tables,omitempty"`
+
+	// SnapshotName is the name of the snapshot. If it is empty, the operator generates one from the name and the
+	// creation timestamp of StarRocksBackup, e.g. daily_backup_1700000000.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+}
+
+// TableName is the name of a table in StarRocks.
+// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+type TableName string
+
+// RepositoryType is the type of the remote storage of a repository.
+// +kubebuilder:validation:Enum=S3;MinIO;HDFS
+type RepositoryType string
+
+const (
+	// S3Repository stores the snapshots in AWS S3 or a storage which is compatible with S3.
+	S3Repository RepositoryType = "S3"
+
+	// MinIORepository stores the snapshots in MinIO, the path-style access is used.
+	MinIORepository RepositoryType = "MinIO"
+
+	// HDFSRepository stores the snapshots in HDFS.
+	HDFSRepository RepositoryType = "HDFS"
+)
+
+// BackupRepository defines a repository in StarRocks, which is used by BACKUP SNAPSHOT and RESTORE SNAPSHOT.
+type BackupRepository struct {
+	// Name is the name of the repository in StarRocks.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	Name string `json:"name"`
+
+	// Type is the type of the remote storage, the possible values are: S3, MinIO and HDFS.
+	Type RepositoryType `json:"type"`
+
+	// Location is the path of the repository in the remote storage, e.g. s3://bucket/backup or hdfs://host:port/backup.
+	Location string `json:"location"`
+
+	// Endpoint is the endpoint of S3 or MinIO, e.g. http://minio.minio:9000.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Region is the region of S3.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// CredentialsSecretRef is a secret in the same namespace. For S3 and MinIO, the keys access_key and secret_key
+	// are used. For HDFS, the keys username and password are used.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// Properties are the extra properties of CREATE REPOSITORY, they override the properties generated from the
+	// fields above.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// BackupPhase represents the phase of StarRocksBackup and StarRocksRestore.
+type BackupPhase string
+
+const (
+	// BackupPending means the job has not been submitted to StarRocks, e.g. another job of the database is running.
+	BackupPending BackupPhase = "Pending"
+
+	// BackupRunning means the job has been submitted to StarRocks and is running.
+	BackupRunning BackupPhase = "Running"
+
+	// BackupSucceeded means the job is finished.
+	BackupSucceeded BackupPhase = "Succeeded"
+
+	// BackupFailed means the job is cancelled or can not be submitted.
+	BackupFailed BackupPhase = "Failed"
+)
+
+// IsFinished returns true if the job will not be changed anymore.
+func (phase BackupPhase) IsFinished() bool {
+	return phase == BackupSucceeded || phase == BackupFailed
+}
+
+// StarRocksBackupStatus defines the observed state of StarRocksBackup.
+type StarRocksBackupStatus struct {
+	// Phase represents the phase of the backup, the possible values are: Pending, Running, Succeeded and Failed.
+	// +optional
+	Phase BackupPhase `json:"phase,omitempty"`
+
+	// Reason represents the reason why the backup is pending or failed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// SnapshotName is the name of the snapshot in the repository.
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// JobID is the id of the backup job in StarRocks.
+	// +optional
+	JobID string `json:"jobId,omitempty"`
+
+	// State is the state of the backup job in StarRocks, e.g. SNAPSHOTING, UPLOADING, FINISHED.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Progress is the progress of uploading the snapshot, e.g. 3/10.
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// StartTime is the time when the backup job is submitted.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the backup job is finished or cancelled.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// StarRocksBackup backs up a database of StarRocksCluster to a repository by BACKUP SNAPSHOT.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=srbackup
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="cluster",type=string,JSONPath=`.spec.starRocksCluster`
+// +kubebuilder:printcolumn:name="database",type=string,JSONPath=`.spec.database`
+// +kubebuilder:printcolumn:name="snapshot",type=string,JSONPath=`.status.snapshotName`
+// +kubebuilder:printcolumn:name="phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="progress",type=string,JSONPath=`.status.progress`
+// +k8s:openapi-gen=true
+// +genclient
+type StarRocksBackup struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of desired state of a backup.
+	Spec StarRocksBackupSpec `json:"spec,omitempty"`
+
+	// Status represents the recent observed status of the backup.
+	Status StarRocksBackupStatus `json:"status,omitempty"`
+}
+
+// StarRocksBackupList contains a list of StarRocksBackup
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type StarRocksBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StarRocksBackup `json:"items"`
+}
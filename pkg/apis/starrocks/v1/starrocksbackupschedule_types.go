@@ -0,0 +1,156 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupScheduleLabelKey is the label of StarRocksBackup, whose value is the name of the StarRocksBackupSchedule
+// which creates the backup.
+const BackupScheduleLabelKey = "app.starrocks.backup/schedule"
+
+// StarRocksBackupScheduleSpec defines the desired state of StarRocksBackupSchedule.
+type StarRocksBackupScheduleSpec struct {
+	// StarRocksCluster is the name of a StarRocksCluster in the same namespace, whose databases are backed up.
+	StarRocksCluster string `json:"starRocksCluster"`
+
+	// Schedule is a cron expression with five fields, e.g. "0 2 * * *" backs up the databases at 02:00 every day.
+	// The descriptors like @daily are also supported.
+	Schedule string `json:"schedule"`
+
+	// TimeZone is the name of the time zone of the schedule, e.g. Asia/Shanghai. The default is UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Suspend stops creating new backups, the existing backups are not affected.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Repository is the repository where the snapshots are stored.
+	Repository BackupRepository `json:"repository"`
+
+	// Targets are the databases to back up. A StarRocksBackup is created for each target on schedule. If the last
+	// backup of a target is not finished, the target is skipped.
+	// +kubebuilder:validation:MinItems=1
+	Targets []BackupTarget `json:"targets"`
+
+	// Retention defines which backups are pruned. The snapshot of a pruned backup is dropped from the repository by
+	// DROP SNAPSHOT, and the StarRocksBackup is deleted. If it is not set, no backup is pruned.
+	// +optional
+	Retention *BackupRetention `json:"retention,omitempty"`
+}
+
+// BackupTarget is a database to back up.
+type BackupTarget struct {
+	// Database is the name of the database to back up.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	Database string `json:"database"`
+
+	// Tables is the list of tables to back up. If it is empty, all the tables of the database are backed up.
+	// +optional
+	Tables []TableName `json:"tables,omitempty"`
+}
+
+// BackupRetention defines how long the backups created by StarRocksBackupSchedule are kept. The rules are applied to
+// every target separately, and the latest succeeded backup of a target is always kept.
+type BackupRetention struct {
+	// KeepLast is the number of the latest succeeded backups to keep, the same number of the latest failed backups
+	// are also kept.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepLast *int32 `json:"keepLast,omitempty"`
+
+	// KeepDays is the number of days to keep a backup after it is created.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepDays *int32 `json:"keepDays,omitempty"`
+}
+
+// StarRocksBackupScheduleStatus defines the observed state of StarRocksBackupSchedule.
+type StarRocksBackupScheduleStatus struct {
+	// Reason represents the reason why the schedule does not work, e.g. the cron expression is invalid.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// LastScheduleTime is the last time when the backups were created.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is the next time when the backups will be created.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// Active is the list of the backups which are not finished.
+	// +optional
+	Active []string `json:"active,omitempty"`
+
+	// LastSuccessfulBackup is the name of the last succeeded StarRocksBackup.
+	// +optional
+	LastSuccessfulBackup string `json:"lastSuccessfulBackup,omitempty"`
+
+	// LastSuccessfulTime is the completion time of the last succeeded StarRocksBackup.
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// LastFailedBackup is the name of the last failed StarRocksBackup.
+	// +optional
+	LastFailedBackup string `json:"lastFailedBackup,omitempty"`
+
+	// LastFailedTime is the completion time of the last failed StarRocksBackup.
+	// +optional
+	LastFailedTime *metav1.Time `json:"lastFailedTime,omitempty"`
+
+	// LastFailureReason is the reason of the last failed StarRocksBackup.
+	// +optional
+	LastFailureReason string `json:"lastFailureReason,omitempty"`
+}
+
+// StarRocksBackupSchedule creates StarRocksBackup on schedule, and prunes the expired backups.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=srbackupschedule
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="cluster",type=string,JSONPath=`.spec.starRocksCluster`
+// +kubebuilder:printcolumn:name="schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="suspend",type=boolean,JSONPath=`.spec.suspend`
+// +kubebuilder:printcolumn:name="last-schedule",type=date,JSONPath=`.status.lastScheduleTime`
+// +kubebuilder:printcolumn:name="last-success",type=date,JSONPath=`.status.lastSuccessfulTime`
+// +k8s:openapi-gen=true
+// +genclient
+type StarRocksBackupSchedule struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of desired state of a backup schedule.
+	Spec StarRocksBackupScheduleSpec `json:"spec,omitempty"`
+
+	// Status represents the recent observed status of the backup schedule.
+	Status StarRocksBackupScheduleStatus `json:"status,omitempty"`
+}
+
+// StarRocksBackupScheduleList contains a list of StarRocksBackupSchedule
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type StarRocksBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StarRocksBackupSchedule `json:"items"`
+}
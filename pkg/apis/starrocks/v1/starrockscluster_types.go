@@ -127,6 +127,25 @@ type StarRocksClusterSpec struct {
 	// When false (default), BE/CN updates can proceed as soon as any FE pod is ready.
 	// Defaults to false for backward compatibility.
 	WaitForFullRollout bool `json:"waitForFullRollout,omitempty"`
+
+	// +optional
+	// DeletionPolicy defines what the operator should do before the StarRocksCluster is deleted, e.g. whether to
+	// delete the persistent volume claims, drop the warehouses, or take a final snapshot.
+	// If it is not set, the persistent volume claims are retained.
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// +optional
+	// Suspend is used to hibernate the StarRocksCluster. When it is true, the operator scales CN, BE, FE proxy, FE
+	// observer groups and FE to zero in order. When it is changed back to false, the operator scales FE, then BE and CN,
+	// and finally FE proxy back to their previous replicas. The data in the persistent volumes is retained.
+	Suspend bool `json:"suspend,omitempty"`
+
+	// +optional
+	// RootPasswordSecretRef refers to the key of a secret which contains the password of the root user. Once FE is
+	// ready, the operator sets the password of root to it, and changes the password again when the secret is changed.
+	// The operator also uses it to execute SQL statements. If it is not set, the operator uses the environment
+	// variable MYSQL_PWD of FE.
+	RootPasswordSecretRef *corev1.SecretKeySelector `json:"rootPasswordSecretRef,omitempty"`
 }
 
 // StarRocksClusterStatus defines the observed state of StarRocksCluster.
@@ -137,6 +156,17 @@ type StarRocksClusterStatus struct {
 	// Reason represents the errors when calling sub-controllers
 	Reason string `json:"reason,omitempty"`
 
+	// ObservedGeneration is the most recent generation of StarRocksCluster observed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of StarRocksCluster, the possible types are:
+	// Available, Progressing, Degraded, FeReady, BeReady, CnReady, DisasterRecoveryInProgress, Suspended.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	// Represents the status of fe. the status have running, failed and creating pods.
 	StarRocksFeStatus *StarRocksFeStatus `json:"starRocksFeStatus,omitempty"`
 
@@ -149,9 +179,19 @@ type StarRocksClusterStatus struct {
 	// Represents the status of fe proxy. the status have running, failed and creating pods.
 	StarRocksFeProxyStatus *StarRocksFeProxyStatus `json:"starRocksFeProxyStatus,omitempty"`
 
+	// Represents the status of fe observer groups. Every group has its own status, which is reported separately
+	// from the status of fe followers.
+	// +optional
+	StarRocksFeObserverStatus []StarRocksFeObserverGroupStatus `json:"starRocksFeObserverStatus,omitempty"`
+
 	// +optional
 	// DisasterRecoveryStatus represents the status of disaster recovery.
 	DisasterRecoveryStatus *DisasterRecoveryStatus `json:"disasterRecoveryStatus,omitempty"`
+
+	// +optional
+	// SuspendStatus represents the status of suspending or resuming the cluster. It is removed after the cluster is
+	// resumed.
+	SuspendStatus *SuspendStatus `json:"suspendStatus,omitempty"`
 }
 
 // StarRocksFeSpec defines the desired state of fe.
@@ -161,6 +201,74 @@ type StarRocksFeSpec struct {
 	// +optional
 	// feEnvVars is a slice of environment variables that are added to the pods, the default is empty.
 	FeEnvVars []corev1.EnvVar `json:"feEnvVars,omitempty"`
+
+	// +optional
+	// ObserverGroups defines the groups of FE observers. Every group is deployed as a separate statefulset, and its
+	// pods are added to the cluster by `ALTER SYSTEM ADD OBSERVER`. Observers serve queries and replay the metadata,
+	// but they do not take part in the leader election, so they can scale the query traffic without growing the quorum.
+	// +listType=map
+	// +listMapKey=name
+	ObserverGroups []StarRocksFeObserverGroup `json:"observerGroups,omitempty"`
+
+	// +optional
+	// RollingUpdatePolicy controls the order in which the FE pods are updated. By default, the operator finds the FE
+	// leader by `SHOW FRONTENDS`, updates the followers one by one, and updates the leader last.
+	RollingUpdatePolicy *StarRocksFeRollingUpdatePolicy `json:"rollingUpdatePolicy,omitempty"`
+
+	// +optional
+	// EnableLeaderService makes the operator create a ClusterIP service named <cluster>-fe-leader, which only selects
+	// the FE leader by the pod label app.starrocks.fe/role=leader. FE proxy sends requests to it if it is enabled.
+	EnableLeaderService bool `json:"enableLeaderService,omitempty"`
+}
+
+// StarRocksFeRollingUpdatePolicy defines how the FE pods are updated when the pod template is changed.
+// It only takes effect when the type of updateStrategy is RollingUpdate.
+type StarRocksFeRollingUpdatePolicy struct {
+	// DisableLeaderAware makes the FE pods updated in the default order of statefulset, from the largest ordinal to
+	// the smallest, no matter which FE is the leader.
+	// +optional
+	DisableLeaderAware bool `json:"disableLeaderAware,omitempty"`
+}
+
+// StarRocksFeObserverGroup defines a group of FE observers. The observers share the image, configuration and storage
+// volumes with the FE followers, the fields below override the corresponding fields of StarRocksFeSpec.
+type StarRocksFeObserverGroup struct {
+	// Name is the name of the observer group. The statefulset of the group is named <cluster>-<name>-observer-fe.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=32
+	Name string `json:"name"`
+
+	// Replicas is the number of observers in the group.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// defines the specification of resource cpu and mem. If it is not set, the resources of FE are used.
+	// +optional
+	corev1.ResourceRequirements `json:",inline"`
+
+	// (Optional) If specified, the pod's nodeSelector. If it is not set, the nodeSelector of FE is used.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// (Optional) If specified, the pod's scheduling constraints. If it is not set, the affinity of FE is used.
+	// The schema is not expanded in the CRD to keep it under the size limit, it is validated when the pods are created.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// (Optional) Tolerations for scheduling pods onto some dedicated nodes. If it is not set, the tolerations of FE
+	// are used.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// (Optional) TopologySpreadConstraints for spreading pods across failure-domains. If it is not set, the
+	// topologySpreadConstraints of FE are used.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 }
 
 // StarRocksBeSpec defines the desired state of be.
@@ -195,13 +303,84 @@ type StarRocksFeProxySpec struct {
 // StarRocksFeStatus represents the status of starrocks fe.
 type StarRocksFeStatus struct {
 	StarRocksComponentStatus `json:",inline"`
+
+	// RollingUpdate is the progress of the leader-aware rolling update of FE. It is nil when no rolling update is
+	// in progress.
+	// +optional
+	RollingUpdate *StarRocksFeRollingUpdateStatus `json:"rollingUpdate,omitempty"`
+}
+
+// StarRocksFeRollingUpdateStatus represents the progress of the leader-aware rolling update of FE.
+type StarRocksFeRollingUpdateStatus struct {
+	// Updating is the name of the FE pod which is being updated.
+	// +optional
+	Updating string `json:"updating,omitempty"`
+
+	// Leader is the name of the pod which is the FE leader, it is updated last.
+	// +optional
+	Leader string `json:"leader,omitempty"`
+}
+
+// StarRocksFeObserverGroupStatus represents the status of a group of FE observers.
+type StarRocksFeObserverGroupStatus struct {
+	// Name is the name of the observer group.
+	Name string `json:"name"`
+
+	StarRocksComponentStatus `json:",inline"`
+
+	// Observers are the FQDNs of the observers which have been added to the cluster by `ALTER SYSTEM ADD OBSERVER`.
+	// +optional
+	Observers []string `json:"observers,omitempty"`
 }
 
 // StarRocksBeStatus represents the status of starrocks be.
 type StarRocksBeStatus struct {
 	StarRocksComponentStatus `json:",inline"`
+
+	// ScaleIn represents the progress of decommissioning BE nodes when the replicas of BE is decreased.
+	// It is only used in shared-nothing mode, and it will be cleared when the scale-in is done.
+	// +optional
+	ScaleIn *BeScaleInStatus `json:"scaleIn,omitempty"`
 }
 
+// BeScaleInStatus represents the progress of decommissioning BE nodes.
+type BeScaleInStatus struct {
+	// Phase represents the phase of scale-in, the possible value are: decommissioning, done.
+	Phase BeScaleInPhase `json:"phase,omitempty"`
+
+	// TargetReplicas is the replicas which user want to scale in to.
+	TargetReplicas int32 `json:"targetReplicas,omitempty"`
+
+	// Reason represents the reason why the scale-in is blocked.
+	Reason string `json:"reason,omitempty"`
+
+	// Backends represents the decommissioning status of BE nodes which will be removed.
+	Backends []DecommissioningBackend `json:"backends,omitempty"`
+}
+
+// DecommissioningBackend represents the decommissioning status of a BE node.
+type DecommissioningBackend struct {
+	// Name is the pod name of the BE node.
+	Name string `json:"name"`
+
+	// TabletNum is the number of tablets which have not been migrated from the BE node.
+	TabletNum int64 `json:"tabletNum"`
+
+	// Decommissioned represents whether FE has marked the BE node as decommissioned.
+	Decommissioned bool `json:"decommissioned,omitempty"`
+}
+
+// BeScaleInPhase represents the phase of BE scale-in.
+type BeScaleInPhase string
+
+const (
+	// BeScaleInDecommissioning represents some BE nodes are being decommissioned, and the tablets are migrating.
+	BeScaleInDecommissioning BeScaleInPhase = "decommissioning"
+
+	// BeScaleInDone represents all BE nodes which will be removed have no tablets, and statefulset can be scaled in.
+	BeScaleInDone BeScaleInPhase = "done"
+)
+
 type StarRocksFeProxyStatus struct {
 	StarRocksComponentStatus `json:",inline"`
 }
@@ -360,6 +539,12 @@ const (
 
 	// ClusterReconciling represents some component is reconciling
 	ClusterReconciling Phase = "reconciling"
+
+	// ClusterDeleting represents starrocks cluster is being deleted, and the operator is executing the deletion policy.
+	ClusterDeleting Phase = "deleting"
+
+	// ClusterSuspended represents all the components of starrocks cluster have been scaled to zero.
+	ClusterSuspended Phase = "suspended"
 )
 
 const (
@@ -0,0 +1,134 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StarRocksRestoreSpec defines the desired state of StarRocksRestore.
+// The snapshot is specified by Backup, or by Repository, Database and SnapshotName.
+type StarRocksRestoreSpec struct {
+	// StarRocksCluster is the name of a StarRocksCluster in the same namespace, where the snapshot is restored to.
+	StarRocksCluster string `json:"starRocksCluster"`
+
+	// Backup is the name of a succeeded StarRocksBackup in the same namespace. The repository, database, tables and
+	// snapshot name of the backup are used if they are not specified.
+	// +optional
+	Backup string `json:"backup,omitempty"`
+
+	// Repository is the repository where the snapshot is stored. It will be created by CREATE REPOSITORY if it
+	// does not exist in StarRocks, e.g. the snapshot is restored to a new cluster.
+	// +optional
+	Repository *BackupRepository `json:"repository,omitempty"`
+
+	// Database is the name of the database to restore.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// Tables is the list of tables to restore. If it is empty, all the tables in the snapshot are restored.
+	// +optional
+	Tables []TableName `json:"tables,omitempty"`
+
+	// SnapshotName is the name of the snapshot in the repository.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// BackupTimestamp is the timestamp of the snapshot, e.g. 2024-01-01-10-00-00-000. If it is empty, the latest
+	// timestamp of the snapshot returned by SHOW SNAPSHOT is used.
+	// +optional
+	BackupTimestamp string `json:"backupTimestamp,omitempty"`
+
+	// ReplicationNum is the number of replicas of the restored tables. The default is the number in the snapshot.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReplicationNum *int32 `json:"replicationNum,omitempty"`
+}
+
+// StarRocksRestoreStatus defines the observed state of StarRocksRestore.
+type StarRocksRestoreStatus struct {
+	// Phase represents the phase of the restore, the possible values are: Pending, Running, Succeeded and Failed.
+	// +optional
+	Phase BackupPhase `json:"phase,omitempty"`
+
+	// Reason represents the reason why the restore is pending or failed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// SnapshotName is the name of the restored snapshot.
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// BackupTimestamp is the timestamp of the restored snapshot.
+	// +optional
+	BackupTimestamp string `json:"backupTimestamp,omitempty"`
+
+	// JobID is the id of the restore job in StarRocks.
+	// +optional
+	JobID string `json:"jobId,omitempty"`
+
+	// State is the state of the restore job in StarRocks, e.g. DOWNLOADING, COMMITTING, FINISHED.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Progress is the progress of downloading the snapshot, e.g. 3/10.
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// StartTime is the time when the restore job is submitted.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the restore job is finished or cancelled.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// StarRocksRestore restores a snapshot in a repository to StarRocksCluster by RESTORE SNAPSHOT.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=srrestore
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="cluster",type=string,JSONPath=`.spec.starRocksCluster`
+// +kubebuilder:printcolumn:name="snapshot",type=string,JSONPath=`.status.snapshotName`
+// +kubebuilder:printcolumn:name="phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="progress",type=string,JSONPath=`.status.progress`
+// +k8s:openapi-gen=true
+// +genclient
+type StarRocksRestore struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of desired state of a restore.
+	Spec StarRocksRestoreSpec `json:"spec,omitempty"`
+
+	// Status represents the recent observed status of the restore.
+	Status StarRocksRestoreStatus `json:"status,omitempty"`
+}
+
+// StarRocksRestoreList contains a list of StarRocksRestore
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type StarRocksRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StarRocksRestore `json:"items"`
+}
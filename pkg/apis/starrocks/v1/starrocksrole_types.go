@@ -0,0 +1,119 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PrivilegeGrant grants privileges on an object, e.g. GRANT SELECT, INSERT ON TABLE db1.t1.
+type PrivilegeGrant struct {
+	// Privileges are the privileges to grant, e.g. SELECT, INSERT, ALTER.
+	// +kubebuilder:validation:MinItems=1
+	Privileges []Privilege `json:"privileges"`
+
+	// On is the object of the privileges, it follows the ON clause of GRANT, e.g. TABLE db1.t1,
+	// ALL TABLES IN DATABASE db1, DATABASE db1 or SYSTEM.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.* ]+$`
+	On string `json:"on"`
+}
+
+// Privilege is a privilege of GRANT, e.g. SELECT or CREATE TABLE.
+// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z ]*$`
+type Privilege string
+
+// AccessControlPhase represents the phase of StarRocksUser and StarRocksRole.
+type AccessControlPhase string
+
+const (
+	// AccessControlPending means the user or role has not been applied, e.g. FE is not ready.
+	AccessControlPending AccessControlPhase = "Pending"
+
+	// AccessControlApplied means the user or role has been applied to StarRocks.
+	AccessControlApplied AccessControlPhase = "Applied"
+
+	// AccessControlFailed means some statements failed, the reason is in the status.
+	AccessControlFailed AccessControlPhase = "Failed"
+)
+
+// StarRocksRoleSpec defines the desired state of StarRocksRole.
+type StarRocksRoleSpec struct {
+	// StarRocksCluster is the name of a StarRocksCluster in the same namespace.
+	StarRocksCluster string `json:"starRocksCluster"`
+
+	// RoleName is the name of the role in StarRocks. If it is empty, the name of StarRocksRole is used, and the
+	// hyphens are replaced by underscores.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	// +optional
+	RoleName string `json:"roleName,omitempty"`
+
+	// Grants are the privileges granted to the role.
+	// +optional
+	Grants []PrivilegeGrant `json:"grants,omitempty"`
+}
+
+// StarRocksRoleStatus defines the observed state of StarRocksRole.
+type StarRocksRoleStatus struct {
+	// Phase represents the phase of the role, the possible values are: Pending, Applied and Failed.
+	// +optional
+	Phase AccessControlPhase `json:"phase,omitempty"`
+
+	// Reason represents the reason why the role is not applied.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// RoleName is the name of the role which has been created in StarRocks.
+	// +optional
+	RoleName string `json:"roleName,omitempty"`
+
+	// Grants are the privileges which have been granted to the role.
+	// +optional
+	Grants []PrivilegeGrant `json:"grants,omitempty"`
+}
+
+// StarRocksRole manages a role of StarRocksCluster and its privileges by CREATE ROLE and GRANT.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=srrole
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="cluster",type=string,JSONPath=`.spec.starRocksCluster`
+// +kubebuilder:printcolumn:name="role",type=string,JSONPath=`.status.roleName`
+// +kubebuilder:printcolumn:name="phase",type=string,JSONPath=`.status.phase`
+// +k8s:openapi-gen=true
+// +genclient
+type StarRocksRole struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of desired state of a role.
+	Spec StarRocksRoleSpec `json:"spec,omitempty"`
+
+	// Status represents the recent observed status of the role.
+	Status StarRocksRoleStatus `json:"status,omitempty"`
+}
+
+// StarRocksRoleList contains a list of StarRocksRole
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type StarRocksRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StarRocksRole `json:"items"`
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StarRocksStorageVolumeSpec defines the desired state of StarRocksStorageVolume.
+type StarRocksStorageVolumeSpec struct {
+	// StarRocksCluster is the name of a StarRocksCluster in the same namespace, which must run in shared-data mode.
+	StarRocksCluster string `json:"starRocksCluster"`
+
+	// VolumeName is the name of the storage volume in StarRocks. If it is empty, the name of StarRocksStorageVolume
+	// is used, and the hyphens are replaced by underscores.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	// +optional
+	VolumeName string `json:"volumeName,omitempty"`
+
+	// Type is the type of the remote storage, the possible values are: S3, MinIO, AZBLOB and HDFS.
+	Type StorageVolumeType `json:"type"`
+
+	// Endpoint is the endpoint of the remote storage, e.g. http://minio.minio:9000 for MinIO,
+	// https://account.blob.core.windows.net for AZBLOB and hdfs://host:port for HDFS.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Region is the region of S3.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Bucket is the bucket of S3 and MinIO, or the container of AZBLOB. It is not used by HDFS.
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+
+	// Prefix is the path in the bucket, or the path in HDFS.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// CredentialsSecretRef is a secret in the same namespace. For S3 and MinIO, the keys access_key and secret_key
+	// are used. For AZBLOB, the key shared_key is used. For HDFS, the keys username and password are used.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// Default makes the storage volume the default storage volume of StarRocks. Note that the default storage volume
+	// can not be unset, you can only make another storage volume the default one.
+	// +optional
+	Default bool `json:"default,omitempty"`
+
+	// Comment is the comment of the storage volume.
+	// +optional
+	Comment string `json:"comment,omitempty"`
+
+	// Properties are the extra properties of the storage volume, they override the properties generated from the
+	// fields above.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// StorageVolumeType is the type of the remote storage of a storage volume.
+// +kubebuilder:validation:Enum=S3;MinIO;AZBLOB;HDFS
+type StorageVolumeType string
+
+const (
+	// S3StorageVolume stores the data in AWS S3 or a storage which is compatible with S3.
+	S3StorageVolume StorageVolumeType = "S3"
+
+	// MinIOStorageVolume stores the data in MinIO, the path-style access is used.
+	MinIOStorageVolume StorageVolumeType = "MinIO"
+
+	// AzureBlobStorageVolume stores the data in Azure Blob Storage.
+	AzureBlobStorageVolume StorageVolumeType = "AZBLOB"
+
+	// HDFSStorageVolume stores the data in HDFS.
+	HDFSStorageVolume StorageVolumeType = "HDFS"
+)
+
+// StorageVolumePhase represents the phase of StarRocksStorageVolume.
+type StorageVolumePhase string
+
+const (
+	// StorageVolumePending means the storage volume has not been created, e.g. FE is not ready.
+	StorageVolumePending StorageVolumePhase = "Pending"
+
+	// StorageVolumeReady means the storage volume in StarRocks matches the spec.
+	StorageVolumeReady StorageVolumePhase = "Ready"
+
+	// StorageVolumeDrifted means the storage volume in StarRocks differs from the spec, and the difference can not
+	// be fixed by ALTER STORAGE VOLUME, e.g. the type or the location is changed.
+	StorageVolumeDrifted StorageVolumePhase = "Drifted"
+
+	// StorageVolumeFailed means the storage volume can not be created or altered.
+	StorageVolumeFailed StorageVolumePhase = "Failed"
+)
+
+// StarRocksStorageVolumeStatus defines the observed state of StarRocksStorageVolume.
+type StarRocksStorageVolumeStatus struct {
+	// Phase represents the phase of the storage volume, the possible values are: Pending, Ready, Drifted and Failed.
+	// +optional
+	Phase StorageVolumePhase `json:"phase,omitempty"`
+
+	// Reason represents the reason why the storage volume is not ready.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// VolumeName is the name of the storage volume in StarRocks.
+	// +optional
+	VolumeName string `json:"volumeName,omitempty"`
+
+	// IsDefault is true if the storage volume is the default storage volume of StarRocks.
+	// +optional
+	IsDefault bool `json:"isDefault,omitempty"`
+
+	// Drifts are the differences between the spec and DESC STORAGE VOLUME which can not be fixed by the operator.
+	// +optional
+	Drifts []string `json:"drifts,omitempty"`
+
+	// ObservedGeneration is the generation of the spec which has been applied to StarRocks.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// StarRocksStorageVolume manages a storage volume of a shared-data StarRocksCluster by CREATE/ALTER/DROP STORAGE
+// VOLUME.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=srsv
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="cluster",type=string,JSONPath=`.spec.starRocksCluster`
+// +kubebuilder:printcolumn:name="volume",type=string,JSONPath=`.status.volumeName`
+// +kubebuilder:printcolumn:name="type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="default",type=boolean,JSONPath=`.status.isDefault`
+// +kubebuilder:printcolumn:name="phase",type=string,JSONPath=`.status.phase`
+// +k8s:openapi-gen=true
+// +genclient
+type StarRocksStorageVolume struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of desired state of a storage volume.
+	Spec StarRocksStorageVolumeSpec `json:"spec,omitempty"`
+
+	// Status represents the recent observed status of the storage volume.
+	Status StarRocksStorageVolumeStatus `json:"status,omitempty"`
+}
+
+// StarRocksStorageVolumeList contains a list of StarRocksStorageVolume
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type StarRocksStorageVolumeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StarRocksStorageVolume `json:"items"`
+}
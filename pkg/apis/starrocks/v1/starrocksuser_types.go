@@ -0,0 +1,129 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StarRocksUserSpec defines the desired state of StarRocksUser.
+type StarRocksUserSpec struct {
+	// StarRocksCluster is the name of a StarRocksCluster in the same namespace.
+	StarRocksCluster string `json:"starRocksCluster"`
+
+	// UserName is the name of the user in StarRocks. If it is empty, the name of StarRocksUser is used.
+	// +optional
+	UserName string `json:"userName,omitempty"`
+
+	// Host is the host from which the user can connect. The default is %.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// PasswordSecretRef is a key of a secret in the same namespace, whose value is the password of the user.
+	// The password is changed by ALTER USER when the secret is changed.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// AuthPlugin authenticates the user by a plugin instead of a password, e.g. authentication_ldap_simple.
+	// It can not be set together with PasswordSecretRef.
+	// +optional
+	AuthPlugin *UserAuthPlugin `json:"authPlugin,omitempty"`
+
+	// Roles are the roles granted to the user.
+	// +optional
+	Roles []RoleName `json:"roles,omitempty"`
+
+	// Grants are the privileges granted to the user directly.
+	// +optional
+	Grants []PrivilegeGrant `json:"grants,omitempty"`
+}
+
+// RoleName is the name of a role in StarRocks, e.g. db_admin.
+// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+type RoleName string
+
+// UserAuthPlugin is the authentication plugin of a user, e.g. IDENTIFIED WITH authentication_ldap_simple AS 'xxx'.
+type UserAuthPlugin struct {
+	// Name is the name of the plugin, e.g. authentication_ldap_simple.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	Name string `json:"name"`
+
+	// AuthenticationString is the string after AS, e.g. the DN of the user in LDAP.
+	// +optional
+	AuthenticationString string `json:"authenticationString,omitempty"`
+}
+
+// StarRocksUserStatus defines the observed state of StarRocksUser.
+type StarRocksUserStatus struct {
+	// Phase represents the phase of the user, the possible values are: Pending, Applied and Failed.
+	// +optional
+	Phase AccessControlPhase `json:"phase,omitempty"`
+
+	// Reason represents the reason why the user is not applied.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// UserIdentity is the identity of the user which has been created in StarRocks, e.g. 'alice'@'%'.
+	// +optional
+	UserIdentity string `json:"userIdentity,omitempty"`
+
+	// AuthenticationHash is the hash of the authentication which has been applied, it is used to detect the
+	// change of the password.
+	// +optional
+	AuthenticationHash string `json:"authenticationHash,omitempty"`
+
+	// Roles are the roles which have been granted to the user.
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+
+	// Grants are the privileges which have been granted to the user.
+	// +optional
+	Grants []PrivilegeGrant `json:"grants,omitempty"`
+}
+
+// StarRocksUser manages a user of StarRocksCluster, its roles and privileges by CREATE USER and GRANT.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=sruser
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="cluster",type=string,JSONPath=`.spec.starRocksCluster`
+// +kubebuilder:printcolumn:name="user",type=string,JSONPath=`.status.userIdentity`
+// +kubebuilder:printcolumn:name="phase",type=string,JSONPath=`.status.phase`
+// +k8s:openapi-gen=true
+// +genclient
+type StarRocksUser struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of desired state of a user.
+	Spec StarRocksUserSpec `json:"spec,omitempty"`
+
+	// Status represents the recent observed status of the user.
+	Status StarRocksUserStatus `json:"status,omitempty"`
+}
+
+// StarRocksUserList contains a list of StarRocksUser
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type StarRocksUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StarRocksUser `json:"items"`
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// SuspendPhase represents the phase of suspending or resuming a StarRocksCluster.
+type SuspendPhase string
+
+const (
+	// SuspendPhaseSuspending means the components are being scaled to zero one by one.
+	SuspendPhaseSuspending SuspendPhase = "suspending"
+
+	// SuspendPhaseSuspended means all the components have been scaled to zero.
+	SuspendPhaseSuspended SuspendPhase = "suspended"
+
+	// SuspendPhaseResuming means the components are being scaled back one by one.
+	SuspendPhaseResuming SuspendPhase = "resuming"
+)
+
+// FeObserverComponent is the name of FE observer groups in SuspendStatus.SuspendedComponents.
+const FeObserverComponent = "fe-observer"
+
+// SuspendStatus represents the status of suspending or resuming a StarRocksCluster.
+type SuspendStatus struct {
+	// Phase is the phase of suspending or resuming, the possible values are: suspending, suspended, resuming.
+	Phase SuspendPhase `json:"phase,omitempty"`
+
+	// Reason is the component the operator is waiting for.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// SuspendedComponents are the components whose replicas are set to zero by the operator, the possible values
+	// are: cn, be, fe-proxy, fe-observer, fe.
+	// +optional
+	SuspendedComponents []string `json:"suspendedComponents,omitempty"`
+
+	// Replicas records the replicas of the statefulsets and deployments before the cluster is suspended. The key is
+	// the name of the statefulset or deployment.
+	// +optional
+	Replicas map[string]int32 `json:"replicas,omitempty"`
+
+	// HorizontalScaler records the HPA of CN before the cluster is suspended. The HPA is deleted while CN is
+	// suspended, and it is created again when CN is resumed.
+	// +optional
+	HorizontalScaler *HorizontalScaler `json:"horizontalScaler,omitempty"`
+}
+
+// IsComponentSuspended returns true if the replicas of the component should be set to zero.
+func (src *StarRocksCluster) IsComponentSuspended(component string) bool {
+	if src == nil || src.Status.SuspendStatus == nil {
+		return false
+	}
+	for _, c := range src.Status.SuspendStatus.SuspendedComponents {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSuspendedReplicas returns the replicas of the statefulset or deployment before the cluster is suspended.
+func (src *StarRocksCluster) GetSuspendedReplicas(name string) (int32, bool) {
+	if src == nil || src.Status.SuspendStatus == nil {
+		return 0, false
+	}
+	replicas, ok := src.Status.SuspendStatus.Replicas[name]
+	return replicas, ok
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// VolumeExpansionPhase represents the phase of expanding the persistent volume claims of a pod.
+type VolumeExpansionPhase string
+
+const (
+	// VolumeExpansionResizing means the persistent volume claims have been patched, and the volumes are being resized.
+	VolumeExpansionResizing VolumeExpansionPhase = "resizing"
+
+	// VolumeExpansionFileSystemResizePending means the volumes have been resized, and the file systems will be resized
+	// after the pod is restarted.
+	VolumeExpansionFileSystemResizePending VolumeExpansionPhase = "fileSystemResizePending"
+
+	// VolumeExpansionDone means the persistent volume claims have been expanded.
+	VolumeExpansionDone VolumeExpansionPhase = "done"
+
+	// VolumeExpansionFailed means the persistent volume claims can not be expanded, e.g. the storage class does not
+	// allow volume expansion.
+	VolumeExpansionFailed VolumeExpansionPhase = "failed"
+)
+
+// VolumeExpansionStatus represents the progress of expanding the persistent volume claims of a component.
+type VolumeExpansionStatus struct {
+	// Pods is the progress of every pod.
+	Pods []PodVolumeExpansionStatus `json:"pods,omitempty"`
+}
+
+// PodVolumeExpansionStatus represents the progress of expanding the persistent volume claims of a pod.
+type PodVolumeExpansionStatus struct {
+	// Name is the name of the pod.
+	Name string `json:"name"`
+
+	// Phase is the phase of the persistent volume claims which are being expanded, the possible values are: resizing,
+	// fileSystemResizePending, done, failed.
+	Phase VolumeExpansionPhase `json:"phase"`
+
+	// Reason is the reason why the persistent volume claims have not been expanded.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
@@ -24,6 +24,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -35,6 +36,13 @@ func (in *AutoScalingPolicy) DeepCopyInto(out *AutoScalingPolicy) {
 		*out = new(HPAPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StarRocksMetrics != nil {
+		in, out := &in.StarRocksMetrics, &out.StarRocksMetrics
+		*out = make([]StarRocksMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.MinReplicas != nil {
 		in, out := &in.MinReplicas, &out.MinReplicas
 		*out = new(int32)
@@ -52,6 +60,114 @@ func (in *AutoScalingPolicy) DeepCopy() *AutoScalingPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRepository) DeepCopyInto(out *BackupRepository) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRepository.
+func (in *BackupRepository) DeepCopy() *BackupRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRetention) DeepCopyInto(out *BackupRetention) {
+	*out = *in
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(int32)
+		**out = **in
+	}
+	if in.KeepDays != nil {
+		in, out := &in.KeepDays, &out.KeepDays
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRetention.
+func (in *BackupRetention) DeepCopy() *BackupRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupTarget) DeepCopyInto(out *BackupTarget) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]TableName, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTarget.
+func (in *BackupTarget) DeepCopy() *BackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BeScaleInStatus) DeepCopyInto(out *BeScaleInStatus) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]DecommissioningBackend, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeScaleInStatus.
+func (in *BeScaleInStatus) DeepCopy() *BeScaleInStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BeScaleInStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSnapshot) DeepCopyInto(out *ClusterSnapshot) {
+	*out = *in
+	in.StorageVolume.DeepCopyInto(&out.StorageVolume)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSnapshot.
+func (in *ClusterSnapshot) DeepCopy() *ClusterSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapInfo) DeepCopyInto(out *ConfigMapInfo) {
 	*out = *in
@@ -82,9 +198,74 @@ func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigStatus) DeepCopyInto(out *ConfigStatus) {
+	*out = *in
+	if in.HotAppliedKeys != nil {
+		in, out := &in.HotAppliedKeys, &out.HotAppliedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingRestartKeys != nil {
+		in, out := &in.PendingRestartKeys, &out.PendingRestartKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigStatus.
+func (in *ConfigStatus) DeepCopy() *ConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecommissioningBackend) DeepCopyInto(out *DecommissioningBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecommissioningBackend.
+func (in *DecommissioningBackend) DeepCopy() *DecommissioningBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(DecommissioningBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionPolicy) DeepCopyInto(out *DeletionPolicy) {
+	*out = *in
+	if in.FinalSnapshot != nil {
+		in, out := &in.FinalSnapshot, &out.FinalSnapshot
+		*out = new(FinalSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPolicy.
+func (in *DeletionPolicy) DeepCopy() *DeletionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DisasterRecovery) DeepCopyInto(out *DisasterRecovery) {
 	*out = *in
+	if in.ClusterSnapshot != nil {
+		in, out := &in.ClusterSnapshot, &out.ClusterSnapshot
+		*out = new(ClusterSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecovery.
@@ -97,9 +278,29 @@ func (in *DisasterRecovery) DeepCopy() *DisasterRecovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryRecord) DeepCopyInto(out *DisasterRecoveryRecord) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryRecord.
+func (in *DisasterRecoveryRecord) DeepCopy() *DisasterRecoveryRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DisasterRecoveryStatus) DeepCopyInto(out *DisasterRecoveryStatus) {
 	*out = *in
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]DisasterRecoveryRecord, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryStatus.
@@ -112,6 +313,31 @@ func (in *DisasterRecoveryStatus) DeepCopy() *DisasterRecoveryStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalSnapshot) DeepCopyInto(out *FinalSnapshot) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseName, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalSnapshot.
+func (in *FinalSnapshot) DeepCopy() *FinalSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HPAPolicy) DeepCopyInto(out *HPAPolicy) {
 	*out = *in
@@ -170,61 +396,84 @@ func (in *MountInfo) DeepCopy() *MountInfo {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+func (in *PodVolumeExpansionStatus) DeepCopyInto(out *PodVolumeExpansionStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
-func (in *SecretReference) DeepCopy() *SecretReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodVolumeExpansionStatus.
+func (in *PodVolumeExpansionStatus) DeepCopy() *PodVolumeExpansionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretReference)
+	out := new(PodVolumeExpansionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksBeSpec) DeepCopyInto(out *StarRocksBeSpec) {
+func (in *PrivilegeGrant) DeepCopyInto(out *PrivilegeGrant) {
 	*out = *in
-	in.StarRocksComponentSpec.DeepCopyInto(&out.StarRocksComponentSpec)
-	if in.BeEnvVars != nil {
-		in, out := &in.BeEnvVars, &out.BeEnvVars
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]Privilege, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBeSpec.
-func (in *StarRocksBeSpec) DeepCopy() *StarRocksBeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivilegeGrant.
+func (in *PrivilegeGrant) DeepCopy() *PrivilegeGrant {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksBeSpec)
+	out := new(PrivilegeGrant)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksBeStatus) DeepCopyInto(out *StarRocksBeStatus) {
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
 	*out = *in
-	in.StarRocksComponentStatus.DeepCopyInto(&out.StarRocksComponentStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBeStatus.
-func (in *StarRocksBeStatus) DeepCopy() *StarRocksBeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksBeStatus)
+	out := new(SecretReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksCluster) DeepCopyInto(out *StarRocksCluster) {
+func (in *SnapshotStorageVolume) DeepCopyInto(out *SnapshotStorageVolume) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotStorageVolume.
+func (in *SnapshotStorageVolume) DeepCopy() *SnapshotStorageVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotStorageVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksBackup) DeepCopyInto(out *StarRocksBackup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -232,18 +481,18 @@ func (in *StarRocksCluster) DeepCopyInto(out *StarRocksCluster) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksCluster.
-func (in *StarRocksCluster) DeepCopy() *StarRocksCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBackup.
+func (in *StarRocksBackup) DeepCopy() *StarRocksBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksCluster)
+	out := new(StarRocksBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *StarRocksCluster) DeepCopyObject() runtime.Object {
+func (in *StarRocksBackup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -251,31 +500,31 @@ func (in *StarRocksCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksClusterList) DeepCopyInto(out *StarRocksClusterList) {
+func (in *StarRocksBackupList) DeepCopyInto(out *StarRocksBackupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]StarRocksCluster, len(*in))
+		*out = make([]StarRocksBackup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksClusterList.
-func (in *StarRocksClusterList) DeepCopy() *StarRocksClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBackupList.
+func (in *StarRocksBackupList) DeepCopy() *StarRocksBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksClusterList)
+	out := new(StarRocksBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *StarRocksClusterList) DeepCopyObject() runtime.Object {
+func (in *StarRocksBackupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -283,56 +532,343 @@ func (in *StarRocksClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksClusterSpec) DeepCopyInto(out *StarRocksClusterSpec) {
+func (in *StarRocksBackupSchedule) DeepCopyInto(out *StarRocksBackupSchedule) {
 	*out = *in
-	if in.StarRocksFeSpec != nil {
-		in, out := &in.StarRocksFeSpec, &out.StarRocksFeSpec
-		*out = new(StarRocksFeSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.StarRocksBeSpec != nil {
-		in, out := &in.StarRocksBeSpec, &out.StarRocksBeSpec
-		*out = new(StarRocksBeSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.StarRocksCnSpec != nil {
-		in, out := &in.StarRocksCnSpec, &out.StarRocksCnSpec
-		*out = new(StarRocksCnSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.StarRocksFeProxySpec != nil {
-		in, out := &in.StarRocksFeProxySpec, &out.StarRocksFeProxySpec
-		*out = new(StarRocksFeProxySpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DisasterRecovery != nil {
-		in, out := &in.DisasterRecovery, &out.DisasterRecovery
-		*out = new(DisasterRecovery)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksClusterSpec.
-func (in *StarRocksClusterSpec) DeepCopy() *StarRocksClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBackupSchedule.
+func (in *StarRocksBackupSchedule) DeepCopy() *StarRocksBackupSchedule {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksClusterSpec)
+	out := new(StarRocksBackupSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksBackupSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksClusterStatus) DeepCopyInto(out *StarRocksClusterStatus) {
+func (in *StarRocksBackupScheduleList) DeepCopyInto(out *StarRocksBackupScheduleList) {
 	*out = *in
-	if in.StarRocksFeStatus != nil {
-		in, out := &in.StarRocksFeStatus, &out.StarRocksFeStatus
-		*out = new(StarRocksFeStatus)
-		(*in).DeepCopyInto(*out)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StarRocksBackupSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.StarRocksBeStatus != nil {
-		in, out := &in.StarRocksBeStatus, &out.StarRocksBeStatus
-		*out = new(StarRocksBeStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBackupScheduleList.
+func (in *StarRocksBackupScheduleList) DeepCopy() *StarRocksBackupScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksBackupScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksBackupScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksBackupScheduleSpec) DeepCopyInto(out *StarRocksBackupScheduleSpec) {
+	*out = *in
+	in.Repository.DeepCopyInto(&out.Repository)
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]BackupTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(BackupRetention)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBackupScheduleSpec.
+func (in *StarRocksBackupScheduleSpec) DeepCopy() *StarRocksBackupScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksBackupScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksBackupScheduleStatus) DeepCopyInto(out *StarRocksBackupScheduleStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailedTime != nil {
+		in, out := &in.LastFailedTime, &out.LastFailedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBackupScheduleStatus.
+func (in *StarRocksBackupScheduleStatus) DeepCopy() *StarRocksBackupScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksBackupScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksBackupSpec) DeepCopyInto(out *StarRocksBackupSpec) {
+	*out = *in
+	in.Repository.DeepCopyInto(&out.Repository)
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]TableName, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBackupSpec.
+func (in *StarRocksBackupSpec) DeepCopy() *StarRocksBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksBackupStatus) DeepCopyInto(out *StarRocksBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBackupStatus.
+func (in *StarRocksBackupStatus) DeepCopy() *StarRocksBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksBeSpec) DeepCopyInto(out *StarRocksBeSpec) {
+	*out = *in
+	in.StarRocksComponentSpec.DeepCopyInto(&out.StarRocksComponentSpec)
+	if in.BeEnvVars != nil {
+		in, out := &in.BeEnvVars, &out.BeEnvVars
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBeSpec.
+func (in *StarRocksBeSpec) DeepCopy() *StarRocksBeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksBeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksBeStatus) DeepCopyInto(out *StarRocksBeStatus) {
+	*out = *in
+	in.StarRocksComponentStatus.DeepCopyInto(&out.StarRocksComponentStatus)
+	if in.ScaleIn != nil {
+		in, out := &in.ScaleIn, &out.ScaleIn
+		*out = new(BeScaleInStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksBeStatus.
+func (in *StarRocksBeStatus) DeepCopy() *StarRocksBeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksBeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksCluster) DeepCopyInto(out *StarRocksCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksCluster.
+func (in *StarRocksCluster) DeepCopy() *StarRocksCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksClusterList) DeepCopyInto(out *StarRocksClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StarRocksCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksClusterList.
+func (in *StarRocksClusterList) DeepCopy() *StarRocksClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksClusterSpec) DeepCopyInto(out *StarRocksClusterSpec) {
+	*out = *in
+	if in.StarRocksFeSpec != nil {
+		in, out := &in.StarRocksFeSpec, &out.StarRocksFeSpec
+		*out = new(StarRocksFeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StarRocksBeSpec != nil {
+		in, out := &in.StarRocksBeSpec, &out.StarRocksBeSpec
+		*out = new(StarRocksBeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StarRocksCnSpec != nil {
+		in, out := &in.StarRocksCnSpec, &out.StarRocksCnSpec
+		*out = new(StarRocksCnSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StarRocksFeProxySpec != nil {
+		in, out := &in.StarRocksFeProxySpec, &out.StarRocksFeProxySpec
+		*out = new(StarRocksFeProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecovery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RootPasswordSecretRef != nil {
+		in, out := &in.RootPasswordSecretRef, &out.RootPasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksClusterSpec.
+func (in *StarRocksClusterSpec) DeepCopy() *StarRocksClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksClusterStatus) DeepCopyInto(out *StarRocksClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StarRocksFeStatus != nil {
+		in, out := &in.StarRocksFeStatus, &out.StarRocksFeStatus
+		*out = new(StarRocksFeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StarRocksBeStatus != nil {
+		in, out := &in.StarRocksBeStatus, &out.StarRocksBeStatus
+		*out = new(StarRocksBeStatus)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.StarRocksCnStatus != nil {
@@ -345,10 +881,22 @@ func (in *StarRocksClusterStatus) DeepCopyInto(out *StarRocksClusterStatus) {
 		*out = new(StarRocksFeProxyStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StarRocksFeObserverStatus != nil {
+		in, out := &in.StarRocksFeObserverStatus, &out.StarRocksFeObserverStatus
+		*out = make([]StarRocksFeObserverGroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.DisasterRecoveryStatus != nil {
 		in, out := &in.DisasterRecoveryStatus, &out.DisasterRecoveryStatus
 		*out = new(DisasterRecoveryStatus)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SuspendStatus != nil {
+		in, out := &in.SuspendStatus, &out.SuspendStatus
+		*out = new(SuspendStatus)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -421,6 +969,13 @@ func (in *StarRocksComponentSpec) DeepCopyInto(out *StarRocksComponentSpec) {
 		*out = new(corev1.Capabilities)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.ConfigMaps != nil {
 		in, out := &in.ConfigMaps, &out.ConfigMaps
 		*out = make([]ConfigMapReference, len(*in))
@@ -527,268 +1082,860 @@ func (in *StarRocksComponentStatus) DeepCopyInto(out *StarRocksComponentStatus)
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksComponentStatus.
-func (in *StarRocksComponentStatus) DeepCopy() *StarRocksComponentStatus {
-	if in == nil {
-		return nil
-	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeExpansion != nil {
+		in, out := &in.VolumeExpansion, &out.VolumeExpansion
+		*out = new(VolumeExpansionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(ConfigStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksComponentStatus.
+func (in *StarRocksComponentStatus) DeepCopy() *StarRocksComponentStatus {
+	if in == nil {
+		return nil
+	}
 	out := new(StarRocksComponentStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksFeObserverGroup) DeepCopyInto(out *StarRocksFeObserverGroup) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeObserverGroup.
+func (in *StarRocksFeObserverGroup) DeepCopy() *StarRocksFeObserverGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksFeObserverGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksFeObserverGroupStatus) DeepCopyInto(out *StarRocksFeObserverGroupStatus) {
+	*out = *in
+	in.StarRocksComponentStatus.DeepCopyInto(&out.StarRocksComponentStatus)
+	if in.Observers != nil {
+		in, out := &in.Observers, &out.Observers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeObserverGroupStatus.
+func (in *StarRocksFeObserverGroupStatus) DeepCopy() *StarRocksFeObserverGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksFeObserverGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StarRocksFeProxySpec) DeepCopyInto(out *StarRocksFeProxySpec) {
 	*out = *in
 	in.StarRocksLoadSpec.DeepCopyInto(&out.StarRocksLoadSpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeProxySpec.
-func (in *StarRocksFeProxySpec) DeepCopy() *StarRocksFeProxySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeProxySpec.
+func (in *StarRocksFeProxySpec) DeepCopy() *StarRocksFeProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksFeProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksFeProxyStatus) DeepCopyInto(out *StarRocksFeProxyStatus) {
+	*out = *in
+	in.StarRocksComponentStatus.DeepCopyInto(&out.StarRocksComponentStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeProxyStatus.
+func (in *StarRocksFeProxyStatus) DeepCopy() *StarRocksFeProxyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksFeProxyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksFeRollingUpdatePolicy) DeepCopyInto(out *StarRocksFeRollingUpdatePolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeRollingUpdatePolicy.
+func (in *StarRocksFeRollingUpdatePolicy) DeepCopy() *StarRocksFeRollingUpdatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksFeRollingUpdatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksFeRollingUpdateStatus) DeepCopyInto(out *StarRocksFeRollingUpdateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeRollingUpdateStatus.
+func (in *StarRocksFeRollingUpdateStatus) DeepCopy() *StarRocksFeRollingUpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksFeRollingUpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksFeSpec) DeepCopyInto(out *StarRocksFeSpec) {
+	*out = *in
+	in.StarRocksComponentSpec.DeepCopyInto(&out.StarRocksComponentSpec)
+	if in.FeEnvVars != nil {
+		in, out := &in.FeEnvVars, &out.FeEnvVars
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ObserverGroups != nil {
+		in, out := &in.ObserverGroups, &out.ObserverGroups
+		*out = make([]StarRocksFeObserverGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RollingUpdatePolicy != nil {
+		in, out := &in.RollingUpdatePolicy, &out.RollingUpdatePolicy
+		*out = new(StarRocksFeRollingUpdatePolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeSpec.
+func (in *StarRocksFeSpec) DeepCopy() *StarRocksFeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksFeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksFeStatus) DeepCopyInto(out *StarRocksFeStatus) {
+	*out = *in
+	in.StarRocksComponentStatus.DeepCopyInto(&out.StarRocksComponentStatus)
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(StarRocksFeRollingUpdateStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeStatus.
+func (in *StarRocksFeStatus) DeepCopy() *StarRocksFeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksFeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksLoadSpec) DeepCopyInto(out *StarRocksLoadSpec) {
+	*out = *in
+	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(StarRocksService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageVolumes != nil {
+		in, out := &in.StorageVolumes, &out.StorageVolumes
+		*out = make([]StorageVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.ConfigMapInfo = in.ConfigMapInfo
+	if in.StartupProbeFailureSeconds != nil {
+		in, out := &in.StartupProbeFailureSeconds, &out.StartupProbeFailureSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LivenessProbeFailureSeconds != nil {
+		in, out := &in.LivenessProbeFailureSeconds, &out.LivenessProbeFailureSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReadinessProbeFailureSeconds != nil {
+		in, out := &in.ReadinessProbeFailureSeconds, &out.ReadinessProbeFailureSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(corev1.Lifecycle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShareProcessNamespace != nil {
+		in, out := &in.ShareProcessNamespace, &out.ShareProcessNamespace
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksLoadSpec.
+func (in *StarRocksLoadSpec) DeepCopy() *StarRocksLoadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksLoadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksMetric) DeepCopyInto(out *StarRocksMetric) {
+	*out = *in
+	out.Target = in.Target.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksMetric.
+func (in *StarRocksMetric) DeepCopy() *StarRocksMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksProbe) DeepCopyInto(out *StarRocksProbe) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksProbe.
+func (in *StarRocksProbe) DeepCopy() *StarRocksProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksRestore) DeepCopyInto(out *StarRocksRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksRestore.
+func (in *StarRocksRestore) DeepCopy() *StarRocksRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksRestoreList) DeepCopyInto(out *StarRocksRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StarRocksRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksRestoreList.
+func (in *StarRocksRestoreList) DeepCopy() *StarRocksRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksRestoreSpec) DeepCopyInto(out *StarRocksRestoreSpec) {
+	*out = *in
+	if in.Repository != nil {
+		in, out := &in.Repository, &out.Repository
+		*out = new(BackupRepository)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]TableName, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReplicationNum != nil {
+		in, out := &in.ReplicationNum, &out.ReplicationNum
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksRestoreSpec.
+func (in *StarRocksRestoreSpec) DeepCopy() *StarRocksRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksRestoreStatus) DeepCopyInto(out *StarRocksRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksRestoreStatus.
+func (in *StarRocksRestoreStatus) DeepCopy() *StarRocksRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksRole) DeepCopyInto(out *StarRocksRole) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksRole.
+func (in *StarRocksRole) DeepCopy() *StarRocksRole {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksRoleList) DeepCopyInto(out *StarRocksRoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StarRocksRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksRoleList.
+func (in *StarRocksRoleList) DeepCopy() *StarRocksRoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksRoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksRoleSpec) DeepCopyInto(out *StarRocksRoleSpec) {
+	*out = *in
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]PrivilegeGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksRoleSpec.
+func (in *StarRocksRoleSpec) DeepCopy() *StarRocksRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksRoleStatus) DeepCopyInto(out *StarRocksRoleStatus) {
+	*out = *in
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]PrivilegeGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksRoleStatus.
+func (in *StarRocksRoleStatus) DeepCopy() *StarRocksRoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksRoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksService) DeepCopyInto(out *StarRocksService) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]StarRocksServicePort, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksService.
+func (in *StarRocksService) DeepCopy() *StarRocksService {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksServicePort) DeepCopyInto(out *StarRocksServicePort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksServicePort.
+func (in *StarRocksServicePort) DeepCopy() *StarRocksServicePort {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksServicePort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksStorageVolume) DeepCopyInto(out *StarRocksStorageVolume) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksStorageVolume.
+func (in *StarRocksStorageVolume) DeepCopy() *StarRocksStorageVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(StarRocksStorageVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksStorageVolume) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StarRocksStorageVolumeList) DeepCopyInto(out *StarRocksStorageVolumeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StarRocksStorageVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksStorageVolumeList.
+func (in *StarRocksStorageVolumeList) DeepCopy() *StarRocksStorageVolumeList {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksFeProxySpec)
+	out := new(StarRocksStorageVolumeList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksStorageVolumeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksFeProxyStatus) DeepCopyInto(out *StarRocksFeProxyStatus) {
+func (in *StarRocksStorageVolumeSpec) DeepCopyInto(out *StarRocksStorageVolumeSpec) {
 	*out = *in
-	in.StarRocksComponentStatus.DeepCopyInto(&out.StarRocksComponentStatus)
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeProxyStatus.
-func (in *StarRocksFeProxyStatus) DeepCopy() *StarRocksFeProxyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksStorageVolumeSpec.
+func (in *StarRocksStorageVolumeSpec) DeepCopy() *StarRocksStorageVolumeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksFeProxyStatus)
+	out := new(StarRocksStorageVolumeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksFeSpec) DeepCopyInto(out *StarRocksFeSpec) {
+func (in *StarRocksStorageVolumeStatus) DeepCopyInto(out *StarRocksStorageVolumeStatus) {
 	*out = *in
-	in.StarRocksComponentSpec.DeepCopyInto(&out.StarRocksComponentSpec)
-	if in.FeEnvVars != nil {
-		in, out := &in.FeEnvVars, &out.FeEnvVars
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeSpec.
-func (in *StarRocksFeSpec) DeepCopy() *StarRocksFeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksStorageVolumeStatus.
+func (in *StarRocksStorageVolumeStatus) DeepCopy() *StarRocksStorageVolumeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksFeSpec)
+	out := new(StarRocksStorageVolumeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksFeStatus) DeepCopyInto(out *StarRocksFeStatus) {
+func (in *StarRocksUser) DeepCopyInto(out *StarRocksUser) {
 	*out = *in
-	in.StarRocksComponentStatus.DeepCopyInto(&out.StarRocksComponentStatus)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksFeStatus.
-func (in *StarRocksFeStatus) DeepCopy() *StarRocksFeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksUser.
+func (in *StarRocksUser) DeepCopy() *StarRocksUser {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksFeStatus)
+	out := new(StarRocksUser)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksLoadSpec) DeepCopyInto(out *StarRocksLoadSpec) {
+func (in *StarRocksUserList) DeepCopyInto(out *StarRocksUserList) {
 	*out = *in
-	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.PodLabels != nil {
-		in, out := &in.PodLabels, &out.PodLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(corev1.Affinity)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.TopologySpreadConstraints != nil {
-		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]corev1.TopologySpreadConstraint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(StarRocksService)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.StorageVolumes != nil {
-		in, out := &in.StorageVolumes, &out.StorageVolumes
-		*out = make([]StorageVolume, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StarRocksUser, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.ConfigMapInfo = in.ConfigMapInfo
-	if in.StartupProbeFailureSeconds != nil {
-		in, out := &in.StartupProbeFailureSeconds, &out.StartupProbeFailureSeconds
-		*out = new(int32)
-		**out = **in
-	}
-	if in.LivenessProbeFailureSeconds != nil {
-		in, out := &in.LivenessProbeFailureSeconds, &out.LivenessProbeFailureSeconds
-		*out = new(int32)
-		**out = **in
-	}
-	if in.ReadinessProbeFailureSeconds != nil {
-		in, out := &in.ReadinessProbeFailureSeconds, &out.ReadinessProbeFailureSeconds
-		*out = new(int32)
-		**out = **in
-	}
-	if in.Lifecycle != nil {
-		in, out := &in.Lifecycle, &out.Lifecycle
-		*out = new(corev1.Lifecycle)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ShareProcessNamespace != nil {
-		in, out := &in.ShareProcessNamespace, &out.ShareProcessNamespace
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksLoadSpec.
-func (in *StarRocksLoadSpec) DeepCopy() *StarRocksLoadSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksUserList.
+func (in *StarRocksUserList) DeepCopy() *StarRocksUserList {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksLoadSpec)
+	out := new(StarRocksUserList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksProbe) DeepCopyInto(out *StarRocksProbe) {
-	*out = *in
-	if in.InitialDelaySeconds != nil {
-		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
-		*out = new(int32)
-		**out = **in
-	}
-	if in.PeriodSeconds != nil {
-		in, out := &in.PeriodSeconds, &out.PeriodSeconds
-		*out = new(int32)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksProbe.
-func (in *StarRocksProbe) DeepCopy() *StarRocksProbe {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StarRocksUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(StarRocksProbe)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksService) DeepCopyInto(out *StarRocksService) {
+func (in *StarRocksUserSpec) DeepCopyInto(out *StarRocksUserSpec) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.AuthPlugin != nil {
+		in, out := &in.AuthPlugin, &out.AuthPlugin
+		*out = new(UserAuthPlugin)
+		**out = **in
 	}
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make([]StarRocksServicePort, len(*in))
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleName, len(*in))
 		copy(*out, *in)
 	}
-	if in.LoadBalancerSourceRanges != nil {
-		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]PrivilegeGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksService.
-func (in *StarRocksService) DeepCopy() *StarRocksService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksUserSpec.
+func (in *StarRocksUserSpec) DeepCopy() *StarRocksUserSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksService)
+	out := new(StarRocksUserSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StarRocksServicePort) DeepCopyInto(out *StarRocksServicePort) {
+func (in *StarRocksUserStatus) DeepCopyInto(out *StarRocksUserStatus) {
 	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]PrivilegeGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksServicePort.
-func (in *StarRocksServicePort) DeepCopy() *StarRocksServicePort {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StarRocksUserStatus.
+func (in *StarRocksUserStatus) DeepCopy() *StarRocksUserStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(StarRocksServicePort)
+	out := new(StarRocksUserStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -917,6 +2064,73 @@ func (in *StorageVolume) DeepCopy() *StorageVolume {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SuspendStatus) DeepCopyInto(out *SuspendStatus) {
+	*out = *in
+	if in.SuspendedComponents != nil {
+		in, out := &in.SuspendedComponents, &out.SuspendedComponents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.HorizontalScaler != nil {
+		in, out := &in.HorizontalScaler, &out.HorizontalScaler
+		*out = new(HorizontalScaler)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SuspendStatus.
+func (in *SuspendStatus) DeepCopy() *SuspendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SuspendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserAuthPlugin) DeepCopyInto(out *UserAuthPlugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserAuthPlugin.
+func (in *UserAuthPlugin) DeepCopy() *UserAuthPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(UserAuthPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeExpansionStatus) DeepCopyInto(out *VolumeExpansionStatus) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]PodVolumeExpansionStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeExpansionStatus.
+func (in *VolumeExpansionStatus) DeepCopy() *VolumeExpansionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeExpansionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WarehouseComponentSpec) DeepCopyInto(out *WarehouseComponentSpec) {
 	*out = *in
@@ -0,0 +1,213 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+// LoadSpec defines the shared specification for all StarRocks components, including FE Proxy.
+type LoadSpec struct {
+	// defines the specification of resource cpu and mem.
+	// +optional
+	corev1.ResourceRequirements `json:",inline"`
+
+	// annotation for pods. user can config monitor annotation for collect to monitor system.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// the pod labels for user select or classify pods.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
+	// Replicas is the number of desired Pods.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Image for a starrocks deployment.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Image pull policy.
+	// One of Always, Never, IfNotPresent.
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImagePullSecrets is an optional list of references to secrets in the same namespace to use for pulling any of the
+	// images used by this PodSpec.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// SchedulerName is the name of the kubernetes scheduler that will be used to schedule the pods.
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+
+	// If specified, the pod's nodeSelector.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// If specified, the pod's scheduling constraints.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Tolerations for scheduling pods onto some dedicated nodes
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// TopologySpreadConstraints for spreading pods across failure-domains
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// Service defines the template for the associated Kubernetes Service object.
+	// +optional
+	Service *v1.StarRocksService `json:"service,omitempty"`
+
+	// StorageVolumes defines the additional storage for FE meta, BE/CN storage or logs.
+	// +optional
+	StorageVolumes []v1.StorageVolume `json:"storageVolumes,omitempty"`
+
+	// serviceAccount for access cloud service.
+	// +optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+
+	// LivenessProbeFailureSeconds defines the total failure seconds of liveness Probe.
+	// Note: you can set it to 0 to disable the liveness probe.
+	// +optional
+	LivenessProbeFailureSeconds *int32 `json:"livenessProbeFailureSeconds,omitempty"`
+
+	// ReadinessProbeFailureSeconds defines the total failure seconds of readiness Probe.
+	// Note: you can set it to 0 to disable the readiness probe.
+	// +optional
+	ReadinessProbeFailureSeconds *int32 `json:"readinessProbeFailureSeconds,omitempty"`
+
+	// Share a single process namespace between all of the containers in a pod.
+	// +optional
+	ShareProcessNamespace *bool `json:"shareProcessNamespace,omitempty"`
+}
+
+// ComponentSpec defines the shared specification for FE, BE and CN.
+type ComponentSpec struct {
+	LoadSpec `json:",inline"`
+
+	// ConfigFile is the reference to the configMap which stores the config file of component, e.g. fe.conf, be.conf
+	// and cn.conf.
+	// +optional
+	ConfigFile *ConfigFile `json:"configFile,omitempty"`
+
+	// Config is the configuration of component, the operator renders it into the config file. It can not be used
+	// together with configFile.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+
+	// envVars is a slice of environment variables that are added to the pods, the default is empty.
+	// +optional
+	EnvVars []corev1.EnvVar `json:"envVars,omitempty"`
+
+	// StartupProbeFailureSeconds defines the total failure seconds of startup Probe.
+	// Note: you can set it to 0 to disable the startup probe.
+	// +optional
+	StartupProbeFailureSeconds *int32 `json:"startupProbeFailureSeconds,omitempty"`
+
+	// Lifecycle describes actions that the management system should take in response to container lifecycle events.
+	// +optional
+	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
+
+	// RunAsNonRoot is used to determine whether to run starrocks as a normal user.
+	// If RunAsNonRoot is true, operator will set RunAsUser and RunAsGroup to 1000 in securityContext.
+	// +optional
+	RunAsNonRoot *bool `json:"runAsNonRoot,omitempty"`
+
+	// grant certain privileges to a process without granting all the privileges of the root user
+	// +optional
+	Capabilities *corev1.Capabilities `json:"capabilities,omitempty"`
+
+	// the reference for configMaps which allow users to mount any files to container.
+	// +optional
+	ConfigMaps []v1.ConfigMapReference `json:"configMaps,omitempty"`
+
+	// the reference for secrets which allow users to mount any files to container.
+	// +optional
+	Secrets []v1.SecretReference `json:"secrets,omitempty"`
+
+	// HostAliases is an optional list of hosts and IPs that will be injected into the pod's hosts file.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// TerminationGracePeriodSeconds defines duration in seconds the pod needs to terminate gracefully.
+	// Defaults to 120 seconds.
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// Sidecars is an optional list of containers that are run in the same pod as the starrocks component.
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// InitContainers is an optional list of containers that are run before the main container starts.
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// Entrypoint array. Not executed within a shell.
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Arguments to the entrypoint.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// UpdateStrategy indicates the StatefulSetUpdateStrategy that will be employed to update Pods in the StatefulSet.
+	// +optional
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// Whether this container has a read-only root filesystem.
+	// +optional
+	ReadOnlyRootFilesystem *bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// Sysctls defines a list of namespaced sysctls for the podSecurityContext.sysctls
+	// +optional
+	Sysctls []corev1.Sysctl `json:"sysctls,omitempty"`
+
+	// PersistentVolumeClaimRetentionPolicy specifies the retention policy for PersistentVolumeClaims associated with the component.
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"` //nolint:lll
+
+	// MinReadySeconds specifies the minimum number of seconds for which a newly created pod should be ready
+	// without any of its container crashing, for it to be considered available.
+	// +optional
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+
+	// podManagementPolicy controls how pods are created during initial scale up, when replacing pods on nodes, or when scaling down.
+	// +optional
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+}
+
+// ConfigFile is the reference to the configMap which stores the config file of component.
+type ConfigFile struct {
+	// ConfigMapName is the name of configMap.
+	ConfigMapName string `json:"configMapName"`
+
+	// Key is the key of the config file in configMap, e.g. fe.conf.
+	Key string `json:"key"`
+
+	// DisableRollout disables restarting the pods when the configMap is changed.
+	// +optional
+	DisableRollout bool `json:"disableRollout,omitempty"`
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+// The conversion between v2 and v1(hub) is lossless except spec.serviceAccount of v1 StarRocksCluster, which has been
+// deprecated and is ignored by the operator.
+
+var _ conversion.Convertible = &StarRocksCluster{}
+var _ conversion.Convertible = &StarRocksWarehouse{}
+
+// ConvertTo converts StarRocksCluster to the hub version v1.
+func (src *StarRocksCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1.StarRocksCluster)
+	if !ok {
+		return fmt.Errorf("expected a v1 StarRocksCluster but got a %T", dstRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1.StarRocksClusterSpec{
+		DisasterRecovery:      src.Spec.DisasterRecovery,
+		WaitForFullRollout:    src.Spec.WaitForFullRollout,
+		DeletionPolicy:        src.Spec.DeletionPolicy,
+		Suspend:               src.Spec.Suspend,
+		RootPasswordSecretRef: src.Spec.RootPasswordSecretRef,
+	}
+	if src.Spec.Fe != nil {
+		dst.Spec.StarRocksFeSpec = &v1.StarRocksFeSpec{}
+		dst.Spec.StarRocksFeSpec.StarRocksComponentSpec, dst.Spec.StarRocksFeSpec.FeEnvVars =
+			convertComponentSpecToV1(&src.Spec.Fe.ComponentSpec)
+		dst.Spec.StarRocksFeSpec.ObserverGroups = copyObserverGroups(src.Spec.Fe.ObserverGroups)
+		dst.Spec.StarRocksFeSpec.RollingUpdatePolicy = src.Spec.Fe.RollingUpdatePolicy.DeepCopy()
+		dst.Spec.StarRocksFeSpec.EnableLeaderService = src.Spec.Fe.EnableLeaderService
+	}
+	if src.Spec.Be != nil {
+		dst.Spec.StarRocksBeSpec = &v1.StarRocksBeSpec{}
+		dst.Spec.StarRocksBeSpec.StarRocksComponentSpec, dst.Spec.StarRocksBeSpec.BeEnvVars =
+			convertComponentSpecToV1(&src.Spec.Be.ComponentSpec)
+	}
+	if src.Spec.Cn != nil {
+		dst.Spec.StarRocksCnSpec = convertCnSpecToV1(src.Spec.Cn)
+	}
+	if feProxySpec := src.Spec.FeProxy; feProxySpec != nil {
+		dst.Spec.StarRocksFeProxySpec = &v1.StarRocksFeProxySpec{
+			StarRocksLoadSpec: convertLoadSpecToV1(&feProxySpec.LoadSpec),
+			Resolver:          feProxySpec.Resolver,
+		}
+		dst.Spec.StarRocksFeProxySpec.ConfigMapInfo = convertConfigFileToV1(feProxySpec.ConfigFile)
+		dst.Spec.StarRocksFeProxySpec.StartupProbeFailureSeconds = feProxySpec.StartupProbeFailureSeconds
+		dst.Spec.StarRocksFeProxySpec.Lifecycle = feProxySpec.Lifecycle
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts the hub version v1 to StarRocksCluster.
+func (dst *StarRocksCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1.StarRocksCluster)
+	if !ok {
+		return fmt.Errorf("expected a v1 StarRocksCluster but got a %T", srcRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = StarRocksClusterSpec{
+		DisasterRecovery:      src.Spec.DisasterRecovery,
+		WaitForFullRollout:    src.Spec.WaitForFullRollout,
+		DeletionPolicy:        src.Spec.DeletionPolicy,
+		Suspend:               src.Spec.Suspend,
+		RootPasswordSecretRef: src.Spec.RootPasswordSecretRef,
+	}
+	if feSpec := src.Spec.StarRocksFeSpec; feSpec != nil {
+		dst.Spec.Fe = &FeSpec{
+			ComponentSpec:       convertComponentSpecFromV1(&feSpec.StarRocksComponentSpec, feSpec.FeEnvVars),
+			ObserverGroups:      copyObserverGroups(feSpec.ObserverGroups),
+			RollingUpdatePolicy: feSpec.RollingUpdatePolicy.DeepCopy(),
+			EnableLeaderService: feSpec.EnableLeaderService,
+		}
+	}
+	if beSpec := src.Spec.StarRocksBeSpec; beSpec != nil {
+		dst.Spec.Be = &BeSpec{ComponentSpec: convertComponentSpecFromV1(&beSpec.StarRocksComponentSpec, beSpec.BeEnvVars)}
+	}
+	if src.Spec.StarRocksCnSpec != nil {
+		dst.Spec.Cn = convertCnSpecFromV1(src.Spec.StarRocksCnSpec)
+	}
+	if feProxySpec := src.Spec.StarRocksFeProxySpec; feProxySpec != nil {
+		dst.Spec.FeProxy = &FeProxySpec{
+			LoadSpec:                   convertLoadSpecFromV1(&feProxySpec.StarRocksLoadSpec),
+			ConfigFile:                 convertConfigFileFromV1(feProxySpec.ConfigMapInfo),
+			StartupProbeFailureSeconds: feProxySpec.StartupProbeFailureSeconds,
+			Lifecycle:                  feProxySpec.Lifecycle,
+			Resolver:                   feProxySpec.Resolver,
+		}
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertTo converts StarRocksWarehouse to the hub version v1.
+func (src *StarRocksWarehouse) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1.StarRocksWarehouse)
+	if !ok {
+		return fmt.Errorf("expected a v1 StarRocksWarehouse but got a %T", dstRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1.StarRocksWarehouseSpec{StarRocksCluster: src.Spec.StarRocksCluster}
+	if src.Spec.Template != nil {
+		cnSpec := convertCnSpecToV1(src.Spec.Template)
+		dst.Spec.Template = &v1.WarehouseComponentSpec{
+			StarRocksComponentSpec: cnSpec.StarRocksComponentSpec,
+			EnvVars:                cnSpec.CnEnvVars,
+			AutoScalingPolicy:      cnSpec.AutoScalingPolicy,
+		}
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+// ConvertFrom converts the hub version v1 to StarRocksWarehouse.
+func (dst *StarRocksWarehouse) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1.StarRocksWarehouse)
+	if !ok {
+		return fmt.Errorf("expected a v1 StarRocksWarehouse but got a %T", srcRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = StarRocksWarehouseSpec{StarRocksCluster: src.Spec.StarRocksCluster}
+	if src.Spec.Template != nil {
+		dst.Spec.Template = convertCnSpecFromV1(src.Spec.Template.ToCnSpec())
+	}
+	dst.Status = src.Status
+	return nil
+}
+
+func convertCnSpecToV1(src *CnSpec) *v1.StarRocksCnSpec {
+	dst := &v1.StarRocksCnSpec{AutoScalingPolicy: src.AutoScalingPolicy}
+	dst.StarRocksComponentSpec, dst.CnEnvVars = convertComponentSpecToV1(&src.ComponentSpec)
+	return dst
+}
+
+func convertCnSpecFromV1(src *v1.StarRocksCnSpec) *CnSpec {
+	return &CnSpec{
+		ComponentSpec:     convertComponentSpecFromV1(&src.StarRocksComponentSpec, src.CnEnvVars),
+		AutoScalingPolicy: src.AutoScalingPolicy,
+	}
+}
+
+// convertComponentSpecToV1 converts ComponentSpec to v1, and returns the environment variables separately because
+// they are defined in the component-prefixed fields of v1, e.g. feEnvVars.
+func convertComponentSpecToV1(src *ComponentSpec) (v1.StarRocksComponentSpec, []corev1.EnvVar) {
+	dst := v1.StarRocksComponentSpec{
+		StarRocksLoadSpec:                    convertLoadSpecToV1(&src.LoadSpec),
+		RunAsNonRoot:                         src.RunAsNonRoot,
+		Capabilities:                         src.Capabilities,
+		Config:                               src.Config,
+		ConfigMaps:                           src.ConfigMaps,
+		Secrets:                              src.Secrets,
+		HostAliases:                          src.HostAliases,
+		TerminationGracePeriodSeconds:        src.TerminationGracePeriodSeconds,
+		Sidecars:                             src.Sidecars,
+		InitContainers:                       src.InitContainers,
+		Command:                              src.Command,
+		Args:                                 src.Args,
+		UpdateStrategy:                       src.UpdateStrategy,
+		ReadOnlyRootFilesystem:               src.ReadOnlyRootFilesystem,
+		Sysctls:                              src.Sysctls,
+		PersistentVolumeClaimRetentionPolicy: src.PersistentVolumeClaimRetentionPolicy,
+		MinReadySeconds:                      src.MinReadySeconds,
+		PodManagementPolicy:                  src.PodManagementPolicy,
+	}
+	dst.ConfigMapInfo = convertConfigFileToV1(src.ConfigFile)
+	dst.StartupProbeFailureSeconds = src.StartupProbeFailureSeconds
+	dst.Lifecycle = src.Lifecycle
+	return dst, src.EnvVars
+}
+
+func convertComponentSpecFromV1(src *v1.StarRocksComponentSpec, envVars []corev1.EnvVar) ComponentSpec {
+	dst := ComponentSpec{
+		LoadSpec:                             convertLoadSpecFromV1(&src.StarRocksLoadSpec),
+		EnvVars:                              envVars,
+		StartupProbeFailureSeconds:           src.StartupProbeFailureSeconds,
+		Lifecycle:                            src.Lifecycle,
+		RunAsNonRoot:                         src.RunAsNonRoot,
+		Capabilities:                         src.Capabilities,
+		Config:                               src.Config,
+		ConfigMaps:                           src.ConfigMaps,
+		Secrets:                              src.Secrets,
+		HostAliases:                          src.HostAliases,
+		TerminationGracePeriodSeconds:        src.TerminationGracePeriodSeconds,
+		Sidecars:                             src.Sidecars,
+		InitContainers:                       src.InitContainers,
+		Command:                              src.Command,
+		Args:                                 src.Args,
+		UpdateStrategy:                       src.UpdateStrategy,
+		ReadOnlyRootFilesystem:               src.ReadOnlyRootFilesystem,
+		Sysctls:                              src.Sysctls,
+		PersistentVolumeClaimRetentionPolicy: src.PersistentVolumeClaimRetentionPolicy,
+		MinReadySeconds:                      src.MinReadySeconds,
+		PodManagementPolicy:                  src.PodManagementPolicy,
+		ConfigFile:                           convertConfigFileFromV1(src.ConfigMapInfo),
+	}
+	return dst
+}
+
+func convertConfigFileToV1(src *ConfigFile) v1.ConfigMapInfo {
+	if src == nil {
+		return v1.ConfigMapInfo{}
+	}
+	return v1.ConfigMapInfo{ConfigMapName: src.ConfigMapName, ResolveKey: src.Key, DisableRollout: src.DisableRollout}
+}
+
+func convertConfigFileFromV1(info v1.ConfigMapInfo) *ConfigFile {
+	if info.ConfigMapName == "" && info.ResolveKey == "" {
+		return nil
+	}
+	return &ConfigFile{ConfigMapName: info.ConfigMapName, Key: info.ResolveKey, DisableRollout: info.DisableRollout}
+}
+
+func convertLoadSpecToV1(src *LoadSpec) v1.StarRocksLoadSpec {
+	return v1.StarRocksLoadSpec{
+		ResourceRequirements:         src.ResourceRequirements,
+		Annotations:                  src.Annotations,
+		PodLabels:                    src.PodLabels,
+		Replicas:                     src.Replicas,
+		Image:                        src.Image,
+		ImagePullPolicy:              src.ImagePullPolicy,
+		ImagePullSecrets:             src.ImagePullSecrets,
+		SchedulerName:                src.SchedulerName,
+		NodeSelector:                 src.NodeSelector,
+		Affinity:                     src.Affinity,
+		Tolerations:                  src.Tolerations,
+		TopologySpreadConstraints:    src.TopologySpreadConstraints,
+		Service:                      src.Service,
+		StorageVolumes:               src.StorageVolumes,
+		ServiceAccount:               src.ServiceAccount,
+		LivenessProbeFailureSeconds:  src.LivenessProbeFailureSeconds,
+		ReadinessProbeFailureSeconds: src.ReadinessProbeFailureSeconds,
+		ShareProcessNamespace:        src.ShareProcessNamespace,
+	}
+}
+
+func convertLoadSpecFromV1(src *v1.StarRocksLoadSpec) LoadSpec {
+	return LoadSpec{
+		ResourceRequirements:         src.ResourceRequirements,
+		Annotations:                  src.Annotations,
+		PodLabels:                    src.PodLabels,
+		Replicas:                     src.Replicas,
+		Image:                        src.Image,
+		ImagePullPolicy:              src.ImagePullPolicy,
+		ImagePullSecrets:             src.ImagePullSecrets,
+		SchedulerName:                src.SchedulerName,
+		NodeSelector:                 src.NodeSelector,
+		Affinity:                     src.Affinity,
+		Tolerations:                  src.Tolerations,
+		TopologySpreadConstraints:    src.TopologySpreadConstraints,
+		Service:                      src.Service,
+		StorageVolumes:               src.StorageVolumes,
+		ServiceAccount:               src.ServiceAccount,
+		LivenessProbeFailureSeconds:  src.LivenessProbeFailureSeconds,
+		ReadinessProbeFailureSeconds: src.ReadinessProbeFailureSeconds,
+		ShareProcessNamespace:        src.ShareProcessNamespace,
+	}
+}
+
+func copyObserverGroups(groups []v1.StarRocksFeObserverGroup) []v1.StarRocksFeObserverGroup {
+	if groups == nil {
+		return nil
+	}
+	result := make([]v1.StarRocksFeObserverGroup, len(groups))
+	for i := range groups {
+		groups[i].DeepCopyInto(&result[i])
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+func newV1ComponentSpec(image string) v1.StarRocksComponentSpec {
+	replicas := int32(3)
+	gracePeriod := int64(30)
+	return v1.StarRocksComponentSpec{
+		StarRocksLoadSpec: v1.StarRocksLoadSpec{
+			Replicas:       &replicas,
+			Image:          image,
+			StorageVolumes: []v1.StorageVolume{{Name: "meta", MountPath: "/opt/starrocks/meta"}},
+			ConfigMapInfo:  v1.ConfigMapInfo{ConfigMapName: "config", ResolveKey: "starrocks.conf", DisableRollout: true},
+			Service:        &v1.StarRocksService{Type: corev1.ServiceTypeNodePort},
+		},
+		Config:                        map[string]string{"sys_log_level": "WARN"},
+		ConfigMaps:                    []v1.ConfigMapReference{{Name: "extra", MountPath: "/etc/extra"}},
+		TerminationGracePeriodSeconds: &gracePeriod,
+		UpdateStrategy:                &appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+	}
+}
+
+func TestStarRocksCluster_Conversion(t *testing.T) {
+	envVars := []corev1.EnvVar{{Name: "TZ", Value: "UTC"}}
+	minReplicas := int32(1)
+	startupProbeFailureSeconds := int32(60)
+	hub := &v1.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default", Generation: 2},
+		Spec: v1.StarRocksClusterSpec{
+			StarRocksFeSpec: &v1.StarRocksFeSpec{
+				StarRocksComponentSpec: newV1ComponentSpec("fe:3.3"),
+				FeEnvVars:              envVars,
+				ObserverGroups:         []v1.StarRocksFeObserverGroup{{Name: "read", Replicas: &minReplicas}},
+				RollingUpdatePolicy:    &v1.StarRocksFeRollingUpdatePolicy{DisableLeaderAware: true},
+				EnableLeaderService:    true,
+			},
+			StarRocksBeSpec: &v1.StarRocksBeSpec{StarRocksComponentSpec: newV1ComponentSpec("be:3.3"), BeEnvVars: envVars},
+			StarRocksCnSpec: &v1.StarRocksCnSpec{
+				StarRocksComponentSpec: newV1ComponentSpec("cn:3.3"),
+				CnEnvVars:              envVars,
+				AutoScalingPolicy:      &v1.AutoScalingPolicy{MinReplicas: &minReplicas, MaxReplicas: 3},
+			},
+			StarRocksFeProxySpec: &v1.StarRocksFeProxySpec{
+				StarRocksLoadSpec: v1.StarRocksLoadSpec{
+					Image:                      "nginx:1.24.0",
+					ConfigMapInfo:              v1.ConfigMapInfo{ConfigMapName: "nginx", ResolveKey: "nginx.conf"},
+					StartupProbeFailureSeconds: &startupProbeFailureSeconds,
+					Lifecycle: &corev1.Lifecycle{
+						PreStop: &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"sleep", "5"}}},
+					},
+				},
+				Resolver: "kube-dns.kube-system.svc.cluster.local",
+			},
+			DisasterRecovery:   &v1.DisasterRecovery{Enabled: true, Generation: 1},
+			WaitForFullRollout: true,
+			DeletionPolicy:     &v1.DeletionPolicy{PersistentVolumeClaims: v1.DeletePersistentVolumeClaims},
+			Suspend:            true,
+			RootPasswordSecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "rootcredential"},
+				Key:                  "password",
+			},
+		},
+		Status: v1.StarRocksClusterStatus{Phase: v1.ClusterRunning, ObservedGeneration: 2},
+	}
+
+	spoke := &StarRocksCluster{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+	require.Equal(t, "fe:3.3", spoke.Spec.Fe.Image)
+	require.Equal(t, envVars, spoke.Spec.Be.EnvVars)
+	require.Equal(t, "read", spoke.Spec.Fe.ObserverGroups[0].Name)
+	require.True(t, spoke.Spec.Fe.RollingUpdatePolicy.DisableLeaderAware)
+	require.True(t, spoke.Spec.Fe.EnableLeaderService)
+	require.Equal(t, &ConfigFile{ConfigMapName: "config", Key: "starrocks.conf", DisableRollout: true}, spoke.Spec.Cn.ConfigFile)
+	require.Equal(t, int32(3), spoke.Spec.Cn.AutoScalingPolicy.MaxReplicas)
+	require.Equal(t, "nginx:1.24.0", spoke.Spec.FeProxy.Image)
+	require.Equal(t, &ConfigFile{ConfigMapName: "nginx", Key: "nginx.conf"}, spoke.Spec.FeProxy.ConfigFile)
+	require.Equal(t, &startupProbeFailureSeconds, spoke.Spec.FeProxy.StartupProbeFailureSeconds)
+	require.NotNil(t, spoke.Spec.FeProxy.Lifecycle.PreStop)
+	require.Equal(t, "rootcredential", spoke.Spec.RootPasswordSecretRef.Name)
+	require.Equal(t, v1.ClusterRunning, spoke.Status.Phase)
+
+	actual := &v1.StarRocksCluster{}
+	require.NoError(t, spoke.ConvertTo(actual))
+	require.Equal(t, hub, actual)
+}
+
+func TestStarRocksCluster_ConvertEmptySpec(t *testing.T) {
+	spoke := &StarRocksCluster{ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks"}}
+	hub := &v1.StarRocksCluster{}
+	require.NoError(t, spoke.ConvertTo(hub))
+	require.Nil(t, hub.Spec.StarRocksFeSpec)
+	require.Nil(t, hub.Spec.StarRocksFeProxySpec)
+
+	actual := &StarRocksCluster{}
+	require.NoError(t, actual.ConvertFrom(hub))
+	require.Equal(t, spoke, actual)
+}
+
+func TestStarRocksWarehouse_Conversion(t *testing.T) {
+	hub := &v1.StarRocksWarehouse{
+		ObjectMeta: metav1.ObjectMeta{Name: "wh1", Namespace: "default"},
+		Spec: v1.StarRocksWarehouseSpec{
+			StarRocksCluster: "kube-starrocks",
+			Template: &v1.WarehouseComponentSpec{
+				StarRocksComponentSpec: newV1ComponentSpec("cn:3.3"),
+				EnvVars:                []corev1.EnvVar{{Name: "TZ", Value: "UTC"}},
+			},
+		},
+	}
+
+	spoke := &StarRocksWarehouse{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+	require.Equal(t, "kube-starrocks", spoke.Spec.StarRocksCluster)
+	require.Equal(t, "TZ", spoke.Spec.Template.EnvVars[0].Name)
+
+	actual := &v1.StarRocksWarehouse{}
+	require.NoError(t, spoke.ConvertTo(actual))
+	require.Equal(t, hub, actual)
+}
@@ -0,0 +1,27 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v2 stores the v2 version of the API. Compared with v1, v2 cleans up the schema:
+//  1. The component specs are named fe, be, cn and feProxy.
+//  2. The environment variables of all components are specified by envVars.
+//  3. The config file of a component is specified by configFile instead of configMapInfo.
+//  4. The FE Proxy spec only contains the fields which are supported by FE Proxy.
+//
+// v1 is the storage version, and the objects of v2 are converted to and from v1 by the conversion webhook.
+// +kubebuilder:object:generate=true
+// +k8s:deepcopy-gen=package
+// +groupName=starrocks.com
+package v2
@@ -0,0 +1,153 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+// StarRocksClusterSpec defines the desired state of StarRocksCluster
+type StarRocksClusterSpec struct {
+	// Fe defines the specification of FE.
+	// +optional
+	Fe *FeSpec `json:"fe,omitempty"`
+
+	// Be defines the specification of BE.
+	// +optional
+	Be *BeSpec `json:"be,omitempty"`
+
+	// Cn defines the specification of CN.
+	// +optional
+	Cn *CnSpec `json:"cn,omitempty"`
+
+	// FeProxy defines the specification of FE Proxy.
+	// +optional
+	FeProxy *FeProxySpec `json:"feProxy,omitempty"`
+
+	// DisasterRecovery is used to determine whether to enter disaster recovery mode.
+	// +optional
+	DisasterRecovery *v1.DisasterRecovery `json:"disasterRecovery,omitempty"`
+
+	// WaitForFullRollout controls rolling upgrade behavior. When set to true, the operator will wait for FE
+	// StatefulSet to be fully rolled out before updating BE/CN components.
+	// +optional
+	WaitForFullRollout bool `json:"waitForFullRollout,omitempty"`
+
+	// DeletionPolicy defines what the operator should do before the StarRocksCluster is deleted.
+	// +optional
+	DeletionPolicy *v1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Suspend is used to hibernate the StarRocksCluster, all the components are scaled to zero in order.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// RootPasswordSecretRef refers to the key of a secret which contains the password of the root user. The operator
+	// sets the password of root to it, and uses it to execute SQL statements.
+	// +optional
+	RootPasswordSecretRef *corev1.SecretKeySelector `json:"rootPasswordSecretRef,omitempty"`
+}
+
+// FeSpec defines the desired state of fe.
+type FeSpec struct {
+	ComponentSpec `json:",inline"`
+
+	// ObserverGroups defines the groups of FE observers, every group is deployed as a separate statefulset.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ObserverGroups []v1.StarRocksFeObserverGroup `json:"observerGroups,omitempty"`
+
+	// RollingUpdatePolicy controls the order in which the FE pods are updated, the leader is updated last by default.
+	// +optional
+	RollingUpdatePolicy *v1.StarRocksFeRollingUpdatePolicy `json:"rollingUpdatePolicy,omitempty"`
+
+	// EnableLeaderService makes the operator create a service named <cluster>-fe-leader, which only selects the leader.
+	// +optional
+	EnableLeaderService bool `json:"enableLeaderService,omitempty"`
+}
+
+// BeSpec defines the desired state of be.
+type BeSpec struct {
+	ComponentSpec `json:",inline"`
+}
+
+// CnSpec defines the desired state of cn.
+type CnSpec struct {
+	ComponentSpec `json:",inline"`
+
+	// AutoScalingPolicy auto scaling strategy
+	// +optional
+	AutoScalingPolicy *v1.AutoScalingPolicy `json:"autoScalingPolicy,omitempty"`
+}
+
+// FeProxySpec defines the specification for FE Proxy.
+type FeProxySpec struct {
+	LoadSpec `json:",inline"`
+
+	// ConfigFile is the reference to the configMap which stores the nginx config file of FE Proxy.
+	// +optional
+	ConfigFile *ConfigFile `json:"configFile,omitempty"`
+
+	// StartupProbeFailureSeconds defines the total failure seconds of startup Probe.
+	// Note: you can set it to 0 to disable the startup probe.
+	// +optional
+	StartupProbeFailureSeconds *int32 `json:"startupProbeFailureSeconds,omitempty"`
+
+	// Lifecycle describes actions that the management system should take in response to container lifecycle events.
+	// +optional
+	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
+
+	// Resolver is the dns server used by nginx to resolve the FE service.
+	// +optional
+	Resolver string `json:"resolver,omitempty"`
+}
+
+// StarRocksCluster defines a starrocks cluster deployment.
+// +kubebuilder:object:root=true
+// +kubebuilder:metadata:annotations="version=v1.11.5"
+// +kubebuilder:resource:shortName=src
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.cn.replicas,statuspath=.status.starRocksCnStatus.replicas,selectorpath=.status.starRocksCnStatus.selector
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="FeStatus",type=string,JSONPath=`.status.starRocksFeStatus.phase`
+// +kubebuilder:printcolumn:name="BeStatus",type=string,JSONPath=`.status.starRocksBeStatus.phase`
+// +kubebuilder:printcolumn:name="CnStatus",type=string,JSONPath=`.status.starRocksCnStatus.phase`
+// +kubebuilder:printcolumn:name="FeProxyStatus",type=string,JSONPath=`.status.starRocksFeProxyStatus.phase`
+// +kubebuilder:unservedversion
+//
+//nolint:lll
+type StarRocksCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired state of the starrocks cluster.
+	Spec StarRocksClusterSpec `json:"spec,omitempty"`
+
+	// Most recent observed status of the starrocks cluster, it is the same as v1.
+	Status v1.StarRocksClusterStatus `json:"status,omitempty"`
+}
+
+// StarRocksClusterList contains a list of StarRocksCluster
+// +kubebuilder:object:root=true
+type StarRocksClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StarRocksCluster `json:"items"`
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+// StarRocksWarehouseSpec defines the desired state of StarRocksWarehouse
+type StarRocksWarehouseSpec struct {
+	// StarRocksCluster is the name of a StarRocksCluster which the warehouse belongs to.
+	StarRocksCluster string `json:"starRocksCluster"`
+
+	// Template define component configuration, a warehouse is a group of CN nodes.
+	Template *CnSpec `json:"template"`
+}
+
+// StarRocksWarehouse defines a starrocks warehouse.
+// +kubebuilder:object:root=true
+// +kubebuilder:metadata:annotations="version=v1.11.5"
+// +kubebuilder:resource:shortName=warehouses
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.template.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="status",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="reason",type=string,JSONPath=`.status.reason`
+// +kubebuilder:unservedversion
+type StarRocksWarehouse struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of desired state of a starrocks warehouse.
+	Spec StarRocksWarehouseSpec `json:"spec,omitempty"`
+
+	// Status represents the recent observed status of the starrocks warehouse, it is the same as v1.
+	Status v1.StarRocksWarehouseStatus `json:"status,omitempty"`
+}
+
+// StarRocksWarehouseList contains a list of StarRocksWarehouse
+// +kubebuilder:object:root=true
+type StarRocksWarehouseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StarRocksWarehouse `json:"items"`
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/cron"
+)
+
+func TestParseInvalid(t *testing.T) {
+	for _, expression := range []string{
+		"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"5-1 * * * *", "*/0 * * * *", "1-2-3 * * * *", "a * * * *", "@every 1h",
+	} {
+		_, err := cron.Parse(expression)
+		require.Error(t, err, expression)
+	}
+}
+
+func TestNext(t *testing.T) {
+	// 2024-01-01 is Monday.
+	from := time.Date(2024, 1, 1, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{expression: "* * * * *", want: time.Date(2024, 1, 1, 10, 31, 0, 0, time.UTC)},
+		{expression: "@hourly", want: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)},
+		{expression: "@daily", want: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{expression: "*/20 * * * *", want: time.Date(2024, 1, 1, 10, 40, 0, 0, time.UTC)},
+		{expression: "0 2 * * *", want: time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)},
+		{expression: "0 9-17/4 * * *", want: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)},
+		{expression: "30 1 * * SAT,SUN", want: time.Date(2024, 1, 6, 1, 30, 0, 0, time.UTC)},
+		{expression: "0 0 * * 7", want: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 1 MAR *", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 29 2 *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// either day of month or day of week matches.
+		{expression: "0 0 15 * FRI", want: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 30 2 *", want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			schedule, err := cron.Parse(tt.expression)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, schedule.Next(from))
+		})
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	location := time.FixedZone("UTC+8", 8*60*60)
+	schedule, err := cron.Parse("0 2 * * *")
+	require.NoError(t, err)
+	next := schedule.Next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).In(location))
+	require.Equal(t, time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC), next.UTC())
+}
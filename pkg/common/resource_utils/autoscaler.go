@@ -59,6 +59,10 @@ type HPAParams struct {
 
 	// ScalerPolicy defines the scaling policy for the HorizontalPodAutoscaler.
 	ScalerPolicy *srapi.AutoScalingPolicy
+
+	// MetricLabels are the labels to select the metrics of StarRocks in ScalerPolicy.StarRocksMetrics from the external
+	// metrics API. Now there are two labels: cluster: <cluster-name> and warehouse: <warehouse-name-in-fe>.
+	MetricLabels map[string]string
 }
 
 func BuildHPA(hpaParams *HPAParams, autoScalerVersion srapi.AutoScalerVersion) client.Object {
@@ -118,12 +122,10 @@ func BuildHPA(hpaParams *HPAParams, autoScalerVersion srapi.AutoScalerVersion) c
 			},
 		}
 		// the codes use unsafe.Pointer to convert struct, when audit please notice the correctness about memory assign.
+		if metrics := buildMetrics(hpaParams); len(metrics) != 0 {
+			hpa.Spec.Metrics = unsafe.Slice((*v2.MetricSpec)(unsafe.Pointer(&metrics[0])), len(metrics))
+		}
 		if hpaParams.ScalerPolicy != nil && hpaParams.ScalerPolicy.HPAPolicy != nil {
-			if len(hpaParams.ScalerPolicy.HPAPolicy.Metrics) != 0 {
-				metrics := unsafe.Slice((*v2.MetricSpec)(unsafe.Pointer(&hpaParams.ScalerPolicy.HPAPolicy.Metrics[0])),
-					len(hpaParams.ScalerPolicy.HPAPolicy.Metrics))
-				hpa.Spec.Metrics = metrics
-			}
 			hpa.Spec.Behavior = (*v2.HorizontalPodAutoscalerBehavior)(unsafe.Pointer(hpaParams.ScalerPolicy.HPAPolicy.Behavior))
 		}
 		return hpa
@@ -138,10 +140,42 @@ func BuildHPA(hpaParams *HPAParams, autoScalerVersion srapi.AutoScalerVersion) c
 				MinReplicas:    hpaParams.ScalerPolicy.MinReplicas,
 			},
 		}
+		hpa.Spec.Metrics = buildMetrics(hpaParams)
 		if hpaParams.ScalerPolicy != nil && hpaParams.ScalerPolicy.HPAPolicy != nil {
-			hpa.Spec.Metrics = hpaParams.ScalerPolicy.HPAPolicy.Metrics
 			hpa.Spec.Behavior = hpaParams.ScalerPolicy.HPAPolicy.Behavior
 		}
 		return hpa
 	}
 }
+
+// buildMetrics returns the metrics of hpaPolicy and the external metrics of StarRocks in autoscaling v2beta2.
+func buildMetrics(hpaParams *HPAParams) []v2beta2.MetricSpec {
+	policy := hpaParams.ScalerPolicy
+	if policy == nil {
+		return nil
+	}
+
+	var metrics []v2beta2.MetricSpec
+	if policy.HPAPolicy != nil {
+		metrics = append(metrics, policy.HPAPolicy.Metrics...)
+	}
+	for i := range policy.StarRocksMetrics {
+		metric := &policy.StarRocksMetrics[i]
+		value := metric.Target.DeepCopy()
+		target := v2beta2.MetricTarget{Type: v2beta2.ValueMetricType, Value: &value}
+		if metric.Name.IsAverageValue() {
+			target = v2beta2.MetricTarget{Type: v2beta2.AverageValueMetricType, AverageValue: &value}
+		}
+		metrics = append(metrics, v2beta2.MetricSpec{
+			Type: v2beta2.ExternalMetricSourceType,
+			External: &v2beta2.ExternalMetricSource{
+				Metric: v2beta2.MetricIdentifier{
+					Name:     metric.Name.ExternalMetricName(),
+					Selector: &metav1.LabelSelector{MatchLabels: hpaParams.MetricLabels},
+				},
+				Target: target,
+			},
+		})
+	}
+	return metrics
+}
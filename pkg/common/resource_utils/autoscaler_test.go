@@ -684,3 +684,64 @@ func TestBuildHorizontalPodAutoscalerV2_ForWarehouse(t *testing.T) {
 
 	require.Equal(t, expectedHPA, BuildHPA(hpaParams, srapi.AutoScalerV2))
 }
+
+func TestBuildHorizontalPodAutoscaler_StarRocksMetrics(t *testing.T) {
+	cluster := srapi.StarRocksCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       StarRocksClusterKind,
+			APIVersion: srapi.SchemeBuilder.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      _defaultName,
+			Namespace: _defaultNamespace,
+		},
+	}
+	metricLabels := map[string]string{
+		srapi.StarRocksMetricClusterLabel:   _defaultName,
+		srapi.StarRocksMetricWarehouseLabel: "default_warehouse",
+	}
+	cpuMetric := v2beta2.MetricSpec{
+		Type: v2beta2.ResourceMetricSourceType,
+		Resource: &v2beta2.ResourceMetricSource{
+			Name: "cpu",
+			Target: v2beta2.MetricTarget{
+				Type:               v2beta2.UtilizationMetricType,
+				AverageUtilization: GetInt32Pointer(60),
+			},
+		},
+	}
+	policy := &srapi.AutoScalingPolicy{
+		MaxReplicas: 10,
+		HPAPolicy:   &srapi.HPAPolicy{Metrics: []v2beta2.MetricSpec{cpuMetric}},
+		StarRocksMetrics: []srapi.StarRocksMetric{
+			{Name: srapi.PendingQueries, Target: resource.MustParse("2")},
+			{Name: srapi.QueueWaitSeconds, Target: resource.MustParse("30")},
+		},
+	}
+	hpaParams := &HPAParams{
+		Namespace:       _defaultNamespace,
+		Name:            "test-autoscaler",
+		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cluster, cluster.GroupVersionKind())},
+		ScalerPolicy:    policy,
+		MetricLabels:    metricLabels,
+	}
+
+	hpa := BuildHPA(hpaParams, srapi.AutoScalerV2).(*v2.HorizontalPodAutoscaler)
+	require.Len(t, hpa.Spec.Metrics, 3)
+	require.Equal(t, v2.ResourceMetricSourceType, hpa.Spec.Metrics[0].Type)
+	pending := hpa.Spec.Metrics[1].External
+	require.Equal(t, "starrocks_pending_queries", pending.Metric.Name)
+	require.Equal(t, metricLabels, pending.Metric.Selector.MatchLabels)
+	require.Equal(t, v2.AverageValueMetricType, pending.Target.Type)
+	require.Equal(t, "2", pending.Target.AverageValue.String())
+	wait := hpa.Spec.Metrics[2].External
+	require.Equal(t, "starrocks_queue_wait_seconds", wait.Metric.Name)
+	require.Equal(t, v2.ValueMetricType, wait.Target.Type)
+	require.Equal(t, "30", wait.Target.Value.String())
+	// the metrics of hpaPolicy are not changed
+	require.Len(t, policy.HPAPolicy.Metrics, 1)
+
+	hpaV2beta2 := BuildHPA(hpaParams, srapi.AutoScalerV2Beta2).(*v2beta2.HorizontalPodAutoscaler)
+	require.Len(t, hpaV2beta2.Spec.Metrics, 3)
+	require.Equal(t, v2beta2.ExternalMetricSourceType, hpaV2beta2.Spec.Metrics[1].Type)
+}
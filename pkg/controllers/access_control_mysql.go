@@ -0,0 +1,238 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+// userIdentity returns the identity of a user, e.g. 'alice'@'%'.
+func userIdentity(name, host string) string {
+	if host == "" {
+		host = "%"
+	}
+	return fmt.Sprintf("%s@%s", quoteString(name), quoteString(host))
+}
+
+// authenticationClause returns the IDENTIFIED clause of CREATE USER and ALTER USER, it is empty if the user has no
+// password.
+func authenticationClause(password string, plugin *srapi.UserAuthPlugin) string {
+	switch {
+	case plugin != nil && plugin.AuthenticationString != "":
+		return fmt.Sprintf(" IDENTIFIED WITH %s AS %s", plugin.Name, quoteString(plugin.AuthenticationString))
+	case plugin != nil:
+		return fmt.Sprintf(" IDENTIFIED WITH %s", plugin.Name)
+	case password != "":
+		return fmt.Sprintf(" IDENTIFIED BY %s", quoteString(password))
+	}
+	return ""
+}
+
+func createUserStatement(identity, authentication string) string {
+	return fmt.Sprintf("CREATE USER IF NOT EXISTS %s%s", identity, authentication)
+}
+
+func alterUserStatement(identity, authentication string) string {
+	return fmt.Sprintf("ALTER USER %s%s", identity, authentication)
+}
+
+func dropUserStatement(identity string) string {
+	return fmt.Sprintf("DROP USER IF EXISTS %s", identity)
+}
+
+func createRoleStatement(name string) string {
+	return fmt.Sprintf("CREATE ROLE IF NOT EXISTS %s", quoteIdentifier(name))
+}
+
+func dropRoleStatement(name string) string {
+	return fmt.Sprintf("DROP ROLE IF EXISTS %s", quoteIdentifier(name))
+}
+
+// userGrantee returns the grantee of GRANT and REVOKE for a user, e.g. USER 'alice'@'%'.
+func userGrantee(identity string) string {
+	return "USER " + identity
+}
+
+// roleGrantee returns the grantee of GRANT and REVOKE for a role, e.g. ROLE `reader`.
+func roleGrantee(name string) string {
+	return "ROLE " + quoteIdentifier(name)
+}
+
+func grantStatement(grant srapi.PrivilegeGrant, grantee string) string {
+	return fmt.Sprintf("GRANT %s ON %s TO %s", joinPrivileges(grant.Privileges, ", "), grant.On, grantee)
+}
+
+func revokeStatement(grant srapi.PrivilegeGrant, grantee string) string {
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s", joinPrivileges(grant.Privileges, ", "), grant.On, grantee)
+}
+
+func grantRoleStatement(role, identity string) string {
+	return fmt.Sprintf("GRANT %s TO USER %s", quoteIdentifier(role), identity)
+}
+
+func revokeRoleStatement(role, identity string) string {
+	return fmt.Sprintf("REVOKE %s FROM USER %s", quoteIdentifier(role), identity)
+}
+
+// The patterns are the same as the validation of the CRDs, they are checked again because the statements are built by
+// formatting strings.
+var (
+	identifierPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+	objectPattern     = regexp.MustCompile(`^[a-zA-Z0-9_.* ]+$`)
+)
+
+// privileges are the privileges which can be granted in StarRocks.
+var privileges = []string{
+	"ALL", "ALL PRIVILEGES", "ALTER", "APPLY", "CREATE DATABASE", "CREATE EXTERNAL CATALOG", "CREATE FUNCTION",
+	"CREATE GLOBAL FUNCTION", "CREATE MATERIALIZED VIEW", "CREATE PIPE", "CREATE RESOURCE", "CREATE RESOURCE GROUP",
+	"CREATE STORAGE VOLUME", "CREATE TABLE", "CREATE VIEW", "CREATE WAREHOUSE", "DELETE", "DROP", "EXPORT", "GRANT",
+	"IMPERSONATE", "INSERT", "NODE", "OPERATE", "REFRESH", "REPOSITORY", "SECURITY", "SELECT", "UPDATE", "USAGE",
+}
+
+func checkIdentifier(kind, name string) error {
+	if !identifierPattern.MatchString(name) {
+		return fmt.Errorf("invalid %s %q", kind, name)
+	}
+	return nil
+}
+
+// checkGrant checks the privileges are known and the object does not contain any character which can break the
+// statement.
+func checkGrant(grant srapi.PrivilegeGrant) error {
+	grant = normalizeGrant(grant)
+	for _, privilege := range grant.Privileges {
+		if !containsString(privileges, strings.Join(strings.Fields(string(privilege)), " ")) {
+			return fmt.Errorf("unknown privilege %q", privilege)
+		}
+	}
+	if !objectPattern.MatchString(grant.On) {
+		return fmt.Errorf("invalid object %q of privileges", grant.On)
+	}
+	return nil
+}
+
+func checkGrants(grants []srapi.PrivilegeGrant) error {
+	for _, grant := range grants {
+		if err := checkGrant(grant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeGrant returns the grant in upper case with the privileges sorted, so that two grants can be compared.
+func normalizeGrant(grant srapi.PrivilegeGrant) srapi.PrivilegeGrant {
+	privileges := make([]srapi.Privilege, 0, len(grant.Privileges))
+	for _, privilege := range grant.Privileges {
+		privileges = append(privileges, srapi.Privilege(strings.ToUpper(strings.TrimSpace(string(privilege)))))
+	}
+	sort.Slice(privileges, func(i, j int) bool { return privileges[i] < privileges[j] })
+	return srapi.PrivilegeGrant{Privileges: privileges, On: strings.Join(strings.Fields(grant.On), " ")}
+}
+
+func containsGrant(grants []srapi.PrivilegeGrant, grant srapi.PrivilegeGrant) bool {
+	for _, g := range grants {
+		if g.On == grant.On && joinPrivileges(g.Privileges, ",") == joinPrivileges(grant.Privileges, ",") {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPrivileges(privileges []srapi.Privilege, sep string) string {
+	values := make([]string, 0, len(privileges))
+	for _, privilege := range privileges {
+		values = append(values, string(privilege))
+	}
+	return strings.Join(values, sep)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// syncGrants revokes the applied grants which are not expected, and grants the expected ones which have not been
+// applied. It returns the grants which have been applied, even if an error occurs.
+func syncGrants(ctx context.Context, sqlClient *sqlclient.Client, db *sql.DB, applied, expected []srapi.PrivilegeGrant,
+	grantee string) ([]srapi.PrivilegeGrant, error) {
+	normalized := make([]srapi.PrivilegeGrant, 0, len(expected))
+	for _, grant := range expected {
+		if grant = normalizeGrant(grant); !containsGrant(normalized, grant) {
+			normalized = append(normalized, grant)
+		}
+	}
+
+	var result []srapi.PrivilegeGrant
+	for i, grant := range applied {
+		if containsGrant(normalized, grant) {
+			result = append(result, grant)
+			continue
+		}
+		if err := sqlClient.ExecuteContext(ctx, db, revokeStatement(grant, grantee)); err != nil {
+			return append(result, applied[i:]...), err
+		}
+	}
+	for _, grant := range normalized {
+		if containsGrant(result, grant) {
+			continue
+		}
+		if err := sqlClient.ExecuteContext(ctx, db, grantStatement(grant, grantee)); err != nil {
+			return result, err
+		}
+		result = append(result, grant)
+	}
+	return result, nil
+}
+
+// syncUserRoles revokes the granted roles which are not expected, and grants the expected ones. It returns the roles
+// which have been granted, even if an error occurs.
+func syncUserRoles(ctx context.Context, sqlClient *sqlclient.Client, db *sql.DB, applied, expected []string,
+	identity string) ([]string, error) {
+	var result []string
+	for i, role := range applied {
+		if containsString(expected, role) {
+			result = append(result, role)
+			continue
+		}
+		if err := sqlClient.ExecuteContext(ctx, db, revokeRoleStatement(role, identity)); err != nil {
+			return append(result, applied[i:]...), err
+		}
+	}
+	for _, role := range expected {
+		if containsString(result, role) {
+			continue
+		}
+		if err := sqlClient.ExecuteContext(ctx, db, grantRoleStatement(role, identity)); err != nil {
+			return result, err
+		}
+		result = append(result, role)
+	}
+	return result, nil
+}
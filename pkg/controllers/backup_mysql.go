@@ -0,0 +1,239 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+const (
+	backupStateFinished  = "FINISHED"
+	backupStateCancelled = "CANCELLED"
+)
+
+// backupJob represents a row of the result of SHOW BACKUP.
+type backupJob struct {
+	JobID        string
+	SnapshotName string
+	State        string
+	Progress     string
+	TaskErrMsg   string
+	Status       string
+}
+
+// isJobFinished returns true if the job is finished or cancelled. The state of restore jobs is the same.
+func isJobFinished(state string) bool {
+	return state == backupStateFinished || state == backupStateCancelled
+}
+
+// queryLatestBackup executes SHOW BACKUP, which returns the latest backup job of the database.
+func queryLatestBackup(ctx context.Context, sqlClient *sqlclient.Client, db *sql.DB, database string) (*backupJob, error) {
+	rows, err := sqlClient.QueryContext(ctx, db, "SHOW BACKUP FROM "+quoteIdentifier(database))
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	row := rows[len(rows)-1]
+	return &backupJob{
+		JobID:        row["JobId"],
+		SnapshotName: row["SnapshotName"],
+		State:        row["State"],
+		Progress:     row["Progress"],
+		TaskErrMsg:   row["TaskErrMsg"],
+		Status:       row["Status"],
+	}, nil
+}
+
+// restoreJob represents a row of the result of SHOW RESTORE.
+type restoreJob struct {
+	JobID string
+	// Label is the name of the snapshot.
+	Label string
+	// Timestamp is the backup timestamp of the snapshot.
+	Timestamp  string
+	State      string
+	Progress   string
+	TaskErrMsg string
+	Status     string
+}
+
+// queryLatestRestore executes SHOW RESTORE, which returns the latest restore job of the database.
+func queryLatestRestore(ctx context.Context, sqlClient *sqlclient.Client, db *sql.DB, database string) (*restoreJob, error) {
+	rows, err := sqlClient.QueryContext(ctx, db, "SHOW RESTORE FROM "+quoteIdentifier(database))
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	row := rows[len(rows)-1]
+	return &restoreJob{
+		JobID:      row["JobId"],
+		Label:      row["Label"],
+		Timestamp:  row["Timestamp"],
+		State:      row["State"],
+		Progress:   row["Progress"],
+		TaskErrMsg: row["TaskErrMsg"],
+		Status:     row["Status"],
+	}, nil
+}
+
+// querySnapshotTimestamps executes SHOW SNAPSHOT, and returns the backup timestamps of the snapshot in ascending order.
+// A snapshot may have several timestamps if it is backed up several times with the same name.
+func querySnapshotTimestamps(ctx context.Context, sqlClient *sqlclient.Client, db *sql.DB,
+	repository, snapshotName string) ([]string, error) {
+	rows, err := sqlClient.QueryContext(ctx, db,
+		fmt.Sprintf("SHOW SNAPSHOT ON %s WHERE SNAPSHOT = %s", quoteIdentifier(repository), quoteString(snapshotName)))
+	if err != nil {
+		return nil, err
+	}
+	var timestamps []string
+	for _, row := range rows {
+		if row["Snapshot"] != snapshotName || row["Status"] != "OK" {
+			continue
+		}
+		// the timestamps are separated by newline if the result is not expanded.
+		for _, timestamp := range strings.Split(row["Timestamp"], "\n") {
+			if timestamp = strings.TrimSpace(timestamp); timestamp != "" {
+				timestamps = append(timestamps, timestamp)
+			}
+		}
+	}
+	sort.Strings(timestamps)
+	return timestamps, nil
+}
+
+// repositoryExists executes SHOW REPOSITORIES, and returns true if the repository exists.
+func repositoryExists(ctx context.Context, sqlClient *sqlclient.Client, db *sql.DB, name string) (bool, error) {
+	rows, err := sqlClient.QueryContext(ctx, db, "SHOW REPOSITORIES")
+	if err != nil {
+		return false, err
+	}
+	for _, row := range rows {
+		if row["RepoName"] == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// createRepositoryStatement returns the CREATE REPOSITORY statement. credentials contains the keys of the
+// credentials secret, e.g. access_key and secret_key.
+func createRepositoryStatement(repository *srapi.BackupRepository, credentials map[string]string) string {
+	properties := map[string]string{}
+	switch repository.Type {
+	case srapi.S3Repository, srapi.MinIORepository:
+		setIfNotEmpty(properties, "aws.s3.access_key", credentials["access_key"])
+		setIfNotEmpty(properties, "aws.s3.secret_key", credentials["secret_key"])
+		setIfNotEmpty(properties, "aws.s3.endpoint", repository.Endpoint)
+		setIfNotEmpty(properties, "aws.s3.region", repository.Region)
+		if repository.Type == srapi.MinIORepository {
+			properties["aws.s3.enable_path_style_access"] = "true"
+		}
+	case srapi.HDFSRepository:
+		setIfNotEmpty(properties, "username", credentials["username"])
+		setIfNotEmpty(properties, "password", credentials["password"])
+	}
+	for key, value := range repository.Properties {
+		properties[key] = value
+	}
+
+	statement := fmt.Sprintf("CREATE REPOSITORY %s WITH BROKER ON LOCATION %s",
+		quoteIdentifier(repository.Name), quoteString(repository.Location))
+	return statement + propertiesClause(properties)
+}
+
+// checkSnapshotNames checks the names in BACKUP SNAPSHOT and RESTORE SNAPSHOT by the same pattern as the validation
+// of the CRDs, because the statements are executed as root.
+func checkSnapshotNames(database, snapshotName, repository string, tables []srapi.TableName) error {
+	if err := checkIdentifier("database", database); err != nil {
+		return err
+	}
+	if err := checkIdentifier("snapshot name", snapshotName); err != nil {
+		return err
+	}
+	if err := checkIdentifier("repository", repository); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if err := checkIdentifier("table", string(table)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backupStatement returns the BACKUP SNAPSHOT statement.
+func backupStatement(database, snapshotName, repository string, tables []srapi.TableName) string {
+	return fmt.Sprintf("BACKUP SNAPSHOT %s.%s TO %s",
+		quoteIdentifier(database), quoteIdentifier(snapshotName), quoteIdentifier(repository)) + onClause(tables)
+}
+
+// restoreStatement returns the RESTORE SNAPSHOT statement.
+func restoreStatement(database, snapshotName, repository string, tables []srapi.TableName,
+	backupTimestamp string, replicationNum *int32) string {
+	properties := map[string]string{"backup_timestamp": backupTimestamp}
+	if replicationNum != nil {
+		properties["replication_num"] = fmt.Sprintf("%d", *replicationNum)
+	}
+	return fmt.Sprintf("RESTORE SNAPSHOT %s.%s FROM %s",
+		quoteIdentifier(database), quoteIdentifier(snapshotName), quoteIdentifier(repository)) +
+		onClause(tables) + propertiesClause(properties)
+}
+
+// onClause returns the ON clause of BACKUP SNAPSHOT and RESTORE SNAPSHOT, e.g. ON (`t1`, `t2`).
+func onClause(tables []srapi.TableName) string {
+	if len(tables) == 0 {
+		return ""
+	}
+	quoted := make([]string, 0, len(tables))
+	for _, table := range tables {
+		quoted = append(quoted, quoteIdentifier(string(table)))
+	}
+	return fmt.Sprintf(" ON (%s)", strings.Join(quoted, ", "))
+}
+
+// propertiesClause returns the PROPERTIES clause, the keys are sorted so that the statement is stable.
+func propertiesClause(properties map[string]string) string {
+	if len(properties) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" PROPERTIES (%s)", propertyPairs(properties))
+}
+
+// propertyPairs returns the properties sorted by key, e.g. "k1" = "v1", "k2" = "v2".
+func propertyPairs(properties map[string]string) string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s = %s", quoteString(key), quoteString(properties[key])))
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func setIfNotEmpty(properties map[string]string, key, value string) {
+	if value != "" {
+		properties[key] = value
+	}
+}
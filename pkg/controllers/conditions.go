@@ -0,0 +1,271 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+// componentCondition is used to aggregate the status of components into the conditions of StarRocksCluster.
+type componentCondition struct {
+	name          string
+	conditionType string
+	status        *srapi.StarRocksComponentStatus
+}
+
+// setComponentConditions sets the Ready and Progressing conditions of a component according to its phase.
+func setComponentConditions(status *srapi.StarRocksComponentStatus, generation int64) {
+	if status == nil {
+		return
+	}
+	status.ObservedGeneration = generation
+	meta.SetStatusCondition(&status.Conditions, readyCondition(srapi.ConditionReady, status, generation))
+
+	progressing := metav1.Condition{
+		Type:               srapi.ConditionProgressing,
+		Status:             metav1.ConditionFalse,
+		Reason:             srapi.ReasonAsExpected,
+		ObservedGeneration: generation,
+	}
+	if status.Phase == srapi.ComponentReconciling {
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = srapi.ReasonComponentReconciling
+		progressing.Message = status.Reason
+	}
+	meta.SetStatusCondition(&status.Conditions, progressing)
+}
+
+// readyCondition returns a condition which is true when the phase of component is running.
+func readyCondition(conditionType string, status *srapi.StarRocksComponentStatus, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             srapi.ReasonComponentsRunning,
+		ObservedGeneration: generation,
+	}
+	switch status.Phase {
+	case srapi.ComponentRunning:
+	case srapi.ComponentFailed:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = srapi.ReasonComponentFailed
+		condition.Message = status.Reason
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = srapi.ReasonComponentReconciling
+		condition.Message = status.Reason
+	}
+	return condition
+}
+
+// reconcileClusterConditions sets the conditions of StarRocksCluster according to the status of components and the
+// status of disaster recovery.
+func reconcileClusterConditions(src *srapi.StarRocksCluster) {
+	generation := src.Generation
+	status := &src.Status
+	status.ObservedGeneration = generation
+
+	var components []componentCondition
+	if status.StarRocksFeStatus != nil {
+		components = append(components,
+			componentCondition{"fe", srapi.ConditionFeReady, &status.StarRocksFeStatus.StarRocksComponentStatus})
+	} else {
+		meta.RemoveStatusCondition(&status.Conditions, srapi.ConditionFeReady)
+	}
+	if status.StarRocksBeStatus != nil {
+		components = append(components,
+			componentCondition{"be", srapi.ConditionBeReady, &status.StarRocksBeStatus.StarRocksComponentStatus})
+	} else {
+		meta.RemoveStatusCondition(&status.Conditions, srapi.ConditionBeReady)
+	}
+	if status.StarRocksCnStatus != nil {
+		components = append(components,
+			componentCondition{"cn", srapi.ConditionCnReady, &status.StarRocksCnStatus.StarRocksComponentStatus})
+	} else {
+		meta.RemoveStatusCondition(&status.Conditions, srapi.ConditionCnReady)
+	}
+	if status.StarRocksFeProxyStatus != nil {
+		setComponentConditions(&status.StarRocksFeProxyStatus.StarRocksComponentStatus, generation)
+	}
+	for i := range status.StarRocksFeObserverStatus {
+		setComponentConditions(&status.StarRocksFeObserverStatus[i].StarRocksComponentStatus, generation)
+	}
+
+	var unavailable, reconciling, failed []string
+	for _, component := range components {
+		setComponentConditions(component.status, generation)
+		meta.SetStatusCondition(&status.Conditions, readyCondition(component.conditionType, component.status, generation))
+		if len(component.status.RunningInstances) == 0 {
+			unavailable = append(unavailable, component.name)
+		}
+		switch component.status.Phase {
+		case srapi.ComponentFailed:
+			failed = append(failed, fmt.Sprintf("%s: %s", component.name, component.status.Reason))
+		case srapi.ComponentReconciling:
+			reconciling = append(reconciling, component.name)
+		}
+	}
+
+	available := metav1.Condition{
+		Type:               srapi.ConditionAvailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             srapi.ReasonRunningInstancesAvailable,
+		ObservedGeneration: generation,
+	}
+	if status.StarRocksFeStatus == nil || len(unavailable) != 0 {
+		available.Status = metav1.ConditionFalse
+		available.Reason = srapi.ReasonNoRunningInstances
+		available.Message = fmt.Sprintf("no running instances: %s", strings.Join(unavailable, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, available)
+
+	progressing := metav1.Condition{
+		Type:               srapi.ConditionProgressing,
+		Status:             metav1.ConditionFalse,
+		Reason:             srapi.ReasonAsExpected,
+		ObservedGeneration: generation,
+	}
+	if len(reconciling) != 0 {
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = srapi.ReasonComponentReconciling
+		progressing.Message = fmt.Sprintf("reconciling components: %s", strings.Join(reconciling, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, progressing)
+
+	degraded := metav1.Condition{
+		Type:               srapi.ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             srapi.ReasonAsExpected,
+		ObservedGeneration: generation,
+	}
+	if len(failed) != 0 {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = srapi.ReasonComponentFailed
+		degraded.Message = strings.Join(failed, "; ")
+	}
+	meta.SetStatusCondition(&status.Conditions, degraded)
+
+	meta.SetStatusCondition(&status.Conditions, disasterRecoveryCondition(status.DisasterRecoveryStatus, generation))
+	meta.SetStatusCondition(&status.Conditions, suspendCondition(status.SuspendStatus, generation))
+}
+
+// disasterRecoveryCondition returns a condition which is true when the cluster is in disaster recovery mode.
+func disasterRecoveryCondition(drStatus *srapi.DisasterRecoveryStatus, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               srapi.ConditionDisasterRecoveryInProgress,
+		Status:             metav1.ConditionFalse,
+		Reason:             srapi.ReasonDisasterRecoveryNotRequested,
+		ObservedGeneration: generation,
+	}
+	if drStatus == nil {
+		return condition
+	}
+	condition.Message = drStatus.Reason
+	switch drStatus.Phase {
+	case srapi.DRPhaseTodo:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = srapi.ReasonDisasterRecoveryTodo
+	case srapi.DRPhaseDoing:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = srapi.ReasonDisasterRecoveryDoing
+	case srapi.DRPhaseDone:
+		condition.Reason = srapi.ReasonDisasterRecoveryDone
+	case srapi.DRPhaseFailed:
+		condition.Reason = srapi.ReasonDisasterRecoveryFailed
+	case srapi.DRPhaseAborted:
+		condition.Reason = srapi.ReasonDisasterRecoveryAborted
+	}
+	return condition
+}
+
+// suspendCondition returns a condition which is true when the cluster is suspended, or it is being suspended or
+// resumed.
+func suspendCondition(suspendStatus *srapi.SuspendStatus, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               srapi.ConditionSuspended,
+		Status:             metav1.ConditionFalse,
+		Reason:             srapi.ReasonNotSuspended,
+		ObservedGeneration: generation,
+	}
+	if suspendStatus == nil {
+		return condition
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Message = suspendStatus.Reason
+	switch suspendStatus.Phase {
+	case srapi.SuspendPhaseSuspended:
+		condition.Reason = srapi.ReasonSuspended
+	case srapi.SuspendPhaseResuming:
+		condition.Reason = srapi.ReasonResuming
+	default:
+		condition.Reason = srapi.ReasonSuspending
+	}
+	return condition
+}
+
+// reconcileWarehouseConditions sets the conditions of StarRocksWarehouse according to the status of CN.
+func reconcileWarehouseConditions(warehouse *srapi.StarRocksWarehouse) {
+	status := warehouse.Status.WarehouseComponentStatus
+	if status == nil {
+		return
+	}
+	generation := warehouse.Generation
+	setComponentConditions(&status.StarRocksComponentStatus, generation)
+	meta.SetStatusCondition(&status.Conditions, readyCondition(srapi.ConditionCnReady, &status.StarRocksComponentStatus, generation))
+
+	available := metav1.Condition{
+		Type:               srapi.ConditionAvailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             srapi.ReasonRunningInstancesAvailable,
+		ObservedGeneration: generation,
+	}
+	if len(status.RunningInstances) == 0 {
+		available.Status = metav1.ConditionFalse
+		available.Reason = srapi.ReasonNoRunningInstances
+		available.Message = "no running instances: cn"
+	}
+	meta.SetStatusCondition(&status.Conditions, available)
+
+	degraded := metav1.Condition{
+		Type:               srapi.ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             srapi.ReasonAsExpected,
+		ObservedGeneration: generation,
+	}
+	if status.Phase == srapi.ComponentFailed {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = srapi.ReasonComponentFailed
+		degraded.Message = status.Reason
+	}
+	meta.SetStatusCondition(&status.Conditions, degraded)
+}
+
+// setSyncFailedCondition marks the object as degraded when the operator failed to reconcile the spec.
+func setSyncFailedCondition(conditions *[]metav1.Condition, generation int64, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               srapi.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             srapi.ReasonSyncFailed,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+func TestReconcileClusterConditions(t *testing.T) {
+	tests := []struct {
+		name   string
+		status srapi.StarRocksClusterStatus
+		want   map[string]metav1.ConditionStatus
+	}{
+		{
+			name: "all components are running",
+			status: srapi.StarRocksClusterStatus{
+				StarRocksFeStatus: &srapi.StarRocksFeStatus{StarRocksComponentStatus: srapi.StarRocksComponentStatus{
+					Phase: srapi.ComponentRunning, RunningInstances: []string{"fe-0"},
+				}},
+				StarRocksBeStatus: &srapi.StarRocksBeStatus{StarRocksComponentStatus: srapi.StarRocksComponentStatus{
+					Phase: srapi.ComponentRunning, RunningInstances: []string{"be-0"},
+				}},
+			},
+			want: map[string]metav1.ConditionStatus{
+				srapi.ConditionAvailable:                  metav1.ConditionTrue,
+				srapi.ConditionProgressing:                metav1.ConditionFalse,
+				srapi.ConditionDegraded:                   metav1.ConditionFalse,
+				srapi.ConditionFeReady:                    metav1.ConditionTrue,
+				srapi.ConditionBeReady:                    metav1.ConditionTrue,
+				srapi.ConditionDisasterRecoveryInProgress: metav1.ConditionFalse,
+				srapi.ConditionSuspended:                  metav1.ConditionFalse,
+			},
+		},
+		{
+			name: "fe is rolling and be is failed",
+			status: srapi.StarRocksClusterStatus{
+				StarRocksFeStatus: &srapi.StarRocksFeStatus{StarRocksComponentStatus: srapi.StarRocksComponentStatus{
+					Phase: srapi.ComponentReconciling, RunningInstances: []string{"fe-0"},
+				}},
+				StarRocksBeStatus: &srapi.StarRocksBeStatus{StarRocksComponentStatus: srapi.StarRocksComponentStatus{
+					Phase: srapi.ComponentFailed, FailedInstances: []string{"be-0"},
+				}},
+				StarRocksCnStatus: &srapi.StarRocksCnStatus{StarRocksComponentStatus: srapi.StarRocksComponentStatus{
+					Phase: srapi.ComponentRunning, RunningInstances: []string{"cn-0"},
+				}},
+				DisasterRecoveryStatus: &srapi.DisasterRecoveryStatus{Phase: srapi.DRPhaseDoing},
+				SuspendStatus:          &srapi.SuspendStatus{Phase: srapi.SuspendPhaseResuming},
+			},
+			want: map[string]metav1.ConditionStatus{
+				srapi.ConditionAvailable:                  metav1.ConditionFalse,
+				srapi.ConditionProgressing:                metav1.ConditionTrue,
+				srapi.ConditionDegraded:                   metav1.ConditionTrue,
+				srapi.ConditionFeReady:                    metav1.ConditionFalse,
+				srapi.ConditionBeReady:                    metav1.ConditionFalse,
+				srapi.ConditionCnReady:                    metav1.ConditionTrue,
+				srapi.ConditionDisasterRecoveryInProgress: metav1.ConditionTrue,
+				srapi.ConditionSuspended:                  metav1.ConditionTrue,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &srapi.StarRocksCluster{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status:     tt.status,
+			}
+			reconcileClusterConditions(src)
+			require.Equal(t, int64(2), src.Status.ObservedGeneration)
+			require.Len(t, src.Status.Conditions, len(tt.want))
+			for conditionType, status := range tt.want {
+				condition := meta.FindStatusCondition(src.Status.Conditions, conditionType)
+				require.NotNil(t, condition, conditionType)
+				require.Equal(t, status, condition.Status, conditionType)
+				require.Equal(t, int64(2), condition.ObservedGeneration)
+			}
+			require.Equal(t, int64(2), src.Status.StarRocksFeStatus.ObservedGeneration)
+			require.True(t, meta.IsStatusConditionPresentAndEqual(src.Status.StarRocksFeStatus.Conditions,
+				srapi.ConditionReady, tt.want[srapi.ConditionFeReady]))
+		})
+	}
+}
+
+func TestReconcileWarehouseConditions(t *testing.T) {
+	warehouse := &srapi.StarRocksWarehouse{
+		ObjectMeta: metav1.ObjectMeta{Generation: 3},
+		Status: srapi.StarRocksWarehouseStatus{
+			WarehouseComponentStatus: &srapi.StarRocksCnStatus{StarRocksComponentStatus: srapi.StarRocksComponentStatus{
+				Phase: srapi.ComponentReconciling, CreatingInstances: []string{"cn-0"},
+			}},
+		},
+	}
+	reconcileWarehouseConditions(warehouse)
+	conditions := warehouse.Status.Conditions
+	require.Equal(t, int64(3), warehouse.Status.ObservedGeneration)
+	require.True(t, meta.IsStatusConditionFalse(conditions, srapi.ConditionAvailable))
+	require.True(t, meta.IsStatusConditionTrue(conditions, srapi.ConditionProgressing))
+	require.True(t, meta.IsStatusConditionFalse(conditions, srapi.ConditionDegraded))
+	require.True(t, meta.IsStatusConditionFalse(conditions, srapi.ConditionCnReady))
+	require.True(t, meta.IsStatusConditionFalse(conditions, srapi.ConditionReady))
+
+	setSyncFailedCondition(&warehouse.Status.Conditions, warehouse.Generation, "fe is not ready")
+	require.True(t, meta.IsStatusConditionTrue(warehouse.Status.Conditions, srapi.ConditionDegraded))
+}
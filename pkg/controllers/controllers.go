@@ -6,25 +6,36 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/predicates"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/be"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/cn"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/feobserver"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/feproxy"
 )
 
 func SetupClusterReconciler(mgr ctrl.Manager, denyList string) error {
 	feController := fe.New(mgr.GetClient(), mgr.GetEventRecorderFor)
+	feController.QueryFrontends = queryFrontends(mgr.GetClient())
 	beController := be.New(mgr.GetClient(), mgr.GetEventRecorderFor)
 	cnController := cn.New(mgr.GetClient(), mgr.GetEventRecorderFor)
+	feObserverController := feobserver.New(mgr.GetClient(), mgr.GetEventRecorderFor)
 	feProxyController := feproxy.New(mgr.GetClient(), mgr.GetEventRecorderFor)
 	subcs := []subcontrollers.ClusterSubController{
-		feController, beController, cnController, feProxyController,
+		feController, feObserverController, beController, cnController, feProxyController,
 	}
 
 	reconciler := &StarRocksClusterReconciler{
@@ -40,17 +51,49 @@ func SetupClusterReconciler(mgr ctrl.Manager, denyList string) error {
 	return nil
 }
 
+// queryFrontends returns a fe.FrontendsQuerier which executes SHOW FRONTENDS by the FE service of the cluster.
+func queryFrontends(k8sClient client.Client) fe.FrontendsQuerier {
+	return func(ctx context.Context, src *srapi.StarRocksCluster) ([]sqlclient.Frontend, error) {
+		sqlClient, err := fe.NewSQLClient(ctx, k8sClient, src)
+		if err != nil {
+			return nil, err
+		}
+		return sqlClient.ShowFrontends(ctx, nil)
+	}
+}
+
+// autoScalerType returns the type of HPA owned by the reconcilers. autoscaling/v2 does not exist if the kubernetes
+// version is lower than 1.23, and autoscaling/v2beta2 is removed since 1.26, so the type is chosen by the kubernetes
+// version like the default version of spec.autoScalingPolicy. If the kubernetes version is unknown, the version served
+// by the API server is chosen by the mapper. All the versions are the same resource in kubernetes, so the HPAs created
+// in other versions are watched too.
+func autoScalerType(mapper meta.RESTMapper) client.Object {
+	if k8sutils.KUBE_MAJOR_VERSION == "" {
+		groupKind := schema.GroupKind{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"}
+		for _, version := range []srapi.AutoScalerVersion{srapi.AutoScalerV2, srapi.AutoScalerV2Beta2} {
+			if _, err := mapper.RESTMapping(groupKind, string(version)); err == nil {
+				return version.CreateEmptyHPA("", "")
+			}
+		}
+	}
+	return srapi.AutoScalerVersion("").CreateEmptyHPA(k8sutils.KUBE_MAJOR_VERSION, k8sutils.KUBE_MINOR_VERSION)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *StarRocksClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// cannot add Owns(&v2.HorizontalPodAutoscaler{}), because if a kubernetes version is lower than 1.23,
-	// v2.HorizontalPodAutoscaler does not exist.
-	// todo(yandongxiao): watch the HPA resource
+	if err := indexClusterReferences(mgr); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&srapi.StarRocksCluster{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
+		// the status of HPA is updated in every sync period of HPA controller, only the changes of spec are watched.
+		Owns(autoScalerType(mgr.GetRESTMapper()), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.clustersOfConfigMap)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.clustersOfSecret)).
 		WithEventFilter(predicates.NewGenericPredicates(r.denyList)).
 		Complete(r)
 }
@@ -61,16 +104,9 @@ func (r *StarRocksClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 //  2. We try to use list Warehouses operation to check if Warehouse CRD exists or not.
 //  3. By Default, It needs the cluster scope permission.
 func SetupWarehouseReconciler(mgr ctrl.Manager, namespace string, denyList string) error {
-	var listOpts []client.ListOption
-	if namespace != "" {
-		listOpts = append(listOpts, client.InNamespace(namespace))
-	}
 	// check StarRocksWarehouse CRD exists or not
-	if err := mgr.GetAPIReader().List(context.Background(), &srapi.StarRocksWarehouseList{}, listOpts...); err != nil {
-		if meta.IsNoMatchError(err) {
-			// StarRocksWarehouse CRD is not found, skip StarRocksWarehouseReconciler
-			return nil
-		}
+	if installed, err := isCRDInstalled(mgr, namespace, &srapi.StarRocksWarehouseList{}); err != nil || !installed {
+		// StarRocksWarehouse CRD is not found, skip StarRocksWarehouseReconciler
 		return err
 	}
 
@@ -88,14 +124,172 @@ func SetupWarehouseReconciler(mgr ctrl.Manager, namespace string, denyList strin
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *StarRocksWarehouseReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// cannot add Owns(&v2.HorizontalPodAutoscaler{}), because if a kubernetes version is lower than 1.23,
-	// v2.HorizontalPodAutoscaler does not exist.
-	// todo(yandongxiao): watch the HPA resource
+	if err := indexWarehouseReferences(mgr); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&srapi.StarRocksWarehouse{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
+		Owns(autoScalerType(mgr.GetRESTMapper()), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.warehousesOfConfigMap)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.warehousesOfSecret)).
+		WithEventFilter(predicates.NewGenericPredicates(r.denyList)).
+		Complete(r)
+}
+
+// SetupBackupReconcilers sets up the reconcilers of StarRocksBackup, StarRocksBackupSchedule and StarRocksRestore.
+// Like StarRocksWarehouse, the CRDs are optional, and the reconciler is skipped if its CRD is not installed.
+func SetupBackupReconcilers(mgr ctrl.Manager, namespace string, denyList string) error {
+	if installed, err := isCRDInstalled(mgr, namespace, &srapi.StarRocksBackupList{}); err != nil {
+		return err
+	} else if installed {
+		reconciler := &StarRocksBackupReconciler{
+			Client:   mgr.GetClient(),
+			Recorder: mgr.GetEventRecorderFor("starrocksbackup-controller"),
+			denyList: denyList,
+		}
+		if err = reconciler.SetupWithManager(mgr); err != nil {
+			return err
+		}
+	}
+
+	if installed, err := isCRDInstalled(mgr, namespace, &srapi.StarRocksBackupScheduleList{}); err != nil {
+		return err
+	} else if installed {
+		reconciler := &StarRocksBackupScheduleReconciler{
+			Client:   mgr.GetClient(),
+			Recorder: mgr.GetEventRecorderFor("starrocksbackupschedule-controller"),
+			Clock:    clock.RealClock{},
+			denyList: denyList,
+		}
+		if err = reconciler.SetupWithManager(mgr); err != nil {
+			return err
+		}
+	}
+
+	if installed, err := isCRDInstalled(mgr, namespace, &srapi.StarRocksRestoreList{}); err != nil {
+		return err
+	} else if installed {
+		reconciler := &StarRocksRestoreReconciler{
+			Client:   mgr.GetClient(),
+			Recorder: mgr.GetEventRecorderFor("starrocksrestore-controller"),
+			denyList: denyList,
+		}
+		if err = reconciler.SetupWithManager(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StarRocksBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&srapi.StarRocksBackup{}).
+		WithEventFilter(predicates.NewGenericPredicates(r.denyList)).
+		Complete(r)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StarRocksBackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&srapi.StarRocksBackupSchedule{}).
+		Owns(&srapi.StarRocksBackup{}).
+		WithEventFilter(predicates.NewGenericPredicates(r.denyList)).
+		Complete(r)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StarRocksRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&srapi.StarRocksRestore{}).
 		WithEventFilter(predicates.NewGenericPredicates(r.denyList)).
 		Complete(r)
 }
+
+// SetupStorageVolumeReconciler sets up the reconciler of StarRocksStorageVolume if the CRD is installed.
+func SetupStorageVolumeReconciler(mgr ctrl.Manager, namespace string, denyList string) error {
+	if installed, err := isCRDInstalled(mgr, namespace, &srapi.StarRocksStorageVolumeList{}); err != nil || !installed {
+		return err
+	}
+	reconciler := &StarRocksStorageVolumeReconciler{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("starrocksstoragevolume-controller"),
+		denyList: denyList,
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StarRocksStorageVolumeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&srapi.StarRocksStorageVolume{}).
+		WithEventFilter(predicates.NewGenericPredicates(r.denyList)).
+		Complete(r)
+}
+
+// SetupAccessControlReconcilers sets up the reconcilers of StarRocksUser and StarRocksRole if the CRDs are installed.
+func SetupAccessControlReconcilers(mgr ctrl.Manager, namespace string, denyList string) error {
+	if installed, err := isCRDInstalled(mgr, namespace, &srapi.StarRocksRoleList{}); err != nil {
+		return err
+	} else if installed {
+		reconciler := &StarRocksRoleReconciler{
+			Client:   mgr.GetClient(),
+			Recorder: mgr.GetEventRecorderFor("starrocksrole-controller"),
+			denyList: denyList,
+		}
+		if err = reconciler.SetupWithManager(mgr); err != nil {
+			return err
+		}
+	}
+
+	if installed, err := isCRDInstalled(mgr, namespace, &srapi.StarRocksUserList{}); err != nil {
+		return err
+	} else if installed {
+		reconciler := &StarRocksUserReconciler{
+			Client:   mgr.GetClient(),
+			Recorder: mgr.GetEventRecorderFor("starrocksuser-controller"),
+			denyList: denyList,
+		}
+		if err = reconciler.SetupWithManager(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StarRocksRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&srapi.StarRocksRole{}).
+		WithEventFilter(predicates.NewGenericPredicates(r.denyList)).
+		Complete(r)
+}
+
+// SetupWithManager sets up the controller with the Manager. The secrets are watched, so that the password of a user
+// is changed when its secret is changed.
+func (r *StarRocksUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&srapi.StarRocksUser{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.usersOfSecret)).
+		WithEventFilter(predicates.NewGenericPredicates(r.denyList)).
+		Complete(r)
+}
+
+// isCRDInstalled tries to list the objects to check whether the CRD is installed. The namespace is used to list the
+// objects if the operator only watches one namespace, because it may not have the cluster scope permission.
+func isCRDInstalled(mgr ctrl.Manager, namespace string, list client.ObjectList) (bool, error) {
+	var listOpts []client.ListOption
+	if namespace != "" {
+		listOpts = append(listOpts, client.InNamespace(namespace))
+	}
+	if err := mgr.GetAPIReader().List(context.Background(), list, listOpts...); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -5,11 +5,15 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
 )
 
@@ -100,6 +104,28 @@ func TestSetupWarehouseReconciler(t *testing.T) {
 	}
 }
 
+func TestAutoScalerType(t *testing.T) {
+	major, minor := k8sutils.KUBE_MAJOR_VERSION, k8sutils.KUBE_MINOR_VERSION
+	defer func() {
+		k8sutils.KUBE_MAJOR_VERSION, k8sutils.KUBE_MINOR_VERSION = major, minor
+	}()
+	newMapper := func(version string) meta.RESTMapper {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Group: "autoscaling", Version: version, Kind: "HorizontalPodAutoscaler"},
+			meta.RESTScopeNamespace)
+		return mapper
+	}
+
+	// the kubernetes version is known.
+	k8sutils.KUBE_MAJOR_VERSION, k8sutils.KUBE_MINOR_VERSION = "1", "25"
+	assert.IsType(t, &autoscalingv2beta2.HorizontalPodAutoscaler{}, autoScalerType(newMapper("v2")))
+
+	// the kubernetes version is unknown, the version served by the API server is chosen.
+	k8sutils.KUBE_MAJOR_VERSION, k8sutils.KUBE_MINOR_VERSION = "", ""
+	assert.IsType(t, &autoscalingv2beta2.HorizontalPodAutoscaler{}, autoScalerType(newMapper("v2beta2")))
+	assert.IsType(t, &autoscalingv2.HorizontalPodAutoscaler{}, autoScalerType(newMapper("v2")))
+}
+
 type Reader struct {
 	hasWarehouseCRD bool
 }
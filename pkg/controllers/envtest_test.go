@@ -0,0 +1,390 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/fakefe"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+const (
+	envtestClusterName = "kube-starrocks"
+	envtestTimeout     = time.Minute
+	envtestInterval    = 200 * time.Millisecond
+)
+
+// startEnvtest starts kube-apiserver and etcd by envtest, installs the CRDs in config/crd/bases, and runs the
+// reconcilers of StarRocksCluster and StarRocksWarehouse against them. Unlike the fake client, kube-apiserver validates
+// the objects by the CRD schema, serves the status subresource, rejects the changes of immutable fields and applies
+// the strategic merge patches. There is no kube-controller-manager, so no pod is created and no garbage is collected.
+// The binaries are downloaded by `make envtest`, and the tests are skipped if KUBEBUILDER_ASSETS is not set.
+func startEnvtest(t *testing.T) client.Client {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, run `make test` to run the envtest-based tests")
+	}
+
+	srapi.Register()
+	env := fake.NewEnvironmentFromCRDDirectory(filepath.Join("..", "..", "config", "crd", "bases"))
+	cfg, err := env.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, env.Stop())
+	})
+
+	mgr, err := manager.New(cfg, manager.Options{
+		MetricsBindAddress: "0",
+		Scheme:             srapi.Scheme,
+	})
+	require.NoError(t, err)
+	require.NoError(t, SetupClusterReconciler(mgr, ""))
+	require.NoError(t, SetupWarehouseReconciler(mgr, "", ""))
+
+	// the CN controller calls the HTTP API of FE by the default transport, route it to the fake FEs.
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = fakefe.HTTPClient().Transport
+	t.Cleanup(func() {
+		http.DefaultTransport = defaultTransport
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, mgr.Start(ctx))
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// read the objects from kube-apiserver directly, not from the cache of manager.
+	k8sClient, err := client.New(cfg, client.Options{Scheme: srapi.Scheme})
+	require.NoError(t, err)
+	return k8sClient
+}
+
+// setupEnvtestNamespace creates a namespace with a fake FE serving the FE service of the cluster. The endpoints of
+// the FE service are created in advance, so that BE and CN treat FE as ready.
+func setupEnvtestNamespace(t *testing.T, k8sClient client.Client, namespace string) *fakefe.FE {
+	ctx := context.Background()
+	require.NoError(t, k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}))
+	require.NoError(t, k8sClient.Create(ctx, &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: envtestClusterName + "-fe-service", Namespace: namespace},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+			Ports:     []corev1.EndpointPort{{Name: "query", Port: fakefe.QueryPort}},
+		}},
+	}))
+	return fakefe.Start(t, fmt.Sprintf("%s-fe-service.%s", envtestClusterName, namespace))
+}
+
+// createSharedDataFEConfig creates the config map of FE which runs in shared-data mode.
+func createSharedDataFEConfig(t *testing.T, k8sClient client.Client, namespace string) srapi.ConfigMapInfo {
+	require.NoError(t, k8sClient.Create(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe-config", Namespace: namespace},
+		Data:       map[string]string{"fe.conf": "run_mode = shared_data\n"},
+	}))
+	return srapi.ConfigMapInfo{ConfigMapName: "fe-config", ResolveKey: "fe.conf"}
+}
+
+func newEnvtestCluster(namespace string, beReplicas int32) *srapi.StarRocksCluster {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: envtestClusterName, Namespace: namespace},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+						Image:    "starrocks/fe-ubuntu:3.3-latest",
+						Replicas: rutils.GetInt32Pointer(1),
+					},
+				},
+			},
+		},
+	}
+	if beReplicas > 0 {
+		src.Spec.StarRocksBeSpec = &srapi.StarRocksBeSpec{
+			StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+				StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+					Image:    "starrocks/be-ubuntu:3.3-latest",
+					Replicas: rutils.GetInt32Pointer(beReplicas),
+				},
+			},
+		}
+	}
+	return src
+}
+
+// updateCluster changes the spec of the cluster, and retries on conflict because the reconciler updates it too.
+func updateCluster(t *testing.T, k8sClient client.Client, namespace string, mutate func(src *srapi.StarRocksCluster)) {
+	require.NoError(t, retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var src srapi.StarRocksCluster
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: envtestClusterName},
+			&src); err != nil {
+			return err
+		}
+		mutate(&src)
+		return k8sClient.Update(context.Background(), &src)
+	}))
+}
+
+// eventuallyStatefulSet waits for the statefulset to satisfy the condition.
+func eventuallyStatefulSet(t *testing.T, k8sClient client.Client, namespace, name string,
+	condition func(sts *appsv1.StatefulSet) bool) *appsv1.StatefulSet {
+	var sts appsv1.StatefulSet
+	require.Eventually(t, func() bool {
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, &sts); err != nil {
+			return false
+		}
+		return condition(&sts)
+	}, envtestTimeout, envtestInterval, "statefulset %s/%s", namespace, name)
+	return &sts
+}
+
+// eventuallyCluster waits for the cluster to satisfy the condition.
+func eventuallyCluster(t *testing.T, k8sClient client.Client, namespace string,
+	condition func(src *srapi.StarRocksCluster) bool) *srapi.StarRocksCluster {
+	var src srapi.StarRocksCluster
+	require.Eventually(t, func() bool {
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: envtestClusterName},
+			&src); err != nil {
+			return false
+		}
+		return condition(&src)
+	}, envtestTimeout, envtestInterval, "cluster %s/%s", namespace, envtestClusterName)
+	return &src
+}
+
+func getEnv(container *corev1.Container, name string) (string, bool) {
+	for _, env := range container.Env {
+		if env.Name == name {
+			return env.Value, true
+		}
+	}
+	return "", false
+}
+
+func TestEnvtest(t *testing.T) {
+	k8sClient := startEnvtest(t)
+	ctx := context.Background()
+	exists := func(*appsv1.StatefulSet) bool { return true }
+
+	t.Run("create", func(t *testing.T) {
+		namespace := "create"
+		setupEnvtestNamespace(t, k8sClient, namespace)
+		require.NoError(t, k8sClient.Create(ctx, newEnvtestCluster(namespace, 3)))
+
+		feSTS := eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-fe", exists)
+		beSTS := eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-be", exists)
+		assert.Equal(t, int32(1), *feSTS.Spec.Replicas)
+		assert.Equal(t, int32(3), *beSTS.Spec.Replicas)
+		for _, name := range []string{"kube-starrocks-fe-service", "kube-starrocks-fe-search",
+			"kube-starrocks-be-service", "kube-starrocks-be-search"} {
+			var svc corev1.Service
+			assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &svc), name)
+		}
+
+		src := eventuallyCluster(t, k8sClient, namespace, func(src *srapi.StarRocksCluster) bool {
+			return src.Status.StarRocksFeStatus != nil && src.Status.StarRocksBeStatus != nil
+		})
+		assert.True(t, controllerutil.ContainsFinalizer(src, StarRocksClusterFinalizer))
+		owner := metav1.GetControllerOf(beSTS)
+		require.NotNil(t, owner)
+		assert.Equal(t, src.UID, owner.UID)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		namespace := "update"
+		setupEnvtestNamespace(t, k8sClient, namespace)
+		require.NoError(t, k8sClient.Create(ctx, newEnvtestCluster(namespace, 1)))
+		beSTS := eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-be", exists)
+
+		// the fields set by others, e.g. kubectl rollout restart, are kept by the three-way merge patch.
+		patch := client.MergeFrom(beSTS.DeepCopy())
+		if beSTS.Spec.Template.Annotations == nil {
+			beSTS.Spec.Template.Annotations = map[string]string{}
+		}
+		beSTS.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = "2024-01-01T00:00:00Z"
+		require.NoError(t, k8sClient.Patch(ctx, beSTS, patch))
+
+		updateCluster(t, k8sClient, namespace, func(src *srapi.StarRocksCluster) {
+			src.Spec.StarRocksBeSpec.Image = "starrocks/be-ubuntu:3.3.1"
+			src.Spec.StarRocksBeSpec.NodeSelector = map[string]string{"disk": "ssd"}
+		})
+		beSTS = eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-be", func(sts *appsv1.StatefulSet) bool {
+			return sts.Spec.Template.Spec.Containers[0].Image == "starrocks/be-ubuntu:3.3.1"
+		})
+		assert.Equal(t, map[string]string{"disk": "ssd"}, beSTS.Spec.Template.Spec.NodeSelector)
+		assert.Equal(t, "2024-01-01T00:00:00Z", beSTS.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"])
+
+		// the fields removed from the spec are removed from the statefulset.
+		updateCluster(t, k8sClient, namespace, func(src *srapi.StarRocksCluster) {
+			src.Spec.StarRocksBeSpec.NodeSelector = nil
+		})
+		eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-be", func(sts *appsv1.StatefulSet) bool {
+			return len(sts.Spec.Template.Spec.NodeSelector) == 0
+		})
+	})
+
+	t.Run("scale", func(t *testing.T) {
+		namespace := "scale"
+		fakeFE := setupEnvtestNamespace(t, k8sClient, namespace)
+		for i := 0; i < 3; i++ {
+			fakeFE.AddBackend(sqlclient.Backend{
+				FQDN:          fmt.Sprintf("kube-starrocks-be-%d.kube-starrocks-be-search.%s.svc.cluster.local", i, namespace),
+				HeartbeatPort: "9050",
+				TabletNum:     10,
+			})
+		}
+		require.NoError(t, k8sClient.Create(ctx, newEnvtestCluster(namespace, 3)))
+		eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-be", exists)
+
+		// the removed BE is decommissioned, and the statefulset is scaled in after its tablets are migrated.
+		updateCluster(t, k8sClient, namespace, func(src *srapi.StarRocksCluster) {
+			src.Spec.StarRocksBeSpec.Replicas = rutils.GetInt32Pointer(2)
+		})
+		eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-be", func(sts *appsv1.StatefulSet) bool {
+			return *sts.Spec.Replicas == 2
+		})
+		assert.Len(t, fakeFE.Backends(), 2)
+		assert.Contains(t, fakeFE.Statements(), fmt.Sprintf(
+			`ALTER SYSTEM DECOMMISSION BACKEND "kube-starrocks-be-2.kube-starrocks-be-search.%s.svc.cluster.local:9050"`, namespace))
+	})
+
+	t.Run("disaster recovery", func(t *testing.T) {
+		namespace := "disaster-recovery"
+		setupEnvtestNamespace(t, k8sClient, namespace)
+		src := newEnvtestCluster(namespace, 0)
+		src.Spec.StarRocksFeSpec.Replicas = rutils.GetInt32Pointer(3)
+		src.Spec.StarRocksFeSpec.ConfigMapInfo = createSharedDataFEConfig(t, k8sClient, namespace)
+		src.Spec.StarRocksFeSpec.ConfigMaps = []srapi.ConfigMapReference{{
+			Name:      "cluster-snapshot",
+			MountPath: "/opt/starrocks/fe/conf/cluster_snapshot.yaml",
+			SubPath:   "cluster_snapshot.yaml",
+		}}
+		require.NoError(t, k8sClient.Create(ctx, src))
+		eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-fe", exists)
+
+		// FE is restarted with one replica to restore the cluster snapshot.
+		updateCluster(t, k8sClient, namespace, func(src *srapi.StarRocksCluster) {
+			src.Spec.DisasterRecovery = &srapi.DisasterRecovery{Enabled: true, Generation: 1}
+		})
+		feSTS := eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-fe", func(sts *appsv1.StatefulSet) bool {
+			_, ok := getEnv(&sts.Spec.Template.Spec.Containers[0], "RESTORE_CLUSTER_SNAPSHOT")
+			return ok
+		})
+		assert.Equal(t, int32(1), *feSTS.Spec.Replicas)
+		eventuallyCluster(t, k8sClient, namespace, func(src *srapi.StarRocksCluster) bool {
+			return src.Status.DisasterRecoveryStatus != nil && src.Status.DisasterRecoveryStatus.Phase == srapi.DRPhaseDoing
+		})
+
+		// FE is deployed as normal once the disaster recovery is aborted.
+		updateCluster(t, k8sClient, namespace, func(src *srapi.StarRocksCluster) {
+			src.Spec.DisasterRecovery.Abort = true
+		})
+		eventuallyCluster(t, k8sClient, namespace, func(src *srapi.StarRocksCluster) bool {
+			return src.Status.DisasterRecoveryStatus.Phase == srapi.DRPhaseAborted
+		})
+		feSTS = eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-fe", func(sts *appsv1.StatefulSet) bool {
+			_, ok := getEnv(&sts.Spec.Template.Spec.Containers[0], "RESTORE_CLUSTER_SNAPSHOT")
+			return !ok
+		})
+		assert.Equal(t, int32(3), *feSTS.Spec.Replicas)
+	})
+
+	t.Run("warehouse", func(t *testing.T) {
+		namespace := "warehouse"
+		fakeFE := setupEnvtestNamespace(t, k8sClient, namespace)
+		fakeFE.SetFeatures(fakefe.FeatureMultiWarehouse)
+		fakeFE.AddWarehouse("wh1")
+		src := newEnvtestCluster(namespace, 0)
+		src.Spec.StarRocksFeSpec.ConfigMapInfo = createSharedDataFEConfig(t, k8sClient, namespace)
+		require.NoError(t, k8sClient.Create(ctx, src))
+
+		warehouse := &srapi.StarRocksWarehouse{
+			ObjectMeta: metav1.ObjectMeta{Name: "wh1", Namespace: namespace},
+			Spec: srapi.StarRocksWarehouseSpec{
+				StarRocksCluster: envtestClusterName,
+				Template: &srapi.WarehouseComponentSpec{
+					StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+						StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+							Image:    "starrocks/cn-ubuntu:3.3-latest",
+							Replicas: rutils.GetInt32Pointer(1),
+						},
+					},
+				},
+			},
+		}
+		require.NoError(t, k8sClient.Create(ctx, warehouse))
+		cnSTS := eventuallyStatefulSet(t, k8sClient, namespace, "wh1-warehouse-cn", exists)
+		value, ok := getEnv(&cnSTS.Spec.Template.Spec.Containers[0], "KUBE_STARROCKS_MULTI_WAREHOUSE")
+		assert.True(t, ok)
+		assert.Equal(t, "wh1", value)
+
+		// the warehouse is dropped from FE when the StarRocksWarehouse is deleted.
+		require.NoError(t, k8sClient.Delete(ctx, warehouse))
+		require.Eventually(t, func() bool {
+			warehouses := fakeFE.Warehouses()
+			return len(warehouses) == 1 && warehouses[0] == fakefe.DefaultWarehouse
+		}, envtestTimeout, envtestInterval)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		namespace := "delete"
+		setupEnvtestNamespace(t, k8sClient, namespace)
+		require.NoError(t, k8sClient.Create(ctx, newEnvtestCluster(namespace, 1)))
+		eventuallyStatefulSet(t, k8sClient, namespace, "kube-starrocks-be", exists)
+		eventuallyCluster(t, k8sClient, namespace, func(src *srapi.StarRocksCluster) bool {
+			return controllerutil.ContainsFinalizer(src, StarRocksClusterFinalizer)
+		})
+
+		// the finalizer is removed after the resources of the cluster are cleared.
+		require.NoError(t, k8sClient.Delete(ctx, newEnvtestCluster(namespace, 0)))
+		require.Eventually(t, func() bool {
+			var src srapi.StarRocksCluster
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: envtestClusterName}, &src)
+			return apierrors.IsNotFound(err)
+		}, envtestTimeout, envtestInterval)
+		for _, name := range []string{"kube-starrocks-fe", "kube-starrocks-be"} {
+			var sts appsv1.StatefulSet
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &sts)
+			assert.True(t, apierrors.IsNotFound(err), name)
+		}
+	})
+}
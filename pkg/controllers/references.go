@@ -0,0 +1,161 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers"
+)
+
+// The configMaps and secrets referenced by StarRocksClusters and StarRocksWarehouses are not owned by the operator,
+// so they are watched through the indexes below. When they are changed, the referencing objects are reconciled, and
+// the pods are rolled out because the hashes in the annotations of pods are changed.
+const (
+	configMapIndexKey = "spec.referencedConfigMaps"
+	secretIndexKey    = "spec.referencedSecrets"
+)
+
+// componentSpecsOfCluster returns the component specs of FE, BE and CN. The FE observer groups share the references
+// of FE.
+func componentSpecsOfCluster(src *srapi.StarRocksCluster) []*srapi.StarRocksComponentSpec {
+	var specs []*srapi.StarRocksComponentSpec
+	if src.Spec.StarRocksFeSpec != nil {
+		specs = append(specs, &src.Spec.StarRocksFeSpec.StarRocksComponentSpec)
+	}
+	if src.Spec.StarRocksBeSpec != nil {
+		specs = append(specs, &src.Spec.StarRocksBeSpec.StarRocksComponentSpec)
+	}
+	if src.Spec.StarRocksCnSpec != nil {
+		specs = append(specs, &src.Spec.StarRocksCnSpec.StarRocksComponentSpec)
+	}
+	return specs
+}
+
+func referencedConfigMapsOfCluster(object client.Object) []string {
+	var names []string
+	for _, spec := range componentSpecsOfCluster(object.(*srapi.StarRocksCluster)) {
+		names = append(names, subcontrollers.ReferencedConfigMaps(spec)...)
+	}
+	return names
+}
+
+func referencedSecretsOfCluster(object client.Object) []string {
+	src := object.(*srapi.StarRocksCluster)
+	var names []string
+	for _, spec := range componentSpecsOfCluster(src) {
+		names = append(names, subcontrollers.ReferencedSecrets(spec)...)
+	}
+	if ref := src.Spec.RootPasswordSecretRef; ref != nil {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+func referencedConfigMapsOfWarehouse(object client.Object) []string {
+	warehouse := object.(*srapi.StarRocksWarehouse)
+	if warehouse.Spec.Template == nil {
+		return nil
+	}
+	return subcontrollers.ReferencedConfigMaps(&warehouse.Spec.Template.StarRocksComponentSpec)
+}
+
+func referencedSecretsOfWarehouse(object client.Object) []string {
+	warehouse := object.(*srapi.StarRocksWarehouse)
+	if warehouse.Spec.Template == nil {
+		return nil
+	}
+	return subcontrollers.ReferencedSecrets(&warehouse.Spec.Template.StarRocksComponentSpec)
+}
+
+// indexClusterReferences indexes StarRocksClusters by the configMaps and secrets they reference.
+func indexClusterReferences(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &srapi.StarRocksCluster{}, configMapIndexKey,
+		referencedConfigMapsOfCluster); err != nil {
+		return err
+	}
+	return indexer.IndexField(context.Background(), &srapi.StarRocksCluster{}, secretIndexKey, referencedSecretsOfCluster)
+}
+
+// indexWarehouseReferences indexes StarRocksWarehouses by the configMaps and secrets they reference.
+func indexWarehouseReferences(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &srapi.StarRocksWarehouse{}, configMapIndexKey,
+		referencedConfigMapsOfWarehouse); err != nil {
+		return err
+	}
+	return indexer.IndexField(context.Background(), &srapi.StarRocksWarehouse{}, secretIndexKey,
+		referencedSecretsOfWarehouse)
+}
+
+// clustersOfConfigMap returns the StarRocksClusters which reference the configMap.
+func (r *StarRocksClusterReconciler) clustersOfConfigMap(configMap client.Object) []reconcile.Request {
+	return r.clustersByIndex(configMapIndexKey, configMap)
+}
+
+// clustersOfSecret returns the StarRocksClusters which reference the secret, including the secret which stores the
+// password of root, so that the password is changed when the secret is changed.
+func (r *StarRocksClusterReconciler) clustersOfSecret(secret client.Object) []reconcile.Request {
+	return r.clustersByIndex(secretIndexKey, secret)
+}
+
+func (r *StarRocksClusterReconciler) clustersByIndex(indexKey string, object client.Object) []reconcile.Request {
+	var clusters srapi.StarRocksClusterList
+	if err := r.Client.List(context.Background(), &clusters, client.InNamespace(object.GetNamespace()),
+		client.MatchingFields{indexKey: object.GetName()}); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(clusters.Items))
+	for i := range clusters.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: clusters.Items[i].Namespace, Name: clusters.Items[i].Name},
+		})
+	}
+	return requests
+}
+
+// warehousesOfConfigMap returns the StarRocksWarehouses which reference the configMap.
+func (r *StarRocksWarehouseReconciler) warehousesOfConfigMap(configMap client.Object) []reconcile.Request {
+	return r.warehousesByIndex(configMapIndexKey, configMap)
+}
+
+// warehousesOfSecret returns the StarRocksWarehouses which reference the secret.
+func (r *StarRocksWarehouseReconciler) warehousesOfSecret(secret client.Object) []reconcile.Request {
+	return r.warehousesByIndex(secretIndexKey, secret)
+}
+
+func (r *StarRocksWarehouseReconciler) warehousesByIndex(indexKey string, object client.Object) []reconcile.Request {
+	var warehouses srapi.StarRocksWarehouseList
+	if err := r.Client.List(context.Background(), &warehouses, client.InNamespace(object.GetNamespace()),
+		client.MatchingFields{indexKey: object.GetName()}); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(warehouses.Items))
+	for i := range warehouses.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: warehouses.Items[i].Namespace, Name: warehouses.Items[i].Name},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+// newIndexedFakeClient returns a fake client with the indexes of referenced configMaps and secrets.
+func newIndexedFakeClient(objects ...client.Object) client.Client {
+	return crfake.NewClientBuilder().WithScheme(srapi.Scheme).WithObjects(objects...).
+		WithIndex(&srapi.StarRocksCluster{}, configMapIndexKey, referencedConfigMapsOfCluster).
+		WithIndex(&srapi.StarRocksCluster{}, secretIndexKey, referencedSecretsOfCluster).
+		WithIndex(&srapi.StarRocksWarehouse{}, configMapIndexKey, referencedConfigMapsOfWarehouse).
+		WithIndex(&srapi.StarRocksWarehouse{}, secretIndexKey, referencedSecretsOfWarehouse).
+		Build()
+}
+
+func TestClustersOfSecret(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+		Spec: srapi.StarRocksClusterSpec{
+			RootPasswordSecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "rootcredential"},
+				Key:                  "password",
+			},
+			StarRocksBeSpec: &srapi.StarRocksBeSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					Secrets: []srapi.SecretReference{
+						{Name: "s3-credential", MountPath: "/etc/s3"},
+						{Name: "static", MountPath: "/etc/static", DisableRollout: true},
+					},
+				},
+			},
+		},
+	}
+	r := &StarRocksClusterReconciler{Client: newIndexedFakeClient(src)}
+
+	requests := r.clustersOfSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "rootcredential", Namespace: "default"}})
+	require.Len(t, requests, 1)
+	require.Equal(t, "kube-starrocks", requests[0].Name)
+	require.Len(t, r.clustersOfSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s3-credential", Namespace: "default"}}), 1)
+	require.Empty(t, r.clustersOfSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "static", Namespace: "default"}}))
+	require.Empty(t, r.clustersOfSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}}))
+	require.Empty(t, r.clustersOfSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "rootcredential", Namespace: "other"}}))
+}
+
+func TestClustersOfConfigMap(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+						ConfigMapInfo: srapi.ConfigMapInfo{ConfigMapName: "fe-cm", ResolveKey: "fe.conf"},
+					},
+				},
+			},
+			StarRocksCnSpec: &srapi.StarRocksCnSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					ConfigMaps: []srapi.ConfigMapReference{{Name: "cn-extra", MountPath: "/etc/extra"}},
+				},
+			},
+		},
+	}
+	r := &StarRocksClusterReconciler{Client: newIndexedFakeClient(src)}
+
+	for _, name := range []string{"fe-cm", "cn-extra"} {
+		requests := r.clustersOfConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}})
+		require.Len(t, requests, 1)
+		require.Equal(t, "kube-starrocks", requests[0].Name)
+	}
+	require.Empty(t, r.clustersOfConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}}))
+}
+
+func TestWarehousesOfConfigMapAndSecret(t *testing.T) {
+	warehouse := &srapi.StarRocksWarehouse{
+		ObjectMeta: metav1.ObjectMeta{Name: "wh1", Namespace: "default"},
+		Spec: srapi.StarRocksWarehouseSpec{
+			StarRocksCluster: "kube-starrocks",
+			Template: &srapi.WarehouseComponentSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+						ConfigMapInfo: srapi.ConfigMapInfo{ConfigMapName: "wh1-cm", ResolveKey: "cn.conf", DisableRollout: true},
+					},
+					Secrets: []srapi.SecretReference{{Name: "wh1-secret", MountPath: "/etc/secret"}},
+				},
+			},
+		},
+	}
+	r := &StarRocksWarehouseReconciler{Client: newIndexedFakeClient(warehouse)}
+
+	requests := r.warehousesOfSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "wh1-secret", Namespace: "default"}})
+	require.Len(t, requests, 1)
+	require.Equal(t, "wh1", requests[0].Name)
+	require.Empty(t, r.warehousesOfConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "wh1-cm", Namespace: "default"}}))
+}
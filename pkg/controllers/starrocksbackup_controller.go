@@ -0,0 +1,273 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+// backupRequeueInterval is the interval to check the progress of the backup and restore jobs.
+const backupRequeueInterval = 10 * time.Second
+
+// StarRocksBackupReconciler reconciles a StarRocksBackup object
+type StarRocksBackupReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	denyList string
+}
+
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksbackups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile submits the backup job to StarRocks, and updates the status of StarRocksBackup until the job is finished.
+func (r *StarRocksBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx).WithName("StarRocksBackupReconciler").
+		WithValues("name", req.Name, "namespace", req.Namespace)
+	ctx = logr.NewContext(ctx, logger)
+
+	backup := &srapi.StarRocksBackup{}
+	if err := r.Client.Get(ctx, req.NamespacedName, backup); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "get StarRocksBackup CR failed")
+		return ctrl.Result{}, err
+	}
+	if backup.Status.Phase.IsFinished() || !backup.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	err := r.syncBackup(ctx, backup, nil)
+	if err != nil {
+		logger.Error(err, "sync backup failed")
+		backup.Status.Reason = err.Error()
+	}
+	if updateError := r.updateStatus(ctx, backup); updateError != nil {
+		logger.Error(updateError, "update StarRocksBackup status failed")
+		return ctrl.Result{}, updateError
+	}
+	if err != nil {
+		return requeueIfError(err)
+	}
+	if !backup.Status.Phase.IsFinished() {
+		return ctrl.Result{RequeueAfter: backupRequeueInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncBackup submits BACKUP SNAPSHOT if it has not been submitted, and updates the status from SHOW BACKUP.
+func (r *StarRocksBackupReconciler) syncBackup(ctx context.Context, backup *srapi.StarRocksBackup, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	status := &backup.Status
+	if status.Phase == "" {
+		status.Phase = srapi.BackupPending
+	}
+	if status.SnapshotName == "" {
+		status.SnapshotName = backupSnapshotName(backup)
+	}
+	spec := &backup.Spec
+	if err := checkSnapshotNames(spec.Database, status.SnapshotName, spec.Repository.Name, spec.Tables); err != nil {
+		// it will not succeed by retrying.
+		r.finishBackup(backup, srapi.BackupFailed, err.Error())
+		return nil
+	}
+
+	sqlClient, reason, err := newSQLClientForCluster(ctx, r.Client, backup.Namespace, backup.Spec.StarRocksCluster)
+	if err != nil || sqlClient == nil {
+		status.Reason = reason
+		return err
+	}
+	if err = ensureRepository(ctx, r.Client, r.Recorder, backup, &spec.Repository, sqlClient, db); err != nil {
+		return err
+	}
+
+	job, err := queryLatestBackup(ctx, sqlClient, db, spec.Database)
+	if err != nil {
+		return err
+	}
+	switch {
+	case job != nil && job.SnapshotName == status.SnapshotName:
+		status.JobID, status.State, status.Progress = job.JobID, job.State, job.Progress
+		status.Reason = ""
+		switch job.State {
+		case backupStateFinished:
+			r.finishBackup(backup, srapi.BackupSucceeded, "")
+		case backupStateCancelled:
+			r.finishBackup(backup, srapi.BackupFailed, jobErrorMessage(job.Status, job.TaskErrMsg))
+		default:
+			status.Phase = srapi.BackupRunning
+		}
+	case status.Phase == srapi.BackupRunning:
+		// SHOW BACKUP only returns the latest job of the database, the job may be replaced by another one.
+		timestamps, err := querySnapshotTimestamps(ctx, sqlClient, db, spec.Repository.Name, status.SnapshotName)
+		if err != nil {
+			return err
+		}
+		if len(timestamps) > 0 {
+			r.finishBackup(backup, srapi.BackupSucceeded, "")
+		} else {
+			r.finishBackup(backup, srapi.BackupFailed, "the backup job is not found in StarRocks")
+		}
+	case job != nil && !isJobFinished(job.State):
+		// only one backup job can be running in a database.
+		status.Reason = fmt.Sprintf("waiting for backup job %s of database %s to finish", job.JobID, spec.Database)
+	default:
+		logger.Info("backup database", "database", spec.Database, "snapshot", status.SnapshotName)
+		statement := backupStatement(spec.Database, status.SnapshotName, spec.Repository.Name, spec.Tables)
+		if err = sqlClient.ExecuteContext(ctx, db, statement); err != nil {
+			return err
+		}
+		now := metav1.Now()
+		status.Phase, status.Reason, status.StartTime = srapi.BackupRunning, "", &now
+		r.Recorder.Event(backup, corev1.EventTypeNormal, "BackupStarted",
+			fmt.Sprintf("backup database %s to snapshot %s in repository %s",
+				spec.Database, status.SnapshotName, spec.Repository.Name))
+	}
+	return nil
+}
+
+// finishBackup sets the final phase of StarRocksBackup, and records an event.
+func (r *StarRocksBackupReconciler) finishBackup(backup *srapi.StarRocksBackup, phase srapi.BackupPhase, reason string) {
+	now := metav1.Now()
+	backup.Status.Phase, backup.Status.Reason, backup.Status.CompletionTime = phase, reason, &now
+	if phase == srapi.BackupSucceeded {
+		r.Recorder.Event(backup, corev1.EventTypeNormal, "BackupSucceeded",
+			fmt.Sprintf("snapshot %s is backed up", backup.Status.SnapshotName))
+	} else {
+		r.Recorder.Event(backup, corev1.EventTypeWarning, "BackupFailed",
+			fmt.Sprintf("failed to back up snapshot %s: %s", backup.Status.SnapshotName, reason))
+	}
+}
+
+// updateStatus updates the status of StarRocksBackup.
+func (r *StarRocksBackupReconciler) updateStatus(ctx context.Context, backup *srapi.StarRocksBackup) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		actual := &srapi.StarRocksBackup{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: backup.Namespace, Name: backup.Name}, actual); err != nil {
+			return err
+		}
+		actual.Status = backup.Status
+		return r.Client.Status().Update(ctx, actual)
+	})
+}
+
+// backupSnapshotName returns the snapshot name of StarRocksBackup, e.g. daily_backup_1700000000.
+func backupSnapshotName(backup *srapi.StarRocksBackup) string {
+	if backup.Spec.SnapshotName != "" {
+		return backup.Spec.SnapshotName
+	}
+	return fmt.Sprintf("%s_%d", toSnapshotName(backup.Name), backup.CreationTimestamp.Unix())
+}
+
+// toSnapshotName converts the name of a kubernetes object to a snapshot name, which can not contain - and .
+func toSnapshotName(name string) string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
+
+// jobErrorMessage returns the error message of a cancelled backup or restore job.
+func jobErrorMessage(status, taskErrMsg string) string {
+	if taskErrMsg != "" {
+		return fmt.Sprintf("%s %s", status, taskErrMsg)
+	}
+	return status
+}
+
+// newSQLClientForCluster returns the SQL client of the StarRocksCluster. If the cluster is not found or FE is not
+// ready, it returns nil and the reason.
+func newSQLClientForCluster(ctx context.Context, k8sClient client.Client,
+	namespace, clusterName string) (*sqlclient.Client, string, error) {
+	src := &srapi.StarRocksCluster{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: clusterName}, src); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Sprintf("StarRocksCluster %s is not found", clusterName), nil
+		}
+		return nil, "", err
+	}
+	if !fe.CheckFEReady(ctx, k8sClient, namespace, clusterName) {
+		return nil, fmt.Sprintf("waiting for FE of StarRocksCluster %s to be ready", clusterName), nil
+	}
+	sqlClient, err := fe.NewSQLClient(ctx, k8sClient, src)
+	return sqlClient, "", err
+}
+
+// newSQLClientForDeletion returns the SQL client to clean up the objects in StarRocks when a CR is deleted. skip
+// is true if the StarRocksCluster has been deleted or is being deleted, so there is nothing to clean up. If FE is not
+// ready, it returns nil and the reason.
+func newSQLClientForDeletion(ctx context.Context, k8sClient client.Client,
+	namespace, clusterName string) (sqlClient *sqlclient.Client, skip bool, reason string, err error) {
+	src := &srapi.StarRocksCluster{}
+	if err = k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: clusterName}, src); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, true, "", nil
+		}
+		return nil, false, "", err
+	}
+	if !src.DeletionTimestamp.IsZero() {
+		return nil, true, "", nil
+	}
+	sqlClient, reason, err = newSQLClientForCluster(ctx, k8sClient, namespace, clusterName)
+	return sqlClient, false, reason, err
+}
+
+// ensureRepository creates the repository by CREATE REPOSITORY if it does not exist in StarRocks.
+func ensureRepository(ctx context.Context, k8sClient client.Client, recorder record.EventRecorder, object client.Object,
+	repository *srapi.BackupRepository, sqlClient *sqlclient.Client, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	exists, err := repositoryExists(ctx, sqlClient, db, repository.Name)
+	if err != nil || exists {
+		return err
+	}
+
+	credentials := map[string]string{}
+	if ref := repository.CredentialsSecretRef; ref != nil {
+		var secret corev1.Secret
+		if err = k8sClient.Get(ctx, types.NamespacedName{Namespace: object.GetNamespace(), Name: ref.Name}, &secret); err != nil {
+			return fmt.Errorf("failed to get the credentials of repository %s: %w", repository.Name, err)
+		}
+		for key, value := range secret.Data {
+			credentials[key] = string(value)
+		}
+	}
+
+	logger.Info("create repository", "repository", repository.Name, "location", repository.Location)
+	if err = sqlClient.ExecuteContext(ctx, db, createRepositoryStatement(repository, credentials)); err != nil {
+		return fmt.Errorf("failed to create repository %s: %w", repository.Name, err)
+	}
+	recorder.Event(object, corev1.EventTypeNormal, "CreateRepository",
+		fmt.Sprintf("create repository %s on location %s", repository.Name, repository.Location))
+	return nil
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+)
+
+// newReadyClusterObjects returns the objects which are needed to connect to the FE of StarRocksCluster.
+func newReadyClusterObjects() []runtime.Object {
+	return []runtime.Object{
+		&srapi.StarRocksCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+			Spec:       srapi.StarRocksClusterSpec{StarRocksFeSpec: &srapi.StarRocksFeSpec{}},
+		},
+		newStatefulSet("kube-starrocks-fe"),
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks-fe-service", Namespace: "default"},
+			Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "127.0.0.1"}}}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: "default"},
+			Data:       map[string][]byte{"access_key": []byte("ak"), "secret_key": []byte("sk")},
+		},
+	}
+}
+
+func newBackup() *srapi.StarRocksBackup {
+	return &srapi.StarRocksBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "daily-backup",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Unix(1700000000, 0)),
+		},
+		Spec: srapi.StarRocksBackupSpec{
+			StarRocksCluster: "kube-starrocks",
+			Repository: srapi.BackupRepository{
+				Name:                 "repo",
+				Type:                 srapi.S3Repository,
+				Location:             "s3://bucket/backup",
+				Region:               "us-west-2",
+				CredentialsSecretRef: &corev1.LocalObjectReference{Name: "s3-credentials"},
+			},
+			Database: "db1",
+			Tables:   []srapi.TableName{"t1"},
+		},
+	}
+}
+
+func TestSyncBackup(t *testing.T) {
+	repositoryColumns := []string{"RepoId", "RepoName", "Location"}
+	backupColumns := []string{"JobId", "SnapshotName", "DbName", "State", "Progress", "TaskErrMsg", "Status"}
+	tests := []struct {
+		name      string
+		phase     srapi.BackupPhase
+		objects   []runtime.Object
+		mockSQL   func(mock sqlmock.Sqlmock)
+		wantPhase srapi.BackupPhase
+		wantState string
+	}{
+		{
+			name:      "FE is not ready",
+			objects:   []runtime.Object{},
+			mockSQL:   func(mock sqlmock.Sqlmock) {},
+			wantPhase: srapi.BackupPending,
+		},
+		{
+			name:    "create repository and start backup",
+			objects: newReadyClusterObjects(),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW REPOSITORIES").WillReturnRows(sqlmock.NewRows(repositoryColumns))
+				mock.ExpectExec("CREATE REPOSITORY `repo` WITH BROKER ON LOCATION 's3://bucket/backup' PROPERTIES (" +
+					"'aws.s3.access_key' = 'ak', 'aws.s3.region' = 'us-west-2', 'aws.s3.secret_key' = 'sk')").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(backupColumns))
+				mock.ExpectExec("BACKUP SNAPSHOT `db1`.`daily_backup_1700000000` TO `repo` ON (`t1`)").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantPhase: srapi.BackupRunning,
+		},
+		{
+			name:    "wait for another backup job",
+			objects: newReadyClusterObjects(),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW REPOSITORIES").WillReturnRows(sqlmock.NewRows(repositoryColumns).
+					AddRow("1", "repo", "s3://bucket/backup"))
+				mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(backupColumns).
+					AddRow("1", "other", "db1", "UPLOADING", "1/3", "", "[OK]"))
+			},
+			wantPhase: srapi.BackupPending,
+		},
+		{
+			name:    "backup is running",
+			phase:   srapi.BackupRunning,
+			objects: newReadyClusterObjects(),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW REPOSITORIES").WillReturnRows(sqlmock.NewRows(repositoryColumns).
+					AddRow("1", "repo", "s3://bucket/backup"))
+				mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(backupColumns).
+					AddRow("2", "daily_backup_1700000000", "db1", "UPLOADING", "1/3", "", "[OK]"))
+			},
+			wantPhase: srapi.BackupRunning,
+			wantState: "UPLOADING",
+		},
+		{
+			name:    "backup is finished",
+			phase:   srapi.BackupRunning,
+			objects: newReadyClusterObjects(),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW REPOSITORIES").WillReturnRows(sqlmock.NewRows(repositoryColumns).
+					AddRow("1", "repo", "s3://bucket/backup"))
+				mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(backupColumns).
+					AddRow("2", "daily_backup_1700000000", "db1", "FINISHED", "3/3", "", "[OK]"))
+			},
+			wantPhase: srapi.BackupSucceeded,
+			wantState: "FINISHED",
+		},
+		{
+			name:    "backup is cancelled",
+			phase:   srapi.BackupRunning,
+			objects: newReadyClusterObjects(),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW REPOSITORIES").WillReturnRows(sqlmock.NewRows(repositoryColumns).
+					AddRow("1", "repo", "s3://bucket/backup"))
+				mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(backupColumns).
+					AddRow("2", "daily_backup_1700000000", "db1", "CANCELLED", "0/3", "access denied", "[CANCELLED]"))
+			},
+			wantPhase: srapi.BackupFailed,
+			wantState: "CANCELLED",
+		},
+		{
+			name:    "backup job is replaced by another job",
+			phase:   srapi.BackupRunning,
+			objects: newReadyClusterObjects(),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW REPOSITORIES").WillReturnRows(sqlmock.NewRows(repositoryColumns).
+					AddRow("1", "repo", "s3://bucket/backup"))
+				mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(backupColumns).
+					AddRow("3", "other", "db1", "UPLOADING", "1/3", "", "[OK]"))
+				mock.ExpectQuery("SHOW SNAPSHOT ON `repo` WHERE SNAPSHOT = 'daily_backup_1700000000'").
+					WillReturnRows(sqlmock.NewRows([]string{"Snapshot", "Timestamp", "Status"}).
+						AddRow("daily_backup_1700000000", "2023-11-14-22-13-20-000", "OK"))
+			},
+			wantPhase: srapi.BackupSucceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			backup := newBackup()
+			backup.Status.Phase = tt.phase
+			r := &StarRocksBackupReconciler{
+				Client:   fake.NewFakeClient(srapi.Scheme, append(tt.objects, backup)...),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			require.NoError(t, r.syncBackup(context.Background(), backup, db))
+			require.Equal(t, tt.wantPhase, backup.Status.Phase)
+			require.Equal(t, tt.wantState, backup.Status.State)
+			require.Equal(t, "daily_backup_1700000000", backup.Status.SnapshotName)
+			if tt.wantPhase == srapi.BackupPending {
+				require.NotEmpty(t, backup.Status.Reason)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSyncBackupInvalidNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(backup *srapi.StarRocksBackup)
+	}{
+		{name: "database", modify: func(backup *srapi.StarRocksBackup) { backup.Spec.Database = "db1`; DROP DATABASE db2; --" }},
+		{name: "table", modify: func(backup *srapi.StarRocksBackup) { backup.Spec.Tables = []srapi.TableName{"t1`"} }},
+		{name: "repository", modify: func(backup *srapi.StarRocksBackup) { backup.Spec.Repository.Name = "repo`" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+
+			backup := newBackup()
+			tt.modify(backup)
+			r := &StarRocksBackupReconciler{
+				Client:   fake.NewFakeClient(srapi.Scheme, append(newReadyClusterObjects(), backup)...),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			require.NoError(t, r.syncBackup(context.Background(), backup, db))
+			require.Equal(t, srapi.BackupFailed, backup.Status.Phase)
+			require.Contains(t, backup.Status.Reason, "invalid "+tt.name)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSnapshotStatements(t *testing.T) {
+	tables := []srapi.TableName{"t1", "t`2"}
+	require.Equal(t, "BACKUP SNAPSHOT `db1`.`snapshot` TO `repo` ON (`t1`, `t``2`)",
+		backupStatement("db1", "snapshot", "repo", tables))
+	require.Equal(t, "RESTORE SNAPSHOT `db``1`.`snapshot` FROM `repo` ON (`t1`, `t``2`) PROPERTIES ('backup_timestamp' = 'ts')",
+		restoreStatement("db`1", "snapshot", "repo", tables, "ts", nil))
+	require.Equal(t, `'it\'s a \\ test'`, quoteString(`it's a \ test`))
+}
+
+func TestCreateRepositoryStatement(t *testing.T) {
+	tests := []struct {
+		name        string
+		repository  *srapi.BackupRepository
+		credentials map[string]string
+		want        string
+	}{
+		{
+			name: "minio",
+			repository: &srapi.BackupRepository{
+				Name:     "repo",
+				Type:     srapi.MinIORepository,
+				Location: "s3://bucket/backup",
+				Endpoint: "http://minio:9000",
+			},
+			credentials: map[string]string{"access_key": "ak", "secret_key": `s'k`},
+			want: "CREATE REPOSITORY `repo` WITH BROKER ON LOCATION 's3://bucket/backup' PROPERTIES (" +
+				"'aws.s3.access_key' = 'ak', 'aws.s3.enable_path_style_access' = 'true', " +
+				"'aws.s3.endpoint' = 'http://minio:9000', 'aws.s3.secret_key' = 's\\'k')",
+		},
+		{
+			name: "hdfs with extra properties",
+			repository: &srapi.BackupRepository{
+				Name:       "repo",
+				Type:       srapi.HDFSRepository,
+				Location:   "hdfs://namenode:9000/backup",
+				Properties: map[string]string{"password": "override"},
+			},
+			credentials: map[string]string{"username": "user", "password": "pass"},
+			want: "CREATE REPOSITORY `repo` WITH BROKER ON LOCATION 'hdfs://namenode:9000/backup' PROPERTIES (" +
+				"'password' = 'override', 'username' = 'user')",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, createRepositoryStatement(tt.repository, tt.credentials))
+		})
+	}
+}
@@ -0,0 +1,372 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/cron"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+// StarRocksBackupScheduleReconciler reconciles a StarRocksBackupSchedule object. It does not use CronJob, the
+// request is requeued until the next schedule time, so that it can be tested with a fake clock.
+type StarRocksBackupScheduleReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	Clock    clock.PassiveClock
+	denyList string
+}
+
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksbackupschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksbackupschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksbackupschedules/finalizers,verbs=update
+
+// Reconcile creates StarRocksBackup on schedule, prunes the expired backups, and records the result of the backups.
+func (r *StarRocksBackupScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx).WithName("StarRocksBackupScheduleReconciler").
+		WithValues("name", req.Name, "namespace", req.Namespace)
+	ctx = logr.NewContext(ctx, logger)
+
+	schedule := &srapi.StarRocksBackupSchedule{}
+	if err := r.Client.Get(ctx, req.NamespacedName, schedule); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "get StarRocksBackupSchedule CR failed")
+		return ctrl.Result{}, err
+	}
+	if !schedule.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	result, err := r.reconcileSchedule(ctx, schedule, nil)
+	if err != nil {
+		logger.Error(err, "reconcile backup schedule failed")
+		schedule.Status.Reason = err.Error()
+	}
+	if updateError := r.updateStatus(ctx, schedule); updateError != nil {
+		logger.Error(updateError, "update StarRocksBackupSchedule status failed")
+		return ctrl.Result{}, updateError
+	}
+	return result, err
+}
+
+// reconcileSchedule records the finished backups, prunes the expired backups, and creates the backups if the
+// schedule time is reached. It returns the result which requeues the request at the next schedule time.
+func (r *StarRocksBackupScheduleReconciler) reconcileSchedule(ctx context.Context,
+	schedule *srapi.StarRocksBackupSchedule, db *sql.DB) (ctrl.Result, error) {
+	now := r.Clock.Now()
+	status := &schedule.Status
+	status.Reason = ""
+
+	var backupList srapi.StarRocksBackupList
+	if err := r.Client.List(ctx, &backupList, client.InNamespace(schedule.Namespace),
+		client.MatchingLabels{srapi.BackupScheduleLabelKey: schedule.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	backups := make([]*srapi.StarRocksBackup, 0, len(backupList.Items))
+	for i := range backupList.Items {
+		backups = append(backups, &backupList.Items[i])
+	}
+	r.recordBackupHistory(ctx, schedule, backups)
+	pruneErr := r.pruneBackups(ctx, schedule, backups, now, db)
+
+	cronSchedule, err := cron.Parse(schedule.Spec.Schedule)
+	if err != nil {
+		status.Reason, status.NextScheduleTime = err.Error(), nil
+		return ctrl.Result{}, pruneErr
+	}
+	location := time.UTC
+	if schedule.Spec.TimeZone != "" {
+		if location, err = time.LoadLocation(schedule.Spec.TimeZone); err != nil {
+			status.Reason, status.NextScheduleTime = fmt.Sprintf("invalid time zone %s: %v", schedule.Spec.TimeZone, err), nil
+			return ctrl.Result{}, pruneErr
+		}
+	}
+	if schedule.Spec.Suspend {
+		status.NextScheduleTime = nil
+		return ctrl.Result{}, pruneErr
+	}
+
+	// only the latest missed schedule time is used, e.g. the operator is restarted after a long time.
+	last := schedule.CreationTimestamp.Time
+	if status.LastScheduleTime != nil {
+		last = status.LastScheduleTime.Time
+	}
+	var missed time.Time
+	next := cronSchedule.Next(last.In(location))
+	for !next.IsZero() && !next.After(now) {
+		missed = next
+		next = cronSchedule.Next(next)
+	}
+	if !missed.IsZero() {
+		if err = r.createBackups(ctx, schedule, backups, missed); err != nil {
+			return ctrl.Result{}, err
+		}
+		scheduleTime := metav1.NewTime(missed)
+		status.LastScheduleTime = &scheduleTime
+	}
+	if next.IsZero() {
+		status.NextScheduleTime = nil
+		return ctrl.Result{}, pruneErr
+	}
+	nextScheduleTime := metav1.NewTime(next)
+	status.NextScheduleTime = &nextScheduleTime
+	return ctrl.Result{RequeueAfter: next.Sub(now)}, pruneErr
+}
+
+// createBackups creates a StarRocksBackup for every target. A target is skipped if its last backup is not finished.
+func (r *StarRocksBackupScheduleReconciler) createBackups(ctx context.Context, schedule *srapi.StarRocksBackupSchedule,
+	backups []*srapi.StarRocksBackup, scheduleTime time.Time) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	for _, target := range schedule.Spec.Targets {
+		if active := activeBackupOfDatabase(backups, target.Database); active != nil {
+			r.Recorder.Event(schedule, corev1.EventTypeWarning, "BackupSkipped",
+				fmt.Sprintf("skip backing up database %s, backup %s is not finished", target.Database, active.Name))
+			continue
+		}
+
+		name := scheduledBackupName(schedule.Name, target.Database, scheduleTime)
+		backup := &srapi.StarRocksBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: schedule.Namespace,
+				Labels:    map[string]string{srapi.BackupScheduleLabelKey: schedule.Name},
+			},
+			Spec: srapi.StarRocksBackupSpec{
+				StarRocksCluster: schedule.Spec.StarRocksCluster,
+				Repository:       schedule.Spec.Repository,
+				Database:         target.Database,
+				Tables:           target.Tables,
+				SnapshotName:     toSnapshotName(name),
+			},
+		}
+		if err := controllerutil.SetControllerReference(schedule, backup, r.Client.Scheme()); err != nil {
+			return err
+		}
+		logger.Info("create scheduled backup", "backup", name, "database", target.Database)
+		if err := r.Client.Create(ctx, backup); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				continue
+			}
+			return err
+		}
+		r.Recorder.Event(schedule, corev1.EventTypeNormal, "CreateBackup",
+			fmt.Sprintf("create backup %s for database %s", name, target.Database))
+	}
+	return nil
+}
+
+// recordBackupHistory records the last succeeded and failed backups in the status of StarRocksBackupSchedule, and
+// records an event on the StarRocksCluster when a backup is newly finished.
+func (r *StarRocksBackupScheduleReconciler) recordBackupHistory(ctx context.Context,
+	schedule *srapi.StarRocksBackupSchedule, backups []*srapi.StarRocksBackup) {
+	status := &schedule.Status
+	status.Active = nil
+	var finished []*srapi.StarRocksBackup
+	for _, backup := range backups {
+		switch {
+		case !backup.Status.Phase.IsFinished():
+			status.Active = append(status.Active, backup.Name)
+		case backup.Status.CompletionTime != nil:
+			finished = append(finished, backup)
+		}
+	}
+	sort.Strings(status.Active)
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].Status.CompletionTime.Before(finished[j].Status.CompletionTime)
+	})
+
+	src := &srapi.StarRocksCluster{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: schedule.Namespace, Name: schedule.Spec.StarRocksCluster}, src); err != nil {
+		src = nil
+	}
+	for _, backup := range finished {
+		completionTime := backup.Status.CompletionTime
+		if backup.Status.Phase == srapi.BackupSucceeded {
+			if status.LastSuccessfulTime != nil && !status.LastSuccessfulTime.Before(completionTime) {
+				continue
+			}
+			status.LastSuccessfulBackup, status.LastSuccessfulTime = backup.Name, completionTime.DeepCopy()
+			if src != nil {
+				r.Recorder.Event(src, corev1.EventTypeNormal, "ScheduledBackupSucceeded",
+					fmt.Sprintf("backup %s of schedule %s is succeeded, snapshot: %s",
+						backup.Name, schedule.Name, backup.Status.SnapshotName))
+			}
+		} else {
+			if status.LastFailedTime != nil && !status.LastFailedTime.Before(completionTime) {
+				continue
+			}
+			status.LastFailedBackup, status.LastFailedTime = backup.Name, completionTime.DeepCopy()
+			status.LastFailureReason = backup.Status.Reason
+			if src != nil {
+				r.Recorder.Event(src, corev1.EventTypeWarning, "ScheduledBackupFailed",
+					fmt.Sprintf("backup %s of schedule %s is failed: %s", backup.Name, schedule.Name, backup.Status.Reason))
+			}
+		}
+	}
+}
+
+// pruneBackups drops the snapshots of the expired backups from the repository, and deletes the StarRocksBackup objects.
+func (r *StarRocksBackupScheduleReconciler) pruneBackups(ctx context.Context, schedule *srapi.StarRocksBackupSchedule,
+	backups []*srapi.StarRocksBackup, now time.Time, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	expired := expiredBackups(backups, schedule.Spec.Retention, now)
+	if len(expired) == 0 {
+		return nil
+	}
+
+	var sqlClient *sqlclient.Client
+	for _, backup := range expired {
+		if backup.Status.Phase == srapi.BackupSucceeded {
+			if sqlClient == nil {
+				var reason string
+				var err error
+				sqlClient, reason, err = newSQLClientForCluster(ctx, r.Client, schedule.Namespace, schedule.Spec.StarRocksCluster)
+				if err != nil {
+					return err
+				} else if sqlClient == nil {
+					return errors.New(reason)
+				}
+			}
+			repository, snapshotName := backup.Spec.Repository.Name, backup.Status.SnapshotName
+			timestamps, err := querySnapshotTimestamps(ctx, sqlClient, db, repository, snapshotName)
+			if err != nil {
+				return err
+			}
+			if len(timestamps) > 0 {
+				logger.Info("drop expired snapshot", "repository", repository, "snapshot", snapshotName)
+				statement := fmt.Sprintf("DROP SNAPSHOT ON %s WHERE SNAPSHOT = %s",
+					quoteIdentifier(repository), quoteString(snapshotName))
+				if err = sqlClient.ExecuteContext(ctx, db, statement); err != nil {
+					return err
+				}
+			}
+		}
+		if err := r.Client.Delete(ctx, backup); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		r.Recorder.Event(schedule, corev1.EventTypeNormal, "PruneBackup",
+			fmt.Sprintf("prune expired backup %s, snapshot: %s", backup.Name, backup.Status.SnapshotName))
+	}
+	return nil
+}
+
+// expiredBackups returns the finished backups which are expired by the retention. The rules are applied to every
+// database separately, and the latest succeeded backup of a database is always kept.
+func expiredBackups(backups []*srapi.StarRocksBackup, retention *srapi.BackupRetention, now time.Time) []*srapi.StarRocksBackup {
+	if retention == nil || (retention.KeepLast == nil && retention.KeepDays == nil) {
+		return nil
+	}
+
+	backupsByDatabase := map[string][]*srapi.StarRocksBackup{}
+	var databases []string
+	for _, backup := range backups {
+		if _, ok := backupsByDatabase[backup.Spec.Database]; !ok {
+			databases = append(databases, backup.Spec.Database)
+		}
+		backupsByDatabase[backup.Spec.Database] = append(backupsByDatabase[backup.Spec.Database], backup)
+	}
+	sort.Strings(databases)
+
+	var expired []*srapi.StarRocksBackup
+	for _, database := range databases {
+		group := backupsByDatabase[database]
+		// from the newest to the oldest.
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].CreationTimestamp.Equal(&group[j].CreationTimestamp) {
+				return group[i].Name > group[j].Name
+			}
+			return group[j].CreationTimestamp.Before(&group[i].CreationTimestamp)
+		})
+
+		succeeded, failed := 0, 0
+		for _, backup := range group {
+			var count int
+			switch backup.Status.Phase {
+			case srapi.BackupSucceeded:
+				succeeded++
+				if succeeded == 1 {
+					continue
+				}
+				count = succeeded
+			case srapi.BackupFailed:
+				failed++
+				count = failed
+			default:
+				continue
+			}
+			if (retention.KeepLast != nil && count > int(*retention.KeepLast)) || (retention.KeepDays != nil &&
+				backup.CreationTimestamp.Time.Before(now.AddDate(0, 0, -int(*retention.KeepDays)))) {
+				expired = append(expired, backup)
+			}
+		}
+	}
+	return expired
+}
+
+// activeBackupOfDatabase returns the backup of the database which is not finished.
+func activeBackupOfDatabase(backups []*srapi.StarRocksBackup, database string) *srapi.StarRocksBackup {
+	for _, backup := range backups {
+		if backup.Spec.Database == database && !backup.Status.Phase.IsFinished() {
+			return backup
+		}
+	}
+	return nil
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// scheduledBackupName returns the name of StarRocksBackup created by schedule, e.g. daily-db1-1700000000. The snapshot
+// name is the same as the backup name, except that the hyphens are replaced with underscores.
+func scheduledBackupName(scheduleName, database string, scheduleTime time.Time) string {
+	database = strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(database), "-"), "-")
+	return fmt.Sprintf("%s-%s-%d", scheduleName, database, scheduleTime.Unix())
+}
+
+// updateStatus updates the status of StarRocksBackupSchedule.
+func (r *StarRocksBackupScheduleReconciler) updateStatus(ctx context.Context, schedule *srapi.StarRocksBackupSchedule) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		actual := &srapi.StarRocksBackupSchedule{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: schedule.Namespace, Name: schedule.Name}, actual); err != nil {
+			return err
+		}
+		actual.Status = schedule.Status
+		return r.Client.Status().Update(ctx, actual)
+	})
+}
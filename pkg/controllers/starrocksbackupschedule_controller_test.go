@@ -0,0 +1,216 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+)
+
+func newBackupSchedule() *srapi.StarRocksBackupSchedule {
+	return &srapi.StarRocksBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "daily",
+			Namespace:         "default",
+			UID:               "uid",
+			CreationTimestamp: metav1.NewTime(time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)),
+		},
+		Spec: srapi.StarRocksBackupScheduleSpec{
+			StarRocksCluster: "kube-starrocks",
+			Schedule:         "0 * * * *",
+			Repository:       srapi.BackupRepository{Name: "repo", Type: srapi.S3Repository, Location: "s3://bucket/backup"},
+			Targets:          []srapi.BackupTarget{{Database: "db1"}, {Database: "DB_2"}},
+		},
+	}
+}
+
+func newScheduledBackup(name, database string, created time.Time, phase srapi.BackupPhase) *srapi.StarRocksBackup {
+	backup := &srapi.StarRocksBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			Labels:            map[string]string{srapi.BackupScheduleLabelKey: "daily"},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: srapi.StarRocksBackupSpec{
+			StarRocksCluster: "kube-starrocks",
+			Repository:       srapi.BackupRepository{Name: "repo"},
+			Database:         database,
+		},
+		Status: srapi.StarRocksBackupStatus{Phase: phase, SnapshotName: "snapshot_" + name[len(name)-1:]},
+	}
+	if phase.IsFinished() {
+		completionTime := metav1.NewTime(created.Add(time.Minute))
+		backup.Status.CompletionTime = &completionTime
+	}
+	return backup
+}
+
+func TestReconcileScheduleCreatesBackups(t *testing.T) {
+	schedule := newBackupSchedule()
+	fakeClock := testingclock.NewFakeClock(time.Date(2024, 1, 1, 10, 59, 0, 0, time.UTC))
+	r := &StarRocksBackupScheduleReconciler{
+		Client:   fake.NewFakeClient(srapi.Scheme, schedule),
+		Recorder: record.NewFakeRecorder(10),
+		Clock:    fakeClock,
+	}
+
+	// the schedule time is not reached.
+	result, err := r.reconcileSchedule(context.Background(), schedule, nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, result.RequeueAfter)
+	require.Nil(t, schedule.Status.LastScheduleTime)
+	require.Equal(t, time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC), schedule.Status.NextScheduleTime.Time)
+
+	// the backups of 12:00 are created, and the missed schedule time 11:00 is skipped.
+	fakeClock.SetTime(time.Date(2024, 1, 1, 12, 0, 5, 0, time.UTC))
+	result, err = r.reconcileSchedule(context.Background(), schedule, nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour-5*time.Second, result.RequeueAfter)
+	require.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), schedule.Status.LastScheduleTime.Time)
+
+	var backups srapi.StarRocksBackupList
+	require.NoError(t, r.Client.List(context.Background(), &backups, client.InNamespace("default")))
+	require.Len(t, backups.Items, 2)
+	for _, backup := range backups.Items {
+		require.Equal(t, "daily", backup.Labels[srapi.BackupScheduleLabelKey])
+		require.Equal(t, "daily", backup.OwnerReferences[0].Name)
+		require.Equal(t, "repo", backup.Spec.Repository.Name)
+	}
+	require.Equal(t, "daily-db-2-1704110400", backups.Items[0].Name)
+	require.Equal(t, "daily_db_2_1704110400", backups.Items[0].Spec.SnapshotName)
+	require.Equal(t, "DB_2", backups.Items[0].Spec.Database)
+	require.Equal(t, "daily-db1-1704110400", backups.Items[1].Name)
+
+	// the targets whose backups are not finished are skipped.
+	fakeClock.SetTime(time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC))
+	_, err = r.reconcileSchedule(context.Background(), schedule, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"daily-db-2-1704110400", "daily-db1-1704110400"}, schedule.Status.Active)
+	require.NoError(t, r.Client.List(context.Background(), &backups, client.InNamespace("default")))
+	require.Len(t, backups.Items, 2)
+}
+
+func TestReconcileScheduleSuspendedOrInvalid(t *testing.T) {
+	schedule := newBackupSchedule()
+	schedule.Spec.Suspend = true
+	r := &StarRocksBackupScheduleReconciler{
+		Client:   fake.NewFakeClient(srapi.Scheme, schedule),
+		Recorder: record.NewFakeRecorder(10),
+		Clock:    testingclock.NewFakeClock(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+	}
+	result, err := r.reconcileSchedule(context.Background(), schedule, nil)
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+	require.Nil(t, schedule.Status.NextScheduleTime)
+
+	schedule.Spec.Suspend = false
+	schedule.Spec.Schedule = "0 25 * * *"
+	_, err = r.reconcileSchedule(context.Background(), schedule, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, schedule.Status.Reason)
+
+	var backups srapi.StarRocksBackupList
+	require.NoError(t, r.Client.List(context.Background(), &backups))
+	require.Empty(t, backups.Items)
+}
+
+func TestReconcileScheduleRecordsHistoryAndPrunes(t *testing.T) {
+	day := 24 * time.Hour
+	now := time.Date(2024, 1, 10, 0, 30, 0, 0, time.UTC)
+	schedule := newBackupSchedule()
+	schedule.Spec.Schedule = "0 0 * * *"
+	schedule.Spec.Retention = &srapi.BackupRetention{KeepLast: rutils.GetInt32Pointer(2)}
+	lastScheduleTime := metav1.NewTime(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))
+	schedule.Status.LastScheduleTime = &lastScheduleTime
+
+	objects := append(newReadyClusterObjects(), schedule,
+		newScheduledBackup("backup-1", "db1", now.Add(-4*day), srapi.BackupSucceeded),
+		newScheduledBackup("backup-2", "db1", now.Add(-3*day), srapi.BackupSucceeded),
+		newScheduledBackup("backup-3", "db1", now.Add(-2*day), srapi.BackupFailed),
+		newScheduledBackup("backup-4", "db1", now.Add(-day), srapi.BackupSucceeded),
+		newScheduledBackup("backup-5", "db1", now.Add(-time.Minute), srapi.BackupRunning),
+	)
+	recorder := record.NewFakeRecorder(10)
+	r := &StarRocksBackupScheduleReconciler{
+		Client:   fake.NewFakeClient(srapi.Scheme, objects...),
+		Recorder: recorder,
+		Clock:    testingclock.NewFakeClock(now),
+	}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("SHOW SNAPSHOT ON `repo` WHERE SNAPSHOT = 'snapshot_1'").
+		WillReturnRows(sqlmock.NewRows([]string{"Snapshot", "Timestamp", "Status"}).
+			AddRow("snapshot_1", "2024-01-06-00-00-00-000", "OK"))
+	mock.ExpectExec("DROP SNAPSHOT ON `repo` WHERE SNAPSHOT = 'snapshot_1'").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = r.reconcileSchedule(context.Background(), schedule, db)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	status := schedule.Status
+	require.Equal(t, []string{"backup-5"}, status.Active)
+	require.Equal(t, "backup-4", status.LastSuccessfulBackup)
+	require.Equal(t, "backup-3", status.LastFailedBackup)
+
+	var backups srapi.StarRocksBackupList
+	require.NoError(t, r.Client.List(context.Background(), &backups, client.InNamespace("default")))
+	var names []string
+	for _, backup := range backups.Items {
+		names = append(names, backup.Name)
+	}
+	require.Equal(t, []string{"backup-2", "backup-3", "backup-4", "backup-5"}, names)
+}
+
+func TestExpiredBackups(t *testing.T) {
+	day := 24 * time.Hour
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	backups := []*srapi.StarRocksBackup{
+		newScheduledBackup("backup-1", "db1", now.Add(-10*day), srapi.BackupSucceeded),
+		newScheduledBackup("backup-2", "db1", now.Add(-5*day), srapi.BackupFailed),
+		newScheduledBackup("backup-3", "db1", now.Add(-day), srapi.BackupSucceeded),
+		newScheduledBackup("backup-4", "db2", now.Add(-10*day), srapi.BackupSucceeded),
+		newScheduledBackup("backup-5", "db2", now.Add(-9*day), srapi.BackupPending),
+	}
+	names := func(backups []*srapi.StarRocksBackup) []string {
+		var result []string
+		for _, backup := range backups {
+			result = append(result, backup.Name)
+		}
+		return result
+	}
+
+	require.Empty(t, expiredBackups(backups, nil, now))
+	require.Equal(t, []string{"backup-1"}, names(expiredBackups(backups, &srapi.BackupRetention{KeepLast: rutils.GetInt32Pointer(1)}, now)))
+	// the latest succeeded backup of db2 is kept, and the pending backup is not pruned.
+	require.Equal(t, []string{"backup-2", "backup-1"},
+		names(expiredBackups(backups, &srapi.BackupRetention{KeepDays: rutils.GetInt32Pointer(3)}, now)))
+}
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -34,9 +35,22 @@ import (
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/be"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/cn"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/feobserver"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/feproxy"
 )
 
+// rollingUpdateRequeueInterval is the interval to check the progress of the leader-aware rolling update of FE.
+const rollingUpdateRequeueInterval = 10 * time.Second
+
+// volumeExpansionRequeueInterval is the interval to check the progress of expanding the persistent volume claims.
+const volumeExpansionRequeueInterval = 30 * time.Second
+
+// frontendRolesRequeueInterval is the interval to sync the role labels of FE pods when the leader service is enabled.
+const frontendRolesRequeueInterval = 30 * time.Second
+
+// disasterRecoveryRequeueInterval is the interval to check whether the disaster recovery is done or timed out.
+const disasterRecoveryRequeueInterval = 30 * time.Second
+
 // StarRocksClusterReconciler reconciles a StarRocksCluster object
 type StarRocksClusterReconciler struct {
 	client.Client
@@ -48,16 +62,18 @@ type StarRocksClusterReconciler struct {
 // +kubebuilder:rbac:groups=starrocks.com,resources=starrocksclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=starrocks.com,resources=starrocksclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=starrocks.com,resources=starrocksclusters/finalizers,verbs=update
-// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="core",resources=endpoints,verbs=get;watch;list
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;patch;delete;deletecollection
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -84,7 +100,17 @@ func (r *StarRocksClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	// reconcile src deleted
 	if !src.DeletionTimestamp.IsZero() {
 		logger.Info("deletion timestamp is not zero, clear StarRocksCluster related resources")
-		return ctrl.Result{}, nil
+		return r.reconcileDeletion(ctx, src)
+	}
+
+	if err = r.addFinalizer(ctx, src); err != nil {
+		logger.Error(err, "add finalizer to StarRocksCluster failed")
+		return requeueIfError(err)
+	}
+
+	if err = r.reconcileSuspend(ctx, src); err != nil {
+		logger.Error(err, "suspend or resume StarRocksCluster failed")
+		return requeueIfError(err)
 	}
 
 	// subControllers reconcile for create or update component.
@@ -101,6 +127,11 @@ func (r *StarRocksClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}
 
+	rootPasswordErr := r.syncRootPassword(ctx, src, nil)
+	if rootPasswordErr != nil {
+		logger.Error(rootPasswordErr, "sync the password of root failed")
+	}
+
 	for _, rc := range r.Scs {
 		kvs := []interface{}{"subController", rc.GetControllerName()}
 		logger.Info("sub controller update status", kvs...)
@@ -122,6 +153,33 @@ func (r *StarRocksClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 	logger.Info("reconcile StarRocksCluster success")
+	if suspendStatus := src.Status.SuspendStatus; suspendStatus != nil && suspendStatus.Phase != srapi.SuspendPhaseSuspended {
+		// the components are scaled to zero or scaled back one by one, check whether the current one is finished.
+		return ctrl.Result{RequeueAfter: suspendRequeueInterval}, nil
+	}
+	if feStatus := src.Status.StarRocksFeStatus; feStatus != nil && feStatus.RollingUpdate != nil {
+		// the FE pods are updated one by one, check whether the updated FE has rejoined the cluster periodically.
+		return ctrl.Result{RequeueAfter: rollingUpdateRequeueInterval}, nil
+	}
+	if isInDisasterRecovery(src) {
+		// FE pod becomes ready without any change of the statefulset, check the progress periodically.
+		return ctrl.Result{RequeueAfter: disasterRecoveryRequeueInterval}, nil
+	}
+	if be.IsDecommissioning(src) {
+		// the tablets are migrated by FE without any change of kubernetes resources, check the progress periodically.
+		return ctrl.Result{RequeueAfter: be.DecommissionRequeueInterval}, nil
+	}
+	if isExpandingVolumes(src) {
+		// the persistent volume claims are resized asynchronously, check the progress periodically.
+		return ctrl.Result{RequeueAfter: volumeExpansionRequeueInterval}, nil
+	}
+	if feSpec := src.Spec.StarRocksFeSpec; feSpec != nil && feSpec.EnableLeaderService {
+		// the leader may change without any pod event, sync the role labels of FE pods periodically.
+		return ctrl.Result{RequeueAfter: frontendRolesRequeueInterval}, nil
+	}
+	if rootPasswordErr != nil {
+		return ctrl.Result{RequeueAfter: rootPasswordRequeueInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -140,8 +198,19 @@ func (r *StarRocksClusterReconciler) UpdateStarRocksClusterStatus(ctx context.Co
 }
 
 func (r *StarRocksClusterReconciler) reconcileStatus(_ context.Context, src *srapi.StarRocksCluster) {
+	reconcileClusterConditions(src)
+
 	src.Status.Phase = srapi.ClusterRunning
 	src.Status.Reason = ""
+	if suspendStatus := src.Status.SuspendStatus; suspendStatus != nil {
+		if suspendStatus.Phase == srapi.SuspendPhaseSuspended {
+			src.Status.Phase = srapi.ClusterSuspended
+		} else {
+			src.Status.Phase = srapi.ClusterReconciling
+			src.Status.Reason = suspendStatus.Reason
+		}
+		return
+	}
 	var phase srapi.Phase
 	if src.Status.StarRocksFeStatus != nil {
 		phase = GetPhaseFromComponent(&src.Status.StarRocksFeStatus.StarRocksComponentStatus)
@@ -166,12 +235,38 @@ func (r *StarRocksClusterReconciler) reconcileStatus(_ context.Context, src *sra
 	}
 }
 
+// isExpandingVolumes returns true if the persistent volume claims of any component are being expanded.
+func isExpandingVolumes(src *srapi.StarRocksCluster) bool {
+	status := &src.Status
+	var statuses []*srapi.StarRocksComponentStatus
+	if status.StarRocksFeStatus != nil {
+		statuses = append(statuses, &status.StarRocksFeStatus.StarRocksComponentStatus)
+	}
+	if status.StarRocksBeStatus != nil {
+		statuses = append(statuses, &status.StarRocksBeStatus.StarRocksComponentStatus)
+	}
+	if status.StarRocksCnStatus != nil {
+		statuses = append(statuses, &status.StarRocksCnStatus.StarRocksComponentStatus)
+	}
+	for i := range status.StarRocksFeObserverStatus {
+		statuses = append(statuses, &status.StarRocksFeObserverStatus[i].StarRocksComponentStatus)
+	}
+	for _, s := range statuses {
+		if s.VolumeExpansion != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // handleSyncClusterError handle errors from sub-controller, and log it in StarRocksCluster Status
 func handleSyncClusterError(src *srapi.StarRocksCluster, subController subcontrollers.ClusterSubController, err error) {
 	reason := err.Error()
 	switch subController.(type) {
 	case *fe.FeController:
 		reason = fmt.Sprintf("error from FE controller: %v", reason)
+	case *feobserver.FeObserverController:
+		reason = fmt.Sprintf("error from FE observer controller: %v", reason)
 	case *be.BeController:
 		reason = fmt.Sprintf("error from BE controller: %v", reason)
 	case *cn.CnController:
@@ -182,6 +277,7 @@ func handleSyncClusterError(src *srapi.StarRocksCluster, subController subcontro
 
 	src.Status.Phase = srapi.ClusterFailed
 	src.Status.Reason = reason
+	setSyncFailedCondition(&src.Status.Conditions, src.Generation, reason)
 }
 
 func requeueIfError(err error) (ctrl.Result, error) {
@@ -202,3 +298,10 @@ func GetPhaseFromComponent(componentStatus *srapi.StarRocksComponentStatus) srap
 	}
 	return ""
 }
+
+// isInDisasterRecovery returns true if the disaster recovery is requested and not finished.
+func isInDisasterRecovery(src *srapi.StarRocksCluster) bool {
+	drSpec := src.Spec.DisasterRecovery
+	drStatus := src.Status.DisasterRecoveryStatus
+	return drSpec != nil && drSpec.Enabled && drStatus != nil && !drStatus.Phase.IsFinished()
+}
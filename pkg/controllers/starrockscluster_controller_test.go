@@ -36,6 +36,7 @@ import (
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/be"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/cn"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/feobserver"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/feproxy"
 )
 
@@ -45,6 +46,7 @@ func newStarRocksClusterController(objects ...runtime.Object) *StarRocksClusterR
 	srcController.Client = fake.NewFakeClient(srapi.Scheme, objects...)
 	srcController.Scs = []subcontrollers.ClusterSubController{
 		fe.New(srcController.Client, fake.GetEventRecorderFor(nil)),
+		feobserver.New(srcController.Client, fake.GetEventRecorderFor(nil)),
 		be.New(srcController.Client, fake.GetEventRecorderFor(srcController.Recorder)),
 		cn.New(srcController.Client, fake.GetEventRecorderFor(nil)),
 		feproxy.New(srcController.Client, fake.GetEventRecorderFor(nil)),
@@ -0,0 +1,293 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/statefulset"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+// StarRocksClusterFinalizer is added to StarRocksCluster, so that the operator can execute the deletion policy and
+// clear the resources of components before the StarRocksCluster is removed from kubernetes.
+const StarRocksClusterFinalizer = "starrocks.com.starrockscluster/protection"
+
+// deletionRequeueInterval is the interval to check whether the steps of deletion policy are finished.
+const deletionRequeueInterval = 10 * time.Second
+
+// addFinalizer adds the finalizer to StarRocksCluster if it does not exist.
+func (r *StarRocksClusterReconciler) addFinalizer(ctx context.Context, src *srapi.StarRocksCluster) error {
+	if controllerutil.ContainsFinalizer(src, StarRocksClusterFinalizer) {
+		return nil
+	}
+	patch := client.MergeFrom(src.DeepCopy())
+	controllerutil.AddFinalizer(src, StarRocksClusterFinalizer)
+	return r.Client.Patch(ctx, src, patch)
+}
+
+// reconcileDeletion executes the deletion policy, and calls ClearCluster of sub controllers in reverse order. The
+// finalizer is removed after all the steps are finished.
+func (r *StarRocksClusterReconciler) reconcileDeletion(ctx context.Context, src *srapi.StarRocksCluster) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	if !controllerutil.ContainsFinalizer(src, StarRocksClusterFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	policy := src.Spec.DeletionPolicy
+	if policy != nil && policy.FinalSnapshot != nil {
+		finished, err := r.reconcileFinalSnapshot(ctx, src, policy.FinalSnapshot, nil)
+		if err != nil || !finished {
+			return r.waitForDeletion(ctx, src, "take final snapshot", err)
+		}
+	}
+
+	if policy != nil && policy.DropWarehouses {
+		finished, err := r.dropWarehouses(ctx, src)
+		if err != nil || !finished {
+			return r.waitForDeletion(ctx, src, "drop warehouses", err)
+		}
+	}
+
+	// clear the resources in reverse order, e.g. fe proxy, cn, be, and fe.
+	for i := len(r.Scs) - 1; i >= 0; i-- {
+		rc := r.Scs[i]
+		kvs := []interface{}{"subController", rc.GetControllerName()}
+		logger.Info("sub controller clear cluster", kvs...)
+		if err := rc.ClearCluster(ctx, src); err != nil {
+			logger.Error(err, "sub controller clears cluster failed", kvs...)
+			return r.waitForDeletion(ctx, src, fmt.Sprintf("clear resources by %s", rc.GetControllerName()), err)
+		}
+	}
+
+	if policy.GetPersistentVolumeClaims() == srapi.DeletePersistentVolumeClaims {
+		if err := r.deletePersistentVolumeClaims(ctx, src); err != nil {
+			return r.waitForDeletion(ctx, src, "delete persistent volume claims", err)
+		}
+	}
+
+	logger.Info("remove finalizer from StarRocksCluster")
+	patch := client.MergeFrom(src.DeepCopy())
+	controllerutil.RemoveFinalizer(src, StarRocksClusterFinalizer)
+	if err := r.Client.Patch(ctx, src, patch); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "remove finalizer failed")
+		return requeueIfError(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// waitForDeletion records the unfinished step of deletion in the status of StarRocksCluster, and requeue the request.
+func (r *StarRocksClusterReconciler) waitForDeletion(ctx context.Context, src *srapi.StarRocksCluster,
+	step string, err error) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	src.Status.Phase = srapi.ClusterDeleting
+	if err != nil {
+		logger.Error(err, "failed to execute deletion step", "step", step)
+		src.Status.Reason = fmt.Sprintf("failed to %s: %v", step, err)
+		r.Recorder.Event(src, corev1.EventTypeWarning, "DeleteClusterFailed", src.Status.Reason)
+	} else {
+		logger.Info("waiting for deletion step to finish", "step", step)
+		src.Status.Reason = fmt.Sprintf("waiting for the step to finish: %s", step)
+	}
+	if updateError := r.UpdateStarRocksClusterStatus(ctx, src); updateError != nil {
+		logger.Error(updateError, "failed to update StarRocksCluster Status")
+	}
+	return ctrl.Result{RequeueAfter: deletionRequeueInterval}, nil
+}
+
+// reconcileFinalSnapshot takes the final snapshot, and returns true when the snapshot is finished or skipped.
+// The snapshot can not be taken when the cluster is suspended, because FE and BE are scaled to zero and the operator
+// does not resume a deleted cluster. When the snapshot fails, times out, or the cluster is suspended, the deletion is
+// blocked by default, and continues if FinalSnapshot.OnFailure is Continue.
+func (r *StarRocksClusterReconciler) reconcileFinalSnapshot(ctx context.Context, src *srapi.StarRocksCluster,
+	finalSnapshot *srapi.FinalSnapshot, db *sql.DB) (bool, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	var err error
+	finished := false
+	if src.Status.SuspendStatus != nil {
+		err = fmt.Errorf("the cluster is suspended, FE and BE are not running")
+	} else {
+		finished, err = r.takeFinalSnapshot(ctx, src, finalSnapshot, db)
+		if err == nil && !finished && finalSnapshot.Timeout != nil && src.DeletionTimestamp != nil &&
+			time.Since(src.DeletionTimestamp.Time) > finalSnapshot.Timeout.Duration {
+			err = fmt.Errorf("the backup is not finished in %s", finalSnapshot.Timeout.Duration)
+		}
+	}
+	if err == nil || finalSnapshot.GetOnFailure() != srapi.ContinueOnFinalSnapshotFailure {
+		return finished, err
+	}
+
+	logger.Error(err, "skip the final snapshot")
+	r.Recorder.Event(src, corev1.EventTypeWarning, "SkipFinalSnapshot",
+		fmt.Sprintf("skip the final snapshot %s: %v", finalSnapshotName(src), err))
+	return true, nil
+}
+
+// takeFinalSnapshot backs up the databases by BACKUP SNAPSHOT, and returns true when all the backups are finished.
+// The snapshot name is generated from the name and deletion timestamp of StarRocksCluster, so that we can find the
+// backup job by SHOW BACKUP in the next reconciliation.
+func (r *StarRocksClusterReconciler) takeFinalSnapshot(ctx context.Context, src *srapi.StarRocksCluster,
+	finalSnapshot *srapi.FinalSnapshot, db *sql.DB) (bool, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	sqlClient, err := fe.NewSQLClient(ctx, r.Client, src)
+	if err != nil {
+		return false, err
+	}
+
+	snapshotName := finalSnapshotName(src)
+	finished := true
+	for _, name := range finalSnapshot.Databases {
+		database := string(name)
+		if err = checkSnapshotNames(database, snapshotName, finalSnapshot.Repository, nil); err != nil {
+			return false, err
+		}
+		job, err := queryLatestBackup(ctx, sqlClient, db, database)
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case job != nil && job.SnapshotName == snapshotName && job.State == backupStateFinished:
+			continue
+		case job != nil && job.SnapshotName == snapshotName && job.State == backupStateCancelled:
+			return false, fmt.Errorf("backup %s of database %s is cancelled: %s", snapshotName, database, job.Status)
+		case job != nil && job.SnapshotName == snapshotName:
+			finished = false
+		case job != nil && job.State != backupStateFinished && job.State != backupStateCancelled:
+			// only one backup job can be running in a database, wait for the other job to finish.
+			finished = false
+		default:
+			logger.Info("backup database before StarRocksCluster is deleted", "database", database, "snapshot", snapshotName)
+			statement := backupStatement(database, snapshotName, finalSnapshot.Repository, nil)
+			if err = sqlClient.ExecuteContext(ctx, db, statement); err != nil {
+				return false, err
+			}
+			r.Recorder.Event(src, corev1.EventTypeNormal, "BackupDatabase",
+				fmt.Sprintf("backup database %s to snapshot %s in repository %s", database, snapshotName, finalSnapshot.Repository))
+			finished = false
+		}
+	}
+	return finished, nil
+}
+
+// finalSnapshotName returns the snapshot name of the final snapshot, e.g. kube_starrocks_final_1700000000.
+func finalSnapshotName(src *srapi.StarRocksCluster) string {
+	var timestamp int64
+	if src.DeletionTimestamp != nil {
+		timestamp = src.DeletionTimestamp.Unix()
+	}
+	return fmt.Sprintf("%s_final_%d", toSnapshotName(src.Name), timestamp)
+}
+
+// dropWarehouses deletes the StarRocksWarehouse objects which belong to the StarRocksCluster, and returns true
+// when the statefulsets of the warehouses are removed. The statefulset of a warehouse is protected by a finalizer,
+// which is removed after the warehouse is dropped from StarRocks.
+func (r *StarRocksClusterReconciler) dropWarehouses(ctx context.Context, src *srapi.StarRocksCluster) (bool, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	var warehouses srapi.StarRocksWarehouseList
+	if err := r.Client.List(ctx, &warehouses, client.InNamespace(src.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			// StarRocksWarehouse CRD is not installed.
+			return true, nil
+		}
+		return false, err
+	}
+	for i := range warehouses.Items {
+		warehouse := &warehouses.Items[i]
+		if warehouse.Spec.StarRocksCluster != src.Name || !warehouse.DeletionTimestamp.IsZero() {
+			continue
+		}
+		logger.Info("delete warehouse before StarRocksCluster is deleted", "warehouse", warehouse.Name)
+		if err := r.Client.Delete(ctx, warehouse); err != nil && !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		r.Recorder.Event(src, corev1.EventTypeNormal, "DeleteWarehouse", fmt.Sprintf("delete warehouse %s", warehouse.Name))
+	}
+
+	// the CN of warehouse connects to the FE of StarRocksCluster by the environment variable FE_SERVICE_NAME.
+	feServiceName := service.ExternalServiceName(src.Name, src.Spec.StarRocksFeSpec)
+	var statefulSets appsv1.StatefulSetList
+	if err := r.Client.List(ctx, &statefulSets, client.InNamespace(src.Namespace)); err != nil {
+		return false, err
+	}
+	for i := range statefulSets.Items {
+		sts := &statefulSets.Items[i]
+		if !controllerutil.ContainsFinalizer(sts, statefulset.STARROCKS_WAREHOUSE_FINALIZER) ||
+			len(sts.Spec.Template.Spec.Containers) == 0 {
+			continue
+		}
+		for _, envVar := range sts.Spec.Template.Spec.Containers[0].Env {
+			if envVar.Name == srapi.FE_SERVICE_NAME && envVar.Value == feServiceName {
+				logger.Info("waiting for warehouse to be dropped", "statefulset", sts.Name)
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// deletePersistentVolumeClaims deletes the persistent volume claims of FE, BE, CN and FE observer groups. The
+// persistent volume claims created by statefulset have the same labels as the selector of statefulset.
+func (r *StarRocksClusterReconciler) deletePersistentVolumeClaims(ctx context.Context, src *srapi.StarRocksCluster) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	selectors := []map[string]string{
+		load.Selector(src.Name, (*srapi.StarRocksFeSpec)(nil)),
+		load.Selector(src.Name, (*srapi.StarRocksBeSpec)(nil)),
+		load.Selector(src.Name, (*srapi.StarRocksCnSpec)(nil)),
+	}
+	// the groups deleted from the spec are still in the status until their observers are dropped.
+	groups := map[string]bool{}
+	if src.Spec.StarRocksFeSpec != nil {
+		for _, group := range src.Spec.StarRocksFeSpec.ObserverGroups {
+			groups[group.Name] = true
+		}
+	}
+	for _, groupStatus := range src.Status.StarRocksFeObserverStatus {
+		groups[groupStatus.Name] = true
+	}
+	for group := range groups {
+		selectors = append(selectors,
+			load.Selector(object.GetPrefixNameForObserverGroup(src.Name, group), (*srapi.StarRocksFeSpec)(nil)))
+	}
+
+	for _, selector := range selectors {
+		logger.Info("delete persistent volume claims", "selector", selector)
+		if err := r.Client.DeleteAllOf(ctx, &corev1.PersistentVolumeClaim{},
+			client.InNamespace(src.Namespace), client.MatchingLabels(selector)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/statefulset"
+)
+
+func newDeletingCluster(policy *srapi.DeletionPolicy) *srapi.StarRocksCluster {
+	now := metav1.NewTime(time.Unix(1700000000, 0))
+	return &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "kube-starrocks",
+			Namespace:         "default",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{StarRocksClusterFinalizer},
+		},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{},
+			StarRocksBeSpec: &srapi.StarRocksBeSpec{},
+			DeletionPolicy:  policy,
+		},
+		Status: srapi.StarRocksClusterStatus{
+			StarRocksFeStatus: &srapi.StarRocksFeStatus{},
+		},
+	}
+}
+
+func newStatefulSet(name string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: name}}},
+			},
+		},
+	}
+}
+
+func newPersistentVolumeClaim(name string, labels map[string]string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+	}
+}
+
+func TestReconcileAddFinalizer(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+		Spec:       srapi.StarRocksClusterSpec{StarRocksFeSpec: &srapi.StarRocksFeSpec{}},
+	}
+	r := newStarRocksClusterController(src)
+	_, err := r.Reconcile(context.Background(),
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "kube-starrocks"}})
+	require.NoError(t, err)
+
+	var actual srapi.StarRocksCluster
+	require.NoError(t, r.Client.Get(context.Background(), client.ObjectKeyFromObject(src), &actual))
+	require.True(t, controllerutil.ContainsFinalizer(&actual, StarRocksClusterFinalizer))
+}
+
+func TestReconcileDeletion(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     *srapi.DeletionPolicy
+		wantPVCNum int
+	}{
+		{
+			name:       "retain persistent volume claims by default",
+			policy:     nil,
+			wantPVCNum: 4,
+		},
+		{
+			name:       "delete persistent volume claims",
+			policy:     &srapi.DeletionPolicy{PersistentVolumeClaims: srapi.DeletePersistentVolumeClaims},
+			wantPVCNum: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newDeletingCluster(tt.policy)
+			// the group old is deleted from the spec, but its observers have not been dropped.
+			src.Spec.StarRocksFeSpec.ObserverGroups = []srapi.StarRocksFeObserverGroup{{Name: "read"}}
+			src.Status.StarRocksFeObserverStatus = []srapi.StarRocksFeObserverGroupStatus{{Name: "read"}, {Name: "old"}}
+			r := newStarRocksClusterController(src,
+				newStatefulSet("kube-starrocks-fe"),
+				newStatefulSet("kube-starrocks-be"),
+				newPersistentVolumeClaim("fe-meta-kube-starrocks-fe-0", load.Selector(src.Name, src.Spec.StarRocksFeSpec)),
+				newPersistentVolumeClaim("be-data-kube-starrocks-be-0", load.Selector(src.Name, src.Spec.StarRocksBeSpec)),
+				newPersistentVolumeClaim("fe-meta-kube-starrocks-read-observer-fe-0",
+					load.Selector("kube-starrocks-read-observer", src.Spec.StarRocksFeSpec)),
+				newPersistentVolumeClaim("fe-meta-kube-starrocks-old-observer-fe-0",
+					load.Selector("kube-starrocks-old-observer", src.Spec.StarRocksFeSpec)),
+			)
+			res, err := r.Reconcile(context.Background(),
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "kube-starrocks"}})
+			require.NoError(t, err)
+			require.Equal(t, reconcile.Result{}, res)
+
+			for _, name := range []string{"kube-starrocks-fe", "kube-starrocks-be"} {
+				var sts appsv1.StatefulSet
+				err = r.Client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, &sts)
+				require.True(t, apierrors.IsNotFound(err))
+			}
+
+			var pvcs corev1.PersistentVolumeClaimList
+			require.NoError(t, r.Client.List(context.Background(), &pvcs))
+			require.Len(t, pvcs.Items, tt.wantPVCNum)
+
+			var actual srapi.StarRocksCluster
+			err = r.Client.Get(context.Background(), client.ObjectKeyFromObject(src), &actual)
+			if err == nil {
+				require.False(t, controllerutil.ContainsFinalizer(&actual, StarRocksClusterFinalizer))
+			}
+		})
+	}
+}
+
+func TestDropWarehouses(t *testing.T) {
+	src := newDeletingCluster(&srapi.DeletionPolicy{DropWarehouses: true})
+	warehouse := &srapi.StarRocksWarehouse{
+		ObjectMeta: metav1.ObjectMeta{Name: "wh1", Namespace: "default"},
+		Spec:       srapi.StarRocksWarehouseSpec{StarRocksCluster: src.Name},
+	}
+	otherWarehouse := &srapi.StarRocksWarehouse{
+		ObjectMeta: metav1.ObjectMeta{Name: "wh2", Namespace: "default"},
+		Spec:       srapi.StarRocksWarehouseSpec{StarRocksCluster: "other"},
+	}
+	warehouseSTS := newStatefulSet("wh1-warehouse-cn")
+	warehouseSTS.Finalizers = []string{statefulset.STARROCKS_WAREHOUSE_FINALIZER}
+	warehouseSTS.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+		{Name: srapi.FE_SERVICE_NAME, Value: "kube-starrocks-fe-service"},
+	}
+
+	r := newStarRocksClusterController(src, warehouse, otherWarehouse, warehouseSTS)
+	finished, err := r.dropWarehouses(context.Background(), src)
+	require.NoError(t, err)
+	require.False(t, finished)
+
+	var warehouses srapi.StarRocksWarehouseList
+	require.NoError(t, r.Client.List(context.Background(), &warehouses))
+	require.Len(t, warehouses.Items, 1)
+	require.Equal(t, "wh2", warehouses.Items[0].Name)
+
+	// the finalizer of statefulset is removed after the warehouse is dropped from StarRocks.
+	warehouseSTS.Finalizers = nil
+	require.NoError(t, r.Client.Update(context.Background(), warehouseSTS))
+	finished, err = r.dropWarehouses(context.Background(), src)
+	require.NoError(t, err)
+	require.True(t, finished)
+}
+
+func TestTakeFinalSnapshot(t *testing.T) {
+	columns := []string{"JobId", "SnapshotName", "DbName", "State", "Status"}
+	tests := []struct {
+		name         string
+		database     srapi.DatabaseName
+		mockSQL      func(mock sqlmock.Sqlmock)
+		wantFinished bool
+		wantErr      bool
+	}{
+		{
+			name: "start backup",
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectExec("BACKUP SNAPSHOT `db1`.`kube_starrocks_final_1700000000` TO `repo`").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantFinished: false,
+		},
+		{
+			name: "backup is running",
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("1", "kube_starrocks_final_1700000000", "db1", "UPLOADING", "[OK]"))
+			},
+			wantFinished: false,
+		},
+		{
+			name: "backup is finished",
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("1", "kube_starrocks_final_1700000000", "db1", "FINISHED", "[OK]"))
+			},
+			wantFinished: true,
+		},
+		{
+			name: "backup is cancelled",
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("1", "kube_starrocks_final_1700000000", "db1", "CANCELLED", "repository not found"))
+			},
+			wantFinished: false,
+			wantErr:      true,
+		},
+		{
+			name:         "invalid database",
+			database:     "db1`; DROP DATABASE db2; --",
+			mockSQL:      func(mock sqlmock.Sqlmock) {},
+			wantFinished: false,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			database := tt.database
+			if database == "" {
+				database = "db1"
+			}
+			src := newDeletingCluster(nil)
+			finalSnapshot := &srapi.FinalSnapshot{Repository: "repo", Databases: []srapi.DatabaseName{database}}
+			r := newStarRocksClusterController(src, newStatefulSet("kube-starrocks-fe"))
+			finished, err := r.takeFinalSnapshot(context.Background(), src, finalSnapshot, db)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantFinished, finished)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReconcileFinalSnapshot(t *testing.T) {
+	columns := []string{"JobId", "SnapshotName", "DbName", "State", "Status"}
+	running := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("1", "kube_starrocks_final_1700000000", "db1", "UPLOADING", "[OK]"))
+	}
+	cancelled := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SHOW BACKUP FROM `db1`").WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("1", "kube_starrocks_final_1700000000", "db1", "CANCELLED", "repository not found"))
+	}
+	tests := []struct {
+		name         string
+		suspended    bool
+		timeout      *metav1.Duration
+		onFailure    srapi.FinalSnapshotFailurePolicy
+		mockSQL      func(mock sqlmock.Sqlmock)
+		wantFinished bool
+		wantErr      bool
+	}{
+		{
+			name:         "wait for the backup within the timeout",
+			timeout:      &metav1.Duration{Duration: 100000 * time.Hour},
+			mockSQL:      running,
+			wantFinished: false,
+		},
+		{
+			name:    "block the deletion when the backup times out",
+			timeout: &metav1.Duration{Duration: time.Hour},
+			mockSQL: running,
+			wantErr: true,
+		},
+		{
+			name:         "continue the deletion when the backup times out",
+			timeout:      &metav1.Duration{Duration: time.Hour},
+			onFailure:    srapi.ContinueOnFinalSnapshotFailure,
+			mockSQL:      running,
+			wantFinished: true,
+		},
+		{
+			name:         "continue the deletion when the backup is cancelled",
+			onFailure:    srapi.ContinueOnFinalSnapshotFailure,
+			mockSQL:      cancelled,
+			wantFinished: true,
+		},
+		{
+			name:      "block the deletion when the cluster is suspended",
+			suspended: true,
+			mockSQL:   func(mock sqlmock.Sqlmock) {},
+			wantErr:   true,
+		},
+		{
+			name:         "continue the deletion when the cluster is suspended",
+			suspended:    true,
+			onFailure:    srapi.ContinueOnFinalSnapshotFailure,
+			mockSQL:      func(mock sqlmock.Sqlmock) {},
+			wantFinished: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			src := newDeletingCluster(nil)
+			if tt.suspended {
+				src.Status.SuspendStatus = &srapi.SuspendStatus{Phase: srapi.SuspendPhaseSuspended}
+			}
+			finalSnapshot := &srapi.FinalSnapshot{
+				Repository: "repo",
+				Databases:  []srapi.DatabaseName{"db1"},
+				Timeout:    tt.timeout,
+				OnFailure:  tt.onFailure,
+			}
+			r := newStarRocksClusterController(src, newStatefulSet("kube-starrocks-fe"))
+			finished, err := r.reconcileFinalSnapshot(context.Background(), src, finalSnapshot, db)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantFinished, finished)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-sql-driver/mysql"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+// rootPasswordRequeueInterval is the interval to retry setting the root password after it failed.
+const rootPasswordRequeueInterval = 30 * time.Second
+
+// mysqlAccessDenied is the error number of MySQL when the password is wrong.
+const mysqlAccessDenied = 1045
+
+// syncRootPassword sets the password of root to the one in spec.rootPasswordSecretRef once FE is ready, and records
+// the applied password by fe.SaveRootPassword. When the secret is changed, the password is changed again by the
+// applied one.
+func (r *StarRocksClusterReconciler) syncRootPassword(ctx context.Context, src *srapi.StarRocksCluster, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	ref := src.Spec.RootPasswordSecretRef
+	if ref == nil || src.Spec.Suspend || !fe.CheckFEReady(ctx, r.Client, src.Namespace, src.Name) {
+		return nil
+	}
+
+	desired, err := k8sutils.GetValueFromSecret(ctx, r.Client, src.Namespace, ref.Name, ref.Key)
+	if err != nil {
+		return err
+	} else if desired == "" {
+		return fmt.Errorf("the root password in secret %s is empty", ref.Name)
+	}
+
+	var applied corev1.Secret
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: src.Namespace, Name: fe.RootPasswordSecretName(src.Name)}, &applied)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	initialized := err == nil
+	if initialized && string(applied.Data[fe.RootPasswordKey]) == desired {
+		return nil
+	}
+
+	// the client connects to FE by the applied password, or MYSQL_PWD of FE if no password has been applied.
+	sqlClient, err := fe.NewSQLClient(ctx, r.Client, src)
+	if err != nil {
+		return err
+	}
+	logger.Info("change the password of root")
+	statement := alterUserStatement(userIdentity("root", "%"), authenticationClause(desired, nil))
+	if err = sqlClient.ExecuteContext(ctx, db, statement); err != nil {
+		var mysqlErr *mysql.MySQLError
+		if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlAccessDenied {
+			r.Recorder.Event(src, corev1.EventTypeWarning, "ChangeRootPasswordFailed", err.Error())
+			return err
+		}
+		// the password may have been changed to the desired one, e.g. the operator failed to record it last time.
+		sqlClient.RootPassword = desired
+		if err = sqlClient.ExecuteContext(ctx, db, statement); err != nil {
+			r.Recorder.Event(src, corev1.EventTypeWarning, "ChangeRootPasswordFailed", err.Error())
+			return err
+		}
+	}
+	if err = fe.SaveRootPassword(ctx, r.Client, src, desired); err != nil {
+		return err
+	}
+
+	if initialized {
+		r.Recorder.Event(src, corev1.EventTypeNormal, "RootPasswordChanged",
+			fmt.Sprintf("the password of root is changed by secret %s", ref.Name))
+	} else {
+		r.Recorder.Event(src, corev1.EventTypeNormal, "RootPasswordInitialized",
+			fmt.Sprintf("the password of root is set by secret %s", ref.Name))
+	}
+	return nil
+}
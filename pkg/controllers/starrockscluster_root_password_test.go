@@ -0,0 +1,137 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+func TestSyncRootPassword(t *testing.T) {
+	rootCredential := func(password string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rootcredential", Namespace: "default"},
+			Data:       map[string][]byte{"password": []byte(password)},
+		}
+	}
+	appliedPassword := func(password string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks-root-password", Namespace: "default"},
+			Data:       map[string][]byte{"password": []byte(password)},
+		}
+	}
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		mockSQL     func(mock sqlmock.Sqlmock)
+		wantErr     bool
+		wantApplied string
+	}{
+		{
+			name:    "FE is not ready",
+			objects: []runtime.Object{rootCredential("pw1")},
+			mockSQL: func(mock sqlmock.Sqlmock) {},
+		},
+		{
+			name:    "initialize the password",
+			objects: append(newReadyClusterObjects(), rootCredential("pw1")),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("ALTER USER 'root'@'%' IDENTIFIED BY 'pw1'").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantApplied: "pw1",
+		},
+		{
+			name:        "the password is not changed",
+			objects:     append(newReadyClusterObjects(), rootCredential("pw1"), appliedPassword("pw1")),
+			mockSQL:     func(mock sqlmock.Sqlmock) {},
+			wantApplied: "pw1",
+		},
+		{
+			name:    "rotate the password",
+			objects: append(newReadyClusterObjects(), rootCredential("pw2"), appliedPassword("pw1")),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("ALTER USER 'root'@'%' IDENTIFIED BY 'pw2'").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantApplied: "pw2",
+		},
+		{
+			name:    "the password has been changed but not recorded",
+			objects: append(newReadyClusterObjects(), rootCredential("pw2"), appliedPassword("pw1")),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("ALTER USER 'root'@'%' IDENTIFIED BY 'pw2'").
+					WillReturnError(&mysql.MySQLError{Number: mysqlAccessDenied, Message: "Access denied for user 'root'"})
+				mock.ExpectExec("ALTER USER 'root'@'%' IDENTIFIED BY 'pw2'").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantApplied: "pw2",
+		},
+		{
+			name:        "the password is empty",
+			objects:     append(newReadyClusterObjects(), rootCredential("")),
+			mockSQL:     func(mock sqlmock.Sqlmock) {},
+			wantErr:     true,
+			wantApplied: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			src := &srapi.StarRocksCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+				Spec: srapi.StarRocksClusterSpec{
+					StarRocksFeSpec: &srapi.StarRocksFeSpec{},
+					RootPasswordSecretRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "rootcredential"},
+						Key:                  "password",
+					},
+				},
+			}
+			k8sClient := fake.NewFakeClient(srapi.Scheme, tt.objects...)
+			r := &StarRocksClusterReconciler{Client: k8sClient, Recorder: record.NewFakeRecorder(10)}
+			err = r.syncRootPassword(context.Background(), src, db)
+			require.Equal(t, tt.wantErr, err != nil, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+
+			var applied corev1.Secret
+			err = k8sClient.Get(context.Background(),
+				types.NamespacedName{Namespace: "default", Name: fe.RootPasswordSecretName("kube-starrocks")}, &applied)
+			if tt.wantApplied == "" {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantApplied, string(applied.Data[fe.RootPasswordKey]))
+		})
+	}
+}
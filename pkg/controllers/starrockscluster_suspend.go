@@ -0,0 +1,291 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+// suspendRequeueInterval is the interval to check whether the components have been scaled to zero or scaled back.
+const suspendRequeueInterval = 10 * time.Second
+
+// suspendOrder is the order to scale the components to zero. The components which depend on FE are stopped first, and
+// FE is the last one.
+var suspendOrder = []string{
+	srapi.DEFAULT_CN, srapi.DEFAULT_BE, srapi.DEFAULT_FE_PROXY, srapi.FeObserverComponent, srapi.DEFAULT_FE,
+}
+
+// resumeSteps are the steps to scale the components back, the components in the same step are resumed together.
+var resumeSteps = [][]string{
+	{srapi.DEFAULT_FE, srapi.FeObserverComponent},
+	{srapi.DEFAULT_BE, srapi.DEFAULT_CN},
+	{srapi.DEFAULT_FE_PROXY},
+}
+
+// workloadReplicas is the replicas of a statefulset or deployment.
+type workloadReplicas struct {
+	// desired is the replicas in the spec.
+	desired int32
+	// current is the number of pods which have not been terminated.
+	current int32
+	// ready is the number of ready pods.
+	ready int32
+}
+
+// reconcileSuspend scales the components to zero one by one when spec.suspend is true, and scales them back when it
+// is changed to false. The sub controllers set the replicas of the components in status.suspendStatus to zero, so
+// this function only decides which component is the next one and records the progress.
+func (r *StarRocksClusterReconciler) reconcileSuspend(ctx context.Context, src *srapi.StarRocksCluster) error {
+	if src.Spec.Suspend {
+		return r.suspend(ctx, src)
+	}
+	if src.Status.SuspendStatus != nil {
+		return r.resume(ctx, src)
+	}
+	return nil
+}
+
+// suspend records the replicas before the cluster is suspended, and adds the next component to suspendedComponents
+// after the previous one has been scaled to zero.
+func (r *StarRocksClusterReconciler) suspend(ctx context.Context, src *srapi.StarRocksCluster) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	status := src.Status.SuspendStatus
+	if status == nil {
+		logger.Info("start to suspend StarRocksCluster")
+		status = &srapi.SuspendStatus{Replicas: make(map[string]int32)}
+		for _, component := range suspendOrder {
+			for _, name := range suspendWorkloads(src, component) {
+				replicas, err := r.getWorkloadReplicas(ctx, src.Namespace, component, name)
+				if err != nil {
+					return err
+				}
+				if replicas != nil {
+					status.Replicas[name] = replicas.desired
+				}
+			}
+		}
+		if cnStatus := src.Status.StarRocksCnStatus; cnStatus != nil && cnStatus.HorizontalScaler.Name != "" {
+			scaler := cnStatus.HorizontalScaler
+			status.HorizontalScaler = &scaler
+		}
+		src.Status.SuspendStatus = status
+		r.Recorder.Event(src, corev1.EventTypeNormal, "SuspendCluster", "start to suspend the cluster")
+	}
+	status.Phase = srapi.SuspendPhaseSuspending
+
+	for _, component := range suspendOrder {
+		names := suspendWorkloads(src, component)
+		if len(names) == 0 {
+			continue
+		}
+		if !src.IsComponentSuspended(component) {
+			logger.Info("scale component to zero", "component", component)
+			status.SuspendedComponents = append(status.SuspendedComponents, component)
+			status.Reason = fmt.Sprintf("waiting for %s to be scaled to zero", component)
+			r.Recorder.Event(src, corev1.EventTypeNormal, "SuspendComponent", fmt.Sprintf("scale %s to zero", component))
+			return nil
+		}
+		for _, name := range names {
+			replicas, err := r.getWorkloadReplicas(ctx, src.Namespace, component, name)
+			if err != nil {
+				return err
+			}
+			if replicas != nil && replicas.current != 0 {
+				logger.Info("waiting for component to be scaled to zero", "component", component, "name", name)
+				status.Reason = fmt.Sprintf("waiting for %s to be scaled to zero", name)
+				return nil
+			}
+		}
+	}
+
+	if status.Phase != srapi.SuspendPhaseSuspended {
+		r.Recorder.Event(src, corev1.EventTypeNormal, "ClusterSuspended", "all the components have been scaled to zero")
+	}
+	status.Phase = srapi.SuspendPhaseSuspended
+	status.Reason = ""
+	return nil
+}
+
+// resume removes the components from suspendedComponents step by step, and waits for them to be ready. The suspend
+// status is removed after all the components are resumed.
+func (r *StarRocksClusterReconciler) resume(ctx context.Context, src *srapi.StarRocksCluster) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	status := src.Status.SuspendStatus
+	if status.Phase != srapi.SuspendPhaseResuming {
+		logger.Info("start to resume StarRocksCluster")
+		r.Recorder.Event(src, corev1.EventTypeNormal, "ResumeCluster", "start to resume the cluster")
+	}
+	status.Phase = srapi.SuspendPhaseResuming
+
+	for _, step := range resumeSteps {
+		var resumed []string
+		for _, component := range step {
+			if src.IsComponentSuspended(component) {
+				resumed = append(resumed, component)
+			}
+		}
+		if len(resumed) != 0 {
+			logger.Info("scale components back", "components", resumed)
+			status.SuspendedComponents = removeComponents(status.SuspendedComponents, resumed)
+			status.Reason = fmt.Sprintf("waiting for %s to be ready", strings.Join(resumed, ", "))
+			r.Recorder.Event(src, corev1.EventTypeNormal, "ResumeComponent",
+				fmt.Sprintf("scale %s back", strings.Join(resumed, ", ")))
+			return nil
+		}
+
+		for _, component := range step {
+			ready, err := r.isComponentResumed(ctx, src, component)
+			if err != nil {
+				return err
+			}
+			if !ready {
+				logger.Info("waiting for component to be ready", "component", component)
+				status.Reason = fmt.Sprintf("waiting for %s to be ready", component)
+				return nil
+			}
+		}
+	}
+
+	logger.Info("StarRocksCluster has been resumed")
+	r.Recorder.Event(src, corev1.EventTypeNormal, "ClusterResumed", "all the components have been scaled back")
+	src.Status.SuspendStatus = nil
+	return nil
+}
+
+// isComponentResumed returns true if the component has been scaled back and its pods are ready. FE is resumed when
+// it can serve requests, so that BE and CN can register to it.
+func (r *StarRocksClusterReconciler) isComponentResumed(ctx context.Context, src *srapi.StarRocksCluster,
+	component string) (bool, error) {
+	names := suspendWorkloads(src, component)
+	if len(names) == 0 {
+		return true, nil
+	}
+	if component == srapi.DEFAULT_FE {
+		return fe.CheckFEReady(ctx, r.Client, src.Namespace, src.Name), nil
+	}
+	for _, name := range names {
+		replicas, err := r.getWorkloadReplicas(ctx, src.Namespace, component, name)
+		if err != nil {
+			return false, err
+		}
+		if replicas == nil || replicas.ready < replicas.desired {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// suspendWorkloads returns the names of the statefulsets or deployment of the component.
+func suspendWorkloads(src *srapi.StarRocksCluster, component string) []string {
+	feSpec := src.Spec.StarRocksFeSpec
+	switch component {
+	case srapi.DEFAULT_FE:
+		if feSpec != nil {
+			return []string{load.Name(src.Name, feSpec)}
+		}
+	case srapi.FeObserverComponent:
+		if feSpec != nil {
+			var names []string
+			for _, group := range feSpec.ObserverGroups {
+				names = append(names, load.Name(object.GetPrefixNameForObserverGroup(src.Name, group.Name), feSpec))
+			}
+			return names
+		}
+	case srapi.DEFAULT_BE:
+		if src.Spec.StarRocksBeSpec != nil {
+			return []string{load.Name(src.Name, src.Spec.StarRocksBeSpec)}
+		}
+	case srapi.DEFAULT_CN:
+		if src.Spec.StarRocksCnSpec != nil {
+			return []string{load.Name(src.Name, src.Spec.StarRocksCnSpec)}
+		}
+	case srapi.DEFAULT_FE_PROXY:
+		if src.Spec.StarRocksFeProxySpec != nil {
+			return []string{load.Name(src.Name, src.Spec.StarRocksFeProxySpec)}
+		}
+	}
+	return nil
+}
+
+// getWorkloadReplicas returns the replicas of the statefulset, or the deployment of FE proxy. It returns nil if the
+// workload does not exist.
+func (r *StarRocksClusterReconciler) getWorkloadReplicas(ctx context.Context, namespace string,
+	component string, name string) (*workloadReplicas, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	var replicas workloadReplicas
+	var specReplicas *int32
+	if component == srapi.DEFAULT_FE_PROXY {
+		var deployment appsv1.Deployment
+		if err := r.Client.Get(ctx, key, &deployment); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		specReplicas = deployment.Spec.Replicas
+		replicas.current = deployment.Status.Replicas
+		replicas.ready = deployment.Status.ReadyReplicas
+	} else {
+		var sts appsv1.StatefulSet
+		if err := r.Client.Get(ctx, key, &sts); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		specReplicas = sts.Spec.Replicas
+		replicas.current = sts.Status.Replicas
+		replicas.ready = sts.Status.ReadyReplicas
+	}
+	replicas.desired = 1
+	if specReplicas != nil {
+		replicas.desired = *specReplicas
+	}
+	return &replicas, nil
+}
+
+// removeComponents returns the components which are not in removed.
+func removeComponents(components []string, removed []string) []string {
+	var result []string
+	for _, component := range components {
+		found := false
+		for _, c := range removed {
+			if c == component {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, component)
+		}
+	}
+	return result
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+)
+
+func newWorkload(name string, replicas int32) *appsv1.StatefulSet {
+	sts := newStatefulSet(name)
+	sts.Spec.Replicas = rutils.GetInt32Pointer(replicas)
+	sts.Status.Replicas = replicas
+	sts.Status.ReadyReplicas = replicas
+	return sts
+}
+
+// setWorkloadStatus simulates the sub controllers and the statefulset controller, which scale the statefulset and
+// create or delete the pods.
+func setWorkloadStatus(t *testing.T, r *StarRocksClusterReconciler, name string, replicas int32, ready int32) {
+	var sts appsv1.StatefulSet
+	require.NoError(t, r.Client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, &sts))
+	sts.Spec.Replicas = rutils.GetInt32Pointer(replicas)
+	sts.Status.Replicas = ready
+	sts.Status.ReadyReplicas = ready
+	require.NoError(t, r.Client.Update(context.Background(), &sts))
+}
+
+func TestReconcileSuspend(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{},
+			StarRocksBeSpec: &srapi.StarRocksBeSpec{},
+			StarRocksCnSpec: &srapi.StarRocksCnSpec{},
+			Suspend:         true,
+		},
+		Status: srapi.StarRocksClusterStatus{
+			StarRocksCnStatus: &srapi.StarRocksCnStatus{
+				HorizontalScaler: srapi.HorizontalScaler{Name: "kube-starrocks-cn-autoscaler", Version: srapi.AutoScalerV2},
+			},
+		},
+	}
+	r := &StarRocksClusterReconciler{
+		Client: fake.NewFakeClient(srapi.Scheme,
+			newWorkload("kube-starrocks-fe", 3), newWorkload("kube-starrocks-be", 3), newWorkload("kube-starrocks-cn", 5)),
+		Recorder: record.NewFakeRecorder(100),
+	}
+	ctx := context.Background()
+
+	// suspend the components in order: cn, be, fe.
+	require.NoError(t, r.reconcileSuspend(ctx, src))
+	status := src.Status.SuspendStatus
+	require.NotNil(t, status)
+	require.Equal(t, srapi.SuspendPhaseSuspending, status.Phase)
+	require.Equal(t, map[string]int32{"kube-starrocks-fe": 3, "kube-starrocks-be": 3, "kube-starrocks-cn": 5}, status.Replicas)
+	require.Equal(t, "kube-starrocks-cn-autoscaler", status.HorizontalScaler.Name)
+	require.Equal(t, []string{srapi.DEFAULT_CN}, status.SuspendedComponents)
+
+	// be is not suspended until cn has been scaled to zero.
+	require.NoError(t, r.reconcileSuspend(ctx, src))
+	require.Equal(t, []string{srapi.DEFAULT_CN}, status.SuspendedComponents)
+	require.Contains(t, status.Reason, "kube-starrocks-cn")
+
+	setWorkloadStatus(t, r, "kube-starrocks-cn", 0, 0)
+	require.NoError(t, r.reconcileSuspend(ctx, src))
+	require.Equal(t, []string{srapi.DEFAULT_CN, srapi.DEFAULT_BE}, status.SuspendedComponents)
+
+	setWorkloadStatus(t, r, "kube-starrocks-be", 0, 0)
+	require.NoError(t, r.reconcileSuspend(ctx, src))
+	require.Equal(t, []string{srapi.DEFAULT_CN, srapi.DEFAULT_BE, srapi.DEFAULT_FE}, status.SuspendedComponents)
+	require.True(t, src.IsComponentSuspended(srapi.DEFAULT_FE))
+
+	setWorkloadStatus(t, r, "kube-starrocks-fe", 0, 0)
+	require.NoError(t, r.reconcileSuspend(ctx, src))
+	require.Equal(t, srapi.SuspendPhaseSuspended, status.Phase)
+	require.Empty(t, status.Reason)
+
+	// resume the components in order: fe, be and cn.
+	src.Spec.Suspend = false
+	require.NoError(t, r.reconcileSuspend(ctx, src))
+	require.Equal(t, srapi.SuspendPhaseResuming, status.Phase)
+	require.Equal(t, []string{srapi.DEFAULT_CN, srapi.DEFAULT_BE}, status.SuspendedComponents)
+
+	// be and cn are not resumed until fe is ready.
+	require.NoError(t, r.reconcileSuspend(ctx, src))
+	require.Equal(t, []string{srapi.DEFAULT_CN, srapi.DEFAULT_BE}, status.SuspendedComponents)
+	require.Equal(t, "waiting for fe to be ready", status.Reason)
+
+	setWorkloadStatus(t, r, "kube-starrocks-fe", 3, 3)
+	require.NoError(t, r.Client.Create(ctx, &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks-fe-service", Namespace: "default"},
+		Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "127.0.0.1"}}}},
+	}))
+	require.NoError(t, r.reconcileSuspend(ctx, src))
+	require.Empty(t, status.SuspendedComponents)
+
+	setWorkloadStatus(t, r, "kube-starrocks-be", 3, 0)
+	setWorkloadStatus(t, r, "kube-starrocks-cn", 5, 0)
+	require.NoError(t, r.reconcileSuspend(ctx, src))
+	require.NotNil(t, src.Status.SuspendStatus)
+
+	setWorkloadStatus(t, r, "kube-starrocks-be", 3, 3)
+	setWorkloadStatus(t, r, "kube-starrocks-cn", 5, 5)
+	require.NoError(t, r.reconcileSuspend(ctx, src))
+	require.Nil(t, src.Status.SuspendStatus)
+}
+
+func TestReconcileStatusSuspended(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		Status: srapi.StarRocksClusterStatus{
+			SuspendStatus: &srapi.SuspendStatus{Phase: srapi.SuspendPhaseSuspended},
+		},
+	}
+	r := &StarRocksClusterReconciler{}
+	r.reconcileStatus(context.Background(), src)
+	require.Equal(t, srapi.ClusterSuspended, src.Status.Phase)
+
+	src.Status.SuspendStatus = &srapi.SuspendStatus{Phase: srapi.SuspendPhaseResuming, Reason: "waiting for fe to be ready"}
+	r.reconcileStatus(context.Background(), src)
+	require.Equal(t, srapi.ClusterReconciling, src.Status.Phase)
+	require.Equal(t, "waiting for fe to be ready", src.Status.Reason)
+}
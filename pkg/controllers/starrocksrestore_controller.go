@@ -0,0 +1,258 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+// StarRocksRestoreReconciler reconciles a StarRocksRestore object
+type StarRocksRestoreReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	denyList string
+}
+
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksrestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksrestores/finalizers,verbs=update
+
+// Reconcile submits the restore job to StarRocks, and updates the status of StarRocksRestore until the job is finished.
+func (r *StarRocksRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx).WithName("StarRocksRestoreReconciler").
+		WithValues("name", req.Name, "namespace", req.Namespace)
+	ctx = logr.NewContext(ctx, logger)
+
+	restore := &srapi.StarRocksRestore{}
+	if err := r.Client.Get(ctx, req.NamespacedName, restore); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "get StarRocksRestore CR failed")
+		return ctrl.Result{}, err
+	}
+	if restore.Status.Phase.IsFinished() || !restore.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	err := r.syncRestore(ctx, restore, nil)
+	if err != nil {
+		logger.Error(err, "sync restore failed")
+		restore.Status.Reason = err.Error()
+	}
+	if updateError := r.updateStatus(ctx, restore); updateError != nil {
+		logger.Error(updateError, "update StarRocksRestore status failed")
+		return ctrl.Result{}, updateError
+	}
+	if err != nil {
+		return requeueIfError(err)
+	}
+	if !restore.Status.Phase.IsFinished() {
+		return ctrl.Result{RequeueAfter: backupRequeueInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// errInvalidRestoreSource means the snapshot to restore can not be resolved from the spec of StarRocksRestore.
+var errInvalidRestoreSource = errors.New("invalid restore source")
+
+// restoreSource is the snapshot to restore, which is resolved from the spec of StarRocksRestore and StarRocksBackup.
+type restoreSource struct {
+	repository   *srapi.BackupRepository
+	database     string
+	tables       []srapi.TableName
+	snapshotName string
+}
+
+// resolveRestoreSource returns the snapshot to restore. If the referenced StarRocksBackup has not succeeded,
+// it returns nil and the reason.
+func (r *StarRocksRestoreReconciler) resolveRestoreSource(ctx context.Context,
+	restore *srapi.StarRocksRestore) (*restoreSource, string, error) {
+	spec := &restore.Spec
+	source := &restoreSource{
+		repository:   spec.Repository,
+		database:     spec.Database,
+		tables:       spec.Tables,
+		snapshotName: spec.SnapshotName,
+	}
+	if spec.Backup != "" {
+		backup := &srapi.StarRocksBackup{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: spec.Backup}, backup); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, fmt.Sprintf("StarRocksBackup %s is not found", spec.Backup), nil
+			}
+			return nil, "", err
+		}
+		switch backup.Status.Phase {
+		case srapi.BackupSucceeded:
+		case srapi.BackupFailed:
+			return nil, "", fmt.Errorf("%w: StarRocksBackup %s is failed", errInvalidRestoreSource, spec.Backup)
+		default:
+			return nil, fmt.Sprintf("waiting for StarRocksBackup %s to succeed", spec.Backup), nil
+		}
+		if source.repository == nil {
+			source.repository = &backup.Spec.Repository
+		}
+		if source.database == "" {
+			source.database = backup.Spec.Database
+		}
+		if len(source.tables) == 0 {
+			source.tables = backup.Spec.Tables
+		}
+		if source.snapshotName == "" {
+			source.snapshotName = backup.Status.SnapshotName
+		}
+	}
+
+	if source.repository == nil || source.database == "" || source.snapshotName == "" {
+		return nil, "", fmt.Errorf("%w: repository, database and snapshotName are required if backup is not specified",
+			errInvalidRestoreSource)
+	}
+	if err := checkSnapshotNames(source.database, source.snapshotName, source.repository.Name, source.tables); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", errInvalidRestoreSource, err)
+	}
+	return source, "", nil
+}
+
+// syncRestore submits RESTORE SNAPSHOT if it has not been submitted, and updates the status from SHOW RESTORE.
+func (r *StarRocksRestoreReconciler) syncRestore(ctx context.Context, restore *srapi.StarRocksRestore, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	status := &restore.Status
+	if status.Phase == "" {
+		status.Phase = srapi.BackupPending
+	}
+
+	source, reason, err := r.resolveRestoreSource(ctx, restore)
+	if errors.Is(err, errInvalidRestoreSource) {
+		// it will not succeed by retrying.
+		r.finishRestore(restore, srapi.BackupFailed, err.Error())
+		return nil
+	}
+	if err != nil || source == nil {
+		status.Reason = reason
+		return err
+	}
+	status.SnapshotName = source.snapshotName
+
+	sqlClient, reason, err := newSQLClientForCluster(ctx, r.Client, restore.Namespace, restore.Spec.StarRocksCluster)
+	if err != nil || sqlClient == nil {
+		status.Reason = reason
+		return err
+	}
+	if err = ensureRepository(ctx, r.Client, r.Recorder, restore, source.repository, sqlClient, db); err != nil {
+		return err
+	}
+
+	if status.BackupTimestamp == "" {
+		status.BackupTimestamp = restore.Spec.BackupTimestamp
+	}
+	if status.BackupTimestamp == "" {
+		timestamps, err := querySnapshotTimestamps(ctx, sqlClient, db, source.repository.Name, source.snapshotName)
+		if err != nil {
+			return err
+		}
+		if len(timestamps) == 0 {
+			status.Reason = fmt.Sprintf("snapshot %s is not found in repository %s", source.snapshotName, source.repository.Name)
+			return nil
+		}
+		status.BackupTimestamp = timestamps[len(timestamps)-1]
+	}
+
+	if status.Phase == srapi.BackupPending {
+		// SHOW RESTORE fails if the database does not exist, e.g. the snapshot is restored to a new cluster.
+		if err = sqlClient.ExecuteContext(ctx, db, "CREATE DATABASE IF NOT EXISTS "+quoteIdentifier(source.database)); err != nil {
+			return err
+		}
+	}
+	job, err := queryLatestRestore(ctx, sqlClient, db, source.database)
+	if err != nil {
+		return err
+	}
+	switch {
+	case job != nil && job.Label == source.snapshotName && job.Timestamp == status.BackupTimestamp:
+		status.JobID, status.State, status.Progress = job.JobID, job.State, job.Progress
+		status.Reason = ""
+		switch job.State {
+		case backupStateFinished:
+			r.finishRestore(restore, srapi.BackupSucceeded, "")
+		case backupStateCancelled:
+			r.finishRestore(restore, srapi.BackupFailed, jobErrorMessage(job.Status, job.TaskErrMsg))
+		default:
+			status.Phase = srapi.BackupRunning
+		}
+	case status.Phase == srapi.BackupRunning:
+		// SHOW RESTORE only returns the latest job of the database, the result of the job can not be known.
+		r.finishRestore(restore, srapi.BackupFailed, "the restore job is not found in StarRocks")
+	case job != nil && !isJobFinished(job.State):
+		// only one restore job can be running in a database.
+		status.Reason = fmt.Sprintf("waiting for restore job %s of database %s to finish", job.JobID, source.database)
+	default:
+		logger.Info("restore database", "database", source.database, "snapshot", source.snapshotName,
+			"timestamp", status.BackupTimestamp)
+		statement := restoreStatement(source.database, source.snapshotName, source.repository.Name, source.tables,
+			status.BackupTimestamp, restore.Spec.ReplicationNum)
+		if err = sqlClient.ExecuteContext(ctx, db, statement); err != nil {
+			return err
+		}
+		now := metav1.Now()
+		status.Phase, status.Reason, status.StartTime = srapi.BackupRunning, "", &now
+		r.Recorder.Event(restore, corev1.EventTypeNormal, "RestoreStarted",
+			fmt.Sprintf("restore snapshot %s from repository %s to database %s",
+				source.snapshotName, source.repository.Name, source.database))
+	}
+	return nil
+}
+
+// finishRestore sets the final phase of StarRocksRestore, and records an event.
+func (r *StarRocksRestoreReconciler) finishRestore(restore *srapi.StarRocksRestore, phase srapi.BackupPhase, reason string) {
+	now := metav1.Now()
+	restore.Status.Phase, restore.Status.Reason, restore.Status.CompletionTime = phase, reason, &now
+	if phase == srapi.BackupSucceeded {
+		r.Recorder.Event(restore, corev1.EventTypeNormal, "RestoreSucceeded",
+			fmt.Sprintf("snapshot %s is restored", restore.Status.SnapshotName))
+	} else {
+		r.Recorder.Event(restore, corev1.EventTypeWarning, "RestoreFailed",
+			fmt.Sprintf("failed to restore snapshot %s: %s", restore.Status.SnapshotName, reason))
+	}
+}
+
+// updateStatus updates the status of StarRocksRestore.
+func (r *StarRocksRestoreReconciler) updateStatus(ctx context.Context, restore *srapi.StarRocksRestore) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		actual := &srapi.StarRocksRestore{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Name}, actual); err != nil {
+			return err
+		}
+		actual.Status = restore.Status
+		return r.Client.Status().Update(ctx, actual)
+	})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+)
+
+func TestSyncRestore(t *testing.T) {
+	repositoryColumns := []string{"RepoId", "RepoName", "Location"}
+	snapshotColumns := []string{"Snapshot", "Timestamp", "Status"}
+	restoreColumns := []string{"JobId", "Label", "Timestamp", "DbName", "State", "Progress", "TaskErrMsg", "Status"}
+	tests := []struct {
+		name        string
+		phase       srapi.BackupPhase
+		backupPhase srapi.BackupPhase
+		spec        srapi.StarRocksRestoreSpec
+		mockSQL     func(mock sqlmock.Sqlmock)
+		wantPhase   srapi.BackupPhase
+	}{
+		{
+			name:        "wait for backup to succeed",
+			backupPhase: srapi.BackupRunning,
+			spec:        srapi.StarRocksRestoreSpec{StarRocksCluster: "kube-starrocks", Backup: "daily-backup"},
+			mockSQL:     func(mock sqlmock.Sqlmock) {},
+			wantPhase:   srapi.BackupPending,
+		},
+		{
+			name:        "backup is failed",
+			backupPhase: srapi.BackupFailed,
+			spec:        srapi.StarRocksRestoreSpec{StarRocksCluster: "kube-starrocks", Backup: "daily-backup"},
+			mockSQL:     func(mock sqlmock.Sqlmock) {},
+			wantPhase:   srapi.BackupFailed,
+		},
+		{
+			name:      "snapshot is not specified",
+			spec:      srapi.StarRocksRestoreSpec{StarRocksCluster: "kube-starrocks", Database: "db1"},
+			mockSQL:   func(mock sqlmock.Sqlmock) {},
+			wantPhase: srapi.BackupFailed,
+		},
+		{
+			name:        "start restore from backup",
+			backupPhase: srapi.BackupSucceeded,
+			spec:        srapi.StarRocksRestoreSpec{StarRocksCluster: "kube-starrocks", Backup: "daily-backup"},
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW REPOSITORIES").WillReturnRows(sqlmock.NewRows(repositoryColumns).
+					AddRow("1", "repo", "s3://bucket/backup"))
+				mock.ExpectQuery("SHOW SNAPSHOT ON `repo` WHERE SNAPSHOT = 'daily_backup_1700000000'").
+					WillReturnRows(sqlmock.NewRows(snapshotColumns).
+						AddRow("daily_backup_1700000000", "2023-11-14-22-13-20-000", "OK"))
+				mock.ExpectExec("CREATE DATABASE IF NOT EXISTS `db1`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SHOW RESTORE FROM `db1`").WillReturnRows(sqlmock.NewRows(restoreColumns))
+				mock.ExpectExec("RESTORE SNAPSHOT `db1`.`daily_backup_1700000000` FROM `repo` ON (`t1`) " +
+					"PROPERTIES ('backup_timestamp' = '2023-11-14-22-13-20-000', 'replication_num' = '1')").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantPhase: srapi.BackupRunning,
+		},
+		{
+			name:        "restore is finished",
+			phase:       srapi.BackupRunning,
+			backupPhase: srapi.BackupSucceeded,
+			spec: srapi.StarRocksRestoreSpec{StarRocksCluster: "kube-starrocks", Backup: "daily-backup",
+				BackupTimestamp: "2023-11-14-22-13-20-000"},
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW REPOSITORIES").WillReturnRows(sqlmock.NewRows(repositoryColumns).
+					AddRow("1", "repo", "s3://bucket/backup"))
+				mock.ExpectQuery("SHOW RESTORE FROM `db1`").WillReturnRows(sqlmock.NewRows(restoreColumns).
+					AddRow("5", "daily_backup_1700000000", "2023-11-14-22-13-20-000", "db1", "FINISHED", "3/3", "", "[OK]"))
+			},
+			wantPhase: srapi.BackupSucceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			backup := newBackup()
+			backup.Status = srapi.StarRocksBackupStatus{Phase: tt.backupPhase, SnapshotName: "daily_backup_1700000000"}
+			restore := &srapi.StarRocksRestore{
+				ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "default"},
+				Spec:       tt.spec,
+				Status:     srapi.StarRocksRestoreStatus{Phase: tt.phase},
+			}
+			replicationNum := int32(1)
+			restore.Spec.ReplicationNum = &replicationNum
+			r := &StarRocksRestoreReconciler{
+				Client:   fake.NewFakeClient(srapi.Scheme, append(newReadyClusterObjects(), []runtime.Object{backup, restore}...)...),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			require.NoError(t, r.syncRestore(context.Background(), restore, db))
+			require.Equal(t, tt.wantPhase, restore.Status.Phase)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+// StarRocksRoleReconciler reconciles a StarRocksRole object
+type StarRocksRoleReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	denyList string
+}
+
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksroles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksroles/finalizers,verbs=update
+
+// Reconcile creates the role in StarRocks, and grants the privileges to it.
+func (r *StarRocksRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx).WithName("StarRocksRoleReconciler").
+		WithValues("name", req.Name, "namespace", req.Namespace)
+	ctx = logr.NewContext(ctx, logger)
+
+	role := &srapi.StarRocksRole{}
+	if err := r.Client.Get(ctx, req.NamespacedName, role); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "get StarRocksRole CR failed")
+		return ctrl.Result{}, err
+	}
+
+	if !role.DeletionTimestamp.IsZero() {
+		return r.reconcileDeletion(ctx, role, nil)
+	}
+	if !controllerutil.ContainsFinalizer(role, StarRocksAccessControlFinalizer) {
+		controllerutil.AddFinalizer(role, StarRocksAccessControlFinalizer)
+		if err := r.Client.Update(ctx, role); err != nil {
+			logger.Error(err, "add finalizer to StarRocksRole failed")
+			return requeueIfError(err)
+		}
+	}
+
+	err := r.syncRole(ctx, role, nil)
+	if err != nil {
+		logger.Error(err, "sync role failed")
+		role.Status.Phase, role.Status.Reason = srapi.AccessControlFailed, err.Error()
+	}
+	if updateError := r.updateStatus(ctx, role); updateError != nil {
+		logger.Error(updateError, "update StarRocksRole status failed")
+		return ctrl.Result{}, updateError
+	}
+	if err != nil {
+		return requeueIfError(err)
+	}
+	if role.Status.Phase == srapi.AccessControlPending {
+		return ctrl.Result{RequeueAfter: accessControlRequeueInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncRole creates the role if it has not been created, and syncs the privileges of the role.
+func (r *StarRocksRoleReconciler) syncRole(ctx context.Context, role *srapi.StarRocksRole, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	status := &role.Status
+	if status.Phase == "" {
+		status.Phase = srapi.AccessControlPending
+	}
+	if err := checkIdentifier("role name", roleName(role)); err != nil {
+		return err
+	}
+	if err := checkGrants(role.Spec.Grants); err != nil {
+		return err
+	}
+
+	sqlClient, reason, err := newSQLClientForCluster(ctx, r.Client, role.Namespace, role.Spec.StarRocksCluster)
+	if err != nil || sqlClient == nil {
+		status.Phase, status.Reason = srapi.AccessControlPending, reason
+		return err
+	}
+
+	name := roleName(role)
+	if status.RoleName != "" && status.RoleName != name {
+		// the name of the role is changed, drop the old one.
+		logger.Info("drop role", "role", status.RoleName)
+		if err = sqlClient.ExecuteContext(ctx, db, dropRoleStatement(status.RoleName)); err != nil {
+			return err
+		}
+		status.RoleName, status.Grants = "", nil
+	}
+	if status.RoleName == "" {
+		logger.Info("create role", "role", name)
+		if err = sqlClient.ExecuteContext(ctx, db, createRoleStatement(name)); err != nil {
+			return err
+		}
+		status.RoleName = name
+		r.Recorder.Event(role, corev1.EventTypeNormal, "CreateRole", fmt.Sprintf("create role %s", name))
+	}
+
+	status.Grants, err = syncGrants(ctx, sqlClient, db, status.Grants, role.Spec.Grants, roleGrantee(name))
+	if err != nil {
+		return err
+	}
+	status.Phase, status.Reason = srapi.AccessControlApplied, ""
+	return nil
+}
+
+// reconcileDeletion drops the role from StarRocks, and removes the finalizer. The role is revoked from all the users
+// by StarRocks when it is dropped.
+func (r *StarRocksRoleReconciler) reconcileDeletion(ctx context.Context,
+	role *srapi.StarRocksRole, db *sql.DB) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	if !controllerutil.ContainsFinalizer(role, StarRocksAccessControlFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	sqlClient, skip, reason, err := newSQLClientForDeletion(ctx, r.Client, role.Namespace, role.Spec.StarRocksCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !skip && role.Status.RoleName != "" {
+		if sqlClient == nil {
+			logger.Info("can not drop role", "reason", reason)
+			return ctrl.Result{RequeueAfter: accessControlRequeueInterval}, nil
+		}
+		logger.Info("drop role", "role", role.Status.RoleName)
+		if err = sqlClient.ExecuteContext(ctx, db, dropRoleStatement(role.Status.RoleName)); err != nil {
+			return requeueIfError(err)
+		}
+		r.Recorder.Event(role, corev1.EventTypeNormal, "DropRole", fmt.Sprintf("drop role %s", role.Status.RoleName))
+	}
+
+	controllerutil.RemoveFinalizer(role, StarRocksAccessControlFinalizer)
+	if err = r.Client.Update(ctx, role); err != nil {
+		return requeueIfError(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// updateStatus updates the status of StarRocksRole.
+func (r *StarRocksRoleReconciler) updateStatus(ctx context.Context, role *srapi.StarRocksRole) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		actual := &srapi.StarRocksRole{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: role.Namespace, Name: role.Name}, actual); err != nil {
+			return err
+		}
+		actual.Status = role.Status
+		return r.Client.Status().Update(ctx, actual)
+	})
+}
+
+// roleName returns the name of the role in StarRocks.
+func roleName(role *srapi.StarRocksRole) string {
+	if role.Spec.RoleName != "" {
+		return role.Spec.RoleName
+	}
+	return strings.ReplaceAll(role.Name, "-", "_")
+}
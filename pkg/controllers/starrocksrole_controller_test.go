@@ -0,0 +1,190 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+)
+
+func newRole() *srapi.StarRocksRole {
+	return &srapi.StarRocksRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "db1-reader", Namespace: "default"},
+		Spec: srapi.StarRocksRoleSpec{
+			StarRocksCluster: "kube-starrocks",
+			Grants: []srapi.PrivilegeGrant{
+				{Privileges: []srapi.Privilege{"select"}, On: "ALL TABLES IN DATABASE db1"},
+				{Privileges: []srapi.Privilege{"usage"}, On: "ALL RESOURCES"},
+			},
+		},
+	}
+}
+
+func TestSyncRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     srapi.StarRocksRoleStatus
+		mutate     func(role *srapi.StarRocksRole)
+		mockSQL    func(mock sqlmock.Sqlmock)
+		wantName   string
+		wantGrants int
+	}{
+		{
+			name: "create role",
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("CREATE ROLE IF NOT EXISTS `db1_reader`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("GRANT SELECT ON ALL TABLES IN DATABASE db1 TO ROLE `db1_reader`").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("GRANT USAGE ON ALL RESOURCES TO ROLE `db1_reader`").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantName:   "db1_reader",
+			wantGrants: 2,
+		},
+		{
+			name: "revoke the removed grant",
+			status: srapi.StarRocksRoleStatus{
+				Phase:    srapi.AccessControlApplied,
+				RoleName: "db1_reader",
+				Grants: []srapi.PrivilegeGrant{
+					{Privileges: []srapi.Privilege{"SELECT"}, On: "ALL TABLES IN DATABASE db1"},
+					{Privileges: []srapi.Privilege{"USAGE"}, On: "ALL RESOURCES"},
+				},
+			},
+			mutate: func(role *srapi.StarRocksRole) {
+				role.Spec.Grants = role.Spec.Grants[:1]
+			},
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("REVOKE USAGE ON ALL RESOURCES FROM ROLE `db1_reader`").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantName:   "db1_reader",
+			wantGrants: 1,
+		},
+		{
+			name: "rename role",
+			status: srapi.StarRocksRoleStatus{
+				Phase:    srapi.AccessControlApplied,
+				RoleName: "db1_reader",
+				Grants:   []srapi.PrivilegeGrant{{Privileges: []srapi.Privilege{"SELECT"}, On: "ALL TABLES IN DATABASE db1"}},
+			},
+			mutate: func(role *srapi.StarRocksRole) {
+				role.Spec.RoleName = "reader"
+				role.Spec.Grants = role.Spec.Grants[:1]
+			},
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DROP ROLE IF EXISTS `db1_reader`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("CREATE ROLE IF NOT EXISTS `reader`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("GRANT SELECT ON ALL TABLES IN DATABASE db1 TO ROLE `reader`").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantName:   "reader",
+			wantGrants: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			role := newRole()
+			role.Status = tt.status
+			if tt.mutate != nil {
+				tt.mutate(role)
+			}
+			r := &StarRocksRoleReconciler{
+				Client:   fake.NewFakeClient(srapi.Scheme, append(newReadyClusterObjects(), role)...),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			require.NoError(t, r.syncRole(context.Background(), role, db))
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Equal(t, srapi.AccessControlApplied, role.Status.Phase)
+			require.Equal(t, tt.wantName, role.Status.RoleName)
+			require.Len(t, role.Status.Grants, tt.wantGrants)
+		})
+	}
+}
+
+func TestCheckGrant(t *testing.T) {
+	tests := []struct {
+		name    string
+		grant   srapi.PrivilegeGrant
+		wantErr bool
+	}{
+		{
+			name:  "known privileges",
+			grant: srapi.PrivilegeGrant{Privileges: []srapi.Privilege{"select", "create  table"}, On: "DATABASE db1"},
+		},
+		{
+			name:  "wildcard",
+			grant: srapi.PrivilegeGrant{Privileges: []srapi.Privilege{"SELECT"}, On: "TABLE db1.*"},
+		},
+		{
+			name:    "unknown privilege",
+			grant:   srapi.PrivilegeGrant{Privileges: []srapi.Privilege{"SELECT ON *.* TO ROLE root; --"}, On: "SYSTEM"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid object",
+			grant:   srapi.PrivilegeGrant{Privileges: []srapi.Privilege{"SELECT"}, On: "TABLE db1.t1 TO ROLE `root`; --"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkGrant(tt.grant)
+			require.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestSyncRoleInvalidName(t *testing.T) {
+	role := newRole()
+	role.Name = "db1.reader"
+	r := &StarRocksRoleReconciler{
+		Client:   fake.NewFakeClient(srapi.Scheme, append(newReadyClusterObjects(), role)...),
+		Recorder: record.NewFakeRecorder(10),
+	}
+	require.Error(t, r.syncRole(context.Background(), role, nil))
+	require.Equal(t, "`a``b`", quoteIdentifier("a`b"))
+}
+
+func TestRoleDeletion(t *testing.T) {
+	role := newRole()
+	now := metav1.Now()
+	role.DeletionTimestamp = &now
+	role.Finalizers = []string{StarRocksAccessControlFinalizer}
+	role.Status.RoleName = "db1_reader"
+
+	// the cluster has been deleted, the role is dropped with the cluster.
+	r := &StarRocksRoleReconciler{
+		Client:   fake.NewFakeClient(srapi.Scheme, role),
+		Recorder: record.NewFakeRecorder(10),
+	}
+	_, err := r.reconcileDeletion(context.Background(), role, nil)
+	require.NoError(t, err)
+	require.Empty(t, role.Finalizers)
+}
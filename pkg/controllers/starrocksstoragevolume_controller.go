@@ -0,0 +1,280 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+// storageVolumeRequeueInterval is the interval to compare the storage volume in StarRocks with the spec.
+const storageVolumeRequeueInterval = time.Minute
+
+// StarRocksStorageVolumeFinalizer is added to StarRocksStorageVolume, so that the storage volume is dropped from
+// StarRocks before StarRocksStorageVolume is deleted.
+const StarRocksStorageVolumeFinalizer = "starrocks.com.starrocksstoragevolume/protection"
+
+// StarRocksStorageVolumeReconciler reconciles a StarRocksStorageVolume object
+type StarRocksStorageVolumeReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	denyList string
+}
+
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksstoragevolumes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksstoragevolumes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksstoragevolumes/finalizers,verbs=update
+
+// Reconcile creates or alters the storage volume in StarRocks, and reports the drifts which can not be fixed.
+func (r *StarRocksStorageVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx).WithName("StarRocksStorageVolumeReconciler").
+		WithValues("name", req.Name, "namespace", req.Namespace)
+	ctx = logr.NewContext(ctx, logger)
+
+	sv := &srapi.StarRocksStorageVolume{}
+	if err := r.Client.Get(ctx, req.NamespacedName, sv); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "get StarRocksStorageVolume CR failed")
+		return ctrl.Result{}, err
+	}
+
+	if !sv.DeletionTimestamp.IsZero() {
+		return r.reconcileDeletion(ctx, sv, nil)
+	}
+	if !controllerutil.ContainsFinalizer(sv, StarRocksStorageVolumeFinalizer) {
+		controllerutil.AddFinalizer(sv, StarRocksStorageVolumeFinalizer)
+		if err := r.Client.Update(ctx, sv); err != nil {
+			logger.Error(err, "add finalizer to StarRocksStorageVolume failed")
+			return requeueIfError(err)
+		}
+	}
+
+	err := r.syncStorageVolume(ctx, sv, nil)
+	if err != nil {
+		logger.Error(err, "sync storage volume failed")
+		sv.Status.Phase, sv.Status.Reason = srapi.StorageVolumeFailed, err.Error()
+	}
+	if updateError := r.updateStatus(ctx, sv); updateError != nil {
+		logger.Error(updateError, "update StarRocksStorageVolume status failed")
+		return ctrl.Result{}, updateError
+	}
+	if err != nil {
+		return requeueIfError(err)
+	}
+	return ctrl.Result{RequeueAfter: storageVolumeRequeueInterval}, nil
+}
+
+// syncStorageVolume creates the storage volume if it does not exist, otherwise alters the properties which differ
+// from DESC STORAGE VOLUME.
+//
+//nolint:gocyclo
+func (r *StarRocksStorageVolumeReconciler) syncStorageVolume(ctx context.Context,
+	sv *srapi.StarRocksStorageVolume, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	spec := &sv.Spec
+	status := &sv.Status
+	name := storageVolumeName(sv)
+	status.VolumeName = name
+	if status.Phase == "" {
+		status.Phase = srapi.StorageVolumePending
+	}
+
+	sqlClient, reason, err := newSQLClientForCluster(ctx, r.Client, sv.Namespace, spec.StarRocksCluster)
+	if err != nil || sqlClient == nil {
+		status.Phase, status.Reason = srapi.StorageVolumePending, reason
+		return err
+	}
+	if sharedData, err := isSharedDataCluster(ctx, r.Client, sv.Namespace, spec.StarRocksCluster); err != nil {
+		return err
+	} else if !sharedData {
+		status.Phase = srapi.StorageVolumeFailed
+		status.Reason = fmt.Sprintf("StarRocksCluster %s does not run in shared-data mode", spec.StarRocksCluster)
+		return nil
+	}
+
+	location, err := storageVolumeLocation(spec)
+	if err != nil {
+		return err
+	}
+	credentials := map[string]string{}
+	if ref := spec.CredentialsSecretRef; ref != nil {
+		var secret corev1.Secret
+		if err = r.Client.Get(ctx, types.NamespacedName{Namespace: sv.Namespace, Name: ref.Name}, &secret); err != nil {
+			return fmt.Errorf("failed to get the credentials of storage volume %s: %w", name, err)
+		}
+		for key, value := range secret.Data {
+			credentials[key] = string(value)
+		}
+	}
+	volumeType := storageVolumeType(spec.Type)
+	properties, credentialKeys := storageVolumeProperties(spec, credentials)
+
+	actual, err := describeStorageVolume(ctx, sqlClient, db, name)
+	if err != nil {
+		return err
+	}
+	var drifts []string
+	if actual == nil {
+		logger.Info("create storage volume", "storageVolume", name, "location", location)
+		statement := createStorageVolumeStatement(name, volumeType, location, spec.Comment, properties)
+		if err = sqlClient.ExecuteContext(ctx, db, statement); err != nil {
+			return fmt.Errorf("failed to create storage volume %s: %w", name, err)
+		}
+		r.Recorder.Event(sv, corev1.EventTypeNormal, "CreateStorageVolume",
+			fmt.Sprintf("create storage volume %s on location %s", name, location))
+		actual = &storageVolume{Name: name}
+	} else {
+		var changed map[string]string
+		drifts, changed = diffStorageVolume(actual, volumeType, location, properties, credentialKeys)
+		if sv.Generation != status.ObservedGeneration {
+			// the credentials are masked by StarRocks, set them again when the spec is changed.
+			for key := range credentialKeys {
+				changed[key] = properties[key]
+			}
+		}
+		if len(changed) != 0 {
+			logger.Info("alter storage volume", "storageVolume", name, "properties", sortedKeys(changed))
+			if err = sqlClient.ExecuteContext(ctx, db, alterStorageVolumeStatement(name, changed)); err != nil {
+				return fmt.Errorf("failed to alter storage volume %s: %w", name, err)
+			}
+			r.Recorder.Event(sv, corev1.EventTypeNormal, "AlterStorageVolume",
+				fmt.Sprintf("alter properties %s of storage volume %s", strings.Join(sortedKeys(changed), ", "), name))
+		}
+		if actual.Comment != spec.Comment {
+			if err = sqlClient.ExecuteContext(ctx, db, alterStorageVolumeCommentStatement(name, spec.Comment)); err != nil {
+				return fmt.Errorf("failed to alter the comment of storage volume %s: %w", name, err)
+			}
+		}
+	}
+	status.ObservedGeneration = sv.Generation
+
+	if spec.Default && !actual.IsDefault {
+		logger.Info("set default storage volume", "storageVolume", name)
+		if err = sqlClient.ExecuteContext(ctx, db, setDefaultStorageVolumeStatement(name)); err != nil {
+			return fmt.Errorf("failed to set storage volume %s as default: %w", name, err)
+		}
+		r.Recorder.Event(sv, corev1.EventTypeNormal, "SetDefaultStorageVolume",
+			fmt.Sprintf("set storage volume %s as the default storage volume", name))
+		actual.IsDefault = true
+	} else if !spec.Default && actual.IsDefault {
+		drifts = append(drifts, "default: expected false, actual true")
+	}
+	status.IsDefault = actual.IsDefault
+
+	if len(drifts) != 0 && !reflect.DeepEqual(drifts, status.Drifts) {
+		r.Recorder.Event(sv, corev1.EventTypeWarning, "StorageVolumeDrifted",
+			fmt.Sprintf("storage volume %s differs from the spec: %s", name, strings.Join(drifts, "; ")))
+	}
+	status.Drifts = drifts
+	if len(drifts) != 0 {
+		status.Phase, status.Reason = srapi.StorageVolumeDrifted, "the storage volume in StarRocks differs from the spec"
+	} else {
+		status.Phase, status.Reason = srapi.StorageVolumeReady, ""
+	}
+	return nil
+}
+
+// reconcileDeletion drops the storage volume from StarRocks, and removes the finalizer. If the StarRocksCluster has
+// been deleted, the finalizer is removed directly.
+func (r *StarRocksStorageVolumeReconciler) reconcileDeletion(ctx context.Context,
+	sv *srapi.StarRocksStorageVolume, db *sql.DB) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	if !controllerutil.ContainsFinalizer(sv, StarRocksStorageVolumeFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	sqlClient, skip, reason, err := newSQLClientForDeletion(ctx, r.Client, sv.Namespace, sv.Spec.StarRocksCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !skip {
+		if sqlClient == nil {
+			logger.Info("can not drop storage volume", "reason", reason)
+			return ctrl.Result{RequeueAfter: storageVolumeRequeueInterval}, nil
+		}
+		name := storageVolumeName(sv)
+		logger.Info("drop storage volume", "storageVolume", name)
+		if err = sqlClient.ExecuteContext(ctx, db, dropStorageVolumeStatement(name)); err != nil {
+			// e.g. the storage volume is the default one, or it is used by some databases.
+			r.Recorder.Event(sv, corev1.EventTypeWarning, "DropStorageVolumeFailed",
+				fmt.Sprintf("failed to drop storage volume %s: %s", name, err.Error()))
+			return requeueIfError(err)
+		}
+		r.Recorder.Event(sv, corev1.EventTypeNormal, "DropStorageVolume", fmt.Sprintf("drop storage volume %s", name))
+	}
+
+	controllerutil.RemoveFinalizer(sv, StarRocksStorageVolumeFinalizer)
+	if err = r.Client.Update(ctx, sv); err != nil {
+		return requeueIfError(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// updateStatus updates the status of StarRocksStorageVolume.
+func (r *StarRocksStorageVolumeReconciler) updateStatus(ctx context.Context, sv *srapi.StarRocksStorageVolume) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		actual := &srapi.StarRocksStorageVolume{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: sv.Namespace, Name: sv.Name}, actual); err != nil {
+			return err
+		}
+		actual.Status = sv.Status
+		return r.Client.Status().Update(ctx, actual)
+	})
+}
+
+// storageVolumeName returns the name of the storage volume in StarRocks.
+func storageVolumeName(sv *srapi.StarRocksStorageVolume) string {
+	if sv.Spec.VolumeName != "" {
+		return sv.Spec.VolumeName
+	}
+	return strings.ReplaceAll(sv.Name, "-", "_")
+}
+
+// isSharedDataCluster returns true if the FE of StarRocksCluster runs in shared-data mode.
+func isSharedDataCluster(ctx context.Context, k8sClient client.Client, namespace, clusterName string) (bool, error) {
+	src := &srapi.StarRocksCluster{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: clusterName}, src); err != nil {
+		return false, err
+	}
+	if src.Spec.StarRocksFeSpec == nil {
+		return false, nil
+	}
+	feConfig, err := fe.GetFEConfig(ctx, k8sClient, src.Spec.StarRocksFeSpec, namespace)
+	if err != nil {
+		return false, err
+	}
+	return fe.IsRunInSharedDataMode(feConfig), nil
+}
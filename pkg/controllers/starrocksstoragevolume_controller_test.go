@@ -0,0 +1,229 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+)
+
+// newSharedDataClusterObjects returns the objects of a ready StarRocksCluster which runs in shared-data mode.
+func newSharedDataClusterObjects() []runtime.Object {
+	objects := newReadyClusterObjects()
+	src := objects[0].(*srapi.StarRocksCluster)
+	src.Spec.StarRocksFeSpec.ConfigMapInfo = srapi.ConfigMapInfo{ConfigMapName: "kube-starrocks-fe-cm", ResolveKey: "fe.conf"}
+	return append(objects, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks-fe-cm", Namespace: "default"},
+		Data:       map[string]string{"fe.conf": "run_mode = shared_data\n"},
+	})
+}
+
+func newStorageVolume() *srapi.StarRocksStorageVolume {
+	return &srapi.StarRocksStorageVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-volume", Namespace: "default", Generation: 1},
+		Spec: srapi.StarRocksStorageVolumeSpec{
+			StarRocksCluster:     "kube-starrocks",
+			Type:                 srapi.S3StorageVolume,
+			Region:               "us-west-2",
+			Bucket:               "bucket",
+			Prefix:               "/data/",
+			CredentialsSecretRef: &corev1.LocalObjectReference{Name: "s3-credentials"},
+			Default:              true,
+		},
+	}
+}
+
+func TestSyncStorageVolume(t *testing.T) {
+	volumesColumns := []string{"Storage Volume"}
+	descColumns := []string{"Name", "Type", "IsDefault", "Location", "Params", "Enabled", "Comment"}
+	tests := []struct {
+		name               string
+		objects            []runtime.Object
+		observedGeneration int64
+		mockSQL            func(mock sqlmock.Sqlmock)
+		wantPhase          srapi.StorageVolumePhase
+		wantDefault        bool
+		wantDrifts         []string
+	}{
+		{
+			name:      "FE is not ready",
+			objects:   []runtime.Object{},
+			mockSQL:   func(mock sqlmock.Sqlmock) {},
+			wantPhase: srapi.StorageVolumePending,
+		},
+		{
+			name:      "cluster does not run in shared-data mode",
+			objects:   newReadyClusterObjects(),
+			mockSQL:   func(mock sqlmock.Sqlmock) {},
+			wantPhase: srapi.StorageVolumeFailed,
+		},
+		{
+			name:    "create storage volume and set it as default",
+			objects: newSharedDataClusterObjects(),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW STORAGE VOLUMES").WillReturnRows(
+					sqlmock.NewRows(volumesColumns).AddRow("builtin_storage_volume"))
+				mock.ExpectExec("CREATE STORAGE VOLUME IF NOT EXISTS `s3_volume` TYPE = S3 LOCATIONS = ('s3://bucket/data') " +
+					"PROPERTIES ('aws.s3.access_key' = 'ak', 'aws.s3.region' = 'us-west-2', " +
+					"'aws.s3.secret_key' = 'sk', 'aws.s3.use_aws_sdk_default_behavior' = 'false', " +
+					"'aws.s3.use_instance_profile' = 'false')").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("SET `s3_volume` AS DEFAULT STORAGE VOLUME").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantPhase:   srapi.StorageVolumeReady,
+			wantDefault: true,
+		},
+		{
+			name:               "alter the changed properties",
+			objects:            newSharedDataClusterObjects(),
+			observedGeneration: 1,
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW STORAGE VOLUMES").WillReturnRows(sqlmock.NewRows(volumesColumns).AddRow("s3_volume"))
+				mock.ExpectQuery("DESC STORAGE VOLUME `s3_volume`").WillReturnRows(sqlmock.NewRows(descColumns).
+					AddRow("s3_volume", "S3", "true", "s3://bucket/data",
+						`{"aws.s3.region":"us-east-1","aws.s3.access_key":"******","aws.s3.secret_key":"******",`+
+							`"aws.s3.use_aws_sdk_default_behavior":"false","aws.s3.use_instance_profile":"false"}`, "true", ""))
+				mock.ExpectExec("ALTER STORAGE VOLUME `s3_volume` SET ('aws.s3.region' = 'us-west-2')").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantPhase:   srapi.StorageVolumeReady,
+			wantDefault: true,
+		},
+		{
+			name:    "report the drifts",
+			objects: newSharedDataClusterObjects(),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SHOW STORAGE VOLUMES").WillReturnRows(sqlmock.NewRows(volumesColumns).AddRow("s3_volume"))
+				mock.ExpectQuery("DESC STORAGE VOLUME `s3_volume`").WillReturnRows(sqlmock.NewRows(descColumns).
+					AddRow("s3_volume", "S3", "true", "s3://other/data",
+						`{"aws.s3.region":"us-west-2","aws.s3.use_aws_sdk_default_behavior":"false",`+
+							`"aws.s3.use_instance_profile":"false"}`, "true", ""))
+				// the credentials are set again because the spec is changed.
+				mock.ExpectExec("ALTER STORAGE VOLUME `s3_volume` SET ('aws.s3.access_key' = 'ak', 'aws.s3.secret_key' = 'sk')").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantPhase:   srapi.StorageVolumeDrifted,
+			wantDefault: true,
+			wantDrifts:  []string{"location: expected s3://bucket/data, actual s3://other/data"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			sv := newStorageVolume()
+			sv.Status.ObservedGeneration = tt.observedGeneration
+			r := &StarRocksStorageVolumeReconciler{
+				Client:   fake.NewFakeClient(srapi.Scheme, append(tt.objects, sv)...),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			require.NoError(t, r.syncStorageVolume(context.Background(), sv, db))
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Equal(t, tt.wantPhase, sv.Status.Phase, sv.Status.Reason)
+			require.Equal(t, "s3_volume", sv.Status.VolumeName)
+			require.Equal(t, tt.wantDefault, sv.Status.IsDefault)
+			require.Equal(t, tt.wantDrifts, sv.Status.Drifts)
+		})
+	}
+}
+
+func TestStorageVolumeLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    srapi.StarRocksStorageVolumeSpec
+		want    string
+		wantErr bool
+	}{
+		{name: "S3", spec: srapi.StarRocksStorageVolumeSpec{Type: srapi.S3StorageVolume, Bucket: "bucket"}, want: "s3://bucket"},
+		{name: "MinIO", spec: srapi.StarRocksStorageVolumeSpec{Type: srapi.MinIOStorageVolume, Bucket: "bucket", Prefix: "a/b"},
+			want: "s3://bucket/a/b"},
+		{name: "AZBLOB", spec: srapi.StarRocksStorageVolumeSpec{Type: srapi.AzureBlobStorageVolume, Bucket: "container",
+			Prefix: "data"}, want: "azblob://container/data"},
+		{name: "HDFS", spec: srapi.StarRocksStorageVolumeSpec{Type: srapi.HDFSStorageVolume, Endpoint: "hdfs://nn:9000/",
+			Prefix: "/starrocks"}, want: "hdfs://nn:9000/starrocks"},
+		{name: "bucket is required", spec: srapi.StarRocksStorageVolumeSpec{Type: srapi.S3StorageVolume}, wantErr: true},
+		{name: "endpoint is required", spec: srapi.StarRocksStorageVolumeSpec{Type: srapi.HDFSStorageVolume}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := storageVolumeLocation(&tt.spec)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStorageVolumeDeletion(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		mockSQL func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "drop storage volume",
+			objects: newSharedDataClusterObjects(),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DROP STORAGE VOLUME IF EXISTS `s3_volume`").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "cluster has been deleted",
+			objects: []runtime.Object{},
+			mockSQL: func(mock sqlmock.Sqlmock) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			sv := newStorageVolume()
+			now := metav1.Now()
+			sv.DeletionTimestamp = &now
+			sv.Finalizers = []string{StarRocksStorageVolumeFinalizer}
+			r := &StarRocksStorageVolumeReconciler{
+				Client:   fake.NewFakeClient(srapi.Scheme, append(tt.objects, sv)...),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err = r.reconcileDeletion(context.Background(), sv, db)
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Empty(t, sv.Finalizers)
+		})
+	}
+}
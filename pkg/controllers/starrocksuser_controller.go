@@ -0,0 +1,286 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/hash"
+)
+
+// accessControlRequeueInterval is the interval to retry when FE of StarRocksCluster is not ready.
+const accessControlRequeueInterval = 30 * time.Second
+
+// StarRocksAccessControlFinalizer is added to StarRocksUser and StarRocksRole, so that the user or role is dropped
+// from StarRocks before the CR is deleted.
+const StarRocksAccessControlFinalizer = "starrocks.com.accesscontrol/protection"
+
+// StarRocksUserReconciler reconciles a StarRocksUser object
+type StarRocksUserReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	denyList string
+}
+
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksusers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksusers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=starrocks.com,resources=starrocksusers/finalizers,verbs=update
+
+// Reconcile creates the user in StarRocks, changes its password, and grants the roles and privileges to it.
+func (r *StarRocksUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx).WithName("StarRocksUserReconciler").
+		WithValues("name", req.Name, "namespace", req.Namespace)
+	ctx = logr.NewContext(ctx, logger)
+
+	user := &srapi.StarRocksUser{}
+	if err := r.Client.Get(ctx, req.NamespacedName, user); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "get StarRocksUser CR failed")
+		return ctrl.Result{}, err
+	}
+
+	if !user.DeletionTimestamp.IsZero() {
+		return r.reconcileDeletion(ctx, user, nil)
+	}
+	if !controllerutil.ContainsFinalizer(user, StarRocksAccessControlFinalizer) {
+		controllerutil.AddFinalizer(user, StarRocksAccessControlFinalizer)
+		if err := r.Client.Update(ctx, user); err != nil {
+			logger.Error(err, "add finalizer to StarRocksUser failed")
+			return requeueIfError(err)
+		}
+	}
+
+	err := r.syncUser(ctx, user, nil)
+	if err != nil {
+		logger.Error(err, "sync user failed")
+		user.Status.Phase, user.Status.Reason = srapi.AccessControlFailed, err.Error()
+	}
+	if updateError := r.updateStatus(ctx, user); updateError != nil {
+		logger.Error(updateError, "update StarRocksUser status failed")
+		return ctrl.Result{}, updateError
+	}
+	if err != nil {
+		return requeueIfError(err)
+	}
+	if user.Status.Phase == srapi.AccessControlPending {
+		return ctrl.Result{RequeueAfter: accessControlRequeueInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncUser creates the user if it has not been created, runs ALTER USER if the authentication is changed, and syncs
+// the roles and privileges of the user.
+func (r *StarRocksUserReconciler) syncUser(ctx context.Context, user *srapi.StarRocksUser, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	spec := &user.Spec
+	status := &user.Status
+	if status.Phase == "" {
+		status.Phase = srapi.AccessControlPending
+	}
+	if err := validateUserSpec(spec); err != nil {
+		return err
+	}
+
+	sqlClient, reason, err := newSQLClientForCluster(ctx, r.Client, user.Namespace, spec.StarRocksCluster)
+	if err != nil || sqlClient == nil {
+		status.Phase, status.Reason = srapi.AccessControlPending, reason
+		return err
+	}
+
+	password, err := r.password(ctx, user)
+	if err != nil {
+		return err
+	}
+	authentication := authenticationClause(password, spec.AuthPlugin)
+	authenticationHash := hashAuthentication(user, authentication)
+
+	identity := userIdentity(userName(user), spec.Host)
+	if status.UserIdentity != "" && status.UserIdentity != identity {
+		// the name or host of the user is changed, drop the old one.
+		logger.Info("drop user", "user", status.UserIdentity)
+		if err = sqlClient.ExecuteContext(ctx, db, dropUserStatement(status.UserIdentity)); err != nil {
+			return err
+		}
+		status.UserIdentity, status.AuthenticationHash, status.Roles, status.Grants = "", "", nil, nil
+	}
+	if status.UserIdentity == "" {
+		logger.Info("create user", "user", identity)
+		if err = sqlClient.ExecuteContext(ctx, db, createUserStatement(identity, authentication)); err != nil {
+			return err
+		}
+		status.UserIdentity = identity
+		r.Recorder.Event(user, corev1.EventTypeNormal, "CreateUser", fmt.Sprintf("create user %s", identity))
+	}
+	if status.AuthenticationHash != authenticationHash {
+		// the user may exist before it is created by the operator, so ALTER USER is also executed for a new user.
+		if authentication != "" {
+			logger.Info("alter the authentication of user", "user", identity)
+			if err = sqlClient.ExecuteContext(ctx, db, alterUserStatement(identity, authentication)); err != nil {
+				return err
+			}
+			if status.AuthenticationHash != "" {
+				r.Recorder.Event(user, corev1.EventTypeNormal, "AlterUser",
+					fmt.Sprintf("change the authentication of user %s", identity))
+			}
+		}
+		status.AuthenticationHash = authenticationHash
+	}
+
+	roles := make([]string, 0, len(spec.Roles))
+	for _, role := range spec.Roles {
+		roles = append(roles, string(role))
+	}
+	status.Roles, err = syncUserRoles(ctx, sqlClient, db, status.Roles, roles, identity)
+	if err != nil {
+		return err
+	}
+	status.Grants, err = syncGrants(ctx, sqlClient, db, status.Grants, spec.Grants, userGrantee(identity))
+	if err != nil {
+		return err
+	}
+	status.Phase, status.Reason = srapi.AccessControlApplied, ""
+	return nil
+}
+
+// validateUserSpec checks the fields which are formatted into the statements.
+func validateUserSpec(spec *srapi.StarRocksUserSpec) error {
+	if spec.AuthPlugin != nil {
+		if err := checkIdentifier("authentication plugin", spec.AuthPlugin.Name); err != nil {
+			return err
+		}
+	}
+	for _, role := range spec.Roles {
+		if err := checkIdentifier("role", string(role)); err != nil {
+			return err
+		}
+	}
+	return checkGrants(spec.Grants)
+}
+
+// password returns the password of the user from the secret.
+func (r *StarRocksUserReconciler) password(ctx context.Context, user *srapi.StarRocksUser) (string, error) {
+	ref := user.Spec.PasswordSecretRef
+	if ref == nil {
+		return "", nil
+	}
+	if user.Spec.AuthPlugin != nil {
+		return "", errors.New("passwordSecretRef and authPlugin can not be set at the same time")
+	}
+	var secret corev1.Secret
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: user.Namespace, Name: ref.Name}, &secret); err != nil {
+		return "", fmt.Errorf("failed to get the password of user: %w", err)
+	}
+	password, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s is not found in secret %s", ref.Key, ref.Name)
+	}
+	return string(password), nil
+}
+
+// reconcileDeletion drops the user from StarRocks, and removes the finalizer.
+func (r *StarRocksUserReconciler) reconcileDeletion(ctx context.Context,
+	user *srapi.StarRocksUser, db *sql.DB) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	if !controllerutil.ContainsFinalizer(user, StarRocksAccessControlFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	sqlClient, skip, reason, err := newSQLClientForDeletion(ctx, r.Client, user.Namespace, user.Spec.StarRocksCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !skip && user.Status.UserIdentity != "" {
+		if sqlClient == nil {
+			logger.Info("can not drop user", "reason", reason)
+			return ctrl.Result{RequeueAfter: accessControlRequeueInterval}, nil
+		}
+		logger.Info("drop user", "user", user.Status.UserIdentity)
+		if err = sqlClient.ExecuteContext(ctx, db, dropUserStatement(user.Status.UserIdentity)); err != nil {
+			return requeueIfError(err)
+		}
+		r.Recorder.Event(user, corev1.EventTypeNormal, "DropUser", fmt.Sprintf("drop user %s", user.Status.UserIdentity))
+	}
+
+	controllerutil.RemoveFinalizer(user, StarRocksAccessControlFinalizer)
+	if err = r.Client.Update(ctx, user); err != nil {
+		return requeueIfError(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// updateStatus updates the status of StarRocksUser.
+func (r *StarRocksUserReconciler) updateStatus(ctx context.Context, user *srapi.StarRocksUser) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		actual := &srapi.StarRocksUser{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: user.Namespace, Name: user.Name}, actual); err != nil {
+			return err
+		}
+		actual.Status = user.Status
+		return r.Client.Status().Update(ctx, actual)
+	})
+}
+
+// usersOfSecret returns the StarRocksUsers whose password is stored in the secret, so that the password is changed
+// when the secret is changed.
+func (r *StarRocksUserReconciler) usersOfSecret(secret client.Object) []reconcile.Request {
+	var users srapi.StarRocksUserList
+	if err := r.Client.List(context.Background(), &users, client.InNamespace(secret.GetNamespace())); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range users.Items {
+		user := &users.Items[i]
+		if ref := user.Spec.PasswordSecretRef; ref != nil && ref.Name == secret.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: user.Namespace, Name: user.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// hashAuthentication returns the hash of the authentication, the UID of the user is mixed in, so the password can not
+// be guessed from the hash easily.
+func hashAuthentication(user *srapi.StarRocksUser, authentication string) string {
+	return hash.HashObject([]interface{}{user.UID, authentication})
+}
+
+// userName returns the name of the user in StarRocks.
+func userName(user *srapi.StarRocksUser) string {
+	if user.Spec.UserName != "" {
+		return user.Spec.UserName
+	}
+	return user.Name
+}
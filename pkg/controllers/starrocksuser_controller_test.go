@@ -0,0 +1,199 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+)
+
+func newUser() *srapi.StarRocksUser {
+	return &srapi.StarRocksUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice", Namespace: "default", UID: "uid"},
+		Spec: srapi.StarRocksUserSpec{
+			StarRocksCluster: "kube-starrocks",
+			PasswordSecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "alice-password"},
+				Key:                  "password",
+			},
+			Roles:  []srapi.RoleName{"reader"},
+			Grants: []srapi.PrivilegeGrant{{Privileges: []srapi.Privilege{"insert", "select"}, On: "ALL TABLES IN DATABASE db1"}},
+		},
+	}
+}
+
+func newPasswordSecret(password string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice-password", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte(password)},
+	}
+}
+
+func TestSyncUser(t *testing.T) {
+	// the status after the user is created with password "p1"
+	appliedStatus := func() srapi.StarRocksUserStatus {
+		user := newUser()
+		return srapi.StarRocksUserStatus{
+			Phase:              srapi.AccessControlApplied,
+			UserIdentity:       "'alice'@'%'",
+			AuthenticationHash: hashAuthentication(user, " IDENTIFIED BY 'p1'"),
+			Roles:              []string{"reader"},
+			Grants:             []srapi.PrivilegeGrant{{Privileges: []srapi.Privilege{"INSERT", "SELECT"}, On: "ALL TABLES IN DATABASE db1"}},
+		}
+	}
+	tests := []struct {
+		name       string
+		objects    []runtime.Object
+		status     srapi.StarRocksUserStatus
+		mutate     func(user *srapi.StarRocksUser)
+		mockSQL    func(mock sqlmock.Sqlmock)
+		wantPhase  srapi.AccessControlPhase
+		wantRoles  []string
+		wantGrants int
+	}{
+		{
+			name:      "FE is not ready",
+			objects:   []runtime.Object{newPasswordSecret("p1")},
+			mockSQL:   func(mock sqlmock.Sqlmock) {},
+			wantPhase: srapi.AccessControlPending,
+		},
+		{
+			name:    "create user",
+			objects: append(newReadyClusterObjects(), newPasswordSecret("p1")),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("CREATE USER IF NOT EXISTS 'alice'@'%' IDENTIFIED BY 'p1'").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ALTER USER 'alice'@'%' IDENTIFIED BY 'p1'").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("GRANT `reader` TO USER 'alice'@'%'").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("GRANT INSERT, SELECT ON ALL TABLES IN DATABASE db1 TO USER 'alice'@'%'").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantPhase:  srapi.AccessControlApplied,
+			wantRoles:  []string{"reader"},
+			wantGrants: 1,
+		},
+		{
+			name:      "nothing is changed",
+			objects:   append(newReadyClusterObjects(), newPasswordSecret("p1")),
+			status:    appliedStatus(),
+			mockSQL:   func(mock sqlmock.Sqlmock) {},
+			wantPhase: srapi.AccessControlApplied,
+			wantRoles: []string{"reader"}, wantGrants: 1,
+		},
+		{
+			name:    "rotate password and revoke",
+			objects: append(newReadyClusterObjects(), newPasswordSecret("p2")),
+			status:  appliedStatus(),
+			mutate: func(user *srapi.StarRocksUser) {
+				user.Spec.Roles = []srapi.RoleName{"writer"}
+				user.Spec.Grants = nil
+			},
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("ALTER USER 'alice'@'%' IDENTIFIED BY 'p2'").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("REVOKE `reader` FROM USER 'alice'@'%'").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("GRANT `writer` TO USER 'alice'@'%'").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("REVOKE INSERT, SELECT ON ALL TABLES IN DATABASE db1 FROM USER 'alice'@'%'").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantPhase: srapi.AccessControlApplied,
+			wantRoles: []string{"writer"},
+		},
+		{
+			name:    "use auth plugin",
+			objects: newReadyClusterObjects(),
+			mutate: func(user *srapi.StarRocksUser) {
+				user.Spec.PasswordSecretRef = nil
+				user.Spec.AuthPlugin = &srapi.UserAuthPlugin{
+					Name:                 "authentication_ldap_simple",
+					AuthenticationString: "uid=alice,ou=people,dc=example,dc=com",
+				}
+				user.Spec.Roles, user.Spec.Grants = nil, nil
+			},
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("CREATE USER IF NOT EXISTS 'alice'@'%' IDENTIFIED WITH authentication_ldap_simple AS " +
+					"'uid=alice,ou=people,dc=example,dc=com'").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ALTER USER 'alice'@'%' IDENTIFIED WITH authentication_ldap_simple AS " +
+					"'uid=alice,ou=people,dc=example,dc=com'").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantPhase: srapi.AccessControlApplied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			user := newUser()
+			user.Status = tt.status
+			if tt.mutate != nil {
+				tt.mutate(user)
+			}
+			r := &StarRocksUserReconciler{
+				Client:   fake.NewFakeClient(srapi.Scheme, append(tt.objects, user)...),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			require.NoError(t, r.syncUser(context.Background(), user, db))
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Equal(t, tt.wantPhase, user.Status.Phase, user.Status.Reason)
+			require.Equal(t, tt.wantRoles, user.Status.Roles)
+			require.Len(t, user.Status.Grants, tt.wantGrants)
+		})
+	}
+}
+
+func TestUsersOfSecret(t *testing.T) {
+	other := newUser()
+	other.Name = "bob"
+	other.Spec.PasswordSecretRef.Name = "bob-password"
+	r := &StarRocksUserReconciler{Client: fake.NewFakeClient(srapi.Scheme, newUser(), other)}
+	requests := r.usersOfSecret(newPasswordSecret("p1"))
+	require.Len(t, requests, 1)
+	require.Equal(t, "alice", requests[0].Name)
+}
+
+func TestUserDeletion(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec("DROP USER IF EXISTS 'alice'@'%'").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	user := newUser()
+	now := metav1.Now()
+	user.DeletionTimestamp = &now
+	user.Finalizers = []string{StarRocksAccessControlFinalizer}
+	user.Status.UserIdentity = "'alice'@'%'"
+	r := &StarRocksUserReconciler{
+		Client:   fake.NewFakeClient(srapi.Scheme, append(newReadyClusterObjects(), user)...),
+		Recorder: record.NewFakeRecorder(10),
+	}
+	_, err = r.reconcileDeletion(context.Background(), user, db)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Empty(t, user.Finalizers)
+}
@@ -116,6 +116,7 @@ func (r *StarRocksWarehouseReconciler) Reconcile(ctx context.Context, req ctrl.R
 			logger.Error(err, "update warehouse status failed", kvs...)
 			warehouse.Status.Phase = srapi.ComponentFailed
 			warehouse.Status.Reason = err.Error()
+			setSyncFailedCondition(&warehouse.Status.Conditions, warehouse.Generation, err.Error())
 			if updateError := r.UpdateStarRocksWarehouseStatus(ctx, warehouse); updateError != nil {
 				logger.Error(err, "failed to update warehouse status")
 			}
@@ -124,6 +125,7 @@ func (r *StarRocksWarehouseReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	logger.Info("update StarRocksWarehouse status")
+	reconcileWarehouseConditions(warehouse)
 	err = r.UpdateStarRocksWarehouseStatus(ctx, warehouse)
 	if err != nil {
 		logger.Error(err, "update StarRocksWarehouse status failed")
@@ -131,6 +133,10 @@ func (r *StarRocksWarehouseReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	logger.Info("reconcile StarRocksWarehouse success")
+	if status := warehouse.Status.WarehouseComponentStatus; status != nil && status.VolumeExpansion != nil {
+		// the persistent volume claims are resized asynchronously, check the progress periodically.
+		return ctrl.Result{RequeueAfter: volumeExpansionRequeueInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -154,6 +160,7 @@ func handleSyncWarehouseError(ctx context.Context, err error, warehouse *srapi.S
 	logger.Error(err, "sub controller reconciles spec failed")
 	warehouse.Status.Phase = srapi.ComponentFailed
 	warehouse.Status.Reason = err.Error()
+	setSyncFailedCondition(&warehouse.Status.Conditions, warehouse.Generation, err.Error())
 	if errors.Is(err, cn.ErrSpecIsMissing) || errors.Is(err, cn.ErrStarRocksClusterIsMissing) ||
 		errors.Is(err, cn.ErrFeIsNotReady) || errors.Is(err, cn.ErrFailedToGetFeFeatureList) {
 		return true
@@ -0,0 +1,216 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+// storageVolume represents the result of DESC STORAGE VOLUME.
+type storageVolume struct {
+	Name      string
+	Type      string
+	IsDefault bool
+	Locations []string
+	Params    map[string]string
+	Comment   string
+}
+
+// describeStorageVolume returns the storage volume in StarRocks, it returns nil if the storage volume does not exist.
+func describeStorageVolume(ctx context.Context, sqlClient *sqlclient.Client, db *sql.DB, name string) (*storageVolume, error) {
+	rows, err := sqlClient.QueryContext(ctx, db, "SHOW STORAGE VOLUMES")
+	if err != nil {
+		return nil, err
+	}
+	exists := false
+	for _, row := range rows {
+		if row["Storage Volume"] == name {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	rows, err = sqlClient.QueryContext(ctx, db, fmt.Sprintf("DESC STORAGE VOLUME `%s`", name))
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	row := rows[0]
+	volume := &storageVolume{
+		Name:      row["Name"],
+		Type:      row["Type"],
+		IsDefault: strings.EqualFold(row["IsDefault"], "true"),
+		Comment:   row["Comment"],
+		Params:    map[string]string{},
+	}
+	for _, location := range strings.Split(row["Location"], ",") {
+		if location = strings.TrimSpace(location); location != "" {
+			volume.Locations = append(volume.Locations, location)
+		}
+	}
+	if params := row["Params"]; params != "" {
+		if err = json.Unmarshal([]byte(params), &volume.Params); err != nil {
+			return nil, fmt.Errorf("failed to parse the params of storage volume %s: %w", name, err)
+		}
+	}
+	return volume, nil
+}
+
+// storageVolumeType returns the type used by CREATE STORAGE VOLUME, MinIO is a storage volume of S3.
+func storageVolumeType(volumeType srapi.StorageVolumeType) string {
+	if volumeType == srapi.MinIOStorageVolume {
+		return string(srapi.S3StorageVolume)
+	}
+	return string(volumeType)
+}
+
+// storageVolumeLocation returns the location of the storage volume, e.g. s3://bucket/prefix.
+func storageVolumeLocation(spec *srapi.StarRocksStorageVolumeSpec) (string, error) {
+	var location string
+	switch spec.Type {
+	case srapi.S3StorageVolume, srapi.MinIOStorageVolume, srapi.AzureBlobStorageVolume:
+		if spec.Bucket == "" {
+			return "", fmt.Errorf("bucket is required by storage volume of type %s", spec.Type)
+		}
+		scheme := "s3"
+		if spec.Type == srapi.AzureBlobStorageVolume {
+			scheme = "azblob"
+		}
+		location = fmt.Sprintf("%s://%s", scheme, strings.Trim(spec.Bucket, "/"))
+	case srapi.HDFSStorageVolume:
+		if spec.Endpoint == "" {
+			return "", fmt.Errorf("endpoint is required by storage volume of type %s", spec.Type)
+		}
+		location = strings.TrimSuffix(spec.Endpoint, "/")
+	default:
+		return "", fmt.Errorf("unsupported storage volume type %q", spec.Type)
+	}
+	if prefix := strings.Trim(spec.Prefix, "/"); prefix != "" {
+		location = location + "/" + prefix
+	}
+	return location, nil
+}
+
+// storageVolumeProperties returns the properties of the storage volume, and the keys of the properties which are
+// generated from the credentials. StarRocks masks the credentials in DESC STORAGE VOLUME, so they can not be compared.
+func storageVolumeProperties(spec *srapi.StarRocksStorageVolumeSpec,
+	credentials map[string]string) (map[string]string, map[string]bool) {
+	properties := map[string]string{}
+	credentialKeys := map[string]bool{}
+	setCredential := func(key, value string) {
+		if value != "" {
+			properties[key] = value
+			credentialKeys[key] = true
+		}
+	}
+
+	switch spec.Type {
+	case srapi.S3StorageVolume, srapi.MinIOStorageVolume:
+		setIfNotEmpty(properties, "aws.s3.endpoint", spec.Endpoint)
+		setIfNotEmpty(properties, "aws.s3.region", spec.Region)
+		setCredential("aws.s3.access_key", credentials["access_key"])
+		setCredential("aws.s3.secret_key", credentials["secret_key"])
+		if len(credentialKeys) != 0 {
+			properties["aws.s3.use_aws_sdk_default_behavior"] = "false"
+			properties["aws.s3.use_instance_profile"] = "false"
+		}
+		if spec.Type == srapi.MinIOStorageVolume {
+			properties["aws.s3.enable_path_style_access"] = "true"
+		}
+	case srapi.AzureBlobStorageVolume:
+		setIfNotEmpty(properties, "azure.blob.endpoint", spec.Endpoint)
+		setCredential("azure.blob.shared_key", credentials["shared_key"])
+	case srapi.HDFSStorageVolume:
+		setCredential("username", credentials["username"])
+		setCredential("password", credentials["password"])
+	}
+	for key, value := range spec.Properties {
+		properties[key] = value
+		delete(credentialKeys, key)
+	}
+	return properties, credentialKeys
+}
+
+// diffStorageVolume compares the storage volume in StarRocks with the expected one. It returns the drifts which can
+// not be fixed by ALTER STORAGE VOLUME, and the properties which need to be altered.
+func diffStorageVolume(actual *storageVolume, volumeType, location string,
+	properties map[string]string, credentialKeys map[string]bool) ([]string, map[string]string) {
+	var drifts []string
+	if !strings.EqualFold(actual.Type, volumeType) {
+		drifts = append(drifts, fmt.Sprintf("type: expected %s, actual %s", volumeType, actual.Type))
+	}
+	if len(actual.Locations) != 1 || strings.TrimSuffix(actual.Locations[0], "/") != location {
+		drifts = append(drifts, fmt.Sprintf("location: expected %s, actual %s",
+			location, strings.Join(actual.Locations, ",")))
+	}
+
+	changed := map[string]string{}
+	for key, value := range properties {
+		if credentialKeys[key] {
+			continue
+		}
+		if actualValue, ok := actual.Params[key]; !ok || actualValue != value {
+			changed[key] = value
+		}
+	}
+	return drifts, changed
+}
+
+// sortedKeys returns the keys of properties in order.
+func sortedKeys(properties map[string]string) []string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func createStorageVolumeStatement(name, volumeType, location, comment string, properties map[string]string) string {
+	statement := fmt.Sprintf("CREATE STORAGE VOLUME IF NOT EXISTS `%s` TYPE = %s LOCATIONS = (%s)",
+		name, volumeType, quoteString(location))
+	if comment != "" {
+		statement += " COMMENT " + quoteString(comment)
+	}
+	return statement + propertiesClause(properties)
+}
+
+func alterStorageVolumeStatement(name string, properties map[string]string) string {
+	return fmt.Sprintf("ALTER STORAGE VOLUME `%s` SET (%s)", name, propertyPairs(properties))
+}
+
+func alterStorageVolumeCommentStatement(name, comment string) string {
+	return fmt.Sprintf("ALTER STORAGE VOLUME `%s` COMMENT = %s", name, quoteString(comment))
+}
+
+func setDefaultStorageVolumeStatement(name string) string {
+	return fmt.Sprintf("SET `%s` AS DEFAULT STORAGE VOLUME", name)
+}
+
+func dropStorageVolumeStatement(name string) string {
+	return fmt.Sprintf("DROP STORAGE VOLUME IF EXISTS `%s`", name)
+}
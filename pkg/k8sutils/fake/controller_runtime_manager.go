@@ -84,6 +84,17 @@ func NewEnvironment(crds ...WithCRD) *envtest.Environment {
 	return env
 }
 
+// NewEnvironmentFromCRDDirectory is used to create an environment which installs the CRDs generated by controller-gen,
+// e.g. config/crd/bases. Unlike NewEnvironment, the CRDs have the full schema and the status subresource, so the
+// objects are validated and defaulted by kube-apiserver as in a real cluster.
+func NewEnvironmentFromCRDDirectory(paths ...string) *envtest.Environment {
+	return &envtest.Environment{
+		Scheme:                v1.Scheme,
+		CRDDirectoryPaths:     paths,
+		ErrorIfCRDPathMissing: true,
+	}
+}
+
 func WithClusterCRD() WithCRD {
 	return func() *apiextensionsv1.CustomResourceDefinition {
 		return clusterCRD
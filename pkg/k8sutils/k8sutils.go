@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"unicode"
 
@@ -141,6 +142,11 @@ func ApplyConfigMap(ctx context.Context, k8sClient client.Client, configmap *cor
 				return false
 			}
 		}
+		for k, v := range configmap.Annotations {
+			if actual.Annotations[k] != v {
+				return false
+			}
+		}
 		return true
 	}
 
@@ -152,6 +158,28 @@ func ApplyConfigMap(ctx context.Context, k8sClient client.Client, configmap *cor
 	return nil
 }
 
+// ApplySecret creates or updates the secret. Unlike ApplyConfigMap, the last applied annotation is not added, because
+// it would contain the data of the secret.
+func ApplySecret(ctx context.Context, k8sClient client.Client, secret *corev1.Secret) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	logger.Info("create or update secret", "name", secret.Name)
+
+	var actual corev1.Secret
+	err := k8sClient.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, &actual)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return k8sClient.Create(ctx, secret)
+		}
+		return err
+	}
+
+	if !reflect.DeepEqual(secret.Data, actual.Data) {
+		secret.ResourceVersion = actual.ResourceVersion
+		return k8sClient.Update(ctx, secret)
+	}
+	return nil
+}
+
 // ApplyStatefulSet when the object is not exist, create object. if exist and statefulset have been updated, patch the statefulset.
 func ApplyStatefulSet(ctx context.Context, k8sClient client.Client, expect *appsv1.StatefulSet,
 	enableScaleTo1 bool, equal StatefulSetEqual) error {
@@ -166,6 +194,13 @@ func ApplyStatefulSet(ctx context.Context, k8sClient client.Client, expect *apps
 		return err
 	}
 
+	// The statefulset is being recreated, e.g. its volume claim templates are expanded. Wait for the garbage collector
+	// to orphan its pods, otherwise removing the finalizers will delete the pods.
+	if IsOrphaningDependents(&actual) {
+		logger.Info("statefulset is orphaning its pods, wait for it to be deleted", "name", actual.Name)
+		return nil
+	}
+
 	// When user delete the statefulset, we should remove the finalizers.
 	if actual.DeletionTimestamp != nil && actual.Finalizers != nil {
 		actual.Finalizers = nil
@@ -0,0 +1,317 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config renders the config of component, e.g. spec.starRocksFeSpec.config, into the config file of
+// StarRocks, e.g. fe.conf, which is stored in a configMap owned by the operator.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/hash"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	srobject "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
+)
+
+const arrowFlightPort = "arrow_flight_port"
+
+// keyValue is an item of the config file.
+type keyValue struct {
+	key   string
+	value string
+}
+
+// The default config of components, which is the same as the default config in the helm chart. The config file
+// rendered by the operator replaces the one in the image, so the keys which are required by the start scripts, e.g.
+// JAVA_OPTS, and the ports which are used by the operator are always kept. The order matters, because the later
+// keys may refer to the former ones, e.g. JAVA_OPTS refers to LOG_DIR and DATE.
+var (
+	_feDefaults = []keyValue{
+		{"LOG_DIR", "${STARROCKS_HOME}/log"},
+		{"DATE", `"$(date +%Y%m%d-%H%M%S)"`},
+		{"JAVA_OPTS", `"-Dlog4j2.formatMsgNoLookups=true -Xmx8192m -XX:+UseG1GC -Xlog:gc*:${LOG_DIR}/fe.gc.log.$DATE:time"`},
+		{rutils.HTTP_PORT, "8030"},
+		{rutils.RPC_PORT, "9020"},
+		{rutils.QUERY_PORT, "9030"},
+		{rutils.EDIT_LOG_PORT, "9010"},
+		{"mysql_service_nio_enabled", "true"},
+		{"sys_log_level", "INFO"},
+	}
+	_beDefaults = []keyValue{
+		{rutils.BE_PORT, "9060"},
+		{rutils.WEBSERVER_PORT, "8040"},
+		{rutils.HEARTBEAT_SERVICE_PORT, "9050"},
+		{rutils.BRPC_PORT, "8060"},
+		{"sys_log_level", "INFO"},
+	}
+	_cnDefaults = []keyValue{
+		{rutils.THRIFT_PORT, "9060"},
+		{rutils.WEBSERVER_PORT, "8040"},
+		{rutils.HEARTBEAT_SERVICE_PORT, "9050"},
+		{rutils.BRPC_PORT, "8060"},
+		{"sys_log_level", "INFO"},
+	}
+)
+
+// _aliases are the keys which have the same meaning, the default of a key is not added if its alias is configured.
+var _aliases = map[string]string{
+	rutils.BE_PORT:        rutils.THRIFT_PORT,
+	rutils.THRIFT_PORT:    rutils.BE_PORT,
+	rutils.WEBSERVER_PORT: rutils.BE_HTTP_PORT,
+	rutils.BE_HTTP_PORT:   rutils.WEBSERVER_PORT,
+}
+
+// componentSpec returns the StarRocksComponentSpec of FE, BE and CN, or nil for the other specs.
+func componentSpec(spec v1.SpecInterface) *v1.StarRocksComponentSpec {
+	switch v := spec.(type) {
+	case *v1.StarRocksFeSpec:
+		if v != nil {
+			return &v.StarRocksComponentSpec
+		}
+	case *v1.StarRocksBeSpec:
+		if v != nil {
+			return &v.StarRocksComponentSpec
+		}
+	case *v1.StarRocksCnSpec:
+		if v != nil {
+			return &v.StarRocksComponentSpec
+		}
+	}
+	return nil
+}
+
+func defaults(spec v1.SpecInterface) []keyValue {
+	switch spec.(type) {
+	case *v1.StarRocksFeSpec:
+		return _feDefaults
+	case *v1.StarRocksBeSpec:
+		return _beDefaults
+	case *v1.StarRocksCnSpec:
+		return _cnDefaults
+	}
+	return nil
+}
+
+// FileName returns the name of config file of the component, e.g. fe.conf.
+func FileName(spec v1.SpecInterface) string {
+	switch spec.(type) {
+	case *v1.StarRocksFeSpec:
+		return "fe.conf"
+	case *v1.StarRocksBeSpec:
+		return "be.conf"
+	case *v1.StarRocksCnSpec:
+		return "cn.conf"
+	}
+	return ""
+}
+
+// ConfigMapName returns the name of configMap which stores the config file rendered by the operator. The prefix is
+// the name of cluster for FE and BE, and the prefix of subresources for CN, e.g. the warehouse.
+func ConfigMapName(prefix string, spec v1.SpecInterface) string {
+	return load.Name(prefix, spec) + "-config"
+}
+
+// IsManaged returns true if the config of the component is managed by the operator, that is, spec.config is set.
+func IsManaged(spec v1.SpecInterface) bool {
+	component := componentSpec(spec)
+	return component != nil && len(component.Config) > 0
+}
+
+// Merge returns the config of the component merged with the defaults. The keys in spec.config take precedence.
+func Merge(spec v1.SpecInterface) map[string]string {
+	component := componentSpec(spec)
+	if component == nil {
+		return nil
+	}
+	merged := make(map[string]string)
+	for _, kv := range defaults(spec) {
+		if _, ok := component.Config[_aliases[kv.key]]; ok {
+			continue
+		}
+		merged[kv.key] = kv.value
+	}
+	for key, value := range component.Config {
+		merged[key] = value
+	}
+	return merged
+}
+
+// Render renders the merged config into the content of config file. The default keys are rendered first in their
+// order, and the others are sorted by key.
+func Render(spec v1.SpecInterface) string {
+	merged := Merge(spec)
+	var builder strings.Builder
+	rendered := make(map[string]bool)
+	for _, kv := range defaults(spec) {
+		if value, ok := merged[kv.key]; ok {
+			fmt.Fprintf(&builder, "%s = %s\n", kv.key, value)
+			rendered[kv.key] = true
+		}
+	}
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		if !rendered[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&builder, "%s = %s\n", key, merged[key])
+	}
+	return builder.String()
+}
+
+// Parse parses the content of config file rendered by Render, it returns the config as Merge does. The lines which
+// are not in the form of "key = value", e.g. comments, are ignored.
+func Parse(content string) map[string]string {
+	config := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.HasPrefix(key, "#") {
+			continue
+		}
+		config[key] = strings.TrimSpace(value)
+	}
+	return config
+}
+
+// Resolve returns the config in the same form as k8sutils.GetConfig, which is used to resolve the ports.
+func Resolve(spec v1.SpecInterface) (map[string]interface{}, error) {
+	fileName := FileName(spec)
+	return k8sutils.ResolveConfigMap(&corev1.ConfigMap{Data: map[string]string{fileName: Render(spec)}}, fileName)
+}
+
+// Hash returns the hash of the rendered config file, it is added to the annotations of pods, so that the pods are
+// restarted when the config is changed.
+func Hash(spec v1.SpecInterface) string {
+	return hash.HashObject(Render(spec))
+}
+
+// ConfigMapInfo returns the ConfigMapInfo which should be mounted to the pods. If the config is managed by the
+// operator, it refers to the configMap rendered by the operator, otherwise it is spec.configMapInfo.
+func ConfigMapInfo(prefix string, spec v1.SpecInterface) v1.ConfigMapInfo {
+	component := componentSpec(spec)
+	if component == nil {
+		return v1.ConfigMapInfo{}
+	}
+	if !IsManaged(spec) {
+		return component.ConfigMapInfo
+	}
+	return v1.ConfigMapInfo{ConfigMapName: ConfigMapName(prefix, spec), ResolveKey: FileName(spec)}
+}
+
+// MakeConfigMap makes the configMap which stores the rendered config file, it is owned by the object.
+func MakeConfigMap(object srobject.StarRocksObject, prefix string, spec v1.SpecInterface) *corev1.ConfigMap {
+	or := metav1.NewControllerRef(object, object.GroupVersionKind())
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ConfigMapName(prefix, spec),
+			Namespace:       object.Namespace,
+			Labels:          load.Labels(prefix, spec),
+			OwnerReferences: []metav1.OwnerReference{*or},
+		},
+		Data: map[string]string{
+			FileName(spec): Render(spec),
+		},
+	}
+}
+
+// Validate validates spec.config if it is set:
+//  1. it can not be used together with configMapInfo, or a configMap mounted on the config directory.
+//  2. the ports must be valid port numbers, and different from each other.
+//  3. run_mode must be shared_data or shared_nothing.
+func Validate(spec v1.SpecInterface) error {
+	if !IsManaged(spec) {
+		return nil
+	}
+	component := componentSpec(spec)
+	if info := component.ConfigMapInfo; info.ConfigMapName != "" || info.ResolveKey != "" {
+		return errors.New("config can not be used together with configMapInfo")
+	}
+	configDir, fileName := pod.GetConfigDir(spec), FileName(spec)
+	for _, reference := range component.ConfigMaps {
+		if (reference.SubPath == "" && reference.MountPath == configDir) ||
+			(reference.SubPath == fileName && reference.MountPath == filepath.Join(configDir, fileName)) {
+			return fmt.Errorf("config can not be used together with configMap %s mounted on %s", reference.Name,
+				reference.MountPath)
+		}
+	}
+
+	merged := Merge(spec)
+	var portKeys []string
+	switch spec.(type) {
+	case *v1.StarRocksFeSpec:
+		portKeys = []string{rutils.HTTP_PORT, rutils.RPC_PORT, rutils.QUERY_PORT, rutils.EDIT_LOG_PORT, arrowFlightPort}
+		if runMode, ok := merged["run_mode"]; ok && runMode != "shared_data" && runMode != "shared_nothing" {
+			return fmt.Errorf("invalid run_mode %q, it should be shared_data or shared_nothing", runMode)
+		}
+	default:
+		portKeys = []string{rutils.THRIFT_PORT, rutils.WEBSERVER_PORT, rutils.HEARTBEAT_SERVICE_PORT, rutils.BRPC_PORT,
+			arrowFlightPort}
+		for _, key := range []string{rutils.THRIFT_PORT, rutils.WEBSERVER_PORT} {
+			if _, ok := merged[key]; ok {
+				if _, ok = merged[_aliases[key]]; ok {
+					return fmt.Errorf("%s and %s can not be set together", key, _aliases[key])
+				}
+			}
+		}
+	}
+
+	resolved := make(map[string]interface{}, len(merged))
+	for key, value := range merged {
+		resolved[key] = value
+	}
+	for _, key := range append(portKeys, rutils.BE_PORT, rutils.BE_HTTP_PORT) {
+		value, ok := merged[key]
+		if !ok {
+			continue
+		}
+		if port, err := strconv.Atoi(value); err != nil || port <= 0 || port > 65535 {
+			if key == arrowFlightPort && port == -1 {
+				continue
+			}
+			return fmt.Errorf("invalid %s %q, it should be a port number between 1 and 65535", key, value)
+		}
+	}
+
+	// rutils.GetPort resolves the aliases, e.g. thrift_port and be_port are the same port.
+	owners := make(map[int32]string)
+	for _, key := range portKeys {
+		port := rutils.GetPort(resolved, key)
+		if port <= 0 {
+			// e.g. arrow flight is disabled
+			continue
+		}
+		if owner, ok := owners[port]; ok {
+			return fmt.Errorf("%s and %s use the same port %d", owner, key, port)
+		}
+		owners[port] = key
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	srobject "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+)
+
+func newBeSpec(config map[string]string) *v1.StarRocksBeSpec {
+	return &v1.StarRocksBeSpec{
+		StarRocksComponentSpec: v1.StarRocksComponentSpec{Config: config},
+	}
+}
+
+func TestRender(t *testing.T) {
+	feSpec := &v1.StarRocksFeSpec{
+		StarRocksComponentSpec: v1.StarRocksComponentSpec{
+			Config: map[string]string{
+				"sys_log_level": "WARN",
+				"run_mode":      "shared_data",
+				"query_port":    "19030",
+			},
+		},
+	}
+	want := `LOG_DIR = ${STARROCKS_HOME}/log
+DATE = "$(date +%Y%m%d-%H%M%S)"
+JAVA_OPTS = "-Dlog4j2.formatMsgNoLookups=true -Xmx8192m -XX:+UseG1GC -Xlog:gc*:${LOG_DIR}/fe.gc.log.$DATE:time"
+http_port = 8030
+rpc_port = 9020
+query_port = 19030
+edit_log_port = 9010
+mysql_service_nio_enabled = true
+sys_log_level = WARN
+run_mode = shared_data
+`
+	require.Equal(t, want, Render(feSpec))
+
+	// the default of an alias is not rendered
+	beSpec := newBeSpec(map[string]string{"be_http_port": "8041", "enable_metric_calculator": "true"})
+	require.Equal(t, `be_port = 9060
+heartbeat_service_port = 9050
+brpc_port = 8060
+sys_log_level = INFO
+be_http_port = 8041
+enable_metric_calculator = true
+`, Render(beSpec))
+}
+
+func TestParse(t *testing.T) {
+	feSpec := &v1.StarRocksFeSpec{
+		StarRocksComponentSpec: v1.StarRocksComponentSpec{
+			Config: map[string]string{"sys_log_level": "WARN", "run_mode": "shared_data"},
+		},
+	}
+	require.Equal(t, Merge(feSpec), Parse(Render(feSpec)))
+	require.Equal(t, map[string]string{"a": "1", "b": "x = y"}, Parse("# comment\na=1\n\n  b =  x = y \ninvalid\n"))
+}
+
+func TestResolve(t *testing.T) {
+	cnSpec := &v1.StarRocksCnSpec{
+		StarRocksComponentSpec: v1.StarRocksComponentSpec{
+			Config: map[string]string{"Thrift_Port": "19060"},
+		},
+	}
+	config, err := Resolve(cnSpec)
+	require.NoError(t, err)
+	require.Equal(t, int32(19060), rutils.GetPort(config, rutils.THRIFT_PORT))
+	require.Equal(t, int32(8040), rutils.GetPort(config, rutils.WEBSERVER_PORT))
+}
+
+func TestConfigMapInfo(t *testing.T) {
+	beSpec := newBeSpec(nil)
+	beSpec.ConfigMapInfo = v1.ConfigMapInfo{ConfigMapName: "be-cm", ResolveKey: "be.conf"}
+	require.Equal(t, beSpec.ConfigMapInfo, ConfigMapInfo("kube-starrocks", beSpec))
+
+	beSpec = newBeSpec(map[string]string{"sys_log_level": "WARN"})
+	require.Equal(t, v1.ConfigMapInfo{ConfigMapName: "kube-starrocks-be-config", ResolveKey: "be.conf"},
+		ConfigMapInfo("kube-starrocks", beSpec))
+}
+
+func TestMakeConfigMap(t *testing.T) {
+	cluster := &v1.StarRocksCluster{
+		TypeMeta:   metav1.TypeMeta{Kind: "StarRocksCluster", APIVersion: v1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+	}
+	cnSpec := &v1.StarRocksCnSpec{
+		StarRocksComponentSpec: v1.StarRocksComponentSpec{Config: map[string]string{"sys_log_level": "WARN"}},
+	}
+	configMap := MakeConfigMap(srobject.NewFromCluster(cluster), "kube-starrocks", cnSpec)
+	require.Equal(t, "kube-starrocks-cn-config", configMap.Name)
+	require.Equal(t, "default", configMap.Namespace)
+	require.Len(t, configMap.OwnerReferences, 1)
+	require.Equal(t, "kube-starrocks", configMap.OwnerReferences[0].Name)
+	require.Equal(t, Render(cnSpec), configMap.Data["cn.conf"])
+	require.NotEqual(t, Hash(cnSpec), Hash(&v1.StarRocksCnSpec{
+		StarRocksComponentSpec: v1.StarRocksComponentSpec{Config: map[string]string{"sys_log_level": "INFO"}},
+	}))
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    v1.SpecInterface
+		wantErr bool
+	}{
+		{
+			name: "config is not set",
+			spec: func() *v1.StarRocksBeSpec {
+				spec := newBeSpec(nil)
+				spec.ConfigMapInfo = v1.ConfigMapInfo{ConfigMapName: "be-cm", ResolveKey: "be.conf"}
+				return spec
+			}(),
+		},
+		{
+			name: "valid config",
+			spec: newBeSpec(map[string]string{"be_port": "19060", "arrow_flight_port": "-1"}),
+		},
+		{
+			name: "used together with configMapInfo",
+			spec: func() *v1.StarRocksBeSpec {
+				spec := newBeSpec(map[string]string{"sys_log_level": "WARN"})
+				spec.ConfigMapInfo = v1.ConfigMapInfo{ConfigMapName: "be-cm", ResolveKey: "be.conf"}
+				return spec
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "used together with a configMap mounted on the config directory",
+			spec: func() *v1.StarRocksBeSpec {
+				spec := newBeSpec(map[string]string{"sys_log_level": "WARN"})
+				spec.ConfigMaps = []v1.ConfigMapReference{
+					{Name: "be-cm", MountPath: "/opt/starrocks/be/conf/be.conf", SubPath: "be.conf"},
+				}
+				return spec
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid port",
+			spec:    newBeSpec(map[string]string{"brpc_port": "abc"}),
+			wantErr: true,
+		},
+		{
+			name:    "port out of range",
+			spec:    newBeSpec(map[string]string{"heartbeat_service_port": "70000"}),
+			wantErr: true,
+		},
+		{
+			name:    "ports collide",
+			spec:    newBeSpec(map[string]string{"brpc_port": "9060"}),
+			wantErr: true,
+		},
+		{
+			name:    "ports collide with alias",
+			spec:    newBeSpec(map[string]string{"be_http_port": "8060"}),
+			wantErr: true,
+		},
+		{
+			name:    "alias are set together",
+			spec:    newBeSpec(map[string]string{"be_port": "19060", "thrift_port": "19060"}),
+			wantErr: true,
+		},
+		{
+			name: "invalid run_mode",
+			spec: &v1.StarRocksFeSpec{
+				StarRocksComponentSpec: v1.StarRocksComponentSpec{Config: map[string]string{"run_mode": "shared"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fe ports collide",
+			spec: &v1.StarRocksFeSpec{
+				StarRocksComponentSpec: v1.StarRocksComponentSpec{Config: map[string]string{"arrow_flight_port": "9030"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.spec)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
@@ -33,6 +34,9 @@ type StarRocksObject struct {
 
 	// IsWarehouseObject indicates whether this object is a StarRocksWarehouse object.
 	IsWarehouseObject bool
+
+	// Object is the StarRocksCluster or StarRocksWarehouse, which is used to record events.
+	Object runtime.Object
 }
 
 func NewFromCluster(cluster *srapi.StarRocksCluster) StarRocksObject {
@@ -43,6 +47,7 @@ func NewFromCluster(cluster *srapi.StarRocksCluster) StarRocksObject {
 		Kind:                  StarRocksClusterKind,
 		SubResourcePrefixName: cluster.Name,
 		IsWarehouseObject:     false,
+		Object:                cluster,
 	}
 }
 
@@ -54,9 +59,22 @@ func NewFromWarehouse(warehouse *srapi.StarRocksWarehouse) StarRocksObject {
 		Kind:                  StarRocksWarehouseKind,
 		SubResourcePrefixName: GetPrefixNameForWarehouse(warehouse.Name),
 		IsWarehouseObject:     true,
+		Object:                warehouse,
 	}
 }
 
+// NewFromObserverGroup creates a StarRocksObject for a group of FE observers. The sub resources of the group are owned
+// by StarRocksCluster, but they are prefixed by the name of the group to avoid conflicting with FE followers.
+func NewFromObserverGroup(cluster *srapi.StarRocksCluster, groupName string) StarRocksObject {
+	object := NewFromCluster(cluster)
+	object.SubResourcePrefixName = GetPrefixNameForObserverGroup(cluster.Name, groupName)
+	return object
+}
+
+func GetPrefixNameForObserverGroup(clusterName string, groupName string) string {
+	return clusterName + "-" + groupName + "-observer"
+}
+
 func GetPrefixNameForWarehouse(warehouseName string) string {
 	return warehouseName + "-warehouse"
 }
@@ -45,6 +45,7 @@ func TestNewFromCluster(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			tt.want.Object = tt.args.cluster
 			if got := NewFromCluster(tt.args.cluster); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewFromCluster() = %v, want %v", got, tt.want)
 			}
@@ -92,6 +93,7 @@ func TestNewFromWarehouse(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			tt.want.Object = tt.args.warehouse
 			if got := NewFromWarehouse(tt.args.warehouse); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewFromWarehouse() = %v, want %v", got, tt.want)
 			}
@@ -188,3 +190,29 @@ func TestStarRocksObject_GetWarehouseNameInFE(t *testing.T) {
 		})
 	}
 }
+
+func TestNewFromObserverGroup(t *testing.T) {
+	cluster := &srapi.StarRocksCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "StarRocksCluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "starrocks",
+		},
+	}
+	want := StarRocksObject{
+		TypeMeta: &metav1.TypeMeta{
+			Kind: "StarRocksCluster",
+		},
+		ObjectMeta: &metav1.ObjectMeta{
+			Name: "starrocks",
+		},
+		ClusterName:           "starrocks",
+		Kind:                  "StarRocksCluster",
+		SubResourcePrefixName: "starrocks-read-observer",
+		Object:                cluster,
+	}
+	if got := NewFromObserverGroup(cluster, "read"); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewFromObserverGroup() = %v, want %v", got, want)
+	}
+}
@@ -110,7 +110,7 @@ func MountConfigMaps(spec v1.SpecInterface, volumes []corev1.Volume, volumeMount
 	prerequisitesOfChangingMode := spec != nil && (spec.GetCommand() != nil || spec.GetArgs() != nil)
 
 	for _, reference := range references {
-		volumeName := getVolumeName(v1.MountInfo(reference))
+		volumeName := getVolumeName(reference.MountInfo())
 		volumes = append(volumes, corev1.Volume{
 			Name: volumeName,
 			VolumeSource: corev1.VolumeSource{
@@ -164,7 +164,7 @@ func MountConfigMapInfo(volumes []corev1.Volume, volumeMounts []corev1.VolumeMou
 func MountSecrets(volumes []corev1.Volume, volumeMounts []corev1.VolumeMount,
 	references []v1.SecretReference) ([]corev1.Volume, []corev1.VolumeMount) {
 	for _, reference := range references {
-		volumeName := getVolumeName(v1.MountInfo(reference))
+		volumeName := getVolumeName(reference.MountInfo())
 		volumes = append(volumes, corev1.Volume{
 			Name: volumeName,
 			VolumeSource: corev1.VolumeSource{
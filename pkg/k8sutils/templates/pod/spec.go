@@ -24,6 +24,7 @@ import (
 
 	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/common"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/hash"
 	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
 )
@@ -289,8 +290,13 @@ func Spec(spec v1.SpecInterface, container corev1.Container, volumes []corev1.Vo
 	return podSpec
 }
 
-func Annotations(spec v1.SpecInterface) map[string]string {
+// Annotations returns the annotations of pods. Besides the annotations in spec, it contains the hashes of the
+// configMaps and secrets referenced by spec, see ConfigMapHashAnnotation and SecretHashAnnotation.
+func Annotations(spec v1.SpecInterface, referenceHashes map[string]string) map[string]string {
 	annotations := make(map[string]string)
+	for k, v := range referenceHashes {
+		annotations[k] = v
+	}
 	for k, v := range spec.GetAnnotations() {
 		annotations[k] = v
 	}
@@ -435,3 +441,26 @@ func ContainerArgs(spec v1.SpecInterface) []string {
 
 	return []string{"$(FE_SERVICE_NAME)"}
 }
+
+// ConfigMapHashAnnotation returns the key of annotation which stores the hash of the configMap.
+func ConfigMapHashAnnotation(name string) string {
+	return referenceHashAnnotation(v1.ConfigMapHashAnnotationPrefix, name)
+}
+
+// SecretHashAnnotation returns the key of annotation which stores the hash of the secret.
+func SecretHashAnnotation(name string) string {
+	return referenceHashAnnotation(v1.SecretHashAnnotationPrefix, name)
+}
+
+func referenceHashAnnotation(prefix, name string) string {
+	// the name part of an annotation key must be no more than 63 characters, the long name is truncated and
+	// suffixed with its hash to keep it unique.
+	const maxNameLength = 63
+	key := prefix + name
+	domain, keyName, _ := strings.Cut(key, "/")
+	if len(keyName) > maxNameLength {
+		suffix := hash.HashObject(name)
+		keyName = keyName[:maxNameLength-len(suffix)-1] + "-" + suffix
+	}
+	return domain + "/" + keyName
+}
@@ -18,8 +18,10 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/yaml"
 
@@ -431,7 +433,8 @@ func TestSecurityContext(t *testing.T) {
 
 func TestAnnotations(t *testing.T) {
 	type args struct {
-		spec v1.SpecInterface
+		spec            v1.SpecInterface
+		referenceHashes map[string]string
 	}
 	tests := []struct {
 		name string
@@ -453,10 +456,27 @@ func TestAnnotations(t *testing.T) {
 				"v1": "v1",
 			},
 		},
+		{
+			name: "test annotations with reference hashes",
+			args: args{
+				spec: &v1.StarRocksFeSpec{
+					StarRocksComponentSpec: v1.StarRocksComponentSpec{
+						StarRocksLoadSpec: v1.StarRocksLoadSpec{
+							Annotations: map[string]string{"v1": "v1"},
+						},
+					},
+				},
+				referenceHashes: map[string]string{ConfigMapHashAnnotation("fe-cm"): "123"},
+			},
+			want: map[string]string{
+				"v1": "v1",
+				"app.starrocks.components/configmap-fe-cm": "123",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := Annotations(tt.args.spec); !reflect.DeepEqual(got, tt.want) {
+			if got := Annotations(tt.args.spec, tt.args.referenceHashes); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Annotations() = %v, want %v", got, tt.want)
 			}
 		})
@@ -659,3 +679,13 @@ func TestGetPreStopCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestReferenceHashAnnotation(t *testing.T) {
+	require.Equal(t, "app.starrocks.components/secret-s3-credential", SecretHashAnnotation("s3-credential"))
+
+	name := strings.Repeat("a", 60)
+	key := ConfigMapHashAnnotation(name)
+	_, keyName, _ := strings.Cut(key, "/")
+	require.Len(t, keyName, 63)
+	require.NotEqual(t, key, ConfigMapHashAnnotation(strings.Repeat("a", 61)))
+}
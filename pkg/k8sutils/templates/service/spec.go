@@ -45,6 +45,39 @@ func MakeSearchService(serviceName string, externalService *corev1.Service, port
 
 // SearchServiceName get the domain service name, the domain service for statefulset.
 // domain service have PublishNotReadyAddresses. while used PublishNotReadyAddresses, the fe start need all instance domain can resolve.
+// MakeLeaderService makes a ClusterIP service which only selects the FE leader by the pod label FeRoleLabelKey.
+// The ports are the same as the external service of FE, but the node ports are not used.
+func MakeLeaderService(serviceName string, externalService *corev1.Service, defaultLabels map[string]string) *corev1.Service {
+	leaderSvc := &corev1.Service{}
+	externalService.ObjectMeta.DeepCopyInto(&leaderSvc.ObjectMeta)
+	leaderSvc.Annotations = nil
+	leaderSvc.Name = serviceName
+	leaderSvc.Labels = defaultLabels
+
+	selector := make(map[string]string, len(externalService.Spec.Selector)+1)
+	for key, value := range externalService.Spec.Selector {
+		selector[key] = value
+	}
+	selector[v1.FeRoleLabelKey] = v1.FeRoleLeader
+
+	ports := make([]corev1.ServicePort, 0, len(externalService.Spec.Ports))
+	for _, port := range externalService.Spec.Ports {
+		port.NodePort = 0
+		ports = append(ports, port)
+	}
+	leaderSvc.Spec = corev1.ServiceSpec{
+		Type:     corev1.ServiceTypeClusterIP,
+		Ports:    ports,
+		Selector: selector,
+	}
+	return leaderSvc
+}
+
+// LeaderServiceName returns the name of the service which only selects the FE leader.
+func LeaderServiceName(clusterName string) string {
+	return clusterName + "-" + v1.DEFAULT_FE + "-leader"
+}
+
 func SearchServiceName(clusterName string, spec v1.SpecInterface) string {
 	switch spec.(type) {
 	case *v1.StarRocksBeSpec:
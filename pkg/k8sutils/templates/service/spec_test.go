@@ -220,3 +220,37 @@ func TestGetFeExternalServiceName(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeLeaderService(t *testing.T) {
+	externalService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-fe-service",
+			Namespace:   "default",
+			Labels:      map[string]string{"user-label": "test"},
+			Annotations: map[string]string{"service.beta.kubernetes.io/load-balancer-source-ranges": "10.0.0.0/8"},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeNodePort,
+			Selector: map[string]string{v1.ComponentLabelKey: v1.DEFAULT_FE},
+			Ports:    []corev1.ServicePort{{Name: "query", Port: 9030, NodePort: 30030}},
+		},
+	}
+	labels := map[string]string{v1.ComponentLabelKey: v1.DEFAULT_FE}
+
+	want := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-fe-leader", Namespace: "default", Labels: labels},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{v1.ComponentLabelKey: v1.DEFAULT_FE, v1.FeRoleLabelKey: v1.FeRoleLeader},
+			Ports:    []corev1.ServicePort{{Name: "query", Port: 9030}},
+		},
+	}
+	got := MakeLeaderService(LeaderServiceName("test"), externalService, labels)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MakeLeaderService() = %v, want %v", got, want)
+	}
+	// the selector of the external service is not changed.
+	if len(externalService.Spec.Selector) != 1 {
+		t.Errorf("the selector of external service is changed: %v", externalService.Spec.Selector)
+	}
+}
@@ -31,13 +31,16 @@ import (
 
 const STARROCKS_WAREHOUSE_FINALIZER = "starrocks.com.starrockswarehouse/protection"
 
+// IsClaimTemplate returns whether the storage volume is rendered into the volumeClaimTemplates of statefulset.
+// The emptyDir, hostPath and zero-size storage volumes are mounted into the pod template directly.
+func IsClaimTemplate(vm v1.StorageVolume) bool {
+	return pod.SpecialStorageClassName(vm) == "" && !strings.HasPrefix(vm.StorageSize, "0")
+}
+
 func PVCList(volumes []v1.StorageVolume) []corev1.PersistentVolumeClaim {
 	var pvcs []corev1.PersistentVolumeClaim
 	for _, vm := range volumes {
-		if name := pod.SpecialStorageClassName(vm); name != "" {
-			continue
-		}
-		if strings.HasPrefix(vm.StorageSize, "0") {
+		if !IsClaimTemplate(vm) {
 			continue
 		}
 		pvc := corev1.PersistentVolumeClaim{
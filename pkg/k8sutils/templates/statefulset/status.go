@@ -25,6 +25,10 @@ import (
 // Status returns a message describing statefulset status, and a bool value indicating if the status is considered done.
 // Copy from kubelet
 func Status(sts *appsv1.StatefulSet) (string, bool, error) {
+	// the pods of OnDelete statefulset are updated after they are deleted, e.g. by the leader-aware rolling update of FE.
+	if sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return onDeleteStatus(sts)
+	}
 	if sts.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType {
 		return "", true, fmt.Errorf("rollout status is only available for %s strategy type", appsv1.RollingUpdateStatefulSetStrategyType)
 	}
@@ -50,3 +54,20 @@ func Status(sts *appsv1.StatefulSet) (string, bool, error) {
 	return fmt.Sprintf("statefulset rolling update complete %d pods at revision %s",
 		sts.Status.CurrentReplicas, sts.Status.CurrentRevision), true, nil
 }
+
+// onDeleteStatus returns a message describing the status of an OnDelete statefulset, it is done when all the pods are
+// ready and updated to the latest revision.
+func onDeleteStatus(sts *appsv1.StatefulSet) (string, bool, error) {
+	if sts.Status.ObservedGeneration == 0 || sts.Generation > sts.Status.ObservedGeneration {
+		return "Waiting for statefulset spec update to be observed", false, nil
+	}
+	if sts.Spec.Replicas != nil && sts.Status.ReadyReplicas < *sts.Spec.Replicas {
+		return fmt.Sprintf("Waiting for %d pods to be ready", *sts.Spec.Replicas-sts.Status.ReadyReplicas), false, nil
+	}
+	if sts.Spec.Replicas != nil && sts.Status.UpdatedReplicas < *sts.Spec.Replicas {
+		return fmt.Sprintf("Waiting for %d pods to be deleted and updated to revision %s",
+			*sts.Spec.Replicas-sts.Status.UpdatedReplicas, sts.Status.UpdateRevision), false, nil
+	}
+	return fmt.Sprintf("statefulset update complete %d pods at revision %s",
+		sts.Status.UpdatedReplicas, sts.Status.UpdateRevision), true, nil
+}
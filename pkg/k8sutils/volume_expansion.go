@@ -0,0 +1,276 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+)
+
+// ExpandPersistentVolumeClaims expands the persistent volume claims of the statefulset when the storage size in the
+// volume claim templates is increased.
+// The volume claim templates of a statefulset are immutable, so after the persistent volume claims of all the pods are
+// patched, the statefulset is deleted with the orphan propagation policy, which is the same as
+// `kubectl delete --cascade=orphan`. The pods keep running, and they are adopted by the statefulset which is created
+// again by ApplyStatefulSet with the new volume claim templates.
+// The storage size can not be decreased, and it can only be increased if allowVolumeExpansion of the storage class is
+// true. Otherwise, the volume claim template is skipped: its actual storage size is kept in expect, so that the
+// statefulset can still be applied, and the pods are reported as failed. It returns the progress of every pod, which is
+// nil if all the persistent volume claims have been expanded.
+func ExpandPersistentVolumeClaims(ctx context.Context, k8sClient client.Client,
+	expect *appsv1.StatefulSet) (*srapi.VolumeExpansionStatus, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	var actual appsv1.StatefulSet
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: expect.Namespace, Name: expect.Name}, &actual); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	expanded, skipped, err := expandedVolumeClaimTemplates(ctx, k8sClient, &actual, expect)
+	if err != nil {
+		return nil, err
+	}
+	for _, reason := range skipped {
+		logger.Info("skip volume expansion", "name", expect.Name, "reason", reason)
+	}
+
+	replicas := max(getReplicas(&actual), getReplicas(expect))
+	var pods []srapi.PodVolumeExpansionStatus
+	var errs []string
+	finished := true
+	for ordinal := int32(0); ordinal < replicas; ordinal++ {
+		podName := fmt.Sprintf("%s-%d", expect.Name, ordinal)
+		podStatus := srapi.PodVolumeExpansionStatus{Name: podName, Phase: srapi.VolumeExpansionDone}
+		for i := range expect.Spec.VolumeClaimTemplates {
+			template := &expect.Spec.VolumeClaimTemplates[i]
+			size, ok := template.Spec.Resources.Requests[corev1.ResourceStorage]
+			if !ok {
+				continue
+			}
+			pvcName := fmt.Sprintf("%s-%s", template.Name, podName)
+			phase, reason, err := expandPersistentVolumeClaim(ctx, k8sClient, expect.Namespace, pvcName, size)
+			if err != nil {
+				logger.Error(err, "expand persistent volume claim failed", "pvc", pvcName)
+				errs = append(errs, err.Error())
+			}
+			if volumeExpansionPhaseOrder(phase) > volumeExpansionPhaseOrder(podStatus.Phase) {
+				podStatus.Phase = phase
+				podStatus.Reason = reason
+			}
+		}
+		if len(skipped) != 0 {
+			podStatus.Phase = srapi.VolumeExpansionFailed
+			podStatus.Reason = strings.Join(skipped, "; ")
+		}
+		if podStatus.Phase != srapi.VolumeExpansionDone {
+			finished = false
+		}
+		pods = append(pods, podStatus)
+	}
+
+	var status *srapi.VolumeExpansionStatus
+	if !finished {
+		status = &srapi.VolumeExpansionStatus{Pods: pods}
+	}
+	if len(errs) != 0 {
+		return status, fmt.Errorf("failed to expand persistent volume claims: %s", strings.Join(errs, "; "))
+	}
+
+	if len(expanded) != 0 && actual.DeletionTimestamp.IsZero() {
+		logger.Info("recreate statefulset with the expanded volume claim templates", "name", actual.Name,
+			"volumeClaimTemplates", expanded)
+		if err = k8sClient.Delete(ctx, &actual, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil &&
+			!apierrors.IsNotFound(err) {
+			return status, err
+		}
+	}
+	return status, nil
+}
+
+// IsOrphaningDependents returns true if the statefulset is being deleted with the orphan propagation policy, and the
+// garbage collector has not removed the owner references of its pods yet.
+func IsOrphaningDependents(sts *appsv1.StatefulSet) bool {
+	if sts.DeletionTimestamp.IsZero() {
+		return false
+	}
+	for _, finalizer := range sts.Finalizers {
+		if finalizer == metav1.FinalizerOrphanDependents {
+			return true
+		}
+	}
+	return false
+}
+
+// expandedVolumeClaimTemplates returns the names of the volume claim templates whose storage size is increased, and
+// the reasons why the others are skipped. The storage size of a skipped template is reset to the actual one in expect.
+func expandedVolumeClaimTemplates(ctx context.Context, k8sClient client.Client,
+	actual, expect *appsv1.StatefulSet) ([]string, []string, error) {
+	actualSizes := make(map[string]resource.Quantity)
+	for _, template := range actual.Spec.VolumeClaimTemplates {
+		if size, ok := template.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			actualSizes[template.Name] = size
+		}
+	}
+
+	var expanded, skipped []string
+	for i := range expect.Spec.VolumeClaimTemplates {
+		template := &expect.Spec.VolumeClaimTemplates[i]
+		size, ok := template.Spec.Resources.Requests[corev1.ResourceStorage]
+		if !ok {
+			continue
+		}
+		actualSize, ok := actualSizes[template.Name]
+		if !ok {
+			continue
+		}
+		switch size.Cmp(actualSize) {
+		case 1:
+			allowed, reason, err := allowVolumeExpansion(ctx, k8sClient, expect, template)
+			if err != nil {
+				return nil, nil, err
+			}
+			if allowed {
+				expanded = append(expanded, template.Name)
+				continue
+			}
+			skipped = append(skipped, reason)
+		case -1:
+			skipped = append(skipped, fmt.Sprintf("the storage size of %s can not be decreased from %s to %s",
+				template.Name, actualSize.String(), size.String()))
+		default:
+			continue
+		}
+		template.Spec.Resources.Requests[corev1.ResourceStorage] = actualSize
+	}
+	return expanded, skipped, nil
+}
+
+// allowVolumeExpansion checks allowVolumeExpansion of the storage class used by the volume claim template. The storage
+// class is specified by the template, or it is the one of the existing persistent volume claim of the first pod, which
+// is filled by kubernetes with the default storage class. If the storage class can not be found or read, it is left to
+// kubernetes to validate the expansion.
+func allowVolumeExpansion(ctx context.Context, k8sClient client.Client, sts *appsv1.StatefulSet,
+	template *corev1.PersistentVolumeClaim) (bool, string, error) {
+	var storageClassName string
+	if template.Spec.StorageClassName != nil {
+		storageClassName = *template.Spec.StorageClassName
+	} else {
+		var pvc corev1.PersistentVolumeClaim
+		pvcName := fmt.Sprintf("%s-%s-0", template.Name, sts.Name)
+		err := k8sClient.Get(ctx, types.NamespacedName{Namespace: sts.Namespace, Name: pvcName}, &pvc)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false, "", err
+		}
+		if err == nil && pvc.Spec.StorageClassName != nil {
+			storageClassName = *pvc.Spec.StorageClassName
+		}
+	}
+	if storageClassName == "" {
+		return true, "", nil
+	}
+
+	var storageClass storagev1.StorageClass
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: storageClassName}, &storageClass); err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			return true, "", nil
+		}
+		return false, "", err
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return false, fmt.Sprintf("the storage size of %s can not be increased, because allowVolumeExpansion of "+
+			"storage class %s is not true", template.Name, storageClassName), nil
+	}
+	return true, "", nil
+}
+
+// expandPersistentVolumeClaim patches the storage request of the persistent volume claim if it is smaller than size,
+// and returns the phase of expansion.
+func expandPersistentVolumeClaim(ctx context.Context, k8sClient client.Client, namespace string, name string,
+	size resource.Quantity) (srapi.VolumeExpansionPhase, string, error) {
+	var pvc corev1.PersistentVolumeClaim
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &pvc); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the pod has not been created, the persistent volume claim will be created with the new size.
+			return srapi.VolumeExpansionDone, "", nil
+		}
+		return srapi.VolumeExpansionFailed, err.Error(), err
+	}
+
+	request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if request.Cmp(size) < 0 {
+		logr.FromContextOrDiscard(ctx).Info("expand persistent volume claim", "pvc", name, "from", request.String(),
+			"to", size.String())
+		patch := client.MergeFrom(pvc.DeepCopy())
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+		if err := k8sClient.Patch(ctx, &pvc, patch); err != nil {
+			return srapi.VolumeExpansionFailed, fmt.Sprintf("%s: %v", name, err), err
+		}
+	}
+
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if ok && capacity.Cmp(size) >= 0 {
+		return srapi.VolumeExpansionDone, "", nil
+	}
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending &&
+			condition.Status == corev1.ConditionTrue {
+			return srapi.VolumeExpansionFileSystemResizePending,
+				fmt.Sprintf("%s: the file system will be resized after the pod is restarted", name), nil
+		}
+	}
+	return srapi.VolumeExpansionResizing, fmt.Sprintf("%s: resizing to %s", name, size.String()), nil
+}
+
+// volumeExpansionPhaseOrder is used to report the phase of the persistent volume claim which is the furthest from done.
+func volumeExpansionPhaseOrder(phase srapi.VolumeExpansionPhase) int {
+	switch phase {
+	case srapi.VolumeExpansionFailed:
+		return 3
+	case srapi.VolumeExpansionFileSystemResizePending:
+		return 2
+	case srapi.VolumeExpansionResizing:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func getReplicas(sts *appsv1.StatefulSet) int32 {
+	if sts.Spec.Replicas != nil {
+		return *sts.Spec.Replicas
+	}
+	return 1
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutils_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+)
+
+func newStatefulSetWithStorage(size string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks-be", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: rutils.GetInt32Pointer(2),
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "be-data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func newPersistentVolumeClaim(name string, request string, capacity string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(request)},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+		},
+	}
+}
+
+func TestExpandPersistentVolumeClaims(t *testing.T) {
+	ctx := context.Background()
+	pvc1 := newPersistentVolumeClaim("be-data-kube-starrocks-be-1", "10Gi", "10Gi")
+	pvc1.Status.Conditions = []corev1.PersistentVolumeClaimCondition{
+		{Type: corev1.PersistentVolumeClaimFileSystemResizePending, Status: corev1.ConditionTrue},
+	}
+	k8sClient := fake.NewFakeClient(srapi.Scheme, newStatefulSetWithStorage("10Gi"),
+		newPersistentVolumeClaim("be-data-kube-starrocks-be-0", "10Gi", "10Gi"), pvc1)
+
+	expect := newStatefulSetWithStorage("20Gi")
+	status, err := k8sutils.ExpandPersistentVolumeClaims(ctx, k8sClient, expect)
+	require.NoError(t, err)
+	require.Equal(t, &srapi.VolumeExpansionStatus{Pods: []srapi.PodVolumeExpansionStatus{
+		{
+			Name:   "kube-starrocks-be-0",
+			Phase:  srapi.VolumeExpansionResizing,
+			Reason: "be-data-kube-starrocks-be-0: resizing to 20Gi",
+		},
+		{
+			Name:   "kube-starrocks-be-1",
+			Phase:  srapi.VolumeExpansionFileSystemResizePending,
+			Reason: "be-data-kube-starrocks-be-1: the file system will be resized after the pod is restarted",
+		},
+	}}, status)
+
+	// the persistent volume claims are patched.
+	for _, name := range []string{"be-data-kube-starrocks-be-0", "be-data-kube-starrocks-be-1"} {
+		var pvc corev1.PersistentVolumeClaim
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, &pvc))
+		request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		require.Equal(t, "20Gi", request.String())
+	}
+
+	// the statefulset is deleted, and it is created again with the new volume claim templates.
+	var sts appsv1.StatefulSet
+	err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "kube-starrocks-be"}, &sts)
+	require.True(t, apierrors.IsNotFound(err))
+	require.NoError(t, k8sutils.ApplyStatefulSet(ctx, k8sClient, expect, true, rutils.StatefulSetDeepEqual))
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "kube-starrocks-be"}, &sts))
+	size := sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+	require.Equal(t, "20Gi", size.String())
+
+	// the volumes have been resized.
+	for _, name := range []string{"be-data-kube-starrocks-be-0", "be-data-kube-starrocks-be-1"} {
+		var pvc corev1.PersistentVolumeClaim
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, &pvc))
+		pvc.Status = corev1.PersistentVolumeClaimStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+		}
+		require.NoError(t, k8sClient.Update(ctx, &pvc))
+	}
+	status, err = k8sutils.ExpandPersistentVolumeClaims(ctx, k8sClient, expect)
+	require.NoError(t, err)
+	require.Nil(t, status)
+}
+
+func TestExpandPersistentVolumeClaimsDecreased(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fake.NewFakeClient(srapi.Scheme, newStatefulSetWithStorage("20Gi"))
+	expect := newStatefulSetWithStorage("10Gi")
+	status, err := k8sutils.ExpandPersistentVolumeClaims(ctx, k8sClient, expect)
+	require.NoError(t, err)
+	reason := "the storage size of be-data can not be decreased from 20Gi to 10Gi"
+	require.Equal(t, &srapi.VolumeExpansionStatus{Pods: []srapi.PodVolumeExpansionStatus{
+		{Name: "kube-starrocks-be-0", Phase: srapi.VolumeExpansionFailed, Reason: reason},
+		{Name: "kube-starrocks-be-1", Phase: srapi.VolumeExpansionFailed, Reason: reason},
+	}}, status)
+
+	// the actual storage size is kept, and the statefulset is not deleted.
+	size := expect.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+	require.Equal(t, "20Gi", size.String())
+	var sts appsv1.StatefulSet
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "kube-starrocks-be"}, &sts))
+}
+
+func TestExpandPersistentVolumeClaimsNotAllowed(t *testing.T) {
+	ctx := context.Background()
+	pvc0 := newPersistentVolumeClaim("be-data-kube-starrocks-be-0", "10Gi", "10Gi")
+	pvc0.Spec.StorageClassName = rutils.GetStringPointer("standard")
+	k8sClient := fake.NewFakeClient(srapi.Scheme, newStatefulSetWithStorage("10Gi"), pvc0,
+		newPersistentVolumeClaim("be-data-kube-starrocks-be-1", "10Gi", "10Gi"),
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard"}})
+
+	expect := newStatefulSetWithStorage("20Gi")
+	status, err := k8sutils.ExpandPersistentVolumeClaims(ctx, k8sClient, expect)
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	for _, pod := range status.Pods {
+		require.Equal(t, srapi.VolumeExpansionFailed, pod.Phase)
+		require.Equal(t, "the storage size of be-data can not be increased, because allowVolumeExpansion of "+
+			"storage class standard is not true", pod.Reason)
+	}
+	size := expect.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+	require.Equal(t, "10Gi", size.String())
+
+	// the persistent volume claims are not patched.
+	var pvc corev1.PersistentVolumeClaim
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: pvc0.Name}, &pvc))
+	request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	require.Equal(t, "10Gi", request.String())
+}
+
+func TestApplyStatefulSetOrphaningDependents(t *testing.T) {
+	now := metav1.Now()
+	actual := newStatefulSetWithStorage("10Gi")
+	actual.DeletionTimestamp = &now
+	actual.Finalizers = []string{metav1.FinalizerOrphanDependents}
+	k8sClient := fake.NewFakeClient(srapi.Scheme, actual)
+
+	require.NoError(t, k8sutils.ApplyStatefulSet(context.Background(), k8sClient, newStatefulSetWithStorage("20Gi"),
+		true, rutils.StatefulSetDeepEqual))
+	var sts appsv1.StatefulSet
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "kube-starrocks-be"}, &sts))
+	require.Equal(t, []string{metav1.FinalizerOrphanDependents}, sts.Finalizers)
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metricsadapter serves the metrics of StarRocks warehouses through the external metrics API, so that the HPA
+// of CN can scale on the load of queries instead of CPU. The API is served by the webhook server of operator, and the
+// APIService of external.metrics.k8s.io/v1beta1 must point to the service of webhook server. The requests are
+// authenticated and authorized by kube-apiserver, see RequestHeaderAuthorizer.
+package metricsadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+const (
+	Group   = "external.metrics.k8s.io"
+	Version = "v1beta1"
+
+	apiPath = "/apis/" + Group + "/" + Version
+
+	// DefaultCacheTTL is how long the metrics of a cluster are cached. HPA controller gets every metric of every HPA
+	// in a sync period, which is 15s by default, the cache makes them share one query to FE.
+	DefaultCacheTTL = 10 * time.Second
+)
+
+// WarehouseMetrics are the metrics of the warehouses in a StarRocksCluster, keyed by the name of warehouse in FE.
+type WarehouseMetrics map[string]map[srapi.StarRocksMetricName]int64
+
+// MetricsQuerier queries the metrics of warehouses from the FE of a StarRocksCluster.
+type MetricsQuerier func(ctx context.Context, src *srapi.StarRocksCluster) (WarehouseMetrics, error)
+
+// Adapter is the http.Handler of the external metrics API.
+type Adapter struct {
+	k8sClient  client.Client
+	authorizer Authorizer
+	query      MetricsQuerier
+	cacheTTL   time.Duration
+
+	mu    sync.Mutex
+	cache map[types.NamespacedName]cachedMetrics
+}
+
+type cachedMetrics struct {
+	metrics   WarehouseMetrics
+	timestamp time.Time
+}
+
+// externalMetricValueList and externalMetricValue are the types of external.metrics.k8s.io/v1beta1, they are the
+// same as the types in k8s.io/metrics/pkg/apis/external_metrics/v1beta1.
+type externalMetricValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []externalMetricValue `json:"items"`
+}
+
+type externalMetricValue struct {
+	MetricName   string            `json:"metricName"`
+	MetricLabels map[string]string `json:"metricLabels"`
+	Timestamp    metav1.Time       `json:"timestamp"`
+	Value        resource.Quantity `json:"value"`
+}
+
+// New creates an Adapter which queries the metrics from FE by SQL, the requests are rejected unless the authorizer
+// allows them.
+func New(k8sClient client.Client, authorizer Authorizer) *Adapter {
+	return &Adapter{
+		k8sClient:  k8sClient,
+		authorizer: authorizer,
+		query:      QueryFE(k8sClient),
+		cacheTTL:   DefaultCacheTTL,
+		cache:      make(map[types.NamespacedName]cachedMetrics),
+	}
+}
+
+// Setup registers the external metrics API to the webhook server of manager, and makes the webhook server ask for
+// the client certificate of kube-apiserver.
+func Setup(mgr ctrl.Manager) {
+	adapter := New(mgr.GetClient(), NewRequestHeaderAuthorizer(mgr.GetAPIReader(), mgr.GetClient()))
+	server := mgr.GetWebhookServer()
+	server.TLSOpts = append(server.TLSOpts, RequestClientCert)
+	server.Register(apiPath, adapter)
+	server.Register(apiPath+"/", adapter)
+}
+
+// QueryFE returns a MetricsQuerier which computes the metrics from the result of SHOW WAREHOUSES and SHOW RUNNING
+// QUERIES.
+func QueryFE(k8sClient client.Client) MetricsQuerier {
+	return func(ctx context.Context, src *srapi.StarRocksCluster) (WarehouseMetrics, error) {
+		sqlClient, err := fe.NewSQLClient(ctx, k8sClient, src)
+		if err != nil {
+			return nil, err
+		}
+		warehouses, err := sqlClient.ShowWarehouses(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		queries, err := sqlClient.ShowRunningQueries(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return computeMetrics(warehouses, queries, time.Now()), nil
+	}
+}
+
+// computeMetrics computes the metrics of every warehouse. The queries without warehouse id run in the default
+// warehouse.
+func computeMetrics(warehouses []sqlclient.Warehouse, queries []sqlclient.RunningQuery, now time.Time) WarehouseMetrics {
+	newMetrics := func() map[srapi.StarRocksMetricName]int64 {
+		metrics := make(map[srapi.StarRocksMetricName]int64, len(srapi.StarRocksMetricNames))
+		for _, name := range srapi.StarRocksMetricNames {
+			metrics[name] = 0
+		}
+		return metrics
+	}
+
+	result := WarehouseMetrics{sqlclient.DefaultWarehouse: newMetrics()}
+	warehouseNames := make(map[string]string, len(warehouses))
+	for _, warehouse := range warehouses {
+		warehouseNames[warehouse.Id] = warehouse.Name
+		result[warehouse.Name] = newMetrics()
+	}
+
+	for _, query := range queries {
+		warehouseName := sqlclient.DefaultWarehouse
+		if query.WarehouseId != "" {
+			warehouseName = warehouseNames[query.WarehouseId]
+		}
+		metrics, ok := result[warehouseName]
+		if !ok {
+			// the warehouse is created after SHOW WAREHOUSES
+			continue
+		}
+		switch query.State {
+		case sqlclient.QueryStatePending:
+			metrics[srapi.PendingQueries]++
+			if !query.StartTime.IsZero() {
+				// the clocks of FE and operator may be skewed, a query does not wait for a negative duration.
+				wait := max(int64(now.Sub(query.StartTime)/time.Second), 0)
+				metrics[srapi.QueueWaitSeconds] = max(metrics[srapi.QueueWaitSeconds], wait)
+			}
+		case sqlclient.QueryStateRunning:
+			metrics[srapi.RunningQueries]++
+			metrics[srapi.SlotUsage] += int64(query.Slots)
+		}
+	}
+	return result
+}
+
+// ServeHTTP serves the discovery of the API and the metrics of warehouses. The path of a metric is
+// /apis/external.metrics.k8s.io/v1beta1/namespaces/<namespace>/<metric>, and the label selector of it selects the
+// StarRocksCluster and the warehouse.
+func (a *Adapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, apierrors.NewMethodNotSupported(schema.GroupResource{Group: Group}, r.Method))
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPath), "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "":
+		if err := a.authorizer.Authorize(r, "", ""); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, apiResourceList())
+	case len(parts) == 3 && parts[0] == "namespaces":
+		if err := a.authorizer.Authorize(r, parts[1], parts[2]); err != nil {
+			writeError(w, err)
+			return
+		}
+		a.serveMetric(w, r, parts[1], parts[2])
+	default:
+		writeError(w, apierrors.NewNotFound(schema.GroupResource{Group: Group}, r.URL.Path))
+	}
+}
+
+func (a *Adapter) serveMetric(w http.ResponseWriter, r *http.Request, namespace string, metricName string) {
+	name, ok := parseMetricName(metricName)
+	if !ok {
+		writeError(w, apierrors.NewNotFound(schema.GroupResource{Group: Group, Resource: metricName}, ""))
+		return
+	}
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeError(w, apierrors.NewBadRequest(fmt.Sprintf("invalid label selector: %v", err)))
+		return
+	}
+	clusterName, ok := selector.RequiresExactMatch(srapi.StarRocksMetricClusterLabel)
+	if !ok {
+		writeError(w, apierrors.NewBadRequest(
+			fmt.Sprintf("the label selector must select a StarRocksCluster by label %s", srapi.StarRocksMetricClusterLabel)))
+		return
+	}
+	warehouseName, ok := selector.RequiresExactMatch(srapi.StarRocksMetricWarehouseLabel)
+	if !ok {
+		warehouseName = sqlclient.DefaultWarehouse
+	}
+
+	cached, err := a.getMetrics(r.Context(), types.NamespacedName{Namespace: namespace, Name: clusterName})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			writeError(w, apierrors.NewNotFound(srapi.GroupVersion.WithResource("starrocksclusters").GroupResource(), clusterName))
+			return
+		}
+		ctrl.Log.WithName("metricsadapter").Error(err, "query metrics failed", "namespace", namespace, "cluster", clusterName)
+		writeError(w, apierrors.NewInternalError(err))
+		return
+	}
+	metrics, ok := cached.metrics[warehouseName]
+	if !ok {
+		writeError(w, apierrors.NewNotFound(schema.GroupResource{Group: Group, Resource: metricName}, warehouseName))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &externalMetricValueList{
+		TypeMeta: metav1.TypeMeta{Kind: "ExternalMetricValueList", APIVersion: Group + "/" + Version},
+		Items: []externalMetricValue{{
+			MetricName: metricName,
+			MetricLabels: map[string]string{
+				srapi.StarRocksMetricClusterLabel:   clusterName,
+				srapi.StarRocksMetricWarehouseLabel: warehouseName,
+			},
+			Timestamp: metav1.NewTime(cached.timestamp),
+			Value:     *resource.NewQuantity(metrics[name], resource.DecimalSI),
+		}},
+	})
+}
+
+// getMetrics returns the metrics of a StarRocksCluster from the cache, and queries FE if they are expired.
+func (a *Adapter) getMetrics(ctx context.Context, key types.NamespacedName) (cachedMetrics, error) {
+	a.mu.Lock()
+	cached, ok := a.cache[key]
+	a.mu.Unlock()
+	if ok && time.Since(cached.timestamp) < a.cacheTTL {
+		return cached, nil
+	}
+
+	var src srapi.StarRocksCluster
+	if err := a.k8sClient.Get(ctx, key, &src); err != nil {
+		return cachedMetrics{}, err
+	}
+	metrics, err := a.query(ctx, &src)
+	if err != nil {
+		return cachedMetrics{}, err
+	}
+	cached = cachedMetrics{metrics: metrics, timestamp: time.Now()}
+	a.mu.Lock()
+	a.cache[key] = cached
+	a.mu.Unlock()
+	return cached, nil
+}
+
+func parseMetricName(metricName string) (srapi.StarRocksMetricName, bool) {
+	for _, name := range srapi.StarRocksMetricNames {
+		if name.ExternalMetricName() == metricName {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func apiResourceList() *metav1.APIResourceList {
+	list := &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: Group + "/" + Version,
+	}
+	for _, name := range srapi.StarRocksMetricNames {
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:       name.ExternalMetricName(),
+			Namespaced: true,
+			Kind:       "ExternalMetricValueList",
+			Verbs:      metav1.Verbs{"get"},
+		})
+	}
+	return list
+}
+
+func writeError(w http.ResponseWriter, err *apierrors.StatusError) {
+	status := err.Status()
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	writeJSON(w, int(status.Code), &status)
+}
+
+func writeJSON(w http.ResponseWriter, code int, object interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(object)
+}
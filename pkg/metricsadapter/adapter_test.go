@@ -0,0 +1,188 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+func TestMain(m *testing.M) {
+	srapi.Register()
+	os.Exit(m.Run())
+}
+
+// allowAll is an Authorizer which allows every request.
+type allowAll struct{}
+
+func (allowAll) Authorize(_ *http.Request, _ string, _ string) *apierrors.StatusError {
+	return nil
+}
+
+// newAdapter creates an Adapter with a StarRocksCluster named test, the querier counts the queries to FE.
+func newAdapter(metrics WarehouseMetrics, err error) (*Adapter, *int) {
+	src := &srapi.StarRocksCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	adapter := New(fake.NewFakeClient(srapi.Scheme, src), allowAll{})
+	queries := 0
+	adapter.query = func(_ context.Context, _ *srapi.StarRocksCluster) (WarehouseMetrics, error) {
+		queries++
+		return metrics, err
+	}
+	return adapter, &queries
+}
+
+func get(adapter *Adapter, path string, selector string) *httptest.ResponseRecorder {
+	target := apiPath + path
+	if selector != "" {
+		target += "?labelSelector=" + url.QueryEscape(selector)
+	}
+	recorder := httptest.NewRecorder()
+	adapter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func TestAdapter_Discovery(t *testing.T) {
+	adapter, _ := newAdapter(nil, nil)
+	recorder := get(adapter, "", "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var list metav1.APIResourceList
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &list))
+	assert.Equal(t, "external.metrics.k8s.io/v1beta1", list.GroupVersion)
+	require.Len(t, list.APIResources, len(srapi.StarRocksMetricNames))
+	assert.Equal(t, "starrocks_pending_queries", list.APIResources[0].Name)
+	assert.True(t, list.APIResources[0].Namespaced)
+}
+
+func TestAdapter_GetMetric(t *testing.T) {
+	adapter, queries := newAdapter(WarehouseMetrics{
+		sqlclient.DefaultWarehouse: {srapi.PendingQueries: 3, srapi.RunningQueries: 5},
+		"wh1":                      {srapi.PendingQueries: 7},
+	}, nil)
+
+	recorder := get(adapter, "/namespaces/default/starrocks_pending_queries", "cluster=test")
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	var list externalMetricValueList
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &list))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "starrocks_pending_queries", list.Items[0].MetricName)
+	assert.Equal(t, map[string]string{"cluster": "test", "warehouse": sqlclient.DefaultWarehouse}, list.Items[0].MetricLabels)
+	assert.Equal(t, "3", list.Items[0].Value.String())
+
+	recorder = get(adapter, "/namespaces/default/starrocks_pending_queries", "cluster=test,warehouse=wh1")
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &list))
+	assert.Equal(t, "7", list.Items[0].Value.String())
+	assert.Equal(t, 1, *queries, "the metrics of a cluster should be cached")
+
+	adapter.cache[types.NamespacedName{Namespace: "default", Name: "test"}] = cachedMetrics{
+		timestamp: time.Now().Add(-DefaultCacheTTL),
+	}
+	recorder = get(adapter, "/namespaces/default/starrocks_running_queries", "cluster=test")
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &list))
+	assert.Equal(t, "5", list.Items[0].Value.String())
+	assert.Equal(t, 2, *queries, "the expired metrics should be queried again")
+}
+
+func TestAdapter_GetMetricErrors(t *testing.T) {
+	adapter, _ := newAdapter(WarehouseMetrics{sqlclient.DefaultWarehouse: {}}, nil)
+	tests := []struct {
+		name     string
+		path     string
+		selector string
+		code     int
+	}{
+		{name: "unknown metric", path: "/namespaces/default/cpu", selector: "cluster=test", code: http.StatusNotFound},
+		{name: "unknown path", path: "/namespaces/default", code: http.StatusNotFound},
+		{name: "no cluster", path: "/namespaces/default/starrocks_pending_queries", code: http.StatusBadRequest},
+		{name: "invalid selector", path: "/namespaces/default/starrocks_pending_queries", selector: "cluster in (",
+			code: http.StatusBadRequest},
+		{name: "unknown cluster", path: "/namespaces/default/starrocks_pending_queries", selector: "cluster=other",
+			code: http.StatusNotFound},
+		{name: "unknown warehouse", path: "/namespaces/default/starrocks_pending_queries",
+			selector: "cluster=test,warehouse=wh1", code: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := get(adapter, tt.path, tt.selector)
+			assert.Equal(t, tt.code, recorder.Code, recorder.Body.String())
+			var status metav1.Status
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+			assert.Equal(t, "Status", status.Kind)
+			assert.Equal(t, int32(tt.code), status.Code)
+		})
+	}
+
+	adapter, _ = newAdapter(nil, errors.New("FE is unavailable"))
+	recorder := get(adapter, "/namespaces/default/starrocks_pending_queries", "cluster=test")
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	adapter.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, apiPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestComputeMetrics(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.Local)
+	warehouses := []sqlclient.Warehouse{{Id: "0", Name: sqlclient.DefaultWarehouse}, {Id: "1", Name: "wh1"}}
+	queries := []sqlclient.RunningQuery{
+		{WarehouseId: "0", State: sqlclient.QueryStateRunning, Slots: 2},
+		{WarehouseId: "0", State: sqlclient.QueryStateRunning, Slots: 3},
+		{WarehouseId: "0", State: sqlclient.QueryStatePending, StartTime: now.Add(-20 * time.Second)},
+		{WarehouseId: "0", State: sqlclient.QueryStatePending, StartTime: now.Add(-50 * time.Second)},
+		{WarehouseId: "1", State: sqlclient.QueryStatePending},
+		// the clock of FE is ahead of the operator
+		{WarehouseId: "1", State: sqlclient.QueryStatePending, StartTime: now.Add(10 * time.Second)},
+		{WarehouseId: "2", State: sqlclient.QueryStateRunning, Slots: 1},
+		// FE does not return the warehouse of queries
+		{State: sqlclient.QueryStateRunning, Slots: 1},
+	}
+
+	got := computeMetrics(warehouses, queries, now)
+	assert.Equal(t, WarehouseMetrics{
+		sqlclient.DefaultWarehouse: {
+			srapi.PendingQueries:   2,
+			srapi.RunningQueries:   3,
+			srapi.SlotUsage:        6,
+			srapi.QueueWaitSeconds: 50,
+		},
+		"wh1": {
+			srapi.PendingQueries:   2,
+			srapi.RunningQueries:   0,
+			srapi.SlotUsage:        0,
+			srapi.QueueWaitSeconds: 0,
+		},
+	}, got)
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsadapter
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// The configmap is published by kube-apiserver, it contains the CA which signs the client certificate of the
+	// aggregator, and the headers which carry the user of the proxied requests.
+	AuthenticationConfigMapNamespace = "kube-system"
+	AuthenticationConfigMapName      = "extension-apiserver-authentication"
+
+	// authenticationConfigTTL is how long the content of the configmap is cached.
+	authenticationConfigTTL = time.Minute
+)
+
+// Authorizer authenticates and authorizes a request to the external metrics API. The resource is the name of the
+// metric, and it is empty for the discovery of the API.
+type Authorizer interface {
+	Authorize(r *http.Request, namespace string, resource string) *apierrors.StatusError
+}
+
+// RequestHeaderAuthorizer delegates the authentication and authorization to kube-apiserver, as other aggregated API
+// servers do. The requests must be proxied by kube-apiserver, which is verified by the requestheader client CA, and
+// the user in the request headers must be allowed to get the metric by a SubjectAccessReview.
+type RequestHeaderAuthorizer struct {
+	reader client.Reader
+	review func(ctx context.Context, sar *authorizationv1.SubjectAccessReview) error
+
+	mu     sync.Mutex
+	config *requestHeaderConfig
+}
+
+type requestHeaderConfig struct {
+	clientCA        *x509.CertPool
+	allowedNames    []string
+	usernameHeaders []string
+	groupHeaders    []string
+	timestamp       time.Time
+}
+
+// NewRequestHeaderAuthorizer creates a RequestHeaderAuthorizer, the reader reads the configmap of kube-apiserver and the
+// k8sClient creates SubjectAccessReviews.
+func NewRequestHeaderAuthorizer(reader client.Reader, k8sClient client.Client) *RequestHeaderAuthorizer {
+	return &RequestHeaderAuthorizer{
+		reader: reader,
+		review: func(ctx context.Context, sar *authorizationv1.SubjectAccessReview) error {
+			return k8sClient.Create(ctx, sar)
+		},
+	}
+}
+
+// RequestClientCert makes the webhook server ask for the client certificate without requiring it, so the admission
+// webhooks which are called without client certificate are not affected.
+func RequestClientCert(config *tls.Config) {
+	if config.ClientAuth == tls.NoClientCert {
+		config.ClientAuth = tls.RequestClientCert
+	}
+}
+
+// Authorize implements Authorizer.
+func (a *RequestHeaderAuthorizer) Authorize(r *http.Request, namespace string, resource string) *apierrors.StatusError {
+	config, err := a.getConfig(r.Context())
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	user, groups, err := config.authenticate(r)
+	if err != nil {
+		return apierrors.NewUnauthorized(err.Error())
+	}
+
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{User: user, Groups: groups},
+	}
+	if resource != "" {
+		sar.Spec.ResourceAttributes = &authorizationv1.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      "get",
+			Group:     Group,
+			Version:   Version,
+			Resource:  resource,
+		}
+	} else {
+		sar.Spec.NonResourceAttributes = &authorizationv1.NonResourceAttributes{Path: r.URL.Path, Verb: "get"}
+	}
+	if err = a.review(r.Context(), sar); err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if !sar.Status.Allowed {
+		return apierrors.NewForbidden(schema.GroupResource{Group: Group, Resource: resource}, "",
+			fmt.Errorf("user %q is not allowed to get it: %s", user, sar.Status.Reason))
+	}
+	return nil
+}
+
+// getConfig returns the requestheader config from the cache, and reads the configmap again if it is expired.
+func (a *RequestHeaderAuthorizer) getConfig(ctx context.Context) (*requestHeaderConfig, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.config != nil && time.Since(a.config.timestamp) < authenticationConfigTTL {
+		return a.config, nil
+	}
+
+	var cm corev1.ConfigMap
+	key := types.NamespacedName{Namespace: AuthenticationConfigMapNamespace, Name: AuthenticationConfigMapName}
+	if err := a.reader.Get(ctx, key, &cm); err != nil {
+		return nil, err
+	}
+	config, err := parseRequestHeaderConfig(cm.Data)
+	if err != nil {
+		return nil, err
+	}
+	a.config = config
+	return config, nil
+}
+
+func parseRequestHeaderConfig(data map[string]string) (*requestHeaderConfig, error) {
+	config := &requestHeaderConfig{
+		clientCA:  x509.NewCertPool(),
+		timestamp: time.Now(),
+	}
+	if !config.clientCA.AppendCertsFromPEM([]byte(data["requestheader-client-ca-file"])) {
+		return nil, fmt.Errorf("no requestheader client CA in configmap %s/%s",
+			AuthenticationConfigMapNamespace, AuthenticationConfigMapName)
+	}
+	for key, value := range map[string]*[]string{
+		"requestheader-allowed-names":    &config.allowedNames,
+		"requestheader-username-headers": &config.usernameHeaders,
+		"requestheader-group-headers":    &config.groupHeaders,
+	} {
+		if data[key] == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(data[key]), value); err != nil {
+			return nil, fmt.Errorf("invalid %s in configmap %s/%s: %w",
+				key, AuthenticationConfigMapNamespace, AuthenticationConfigMapName, err)
+		}
+	}
+	if len(config.usernameHeaders) == 0 {
+		config.usernameHeaders = []string{"X-Remote-User"}
+	}
+	if len(config.groupHeaders) == 0 {
+		config.groupHeaders = []string{"X-Remote-Group"}
+	}
+	return config, nil
+}
+
+// authenticate verifies the client certificate of the request, and returns the user and groups in the request headers.
+func (config *requestHeaderConfig) authenticate(r *http.Request) (string, []string, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return "", nil, errors.New("the request has no client certificate")
+	}
+	cert := r.TLS.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, intermediate := range r.TLS.PeerCertificates[1:] {
+		intermediates.AddCert(intermediate)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         config.clientCA,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return "", nil, fmt.Errorf("the client certificate is not signed by the requestheader client CA: %w", err)
+	}
+	if len(config.allowedNames) > 0 && !contains(config.allowedNames, cert.Subject.CommonName) {
+		return "", nil, fmt.Errorf("the common name %q of client certificate is not allowed", cert.Subject.CommonName)
+	}
+
+	var user string
+	for _, header := range config.usernameHeaders {
+		if user = r.Header.Get(header); user != "" {
+			break
+		}
+	}
+	if user == "" {
+		return "", nil, errors.New("the request has no user")
+	}
+	var groups []string
+	for _, header := range config.groupHeaders {
+		groups = append(groups, r.Header.Values(header)...)
+	}
+	return user, groups, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsadapter
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+// newCertificate creates a certificate for client authentication, it is self-signed if parent is nil.
+func newCertificate(t *testing.T, commonName string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (
+	*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func TestRequestHeaderAuthorizer(t *testing.T) {
+	ca, caKey := newCertificate(t, "front-proxy-ca", nil, nil)
+	proxy, _ := newCertificate(t, "front-proxy-client", ca, caKey)
+	other, _ := newCertificate(t, "other-client", ca, caKey)
+	untrusted, _ := newCertificate(t, "front-proxy-client", nil, nil)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: AuthenticationConfigMapNamespace, Name: AuthenticationConfigMapName},
+		Data: map[string]string{
+			"requestheader-client-ca-file":   string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})),
+			"requestheader-allowed-names":    `["front-proxy-client"]`,
+			"requestheader-username-headers": `["X-Remote-User"]`,
+			"requestheader-group-headers":    `["X-Remote-Group"]`,
+		},
+	}
+	src := &srapi.StarRocksCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	k8sClient := fake.NewFakeClient(srapi.Scheme, src, cm)
+	authorizer := NewRequestHeaderAuthorizer(k8sClient, k8sClient)
+	var reviews []authorizationv1.SubjectAccessReviewSpec
+	authorizer.review = func(_ context.Context, sar *authorizationv1.SubjectAccessReview) error {
+		reviews = append(reviews, sar.Spec)
+		sar.Status.Allowed = sar.Spec.User == "system:serviceaccount:kube-system:horizontal-pod-autoscaler"
+		return nil
+	}
+	adapter := New(k8sClient, authorizer)
+	adapter.query = func(_ context.Context, _ *srapi.StarRocksCluster) (WarehouseMetrics, error) {
+		return WarehouseMetrics{sqlclient.DefaultWarehouse: {srapi.PendingQueries: 1}}, nil
+	}
+
+	tests := []struct {
+		name string
+		path string
+		cert *x509.Certificate
+		user string
+		code int
+	}{
+		{name: "metric", path: "/namespaces/default/starrocks_pending_queries?labelSelector=cluster%3Dtest", cert: proxy,
+			user: "system:serviceaccount:kube-system:horizontal-pod-autoscaler", code: http.StatusOK},
+		{name: "discovery", path: "", cert: proxy,
+			user: "system:serviceaccount:kube-system:horizontal-pod-autoscaler", code: http.StatusOK},
+		{name: "forbidden user", path: "/namespaces/default/starrocks_pending_queries?labelSelector=cluster%3Dtest",
+			cert: proxy, user: "system:anonymous", code: http.StatusForbidden},
+		{name: "no user", path: "", cert: proxy, code: http.StatusUnauthorized},
+		{name: "no client certificate", path: "", user: "admin", code: http.StatusUnauthorized},
+		{name: "untrusted client certificate", path: "", cert: untrusted, user: "admin", code: http.StatusUnauthorized},
+		{name: "not allowed common name", path: "", cert: other, user: "admin", code: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, apiPath+tt.path, nil)
+			request.TLS = &tls.ConnectionState{}
+			if tt.cert != nil {
+				request.TLS.PeerCertificates = []*x509.Certificate{tt.cert}
+			}
+			if tt.user != "" {
+				request.Header.Set("X-Remote-User", tt.user)
+				request.Header.Add("X-Remote-Group", "system:serviceaccounts")
+			}
+			recorder := httptest.NewRecorder()
+			adapter.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.code, recorder.Code, recorder.Body.String())
+		})
+	}
+
+	require.Len(t, reviews, 3)
+	assert.Equal(t, &authorizationv1.ResourceAttributes{Namespace: "default", Verb: "get", Group: Group,
+		Version: Version, Resource: "starrocks_pending_queries"}, reviews[0].ResourceAttributes)
+	assert.Equal(t, []string{"system:serviceaccounts"}, reviews[0].Groups)
+	assert.Equal(t, &authorizationv1.NonResourceAttributes{Path: apiPath, Verb: "get"}, reviews[1].NonResourceAttributes)
+}
+
+func TestRequestHeaderAuthorizer_NoConfigMap(t *testing.T) {
+	k8sClient := fake.NewFakeClient(srapi.Scheme)
+	authorizer := NewRequestHeaderAuthorizer(k8sClient, k8sClient)
+	err := authorizer.Authorize(httptest.NewRequest(http.MethodGet, apiPath, nil), "", "")
+	require.NotNil(t, err)
+	assert.Equal(t, int32(http.StatusInternalServerError), err.Status().Code)
+}
+
+func TestRequestClientCert(t *testing.T) {
+	config := &tls.Config{}
+	RequestClientCert(config)
+	assert.Equal(t, tls.RequestClientCert, config.ClientAuth)
+
+	config = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert}
+	RequestClientCert(config)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+}
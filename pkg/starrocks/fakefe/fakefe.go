@@ -0,0 +1,745 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fakefe provides a fake StarRocks FE for tests. It serves the MySQL protocol and the HTTP API of FE in the
+// process, and keeps the frontends, backends, compute nodes and warehouses in memory, so that the controllers can be
+// tested by the real SQL client without a running StarRocks cluster. The FQDNs of the frontends, backends and compute
+// nodes are served too, e.g. to update the config of a backend by /api/update_config.
+//
+// The statements which are not recognized by the fake FE, e.g. CREATE USER, are recorded and succeed.
+package fakefe
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+const (
+	// QueryPort is the default query port of FE, the fake FE serves the MySQL protocol on it.
+	QueryPort = 9030
+	// HTTPPort is the default http port of FE, the fake FE serves the HTTP API on it.
+	HTTPPort = 8030
+	// BackendHTTPPort is the default http port of backends and compute nodes, the fake FE serves the HTTP API of
+	// them on it, e.g. /api/update_config.
+	BackendHTTPPort = 8040
+
+	// DefaultWarehouse is the warehouse which always exists.
+	DefaultWarehouse = "default_warehouse"
+	// FeatureMultiWarehouse is the feature reported by /api/v2/feature if FE supports multi-warehouse.
+	FeatureMultiWarehouse = "multi-warehouse"
+)
+
+// FE is a fake StarRocks FE.
+type FE struct {
+	mu           sync.Mutex
+	rootPassword string
+	nextID       int
+	frontends    []sqlclient.Frontend
+	backends     []sqlclient.Backend
+	computeNodes []sqlclient.ComputeNode
+	warehouses   []string
+	features     []string
+	failures     []*failure
+	statements   []string
+
+	// frontendConfigs is the config shared by all the frontends, backendConfigs is the default config of backends
+	// and compute nodes, and nodeConfigs is the config updated by /api/update_config, keyed by the FQDN of node.
+	frontendConfigs map[string]configItem
+	backendConfigs  map[string]configItem
+	nodeConfigs     map[string]map[string]string
+
+	addresses  []string
+	listener   net.Listener
+	httpServer *httptest.Server
+	conns      map[net.Conn]struct{}
+	wg         sync.WaitGroup
+}
+
+// failure is an error injected by Fail.
+type failure struct {
+	substr  string
+	times   int
+	message string
+}
+
+// configItem is an item of the config of FE or BE.
+type configItem struct {
+	value   string
+	mutable bool
+}
+
+// result is the result set of a query.
+type result struct {
+	columns []string
+	rows    [][]string
+}
+
+// Start starts a fake FE which serves the FE service host, e.g. kube-starrocks-fe-service.default, on QueryPort and
+// HTTPPort. The fake FE is closed when the test finishes.
+func Start(t testing.TB, host string) *FE {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start fake FE: %v", err)
+	}
+
+	fe := &FE{
+		nextID:          10001,
+		warehouses:      []string{DefaultWarehouse},
+		frontendConfigs: map[string]configItem{},
+		backendConfigs:  map[string]configItem{},
+		nodeConfigs:     map[string]map[string]string{},
+		listener:        listener,
+		conns:           map[net.Conn]struct{}{},
+	}
+	fe.httpServer = httptest.NewServer(fe.handler())
+	fe.addresses = []string{
+		net.JoinHostPort(host, strconv.Itoa(QueryPort)),
+		net.JoinHostPort(host, strconv.Itoa(HTTPPort)),
+	}
+	route(fe.addresses[0], listener.Addr().String())
+	route(fe.addresses[1], fe.httpServer.Listener.Addr().String())
+
+	fe.wg.Add(1)
+	go fe.accept()
+	t.Cleanup(fe.Close)
+	return fe
+}
+
+func (fe *FE) accept() {
+	defer fe.wg.Done()
+	for connectionID := uint32(1); ; connectionID++ {
+		netConn, err := fe.listener.Accept()
+		if err != nil {
+			return
+		}
+		fe.mu.Lock()
+		fe.conns[netConn] = struct{}{}
+		fe.mu.Unlock()
+
+		fe.wg.Add(1)
+		go func(id uint32) {
+			defer fe.wg.Done()
+			c := &conn{fe: fe, netConn: netConn, reader: bufio.NewReader(netConn)}
+			c.serve(id)
+			fe.mu.Lock()
+			delete(fe.conns, netConn)
+			fe.mu.Unlock()
+		}(connectionID)
+	}
+}
+
+// Close stops serving, and closes all the connections.
+func (fe *FE) Close() {
+	fe.mu.Lock()
+	addresses := fe.addresses
+	fe.mu.Unlock()
+	for _, address := range addresses {
+		unroute(address)
+	}
+	_ = fe.listener.Close()
+	fe.mu.Lock()
+	for netConn := range fe.conns {
+		_ = netConn.Close()
+	}
+	fe.mu.Unlock()
+	fe.wg.Wait()
+	fe.httpServer.Close()
+}
+
+// RootPassword returns the password of root, it is empty by default.
+func (fe *FE) RootPassword() string {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	return fe.rootPassword
+}
+
+// SetRootPassword sets the password of root.
+func (fe *FE) SetRootPassword(password string) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	fe.rootPassword = password
+}
+
+// AddFrontend adds a frontend, e.g. the leader.
+func (fe *FE) AddFrontend(frontend sqlclient.Frontend) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	if frontend.Name == "" {
+		frontend.Name = fmt.Sprintf("%s_%s_%d", frontend.FQDN, frontend.EditLogPort, fe.newID())
+	}
+	fe.routeFrontend(frontend.FQDN)
+	fe.frontends = append(fe.frontends, frontend)
+}
+
+// Frontends returns the frontends.
+func (fe *FE) Frontends() []sqlclient.Frontend {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	return append([]sqlclient.Frontend(nil), fe.frontends...)
+}
+
+// AddBackend adds a backend.
+func (fe *FE) AddBackend(backend sqlclient.Backend) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	if backend.BackendId == "" {
+		backend.BackendId = strconv.Itoa(fe.newID())
+	}
+	if backend.HttpPort == "" {
+		backend.HttpPort = strconv.Itoa(BackendHTTPPort)
+	}
+	fe.routeNode(backend.FQDN, backend.HttpPort)
+	fe.backends = append(fe.backends, backend)
+}
+
+// Backends returns the backends.
+func (fe *FE) Backends() []sqlclient.Backend {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	return append([]sqlclient.Backend(nil), fe.backends...)
+}
+
+// AddComputeNode adds a compute node, it belongs to the default warehouse if WarehouseName is empty.
+func (fe *FE) AddComputeNode(computeNode sqlclient.ComputeNode) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	if computeNode.ComputeNodeId == "" {
+		computeNode.ComputeNodeId = strconv.Itoa(fe.newID())
+	}
+	if computeNode.WarehouseName == "" {
+		computeNode.WarehouseName = DefaultWarehouse
+	}
+	if computeNode.HttpPort == "" {
+		computeNode.HttpPort = strconv.Itoa(BackendHTTPPort)
+	}
+	fe.routeNode(computeNode.FQDN, computeNode.HttpPort)
+	fe.computeNodes = append(fe.computeNodes, computeNode)
+}
+
+// ComputeNodes returns the compute nodes of all the warehouses.
+func (fe *FE) ComputeNodes() []sqlclient.ComputeNode {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	return append([]sqlclient.ComputeNode(nil), fe.computeNodes...)
+}
+
+// AddWarehouse adds a warehouse.
+func (fe *FE) AddWarehouse(name string) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	if !contains(fe.warehouses, name) {
+		fe.warehouses = append(fe.warehouses, name)
+	}
+}
+
+// Warehouses returns the names of warehouses, including the default warehouse.
+func (fe *FE) Warehouses() []string {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	return append([]string(nil), fe.warehouses...)
+}
+
+// SetFrontendConfig sets the config of all the frontends, mutable is reported by ADMIN SHOW FRONTEND CONFIG. Only the
+// keys which are set can be changed by ADMIN SET FRONTEND CONFIG.
+func (fe *FE) SetFrontendConfig(key, value string, mutable bool) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	fe.frontendConfigs[key] = configItem{value: value, mutable: mutable}
+}
+
+// FrontendConfig returns the value of the config of frontends.
+func (fe *FE) FrontendConfig(key string) string {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	return fe.frontendConfigs[key].value
+}
+
+// SetBackendConfig sets the default config of all the backends and compute nodes, mutable is reported by
+// information_schema.be_configs. Only the mutable keys can be changed by /api/update_config.
+func (fe *FE) SetBackendConfig(key, value string, mutable bool) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	fe.backendConfigs[key] = configItem{value: value, mutable: mutable}
+}
+
+// BackendConfig returns the value of the config of the backend or compute node whose FQDN is fqdn.
+func (fe *FE) BackendConfig(fqdn, key string) string {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	return fe.backendConfig(fqdn, key)
+}
+
+func (fe *FE) backendConfig(fqdn, key string) string {
+	if value, ok := fe.nodeConfigs[fqdn][key]; ok {
+		return value
+	}
+	return fe.backendConfigs[key].value
+}
+
+// SetFeatures sets the features reported by /api/v2/feature, e.g. FeatureMultiWarehouse.
+func (fe *FE) SetFeatures(features ...string) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	fe.features = features
+}
+
+// Fail makes the next times statements which contain substr fail with the message, e.g. to simulate the leader of
+// FE is changing. substr is case-insensitive.
+func (fe *FE) Fail(substr string, times int, message string) {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	fe.failures = append(fe.failures, &failure{substr: strings.ToUpper(substr), times: times, message: message})
+}
+
+// Statements returns all the statements executed in the fake FE, including the failed ones.
+func (fe *FE) Statements() []string {
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	return append([]string(nil), fe.statements...)
+}
+
+// routeFrontend serves the MySQL protocol on the query port of the frontend, so that the statements can be executed
+// in a specific frontend.
+func (fe *FE) routeFrontend(fqdn string) {
+	fe.serve(net.JoinHostPort(fqdn, strconv.Itoa(QueryPort)), fe.listener.Addr().String())
+}
+
+// routeNode serves the HTTP API of the backend or compute node, e.g. /api/update_config.
+func (fe *FE) routeNode(fqdn, httpPort string) {
+	fe.serve(net.JoinHostPort(fqdn, httpPort), fe.httpServer.Listener.Addr().String())
+}
+
+func (fe *FE) serve(address, local string) {
+	if contains(fe.addresses, address) {
+		return
+	}
+	fe.addresses = append(fe.addresses, address)
+	route(address, local)
+}
+
+func (fe *FE) newID() int {
+	id := fe.nextID
+	fe.nextID++
+	return id
+}
+
+// execute executes a statement, the result is nil if the statement does not return a result set.
+func (fe *FE) execute(query string) (*result, error) {
+	statement := strings.TrimSuffix(strings.TrimSpace(query), ";")
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+
+	fe.statements = append(fe.statements, statement)
+	for _, f := range fe.failures {
+		if f.times > 0 && strings.Contains(strings.ToUpper(statement), f.substr) {
+			f.times--
+			return nil, &mysql.MySQLError{Number: ErrUnknown, Message: f.message}
+		}
+	}
+	for _, h := range handlers {
+		if match := h.pattern.FindStringSubmatch(statement); match != nil {
+			return h.handle(fe, match)
+		}
+	}
+	return nil, nil
+}
+
+// handler handles the statements which match the pattern.
+type handler struct {
+	pattern *regexp.Regexp
+	handle  func(fe *FE, match []string) (*result, error)
+}
+
+const (
+	addressPattern   = `"([^"]+):(\d+)"`
+	warehousePattern = "`?([\\w-]+)`?"
+)
+
+var handlers = []handler{
+	{regexp.MustCompile(`(?i)^SHOW FRONTENDS$`), (*FE).showFrontends},
+	{regexp.MustCompile(`(?i)^ALTER SYSTEM ADD (FOLLOWER|OBSERVER) ` + addressPattern + `$`), (*FE).addFrontend},
+	{regexp.MustCompile(`(?i)^ALTER SYSTEM DROP (FOLLOWER|OBSERVER) ` + addressPattern + `$`), (*FE).dropFrontend},
+	{regexp.MustCompile(`(?i)^SHOW BACKENDS$`), (*FE).showBackends},
+	{regexp.MustCompile(`(?i)^ALTER SYSTEM ADD BACKEND ` + addressPattern + `$`), (*FE).addBackend},
+	{regexp.MustCompile(`(?i)^ALTER SYSTEM DECOMMISSION BACKEND ` + addressPattern + `$`), (*FE).decommissionBackend},
+	{regexp.MustCompile(`(?i)^CANCEL DECOMMISSION BACKEND ` + addressPattern + `$`), (*FE).cancelDecommissionBackend},
+	{regexp.MustCompile(`(?i)^ALTER SYSTEM DROP BACKEND ` + addressPattern + `$`), (*FE).dropBackend},
+	{regexp.MustCompile(`(?i)^SHOW COMPUTE NODES$`), (*FE).showComputeNodes},
+	{regexp.MustCompile(`(?i)^ALTER SYSTEM ADD COMPUTE NODE ` + addressPattern + `(?: INTO WAREHOUSE ` + warehousePattern + `)?$`),
+		(*FE).addComputeNode},
+	{regexp.MustCompile(`(?i)^ALTER SYSTEM DROP COMPUTE NODE ` + addressPattern + `(?: FROM WAREHOUSE ` + warehousePattern + `)?$`),
+		(*FE).dropComputeNode},
+	{regexp.MustCompile(`(?i)^SHOW WAREHOUSES$`), (*FE).showWarehouses},
+	{regexp.MustCompile(`(?i)^CREATE WAREHOUSE (IF NOT EXISTS )?` + warehousePattern + `$`), (*FE).createWarehouse},
+	{regexp.MustCompile(`(?i)^DROP WAREHOUSE (IF EXISTS )?` + warehousePattern + `$`), (*FE).dropWarehouse},
+	{regexp.MustCompile(`(?i)^ALTER USER 'root'@'[^']*' IDENTIFIED BY '((?:[^'\\]|\\.)*)'$`), (*FE).alterRootPassword},
+	{regexp.MustCompile(`(?i)^ADMIN SHOW FRONTEND CONFIG LIKE '([^']*)'$`), (*FE).showFrontendConfig},
+	{regexp.MustCompile(`(?i)^ADMIN SET FRONTEND CONFIG \("(\w+)" = "((?:[^"\\]|\\.)*)"\)$`), (*FE).setFrontendConfig},
+	{regexp.MustCompile(`(?i)^SELECT BE_ID, NAME, VALUE, MUTABLE FROM information_schema\.be_configs WHERE NAME IN \((.*)\)$`),
+		(*FE).showBackendConfigs},
+}
+
+func (fe *FE) showFrontends(_ []string) (*result, error) {
+	result := &result{columns: []string{"Name", "IP", "EditLogPort", "HttpPort", "QueryPort", "Role", "Alive"}}
+	for _, frontend := range fe.frontends {
+		result.rows = append(result.rows, []string{frontend.Name, frontend.FQDN, frontend.EditLogPort,
+			strconv.Itoa(HTTPPort), strconv.Itoa(QueryPort), frontend.Role, strconv.FormatBool(frontend.Alive)})
+	}
+	return result, nil
+}
+
+func (fe *FE) addFrontend(match []string) (*result, error) {
+	for _, frontend := range fe.frontends {
+		if frontend.FQDN == match[2] && frontend.EditLogPort == match[3] {
+			return nil, errorf("frontend already exists[%s:%s]", match[2], match[3])
+		}
+	}
+	fe.frontends = append(fe.frontends, sqlclient.Frontend{
+		Name:        fmt.Sprintf("%s_%s_%d", match[2], match[3], fe.newID()),
+		FQDN:        match[2],
+		EditLogPort: match[3],
+		Role:        strings.ToUpper(match[1]),
+		Alive:       true,
+	})
+	fe.routeFrontend(match[2])
+	return nil, nil
+}
+
+func (fe *FE) dropFrontend(match []string) (*result, error) {
+	for i, frontend := range fe.frontends {
+		if frontend.FQDN == match[2] && frontend.EditLogPort == match[3] && frontend.Role == strings.ToUpper(match[1]) {
+			fe.frontends = append(fe.frontends[:i], fe.frontends[i+1:]...)
+			return nil, nil
+		}
+	}
+	return nil, errorf("frontend does not exist[%s:%s]", match[2], match[3])
+}
+
+// showBackends returns the backends. The tablets of the decommissioned backends are migrated to the other backends
+// after they are shown, so that the progress of decommission can be observed.
+func (fe *FE) showBackends(_ []string) (*result, error) {
+	result := &result{columns: []string{"BackendId", "IP", "HeartbeatPort", "HttpPort", "Alive", "SystemDecommissioned",
+		"TabletNum"}}
+	for _, backend := range fe.backends {
+		result.rows = append(result.rows, []string{backend.BackendId, backend.FQDN, backend.HeartbeatPort, backend.HttpPort,
+			"true", strconv.FormatBool(backend.SystemDecommissioned), strconv.FormatInt(backend.TabletNum, 10)})
+	}
+
+	for i := range fe.backends {
+		if !fe.backends[i].SystemDecommissioned || fe.backends[i].TabletNum == 0 {
+			continue
+		}
+		for j := range fe.backends {
+			if !fe.backends[j].SystemDecommissioned {
+				fe.backends[j].TabletNum += fe.backends[i].TabletNum
+				fe.backends[i].TabletNum = 0
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (fe *FE) addBackend(match []string) (*result, error) {
+	if fe.findBackend(match[1], match[2]) >= 0 {
+		return nil, errorf("Same backend already exists[%s:%s]", match[1], match[2])
+	}
+	fe.backends = append(fe.backends, sqlclient.Backend{
+		BackendId:     strconv.Itoa(fe.newID()),
+		FQDN:          match[1],
+		HeartbeatPort: match[2],
+		HttpPort:      strconv.Itoa(BackendHTTPPort),
+	})
+	fe.routeNode(match[1], strconv.Itoa(BackendHTTPPort))
+	return nil, nil
+}
+
+func (fe *FE) decommissionBackend(match []string) (*result, error) {
+	i := fe.findBackend(match[1], match[2])
+	if i < 0 {
+		return nil, errorf("Backend does not exist[%s:%s]", match[1], match[2])
+	}
+	fe.backends[i].SystemDecommissioned = true
+	return nil, nil
+}
+
+func (fe *FE) cancelDecommissionBackend(match []string) (*result, error) {
+	i := fe.findBackend(match[1], match[2])
+	if i < 0 {
+		return nil, errorf("Backend does not exist[%s:%s]", match[1], match[2])
+	}
+	fe.backends[i].SystemDecommissioned = false
+	return nil, nil
+}
+
+func (fe *FE) dropBackend(match []string) (*result, error) {
+	i := fe.findBackend(match[1], match[2])
+	if i < 0 {
+		return nil, errorf("Backend does not exist[%s:%s]", match[1], match[2])
+	}
+	fe.backends = append(fe.backends[:i], fe.backends[i+1:]...)
+	return nil, nil
+}
+
+func (fe *FE) findBackend(fqdn, heartbeatPort string) int {
+	for i, backend := range fe.backends {
+		if backend.FQDN == fqdn && backend.HeartbeatPort == heartbeatPort {
+			return i
+		}
+	}
+	return -1
+}
+
+func (fe *FE) showComputeNodes(_ []string) (*result, error) {
+	result := &result{columns: []string{"ComputeNodeId", "IP", "HeartbeatPort", "HttpPort", "Alive", "WarehouseName"}}
+	for _, computeNode := range fe.computeNodes {
+		result.rows = append(result.rows, []string{computeNode.ComputeNodeId, computeNode.FQDN,
+			computeNode.HeartbeatPort, computeNode.HttpPort, "true", computeNode.WarehouseName})
+	}
+	return result, nil
+}
+
+func (fe *FE) addComputeNode(match []string) (*result, error) {
+	warehouseName := match[3]
+	if warehouseName == "" {
+		warehouseName = DefaultWarehouse
+	}
+	if !contains(fe.warehouses, warehouseName) {
+		return nil, errorf("Warehouse '%s' does not exist", warehouseName)
+	}
+	for _, computeNode := range fe.computeNodes {
+		if computeNode.FQDN == match[1] && computeNode.HeartbeatPort == match[2] {
+			return nil, errorf("Same compute node already exists[%s:%s]", match[1], match[2])
+		}
+	}
+	fe.computeNodes = append(fe.computeNodes, sqlclient.ComputeNode{
+		ComputeNodeId: strconv.Itoa(fe.newID()),
+		FQDN:          match[1],
+		HeartbeatPort: match[2],
+		HttpPort:      strconv.Itoa(BackendHTTPPort),
+		WarehouseName: warehouseName,
+	})
+	fe.routeNode(match[1], strconv.Itoa(BackendHTTPPort))
+	return nil, nil
+}
+
+func (fe *FE) dropComputeNode(match []string) (*result, error) {
+	for i, computeNode := range fe.computeNodes {
+		if computeNode.FQDN == match[1] && computeNode.HeartbeatPort == match[2] &&
+			(match[3] == "" || computeNode.WarehouseName == match[3]) {
+			fe.computeNodes = append(fe.computeNodes[:i], fe.computeNodes[i+1:]...)
+			return nil, nil
+		}
+	}
+	return nil, errorf("Compute node does not exist[%s:%s]", match[1], match[2])
+}
+
+func (fe *FE) showWarehouses(_ []string) (*result, error) {
+	result := &result{columns: []string{"Id", "Name", "State", "NodeCount"}}
+	for i, name := range fe.warehouses {
+		nodeCount := 0
+		for _, computeNode := range fe.computeNodes {
+			if computeNode.WarehouseName == name {
+				nodeCount++
+			}
+		}
+		result.rows = append(result.rows, []string{strconv.Itoa(i), name, "AVAILABLE", strconv.Itoa(nodeCount)})
+	}
+	return result, nil
+}
+
+func (fe *FE) createWarehouse(match []string) (*result, error) {
+	if contains(fe.warehouses, match[2]) {
+		if match[1] != "" {
+			return nil, nil
+		}
+		return nil, errorf("Warehouse '%s' already exists", match[2])
+	}
+	fe.warehouses = append(fe.warehouses, match[2])
+	return nil, nil
+}
+
+// dropWarehouse drops the warehouse and its compute nodes.
+func (fe *FE) dropWarehouse(match []string) (*result, error) {
+	name := match[2]
+	switch {
+	case name == DefaultWarehouse:
+		return nil, errorf("Can not drop %s", DefaultWarehouse)
+	case !contains(fe.warehouses, name) && match[1] != "":
+		return nil, nil
+	case !contains(fe.warehouses, name):
+		return nil, errorf("Warehouse '%s' does not exist", name)
+	}
+
+	var warehouses []string
+	for _, warehouse := range fe.warehouses {
+		if warehouse != name {
+			warehouses = append(warehouses, warehouse)
+		}
+	}
+	fe.warehouses = warehouses
+	var computeNodes []sqlclient.ComputeNode
+	for _, computeNode := range fe.computeNodes {
+		if computeNode.WarehouseName != name {
+			computeNodes = append(computeNodes, computeNode)
+		}
+	}
+	fe.computeNodes = computeNodes
+	return nil, nil
+}
+
+func (fe *FE) alterRootPassword(match []string) (*result, error) {
+	fe.rootPassword = unescape(match[1])
+	return nil, nil
+}
+
+func (fe *FE) showFrontendConfig(match []string) (*result, error) {
+	pattern := regexp.MustCompile("^" + strings.NewReplacer("%", ".*", "_", ".").Replace(regexp.QuoteMeta(match[1])) + "$")
+	keys := make([]string, 0, len(fe.frontendConfigs))
+	for key := range fe.frontendConfigs {
+		if pattern.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	result := &result{columns: []string{"Key", "AliasNames", "Value", "Type", "IsMutable", "Comment"}}
+	for _, key := range keys {
+		item := fe.frontendConfigs[key]
+		result.rows = append(result.rows, []string{key, "[]", item.value, "String", strconv.FormatBool(item.mutable), ""})
+	}
+	return result, nil
+}
+
+func (fe *FE) setFrontendConfig(match []string) (*result, error) {
+	item, ok := fe.frontendConfigs[match[1]]
+	switch {
+	case !ok:
+		return nil, errorf("Config '%s' does not exist", match[1])
+	case !item.mutable:
+		return nil, errorf("Config '%s' is not mutable", match[1])
+	}
+	item.value = unescape(match[2])
+	fe.frontendConfigs[match[1]] = item
+	return nil, nil
+}
+
+// showBackendConfigs returns the config of every backend and compute node, the keys are like 'key1', 'key2'.
+func (fe *FE) showBackendConfigs(match []string) (*result, error) {
+	var keys []string
+	for _, key := range strings.Split(match[1], ",") {
+		keys = append(keys, strings.Trim(strings.TrimSpace(key), "'"))
+	}
+	nodes := make(map[string]string)
+	for _, backend := range fe.backends {
+		nodes[backend.BackendId] = backend.FQDN
+	}
+	for _, computeNode := range fe.computeNodes {
+		nodes[computeNode.ComputeNodeId] = computeNode.FQDN
+	}
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := &result{columns: []string{"BE_ID", "NAME", "VALUE", "TYPE", "DEFAULT", "MUTABLE"}}
+	for _, id := range ids {
+		for _, key := range keys {
+			if item, ok := fe.backendConfigs[key]; ok {
+				result.rows = append(result.rows, []string{id, key, fe.backendConfig(nodes[id], key), "string", item.value,
+					strconv.FormatBool(item.mutable)})
+			}
+		}
+	}
+	return result, nil
+}
+
+// unescape removes the backslashes which escape the characters in a quoted string.
+func unescape(quoted string) string {
+	var builder strings.Builder
+	for i := 0; i < len(quoted); i++ {
+		if quoted[i] == '\\' && i+1 < len(quoted) {
+			i++
+		}
+		builder.WriteByte(quoted[i])
+	}
+	return builder.String()
+}
+
+func errorf(format string, args ...interface{}) error {
+	return &mysql.MySQLError{Number: ErrUnknown, Message: fmt.Sprintf(format, args...)}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// routes are the addresses served by the fake FEs, the value is the local address of the fake FE.
+var routes = struct {
+	sync.RWMutex
+	addresses map[string]string
+}{addresses: map[string]string{}}
+
+var registerDialOnce sync.Once
+
+func route(address, local string) {
+	// the MySQL driver connects to FE by DialContext, so that the SQL client connects to the fake FE by the address
+	// of FE service.
+	registerDialOnce.Do(func() {
+		mysql.RegisterDialContext("tcp", func(ctx context.Context, address string) (net.Conn, error) {
+			return DialContext(ctx, "tcp", address)
+		})
+	})
+	routes.Lock()
+	defer routes.Unlock()
+	routes.addresses[address] = local
+}
+
+func unroute(address string) {
+	routes.Lock()
+	defer routes.Unlock()
+	delete(routes.addresses, address)
+}
+
+// DialContext connects to the address. If the address is served by a fake FE, it connects to the fake FE instead.
+func DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	routes.RLock()
+	if local, ok := routes.addresses[address]; ok {
+		address = local
+	}
+	routes.RUnlock()
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, address)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakefe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+const host = "kube-starrocks-fe-service.default"
+
+func newClient(password string) *sqlclient.Client {
+	return &sqlclient.Client{
+		RootPassword:       password,
+		FeServiceName:      "kube-starrocks-fe-service",
+		FeServiceNamespace: "default",
+		FeServicePort:      fmt.Sprintf("%d", QueryPort),
+	}
+}
+
+func TestFE_Frontends(t *testing.T) {
+	fe := Start(t, host)
+	fe.AddFrontend(sqlclient.Frontend{FQDN: "kube-starrocks-fe-0.kube-starrocks-fe-search", EditLogPort: "9010",
+		Role: sqlclient.FrontendRoleLeader, Alive: true})
+	ctx, c := context.Background(), newClient("")
+
+	require.NoError(t, c.AddObserver(ctx, nil, "kube-starrocks-observer-fe-0.kube-starrocks-observer-fe-search", 9010))
+	assert.Error(t, c.AddObserver(ctx, nil, "kube-starrocks-observer-fe-0.kube-starrocks-observer-fe-search", 9010))
+
+	frontends, err := c.ShowFrontends(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, frontends, 2)
+	assert.Equal(t, sqlclient.FrontendRoleLeader, frontends[0].Role)
+	assert.Equal(t, sqlclient.FrontendRoleObserver, frontends[1].Role)
+	assert.True(t, frontends[1].Alive)
+
+	require.NoError(t, c.DropObserver(ctx, nil, frontends[1]))
+	assert.Len(t, fe.Frontends(), 1)
+}
+
+func TestFE_DecommissionBackend(t *testing.T) {
+	fe := Start(t, host)
+	for i := 0; i < 3; i++ {
+		fe.AddBackend(sqlclient.Backend{FQDN: fmt.Sprintf("kube-starrocks-be-%d.kube-starrocks-be-search", i),
+			HeartbeatPort: "9050", TabletNum: 10})
+	}
+	ctx, c := context.Background(), newClient("")
+
+	backends, err := c.ShowBackends(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, backends, 3)
+	require.NoError(t, c.DecommissionBackend(ctx, nil, backends[2]))
+
+	// the tablets are migrated after the decommissioned backend is shown.
+	backends, err = c.ShowBackends(ctx, nil)
+	require.NoError(t, err)
+	assert.True(t, backends[2].SystemDecommissioned)
+	assert.Equal(t, int64(10), backends[2].TabletNum)
+	backends, err = c.ShowBackends(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), backends[2].TabletNum)
+	assert.Equal(t, int64(20), backends[0].TabletNum)
+
+	require.NoError(t, c.DropBackend(ctx, nil, backends[2]))
+	assert.Len(t, fe.Backends(), 2)
+	assert.Error(t, c.DropBackend(ctx, nil, backends[2]))
+}
+
+func TestFE_Warehouses(t *testing.T) {
+	fe := Start(t, host)
+	ctx, c := context.Background(), newClient("")
+
+	require.NoError(t, c.ExecuteContext(ctx, nil, "CREATE WAREHOUSE wh1"))
+	require.NoError(t, c.ExecuteContext(ctx, nil, `ALTER SYSTEM ADD COMPUTE NODE "wh1-warehouse-cn-0.wh1-warehouse-cn-search:9050" INTO WAREHOUSE wh1`))
+	require.NoError(t, c.ExecuteContext(ctx, nil, `ALTER SYSTEM ADD COMPUTE NODE "wh1-warehouse-cn-1.wh1-warehouse-cn-search:9050" INTO WAREHOUSE wh1`))
+	require.NoError(t, c.ExecuteContext(ctx, nil, `ALTER SYSTEM ADD COMPUTE NODE "kube-starrocks-cn-0.kube-starrocks-cn-search:9050"`))
+	assert.Error(t, c.ExecuteContext(ctx, nil, `ALTER SYSTEM ADD COMPUTE NODE "wh2-warehouse-cn-0.wh2-warehouse-cn-search:9050" INTO WAREHOUSE wh2`))
+
+	result, err := c.ShowComputeNodes(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, result.ComputeNodesByWarehouse["wh1"], 2)
+	require.Len(t, result.ComputeNodesByWarehouse[DefaultWarehouse], 1)
+
+	require.NoError(t, c.DropComputeNode(ctx, nil, result.ComputeNodesByWarehouse["wh1"][1]))
+	require.NoError(t, c.DropWarehouse(ctx, nil, "wh1"))
+	assert.Equal(t, []string{DefaultWarehouse}, fe.Warehouses())
+	assert.Len(t, fe.ComputeNodes(), 1)
+	assert.Error(t, c.DropWarehouse(ctx, nil, "wh1"))
+	assert.Error(t, c.DropWarehouse(ctx, nil, DefaultWarehouse))
+}
+
+func TestFE_RootPassword(t *testing.T) {
+	fe := Start(t, host)
+	ctx := context.Background()
+
+	require.NoError(t, newClient("").ExecuteContext(ctx, nil, `ALTER USER 'root'@'%' IDENTIFIED BY 'it\'s'`))
+	assert.Equal(t, "it's", fe.RootPassword())
+
+	// the password is checked when a new connection is created, the connections are recreated when the password
+	// of client is changed.
+	assert.NoError(t, newClient("it's").ExecuteContext(ctx, nil, "SELECT 1"))
+	err := newClient("").ExecuteContext(ctx, nil, "SELECT 1")
+	var mysqlErr *mysql.MySQLError
+	require.True(t, errors.As(err, &mysqlErr), "unexpected error: %v", err)
+	assert.Equal(t, uint16(ErrAccessDenied), mysqlErr.Number)
+}
+
+func TestFE_Fail(t *testing.T) {
+	opts := sqlclient.DefaultOptions()
+	opts.RetryInterval = time.Millisecond
+	require.NoError(t, sqlclient.Configure(opts))
+	defer func() { _ = sqlclient.Configure(sqlclient.DefaultOptions()) }()
+
+	fe := Start(t, host)
+	fe.Fail("show backends", 2, "current node is not leader")
+	_, err := newClient("").ShowBackends(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SHOW BACKENDS", "SHOW BACKENDS", "SHOW BACKENDS"}, fe.Statements())
+}
+
+func TestFE_Features(t *testing.T) {
+	fe := Start(t, host)
+	fe.SetFeatures(FeatureMultiWarehouse)
+
+	resp, err := HTTPClient().Get(fmt.Sprintf("http://%s:%d/api/v2/feature", host, HTTPPort))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Features []feature `json:"features"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, []feature{{Name: FeatureMultiWarehouse, Description: FeatureMultiWarehouse}}, result.Features)
+}
+
+func TestFE_FrontendConfig(t *testing.T) {
+	fe := Start(t, host)
+	fe.SetFrontendConfig("max_routine_load_task_num_per_be", "16", true)
+	fe.SetFrontendConfig("http_port", "8030", false)
+	ctx := context.Background()
+	c := newClient("")
+
+	config, err := c.ShowFrontendConfig(ctx, nil, "max_routine_load_task_num_per_be")
+	require.NoError(t, err)
+	assert.Equal(t, &sqlclient.FrontendConfig{Key: "max_routine_load_task_num_per_be", Value: "16", Mutable: true}, config)
+
+	require.NoError(t, c.SetFrontendConfig(ctx, nil, "max_routine_load_task_num_per_be", "32"))
+	assert.Equal(t, "32", fe.FrontendConfig("max_routine_load_task_num_per_be"))
+	assert.Error(t, c.SetFrontendConfig(ctx, nil, "http_port", "8031"))
+	assert.Error(t, c.SetFrontendConfig(ctx, nil, "unknown_key", "1"))
+}
+
+func TestFE_BackendConfig(t *testing.T) {
+	fe := Start(t, host)
+	fe.SetBackendConfig("max_compaction_concurrency", "-1", true)
+	fe.SetBackendConfig("be_port", "9060", false)
+	fe.AddBackend(sqlclient.Backend{FQDN: "kube-starrocks-be-0.kube-starrocks-be-search", HeartbeatPort: "9050"})
+	ctx := context.Background()
+
+	updateConfig := func(query string) string {
+		url := fmt.Sprintf("http://kube-starrocks-be-0.kube-starrocks-be-search:%d/api/update_config?%s", BackendHTTPPort, query)
+		resp, err := HTTPClient().Post(url, "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var result map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result["status"]
+	}
+	assert.Equal(t, "OK", updateConfig("max_compaction_concurrency=4"))
+	assert.Equal(t, "BAD", updateConfig("be_port=9061"))
+	assert.Equal(t, "4", fe.BackendConfig("kube-starrocks-be-0.kube-starrocks-be-search", "max_compaction_concurrency"))
+
+	configs, err := newClient("").ShowBackendConfigs(ctx, nil, []string{"max_compaction_concurrency", "be_port"})
+	require.NoError(t, err)
+	backendID := fe.Backends()[0].BackendId
+	assert.Equal(t, []sqlclient.BackendConfig{
+		{BackendId: backendID, Name: "max_compaction_concurrency", Value: "4", Mutable: true},
+		{BackendId: backendID, Name: "be_port", Value: "9060"},
+	}, configs)
+}
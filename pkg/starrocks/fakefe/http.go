@@ -0,0 +1,99 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakefe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// feature is an item of the response of /api/v2/feature.
+type feature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Link        string `json:"link"`
+}
+
+func (fe *FE) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/feature", fe.serveFeatures)
+	mux.HandleFunc("/api/update_config", fe.serveUpdateConfig)
+	return mux
+}
+
+func (fe *FE) serveFeatures(w http.ResponseWriter, _ *http.Request) {
+	fe.mu.Lock()
+	features := make([]feature, 0, len(fe.features))
+	for _, name := range fe.features {
+		features = append(features, feature{Name: name, Description: name})
+	}
+	fe.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"features": features,
+		"version":  "fake",
+		"status":   "OK",
+	})
+}
+
+// serveUpdateConfig serves /api/update_config of the backend or compute node whose FQDN is the host of request. Like
+// BE, the keys which do not exist or are not mutable are rejected.
+func (fe *FE) serveUpdateConfig(w http.ResponseWriter, r *http.Request) {
+	status, message := "OK", ""
+	if r.Method != http.MethodPost {
+		status, message = "BAD", fmt.Sprintf("method %s is not allowed", r.Method)
+	} else if err := fe.updateNodeConfig(r); err != nil {
+		status, message = "BAD", err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": status, "msg": message})
+}
+
+func (fe *FE) updateNodeConfig(r *http.Request) error {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		return err
+	}
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+	for key, values := range r.URL.Query() {
+		if key == "persist" {
+			continue
+		}
+		item, ok := fe.backendConfigs[key]
+		switch {
+		case !ok:
+			return fmt.Errorf("Config.%s not found", key)
+		case !item.mutable:
+			return fmt.Errorf("Config.%s is not mutable", key)
+		}
+		if fe.nodeConfigs[host] == nil {
+			fe.nodeConfigs[host] = make(map[string]string)
+		}
+		fe.nodeConfigs[host][key] = values[0]
+	}
+	return nil
+}
+
+// HTTPClient returns a http client which connects to the fake FEs by the addresses of FE services.
+func HTTPClient() *http.Client {
+	return &http.Client{Transport: &http.Transport{DialContext: DialContext}}
+}
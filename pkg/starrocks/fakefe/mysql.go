@@ -0,0 +1,316 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakefe
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // mysql_native_password is based on SHA1
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// The subset of the MySQL client/server protocol which is used by the operator, see
+// https://dev.mysql.com/doc/dev/mysql-server/latest/PAGE_PROTOCOL.html for details.
+const (
+	serverVersion = "8.0.33"
+	authPlugin    = "mysql_native_password"
+
+	comQuit   = 0x01
+	comInitDB = 0x02
+	comQuery  = 0x03
+	comPing   = 0x0e
+
+	clientLongPassword               = 0x00000001
+	clientFoundRows                  = 0x00000002
+	clientLongFlag                   = 0x00000004
+	clientConnectWithDB              = 0x00000008
+	clientProtocol41                 = 0x00000200
+	clientTransactions               = 0x00002000
+	clientSecureConn                 = 0x00008000
+	clientMultiResults               = 0x00020000
+	clientPluginAuth                 = 0x00080000
+	clientPluginAuthLenEncClientData = 0x00200000
+
+	// capabilities are the capabilities of the fake FE, TLS is not supported.
+	capabilities = clientLongPassword | clientFoundRows | clientLongFlag | clientConnectWithDB | clientProtocol41 |
+		clientTransactions | clientSecureConn | clientMultiResults | clientPluginAuth | clientPluginAuthLenEncClientData
+
+	charsetUTF8      = 33
+	statusAutocommit = 0x0002
+	typeVarString    = 0xfd
+
+	headerOK  = 0x00
+	headerEOF = 0xfe
+	headerERR = 0xff
+
+	// ErrAccessDenied is the error number returned when the password of root is wrong.
+	ErrAccessDenied = 1045
+	// ErrUnknown is the error number returned by FE for most of the errors, e.g. a syntax error.
+	ErrUnknown = 1064
+)
+
+// conn is a MySQL connection to the fake FE.
+type conn struct {
+	fe       *FE
+	netConn  net.Conn
+	reader   *bufio.Reader
+	sequence byte
+}
+
+// serve authenticates the client, and executes the commands until the connection is closed.
+func (c *conn) serve(connectionID uint32) {
+	defer c.netConn.Close()
+
+	nonce := make([]byte, 20)
+	if _, err := rand.Read(nonce); err != nil {
+		return
+	}
+	for i := range nonce {
+		// the nonce must not contain 0, because the second part of it is terminated by 0.
+		nonce[i] = 'a' + nonce[i]%26
+	}
+	if err := c.writePacket(handshakePacket(connectionID, nonce)); err != nil {
+		return
+	}
+	data, err := c.readPacket()
+	if err != nil {
+		return
+	}
+	user, authResponse := parseHandshakeResponse(data)
+	if !bytes.Equal(authResponse, scramblePassword(nonce, c.fe.RootPassword())) {
+		_ = c.writeError(&mysql.MySQLError{Number: ErrAccessDenied,
+			Message: "Access denied for user '" + user + "' (using password: YES)"})
+		return
+	}
+	if err = c.writeOK(); err != nil {
+		return
+	}
+
+	for {
+		data, err = c.readPacket()
+		if err != nil || len(data) == 0 {
+			return
+		}
+		switch data[0] {
+		case comQuit:
+			return
+		case comPing, comInitDB:
+			err = c.writeOK()
+		case comQuery:
+			err = c.query(string(data[1:]))
+		default:
+			err = c.writeError(&mysql.MySQLError{Number: ErrUnknown, Message: "command is not supported by the fake FE"})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (c *conn) query(query string) error {
+	result, err := c.fe.execute(query)
+	var mysqlErr *mysql.MySQLError
+	switch {
+	case errors.As(err, &mysqlErr):
+		return c.writeError(mysqlErr)
+	case err != nil:
+		return c.writeError(&mysql.MySQLError{Number: ErrUnknown, Message: err.Error()})
+	case result == nil:
+		return c.writeOK()
+	}
+
+	if err = c.writePacket(appendLengthEncodedInteger(nil, uint64(len(result.columns)))); err != nil {
+		return err
+	}
+	for _, column := range result.columns {
+		if err = c.writePacket(columnDefinitionPacket(column)); err != nil {
+			return err
+		}
+	}
+	if err = c.writeEOF(); err != nil {
+		return err
+	}
+	for _, row := range result.rows {
+		var data []byte
+		for _, value := range row {
+			data = appendLengthEncodedString(data, value)
+		}
+		if err = c.writePacket(data); err != nil {
+			return err
+		}
+	}
+	return c.writeEOF()
+}
+
+// readPacket reads a packet from the client, the sequence of the response starts from the next one.
+func (c *conn) readPacket() ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return nil, err
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	c.sequence = header[3] + 1
+	data := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (c *conn) writePacket(data []byte) error {
+	length := len(data)
+	packet := append([]byte{byte(length), byte(length >> 8), byte(length >> 16), c.sequence}, data...)
+	c.sequence++
+	_, err := c.netConn.Write(packet)
+	return err
+}
+
+func (c *conn) writeOK() error {
+	// affected rows, last insert id, status flags and warnings.
+	return c.writePacket([]byte{headerOK, 0, 0, statusAutocommit, 0, 0, 0})
+}
+
+func (c *conn) writeEOF() error {
+	// warnings and status flags.
+	return c.writePacket([]byte{headerEOF, 0, 0, statusAutocommit, 0})
+}
+
+func (c *conn) writeError(err *mysql.MySQLError) error {
+	data := []byte{headerERR}
+	data = binary.LittleEndian.AppendUint16(data, err.Number)
+	data = append(data, "#HY000"...)
+	data = append(data, err.Message...)
+	return c.writePacket(data)
+}
+
+// handshakePacket returns the initial handshake packet of protocol version 10.
+func handshakePacket(connectionID uint32, nonce []byte) []byte {
+	data := []byte{10}
+	data = append(data, serverVersion...)
+	data = append(data, 0)
+	data = binary.LittleEndian.AppendUint32(data, connectionID)
+	data = append(data, nonce[:8]...)
+	data = append(data, 0)
+	data = binary.LittleEndian.AppendUint16(data, uint16(capabilities&0xffff))
+	data = append(data, charsetUTF8)
+	data = binary.LittleEndian.AppendUint16(data, statusAutocommit)
+	data = binary.LittleEndian.AppendUint16(data, uint16(capabilities>>16))
+	data = append(data, byte(len(nonce)+1))
+	data = append(data, make([]byte, 10)...)
+	data = append(data, nonce[8:]...)
+	data = append(data, 0)
+	data = append(data, authPlugin...)
+	return append(data, 0)
+}
+
+// parseHandshakeResponse returns the user and the auth response of the HandshakeResponse41 packet.
+func parseHandshakeResponse(data []byte) (string, []byte) {
+	// capability flags, max packet size, character set and the reserved bytes.
+	const pos = 4 + 4 + 1 + 23
+	if len(data) < pos {
+		return "", nil
+	}
+	flags := binary.LittleEndian.Uint32(data)
+	end := bytes.IndexByte(data[pos:], 0)
+	if end < 0 {
+		return "", nil
+	}
+	user, data := string(data[pos:pos+end]), data[pos+end+1:]
+	if len(data) == 0 {
+		return user, nil
+	}
+
+	var length uint64
+	switch {
+	case flags&clientPluginAuthLenEncClientData != 0:
+		length, data = readLengthEncodedInteger(data)
+	case flags&clientSecureConn != 0:
+		length, data = uint64(data[0]), data[1:]
+	default:
+		length = uint64(bytes.IndexByte(data, 0))
+	}
+	if uint64(len(data)) < length {
+		return user, nil
+	}
+	return user, data[:length]
+}
+
+// scramblePassword returns the auth response of mysql_native_password, it is empty if the password is empty.
+func scramblePassword(nonce []byte, password string) []byte {
+	if password == "" {
+		return nil
+	}
+	stage1 := sha1.Sum([]byte(password)) //nolint:gosec
+	stage2 := sha1.Sum(stage1[:])        //nolint:gosec
+	hash := sha1.New()                   //nolint:gosec
+	hash.Write(nonce)
+	hash.Write(stage2[:])
+	scramble := hash.Sum(nil)
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	return scramble
+}
+
+func columnDefinitionPacket(name string) []byte {
+	var data []byte
+	for _, value := range []string{"def", "", "", "", name, name} {
+		data = appendLengthEncodedString(data, value)
+	}
+	data = append(data, 0x0c)
+	data = binary.LittleEndian.AppendUint16(data, charsetUTF8)
+	data = binary.LittleEndian.AppendUint32(data, 1024)
+	data = append(data, typeVarString)
+	// flags, decimals and filler.
+	return append(data, 0, 0, 0, 0, 0)
+}
+
+func appendLengthEncodedInteger(data []byte, n uint64) []byte {
+	switch {
+	case n < 251:
+		return append(data, byte(n))
+	case n < 1<<16:
+		return append(data, 0xfc, byte(n), byte(n>>8))
+	case n < 1<<24:
+		return append(data, 0xfd, byte(n), byte(n>>8), byte(n>>16))
+	}
+	data = append(data, 0xfe)
+	return binary.LittleEndian.AppendUint64(data, n)
+}
+
+func appendLengthEncodedString(data []byte, value string) []byte {
+	data = appendLengthEncodedInteger(data, uint64(len(value)))
+	return append(data, value...)
+}
+
+func readLengthEncodedInteger(data []byte) (uint64, []byte) {
+	switch data[0] {
+	case 0xfc:
+		return uint64(binary.LittleEndian.Uint16(data[1:])), data[3:]
+	case 0xfd:
+		return uint64(data[1]) | uint64(data[2])<<8 | uint64(data[3])<<16, data[4:]
+	case 0xfe:
+		return binary.LittleEndian.Uint64(data[1:]), data[9:]
+	}
+	return uint64(data[0]), data[1:]
+}
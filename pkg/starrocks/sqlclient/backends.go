@@ -0,0 +1,111 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ShowBackendsStatement = "SHOW BACKENDS"
+)
+
+// Backend represents a row of the result of SHOW BACKENDS.
+type Backend struct {
+	BackendId            string
+	FQDN                 string
+	HeartbeatPort        string
+	HttpPort             string
+	SystemDecommissioned bool
+	TabletNum            int64
+
+	index int // the index is from FQDN, used for sorting
+}
+
+// ShowBackends executes SHOW BACKENDS, and returns the backends sorted by the index of pod.
+func (c *Client) ShowBackends(ctx context.Context, db *sql.DB) ([]Backend, error) {
+	rows, err := c.QueryContext(ctx, db, ShowBackendsStatement)
+	if err != nil {
+		return nil, err
+	}
+
+	backends := make([]Backend, 0, len(rows))
+	for _, row := range rows {
+		backend := Backend{
+			BackendId:            row["BackendId"],
+			FQDN:                 row["IP"],
+			HeartbeatPort:        row["HeartbeatPort"],
+			HttpPort:             row["HttpPort"],
+			SystemDecommissioned: strings.EqualFold(row["SystemDecommissioned"], "true"),
+		}
+		if backend.index, err = getIndexFromFQDN(backend.FQDN); err != nil {
+			// the backend is not a pod of statefulset, e.g. it is added manually, put it after the pods.
+			backend.index = math.MaxInt
+		}
+		if value, ok := row["TabletNum"]; ok {
+			backend.TabletNum, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse TabletNum %s of backend %s: %v", value, backend.FQDN, err)
+			}
+		}
+		backends = append(backends, backend)
+	}
+
+	sort.Slice(backends, func(i, j int) bool {
+		return backends[i].index < backends[j].index
+	})
+	return backends, nil
+}
+
+// DecommissionBackend executes the SQL statement to decommission a backend. After the backend is decommissioned,
+// FE will migrate the tablets on it to other backends.
+func (c *Client) DecommissionBackend(ctx context.Context, db *sql.DB, backend Backend) error {
+	statement := fmt.Sprintf("ALTER SYSTEM DECOMMISSION BACKEND \"%v:%v\"", backend.FQDN, backend.HeartbeatPort)
+	return c.ExecuteContext(ctx, db, statement)
+}
+
+// CancelDecommissionBackend executes the SQL statement to cancel the decommission of a backend, the tablets which
+// have not been migrated are kept on it.
+func (c *Client) CancelDecommissionBackend(ctx context.Context, db *sql.DB, backend Backend) error {
+	statement := fmt.Sprintf("CANCEL DECOMMISSION BACKEND \"%v:%v\"", backend.FQDN, backend.HeartbeatPort)
+	return c.ExecuteContext(ctx, db, statement)
+}
+
+// DropBackend executes the SQL statement to drop a backend. It should only be called when the backend has no
+// tablets.
+func (c *Client) DropBackend(ctx context.Context, db *sql.DB, backend Backend) error {
+	statement := fmt.Sprintf("ALTER SYSTEM DROP BACKEND \"%v:%v\"", backend.FQDN, backend.HeartbeatPort)
+	return c.ExecuteContext(ctx, db, statement)
+}
+
+// getIndexFromFQDN gets the index of pod from FQDN. The FQDN looks like:
+// kube-starrocks-be-2.kube-starrocks-be-search.default.svc.cluster.local
+func getIndexFromFQDN(fqdn string) (int, error) {
+	firstPart := strings.Split(fqdn, ".")[0]
+	parts := strings.Split(firstPart, "-")
+	var index int
+	if _, err := fmt.Sscanf(parts[len(parts)-1], "%d", &index); err != nil {
+		return 0, fmt.Errorf("failed to parse index from FQDN %s: %v", fqdn, err)
+	}
+	return index, nil
+}
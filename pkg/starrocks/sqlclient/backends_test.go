@@ -0,0 +1,118 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ShowBackends(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    *sqlmock.Rows
+		want    []Backend
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "test ShowBackends",
+			rows: sqlmock.NewRows([]string{"BackendId", "IP", "HeartbeatPort", "SystemDecommissioned", "TabletNum"}).
+				AddRow([]byte("10003"), []byte("kube-starrocks-be-10.kube-starrocks-be-search"), []byte("9050"), []byte("false"), []byte("100")).
+				AddRow([]byte("10001"), []byte("kube-starrocks-be-0.kube-starrocks-be-search"), []byte("9050"), []byte("false"), []byte("100")).
+				AddRow([]byte("10002"), []byte("kube-starrocks-be-2.kube-starrocks-be-search"), []byte("9050"), []byte("true"), []byte("0")),
+			want: []Backend{
+				{BackendId: "10001", FQDN: "kube-starrocks-be-0.kube-starrocks-be-search", HeartbeatPort: "9050", TabletNum: 100, index: 0},
+				{BackendId: "10002", FQDN: "kube-starrocks-be-2.kube-starrocks-be-search", HeartbeatPort: "9050",
+					SystemDecommissioned: true, TabletNum: 0, index: 2},
+				{BackendId: "10003", FQDN: "kube-starrocks-be-10.kube-starrocks-be-search", HeartbeatPort: "9050", TabletNum: 100, index: 10},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "test ShowBackends with a backend which is not a pod",
+			rows: sqlmock.NewRows([]string{"BackendId", "IP", "HeartbeatPort", "SystemDecommissioned", "TabletNum"}).
+				AddRow([]byte("10002"), []byte("external-be.example.com"), []byte("9050"), []byte("false"), []byte("100")).
+				AddRow([]byte("10001"), []byte("kube-starrocks-be-0.kube-starrocks-be-search"), []byte("9050"), []byte("false"), []byte("100")),
+			want: []Backend{
+				{BackendId: "10001", FQDN: "kube-starrocks-be-0.kube-starrocks-be-search", HeartbeatPort: "9050", TabletNum: 100, index: 0},
+				{BackendId: "10002", FQDN: "external-be.example.com", HeartbeatPort: "9050", TabletNum: 100, index: math.MaxInt},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "test ShowBackends with invalid TabletNum",
+			rows: sqlmock.NewRows([]string{"BackendId", "IP", "TabletNum"}).
+				AddRow([]byte("10001"), []byte("kube-starrocks-be-0.kube-starrocks-be-search"), []byte("N/A")),
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			mock.ExpectQuery(ShowBackendsStatement).WillReturnRows(tt.rows)
+
+			got, err := (&Client{}).ShowBackends(context.Background(), db)
+			if !tt.wantErr(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_DecommissionBackend(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec(`ALTER SYSTEM DECOMMISSION BACKEND "be-1.be-search:9050"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = (&Client{}).DecommissionBackend(context.Background(), db,
+		Backend{FQDN: "be-1.be-search", HeartbeatPort: "9050"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClient_CancelDecommissionBackend(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec(`CANCEL DECOMMISSION BACKEND "be-1.be-search:9050"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = (&Client{}).CancelDecommissionBackend(context.Background(), db,
+		Backend{FQDN: "be-1.be-search", HeartbeatPort: "9050"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClient_DropBackend(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec(`ALTER SYSTEM DROP BACKEND "be-1.be-search:9050"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = (&Client{}).DropBackend(context.Background(), db,
+		Backend{FQDN: "be-1.be-search", HeartbeatPort: "9050"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+)
+
+const (
+	ShowComputeNodesStatement = "SHOW COMPUTE NODES"
+	ShowWarehousesStatement   = "SHOW WAREHOUSES"
+
+	// DefaultWarehouse is the warehouse of the CN nodes of StarRocksCluster.
+	DefaultWarehouse = "default_warehouse"
+)
+
+// ShowComputeNodesResult is the result of SHOW COMPUTE NODES, the compute nodes are grouped by warehouse.
+type ShowComputeNodesResult struct {
+	ComputeNodesByWarehouse map[string][]ComputeNode
+}
+
+// ComputeNode represents a row of the result of SHOW COMPUTE NODES.
+type ComputeNode struct {
+	ComputeNodeId string
+	FQDN          string
+	HeartbeatPort string
+	HttpPort      string
+	WarehouseName string
+
+	index int // the index is from FQDN, used for sorting
+}
+
+// Warehouse represents a row of the result of SHOW WAREHOUSES.
+type Warehouse struct {
+	Id   string
+	Name string
+}
+
+// ShowComputeNodes executes SHOW COMPUTE NODES, and returns the compute nodes of every warehouse sorted by the index
+// of pod.
+func (c *Client) ShowComputeNodes(ctx context.Context, db *sql.DB) (*ShowComputeNodesResult, error) {
+	rows, err := c.QueryContext(ctx, db, ShowComputeNodesStatement)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ShowComputeNodesResult{
+		ComputeNodesByWarehouse: make(map[string][]ComputeNode),
+	}
+	for _, row := range rows {
+		computeNode := ComputeNode{
+			ComputeNodeId: row["ComputeNodeId"],
+			FQDN:          row["IP"],
+			HeartbeatPort: row["HeartbeatPort"],
+			HttpPort:      row["HttpPort"],
+			WarehouseName: row["WarehouseName"],
+		}
+		if computeNode.index, err = getIndexFromFQDN(computeNode.FQDN); err != nil {
+			return nil, err
+		}
+		result.ComputeNodesByWarehouse[computeNode.WarehouseName] = append(
+			result.ComputeNodesByWarehouse[computeNode.WarehouseName], computeNode)
+	}
+
+	// the FQDN looks like: kube-starrocks-cn-2.kube-starrocks-cn-search.default.svc.cluster.local,
+	// kube-starrocks-cn-1.kube-starrocks-cn-search.default.svc.cluster.local,
+	// kube-starrocks-cn-10.kube-starrocks-cn-search.default.svc.cluster.local
+	// kube-starrocks-cn-10 should be after kube-starrocks-cn-2 when sorting.
+	for _, computeNodes := range result.ComputeNodesByWarehouse {
+		sort.Slice(computeNodes, func(i, j int) bool {
+			return computeNodes[i].index < computeNodes[j].index
+		})
+	}
+	return &result, nil
+}
+
+// DropComputeNode executes the SQL statement to drop a compute node from a warehouse.
+func (c *Client) DropComputeNode(ctx context.Context, db *sql.DB, cn ComputeNode) error {
+	statement := fmt.Sprintf("ALTER SYSTEM DROP COMPUTE NODE \"%v:%v\" FROM WAREHOUSE %v", cn.FQDN, cn.HeartbeatPort, cn.WarehouseName)
+	return c.ExecuteContext(ctx, db, statement)
+}
+
+// DropWarehouse executes the SQL statement to drop a warehouse, name is the name of warehouse in FE.
+func (c *Client) DropWarehouse(ctx context.Context, db *sql.DB, name string) error {
+	return c.ExecuteContext(ctx, db, fmt.Sprintf("DROP WAREHOUSE %s", name))
+}
+
+// ShowWarehouses executes SHOW WAREHOUSES, and returns the id and name of warehouses.
+func (c *Client) ShowWarehouses(ctx context.Context, db *sql.DB) ([]Warehouse, error) {
+	rows, err := c.QueryContext(ctx, db, ShowWarehousesStatement)
+	if err != nil {
+		return nil, err
+	}
+	warehouses := make([]Warehouse, 0, len(rows))
+	for _, row := range rows {
+		warehouses = append(warehouses, Warehouse{Id: row["Id"], Name: row["Name"]})
+	}
+	return warehouses, nil
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ShowComputeNodes(t *testing.T) {
+	type fields struct {
+		RootPassword       string
+		FeServiceName      string
+		FeServiceNamespace string
+		FeServicePort      string
+	}
+	type args struct {
+		ctx  context.Context
+		db   *sql.DB
+		rows *sqlmock.Rows
+	}
+
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantErr  assert.ErrorAssertionFunc
+		wantData *ShowComputeNodesResult
+	}{
+		{
+			name: "test ShowComputeNodes",
+			fields: fields{
+				RootPassword:  "",
+				FeServiceName: "localhost",
+				FeServicePort: "9030",
+			},
+			args: args{
+				ctx: context.Background(),
+				rows: sqlmock.NewRows([]string{"ComputeNodeId", "IP", "WarehouseName"}).
+					AddRow([]byte("id"), []byte("cn-1"), []byte("wh1")).
+					AddRow([]byte("id"), []byte("cn-0"), []byte("wh1")).
+					AddRow([]byte("id"), []byte("cn-20"), []byte("wh1")).
+					AddRow([]byte("id"), []byte("cn-10"), []byte("wh1")).
+					AddRow([]byte("id"), []byte("cn-15"), []byte("wh1")).
+					AddRow([]byte("id"), []byte("cn-3"), []byte("wh1")).
+					AddRow([]byte("id"), []byte("cn-1"), []byte("wh2")).
+					AddRow([]byte("id"), []byte("cn-0"), []byte("wh2")).
+					AddRow([]byte("id"), []byte("cn-20"), []byte("wh2")).
+					AddRow([]byte("id"), []byte("cn-10"), []byte("wh2")).
+					AddRow([]byte("id"), []byte("cn-15"), []byte("wh2")).
+					AddRow([]byte("id"), []byte("cn-3"), []byte("wh2")),
+			},
+			wantErr: assert.NoError,
+			wantData: &ShowComputeNodesResult{
+				ComputeNodesByWarehouse: map[string][]ComputeNode{
+					"wh1": {
+						{ComputeNodeId: "id", FQDN: "cn-0", WarehouseName: "wh1", index: 0},
+						{ComputeNodeId: "id", FQDN: "cn-1", WarehouseName: "wh1", index: 1},
+						{ComputeNodeId: "id", FQDN: "cn-3", WarehouseName: "wh1", index: 3},
+						{ComputeNodeId: "id", FQDN: "cn-10", WarehouseName: "wh1", index: 10},
+						{ComputeNodeId: "id", FQDN: "cn-15", WarehouseName: "wh1", index: 15},
+						{ComputeNodeId: "id", FQDN: "cn-20", WarehouseName: "wh1", index: 20},
+					},
+					"wh2": {
+						{ComputeNodeId: "id", FQDN: "cn-0", WarehouseName: "wh2", index: 0},
+						{ComputeNodeId: "id", FQDN: "cn-1", WarehouseName: "wh2", index: 1},
+						{ComputeNodeId: "id", FQDN: "cn-3", WarehouseName: "wh2", index: 3},
+						{ComputeNodeId: "id", FQDN: "cn-10", WarehouseName: "wh2", index: 10},
+						{ComputeNodeId: "id", FQDN: "cn-15", WarehouseName: "wh2", index: 15},
+						{ComputeNodeId: "id", FQDN: "cn-20", WarehouseName: "wh2", index: 20},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				RootPassword:       tt.fields.RootPassword,
+				FeServiceName:      tt.fields.FeServiceName,
+				FeServiceNamespace: tt.fields.FeServiceNamespace,
+				FeServicePort:      tt.fields.FeServicePort,
+			}
+
+			// set expected behavior on mock db
+			// create mock db
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			mock.ExpectQuery(ShowComputeNodesStatement).WillReturnRows(tt.args.rows)
+
+			result, err := c.ShowComputeNodes(tt.args.ctx, db)
+			tt.wantErr(t, err, fmt.Sprintf("ShowComputeNodes(%v, %v)", tt.args.ctx, tt.args.db))
+			assert.True(t, reflect.DeepEqual(tt.wantData.ComputeNodesByWarehouse, result.ComputeNodesByWarehouse))
+		})
+	}
+}
+
+func TestClient_DropComputeNode(t *testing.T) {
+	type fields struct {
+		RootPassword       string
+		FeServiceName      string
+		FeServiceNamespace string
+		FeServicePort      string
+	}
+	type args struct {
+		ctx context.Context
+		db  *sql.DB
+		cn  ComputeNode
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "test DropComputeNode",
+			fields: fields{
+				RootPassword:       "root",
+				FeServiceName:      "fe-search",
+				FeServiceNamespace: "default",
+				FeServicePort:      "9030",
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() *sql.DB {
+					db, mock, err := sqlmock.New()
+					require.NoError(t, err)
+					statement := fmt.Sprintf("ALTER SYSTEM DROP COMPUTE NODE \"%v:%v\" FROM WAREHOUSE %v", "fqdn", "9010", "wh1")
+					mock.ExpectExec(statement).WillReturnResult(sqlmock.NewResult(1, 1))
+					return db
+				}(),
+				cn: ComputeNode{
+					ComputeNodeId: "id",
+					FQDN:          "fqdn",
+					HeartbeatPort: "9010",
+					WarehouseName: "wh1",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				RootPassword:       tt.fields.RootPassword,
+				FeServiceName:      tt.fields.FeServiceName,
+				FeServiceNamespace: tt.fields.FeServiceNamespace,
+				FeServicePort:      tt.fields.FeServicePort,
+			}
+			tt.wantErr(t, c.DropComputeNode(tt.args.ctx, tt.args.db, tt.args.cn), fmt.Sprintf("DropComputeNode(%v, %v, %v)", tt.args.ctx, tt.args.db, tt.args.cn))
+		})
+	}
+}
+
+func TestClient_DropWarehouse(t *testing.T) {
+	type fields struct {
+		RootPassword       string
+		FeServiceName      string
+		FeServiceNamespace string
+		FeServicePort      string
+	}
+	type args struct {
+		ctx  context.Context
+		db   *sql.DB
+		name string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "test DropWarehouse",
+			fields: fields{
+				RootPassword:       "root",
+				FeServiceName:      "fe-search",
+				FeServiceNamespace: "default",
+				FeServicePort:      "9030",
+			},
+			args: args{
+				ctx: context.Background(),
+				db: func() *sql.DB {
+					db, mock, err := sqlmock.New()
+					require.NoError(t, err)
+					mock.ExpectExec("DROP WAREHOUSE wh1").
+						WillReturnResult(sqlmock.NewResult(1, 1))
+					return db
+				}(),
+				name: "wh1",
+			},
+			wantErr: assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				RootPassword:       tt.fields.RootPassword,
+				FeServiceName:      tt.fields.FeServiceName,
+				FeServiceNamespace: tt.fields.FeServiceNamespace,
+				FeServicePort:      tt.fields.FeServicePort,
+			}
+			tt.wantErr(t, c.DropWarehouse(tt.args.ctx, tt.args.db, tt.args.name), fmt.Sprintf("DropWarehouse(%v, %v, %v)", tt.args.ctx, tt.args.db, tt.args.name))
+		})
+	}
+}
+
+func TestClient_ShowWarehouses(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("SHOW WAREHOUSES").WillReturnRows(
+		sqlmock.NewRows([]string{"Id", "Name", "State", "NodeCount", "RunningSql", "QueuedSql"}).
+			AddRow("0", "default_warehouse", "AVAILABLE", "3", "1", "0").
+			AddRow("1", "wh1", "AVAILABLE", "2", "0", "2"))
+
+	got, err := (&Client{}).ShowWarehouses(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, []Warehouse{{Id: "0", Name: DefaultWarehouse}, {Id: "1", Name: "wh1"}}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
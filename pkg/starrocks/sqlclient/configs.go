@@ -0,0 +1,120 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// configKeyPattern is the pattern of config keys, the keys are checked before they are used in statements.
+var configKeyPattern = regexp.MustCompile(`^\w+$`)
+
+// FrontendConfig represents a row of the result of ADMIN SHOW FRONTEND CONFIG.
+type FrontendConfig struct {
+	Key     string
+	Value   string
+	Mutable bool
+}
+
+// BackendConfig represents a row of information_schema.be_configs, it contains the config of backends and compute
+// nodes.
+type BackendConfig struct {
+	BackendId string
+	Name      string
+	Value     string
+	Mutable   bool
+}
+
+// IsConfigKey returns true if the key can be used in the statements of config, e.g. ADMIN SET FRONTEND CONFIG.
+func IsConfigKey(key string) bool {
+	return configKeyPattern.MatchString(key)
+}
+
+func checkConfigKey(key string) error {
+	if !IsConfigKey(key) {
+		return fmt.Errorf("invalid config key %q", key)
+	}
+	return nil
+}
+
+// ShowFrontendConfig executes ADMIN SHOW FRONTEND CONFIG, and returns the config of the key in the FE which the
+// client connects to. It returns nil if FE does not have the key.
+func (c *Client) ShowFrontendConfig(ctx context.Context, db *sql.DB, key string) (*FrontendConfig, error) {
+	if err := checkConfigKey(key); err != nil {
+		return nil, err
+	}
+	rows, err := c.QueryContext(ctx, db, fmt.Sprintf("ADMIN SHOW FRONTEND CONFIG LIKE '%s'", key))
+	if err != nil {
+		return nil, err
+	}
+	// '_' matches any character in LIKE, so the keys are compared again.
+	for _, row := range rows {
+		if row["Key"] == key {
+			return &FrontendConfig{
+				Key:     key,
+				Value:   row["Value"],
+				Mutable: strings.EqualFold(row["IsMutable"], "true"),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// SetFrontendConfig executes ADMIN SET FRONTEND CONFIG, the config only takes effect in the FE which the client
+// connects to, see ForFrontend.
+func (c *Client) SetFrontendConfig(ctx context.Context, db *sql.DB, key, value string) error {
+	if err := checkConfigKey(key); err != nil {
+		return err
+	}
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return c.ExecuteContext(ctx, db, fmt.Sprintf("ADMIN SET FRONTEND CONFIG (\"%s\" = \"%s\")", key, value))
+}
+
+// ShowBackendConfigs queries information_schema.be_configs, and returns the config of the keys in all the backends
+// and compute nodes.
+func (c *Client) ShowBackendConfigs(ctx context.Context, db *sql.DB, keys []string) ([]BackendConfig, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	quoted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if err := checkConfigKey(key); err != nil {
+			return nil, err
+		}
+		quoted = append(quoted, "'"+key+"'")
+	}
+	rows, err := c.QueryContext(ctx, db, fmt.Sprintf(
+		"SELECT BE_ID, NAME, VALUE, MUTABLE FROM information_schema.be_configs WHERE NAME IN (%s)", strings.Join(quoted, ", ")))
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]BackendConfig, 0, len(rows))
+	for _, row := range rows {
+		configs = append(configs, BackendConfig{
+			BackendId: row["BE_ID"],
+			Name:      row["NAME"],
+			Value:     row["VALUE"],
+			Mutable:   strings.EqualFold(row["MUTABLE"], "true") || row["MUTABLE"] == "1",
+		})
+	}
+	return configs, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ShowFrontendConfig(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	columns := []string{"Key", "AliasNames", "Value", "Type", "IsMutable", "Comment"}
+	mock.ExpectQuery("ADMIN SHOW FRONTEND CONFIG LIKE 'max_routine_load_task_num_per_be'").WillReturnRows(
+		sqlmock.NewRows(columns).
+			AddRow("max_routine_load_task_num_per_be", "[]", "16", "int", "true", ""))
+	mock.ExpectQuery("ADMIN SHOW FRONTEND CONFIG LIKE 'http_port'").WillReturnRows(
+		sqlmock.NewRows(columns).AddRow("http_port", "[]", "8030", "int", "false", ""))
+	mock.ExpectQuery("ADMIN SHOW FRONTEND CONFIG LIKE 'unknown_key'").WillReturnRows(
+		sqlmock.NewRows(columns).AddRow("unknownXkey", "[]", "1", "int", "true", ""))
+
+	c := &Client{}
+	got, err := c.ShowFrontendConfig(context.Background(), db, "max_routine_load_task_num_per_be")
+	require.NoError(t, err)
+	assert.Equal(t, &FrontendConfig{Key: "max_routine_load_task_num_per_be", Value: "16", Mutable: true}, got)
+
+	got, err = c.ShowFrontendConfig(context.Background(), db, "http_port")
+	require.NoError(t, err)
+	assert.Equal(t, &FrontendConfig{Key: "http_port", Value: "8030"}, got)
+
+	got, err = c.ShowFrontendConfig(context.Background(), db, "unknown_key")
+	require.NoError(t, err)
+	assert.Nil(t, got, "the keys matched by '_' should be ignored")
+
+	_, err = c.ShowFrontendConfig(context.Background(), db, "a' OR '1' = '1")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClient_SetFrontendConfig(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec(`ADMIN SET FRONTEND CONFIG ("sys_log_verbose_modules" = "com.starrocks.\"a\"")`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = (&Client{}).SetFrontendConfig(context.Background(), db, "sys_log_verbose_modules", `com.starrocks."a"`)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClient_ShowBackendConfigs(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("SELECT BE_ID, NAME, VALUE, MUTABLE FROM information_schema.be_configs " +
+		"WHERE NAME IN ('max_compaction_concurrency', 'be_port')").WillReturnRows(
+		sqlmock.NewRows([]string{"BE_ID", "NAME", "VALUE", "TYPE", "DEFAULT", "MUTABLE"}).
+			AddRow("10001", "max_compaction_concurrency", "-1", "int", "-1", "true").
+			AddRow("10001", "be_port", "9060", "int", "9060", "false"))
+
+	got, err := (&Client{}).ShowBackendConfigs(context.Background(), db, []string{"max_compaction_concurrency", "be_port"})
+	require.NoError(t, err)
+	assert.Equal(t, []BackendConfig{
+		{BackendId: "10001", Name: "max_compaction_concurrency", Value: "-1", Mutable: true},
+		{BackendId: "10001", Name: "be_port", Value: "9060"},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
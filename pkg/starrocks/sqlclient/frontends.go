@@ -0,0 +1,82 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const (
+	ShowFrontendsStatement = "SHOW FRONTENDS"
+
+	// FrontendRoleLeader is the role of the leader in the result of SHOW FRONTENDS.
+	FrontendRoleLeader = "LEADER"
+	// FrontendRoleFollower is the role of followers in the result of SHOW FRONTENDS.
+	FrontendRoleFollower = "FOLLOWER"
+	// FrontendRoleObserver is the role of observers in the result of SHOW FRONTENDS.
+	FrontendRoleObserver = "OBSERVER"
+)
+
+// Frontend represents a row of the result of SHOW FRONTENDS.
+type Frontend struct {
+	Name        string
+	FQDN        string
+	EditLogPort string
+	Role        string
+	Alive       bool
+}
+
+// ShowFrontends executes SHOW FRONTENDS, and returns all the frontends, including followers and observers.
+// The versions before 3.0 report the leader by the column IsMaster, its role is converted to LEADER.
+func (c *Client) ShowFrontends(ctx context.Context, db *sql.DB) ([]Frontend, error) {
+	rows, err := c.QueryContext(ctx, db, ShowFrontendsStatement)
+	if err != nil {
+		return nil, err
+	}
+
+	frontends := make([]Frontend, 0, len(rows))
+	for _, row := range rows {
+		frontend := Frontend{
+			Name:        row["Name"],
+			FQDN:        row["IP"],
+			EditLogPort: row["EditLogPort"],
+			Role:        strings.ToUpper(row["Role"]),
+			Alive:       strings.EqualFold(row["Alive"], "true"),
+		}
+		if strings.EqualFold(row["IsMaster"], "true") {
+			frontend.Role = FrontendRoleLeader
+		}
+		frontends = append(frontends, frontend)
+	}
+	return frontends, nil
+}
+
+// AddObserver executes the SQL statement to add an observer. It should be called before the observer is started,
+// otherwise the observer can not join the cluster by the helper.
+func (c *Client) AddObserver(ctx context.Context, db *sql.DB, fqdn string, editLogPort int32) error {
+	statement := fmt.Sprintf("ALTER SYSTEM ADD OBSERVER \"%v:%v\"", fqdn, editLogPort)
+	return c.ExecuteContext(ctx, db, statement)
+}
+
+// DropObserver executes the SQL statement to drop an observer.
+func (c *Client) DropObserver(ctx context.Context, db *sql.DB, frontend Frontend) error {
+	statement := fmt.Sprintf("ALTER SYSTEM DROP OBSERVER \"%v:%v\"", frontend.FQDN, frontend.EditLogPort)
+	return c.ExecuteContext(ctx, db, statement)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ShowFrontends(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery(ShowFrontendsStatement).WillReturnRows(
+		sqlmock.NewRows([]string{"Name", "IP", "EditLogPort", "Role", "Alive"}).
+			AddRow([]byte("fe-1"), []byte("kube-starrocks-fe-0.kube-starrocks-fe-search"), []byte("9010"), []byte("LEADER"), []byte("true")).
+			AddRow([]byte("fe-2"), []byte("kube-starrocks-read-observer-fe-0.kube-starrocks-read-observer-fe-search"),
+				[]byte("9010"), []byte("OBSERVER"), []byte("false")))
+
+	got, err := (&Client{}).ShowFrontends(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, []Frontend{
+		{Name: "fe-1", FQDN: "kube-starrocks-fe-0.kube-starrocks-fe-search", EditLogPort: "9010", Role: FrontendRoleLeader, Alive: true},
+		{Name: "fe-2", FQDN: "kube-starrocks-read-observer-fe-0.kube-starrocks-read-observer-fe-search", EditLogPort: "9010",
+			Role: FrontendRoleObserver},
+	}, got)
+}
+
+func TestClient_ShowFrontends_IsMaster(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery(ShowFrontendsStatement).WillReturnRows(
+		sqlmock.NewRows([]string{"Name", "IP", "Role", "IsMaster", "Alive"}).
+			AddRow("fe-1", "kube-starrocks-fe-0.kube-starrocks-fe-search", "FOLLOWER", "true", "true").
+			AddRow("fe-2", "kube-starrocks-fe-1.kube-starrocks-fe-search", "FOLLOWER", "false", "true"))
+
+	got, err := (&Client{}).ShowFrontends(context.Background(), db)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, FrontendRoleLeader, got[0].Role)
+	assert.Equal(t, FrontendRoleFollower, got[1].Role)
+}
+
+func TestClient_AddObserver(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec(`ALTER SYSTEM ADD OBSERVER "fe-observer-0.fe-observer-search:9010"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = (&Client{}).AddObserver(context.Background(), db, "fe-observer-0.fe-observer-search", 9010)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClient_DropObserver(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec(`ALTER SYSTEM DROP OBSERVER "fe-observer-0.fe-observer-search:9010"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = (&Client{}).DropObserver(context.Background(), db, Frontend{FQDN: "fe-observer-0.fe-observer-search", EditLogPort: "9010"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
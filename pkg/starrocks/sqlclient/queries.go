@@ -0,0 +1,119 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	ShowRunningQueriesStatement = "SHOW RUNNING QUERIES"
+	TimeZoneStatement           = "SELECT @@time_zone"
+
+	// QueryStatePending and QueryStateRunning are the states of queries in the result of SHOW RUNNING QUERIES. A
+	// pending query is waiting in the query queue.
+	QueryStatePending = "PENDING"
+	QueryStateRunning = "RUNNING"
+
+	startTimeLayout = "2006-01-02 15:04:05"
+)
+
+// RunningQuery represents a row of the result of SHOW RUNNING QUERIES.
+type RunningQuery struct {
+	QueryId string
+	// WarehouseId is empty if FE does not return the warehouse of queries, then the query runs in the default
+	// warehouse.
+	WarehouseId     string
+	ResourceGroupId string
+	State           string
+	// Slots is the number of resource group slots used by the query.
+	Slots int
+	// StartTime is zero if it can not be parsed. FE returns it in the time zone of the session, see TimeZone.
+	StartTime time.Time
+}
+
+// ShowRunningQueries executes SHOW RUNNING QUERIES, and returns the running and pending queries of all the FEs. The
+// start time of queries is parsed in the time zone of FE.
+func (c *Client) ShowRunningQueries(ctx context.Context, db *sql.DB) ([]RunningQuery, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	location, err := c.TimeZone(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := c.QueryContext(ctx, db, ShowRunningQueriesStatement)
+	if err != nil {
+		return nil, err
+	}
+	queries := make([]RunningQuery, 0, len(rows))
+	for _, row := range rows {
+		query := RunningQuery{
+			QueryId:         row["QueryId"],
+			WarehouseId:     row["WarehouseId"],
+			ResourceGroupId: row["ResourceGroupId"],
+			State:           row["State"],
+		}
+		// the slots and the start time are "-" or empty if the query has not been admitted.
+		if slots := row["Slots"]; slots != "" && slots != "-" {
+			if query.Slots, err = strconv.Atoi(slots); err != nil {
+				logger.Error(err, "invalid slots of query", "queryId", query.QueryId, "slots", slots)
+			}
+		}
+		if startTime := row["StartTime"]; startTime != "" && startTime != "-" {
+			if query.StartTime, err = time.ParseInLocation(startTimeLayout, startTime, location); err != nil {
+				logger.Error(err, "invalid start time of query", "queryId", query.QueryId, "startTime", startTime)
+			}
+		}
+		queries = append(queries, query)
+	}
+	return queries, nil
+}
+
+// TimeZone executes SELECT @@time_zone, and returns the time zone of the session. The time zone is a name, e.g.
+// Asia/Shanghai, or an offset, e.g. +08:00.
+func (c *Client) TimeZone(ctx context.Context, db *sql.DB) (*time.Location, error) {
+	rows, err := c.QueryContext(ctx, db, TimeZoneStatement)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no result of %s", TimeZoneStatement)
+	}
+	return parseTimeZone(rows[0]["@@time_zone"])
+}
+
+func parseTimeZone(timeZone string) (*time.Location, error) {
+	if offset, err := time.Parse("-07:00", timeZone); err == nil {
+		_, seconds := offset.Zone()
+		return time.FixedZone(timeZone, seconds), nil
+	}
+	if strings.EqualFold(timeZone, "SYSTEM") {
+		// FE runs in the time zone of its host, which is usually the same as the operator in kubernetes.
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q of FE: %w", timeZone, err)
+	}
+	return location, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ShowRunningQueries(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	columns := []string{"QueryId", "WarehouseId", "ResourceGroupId", "StartTime", "PendingTimeout", "QueryTimeout",
+		"State", "Slots", "Fragments", "DOP", "Frontend", "FeStartTime"}
+	mock.ExpectQuery("SELECT @@time_zone").WillReturnRows(sqlmock.NewRows([]string{"@@time_zone"}).AddRow("+08:00"))
+	mock.ExpectQuery("SHOW RUNNING QUERIES").WillReturnRows(sqlmock.NewRows(columns).
+		AddRow("q1", "0", "10001", "2024-01-02 15:04:05", "2024-01-02 15:09:05", "2024-01-02 15:09:05",
+			"RUNNING", "4", "3", "8", "fe-0", "2024-01-01 00:00:00").
+		AddRow("q2", "1", "-", "", "", "", "PENDING", "-", "0", "0", "fe-1", ""))
+
+	got, err := (&Client{}).ShowRunningQueries(context.Background(), db)
+	require.NoError(t, err)
+	for i := range got {
+		got[i].StartTime = got[i].StartTime.UTC()
+	}
+	assert.Equal(t, []RunningQuery{
+		{
+			QueryId:         "q1",
+			WarehouseId:     "0",
+			ResourceGroupId: "10001",
+			State:           QueryStateRunning,
+			Slots:           4,
+			StartTime:       time.Date(2024, 1, 2, 7, 4, 5, 0, time.UTC),
+		},
+		{QueryId: "q2", WarehouseId: "1", ResourceGroupId: "-", State: QueryStatePending},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestParseTimeZone(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	tests := []struct {
+		timeZone string
+		want     time.Time
+		wantErr  bool
+	}{
+		{timeZone: "Asia/Shanghai", want: time.Date(2024, 1, 2, 15, 4, 5, 0, shanghai)},
+		{timeZone: "+08:00", want: time.Date(2024, 1, 2, 7, 4, 5, 0, time.UTC)},
+		{timeZone: "-05:30", want: time.Date(2024, 1, 2, 20, 34, 5, 0, time.UTC)},
+		{timeZone: "UTC", want: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{timeZone: "SYSTEM", want: time.Date(2024, 1, 2, 15, 4, 5, 0, time.Local)},
+		{timeZone: "Mars/Olympus", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timeZone, func(t *testing.T) {
+			location, err := parseTimeZone(tt.timeZone)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			got, err := time.ParseInLocation(startTimeLayout, "2024-01-02 15:04:05", location)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
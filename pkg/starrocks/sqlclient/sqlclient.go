@@ -0,0 +1,339 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sqlclient provides the client used by the operator to execute SQL statements in StarRocks by the FE
+// service, and the typed helpers of the statements which are shared by the controllers, e.g. SHOW FRONTENDS.
+package sqlclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-sql-driver/mysql"
+)
+
+// The modes of TLS to connect to FE.
+const (
+	TLSDisabled   = "disabled"
+	TLSPreferred  = "preferred"
+	TLSSkipVerify = "skip-verify"
+	TLSVerify     = "verify"
+
+	// tlsConfigName is the name of the TLS config registered to the MySQL driver when a CA file is specified.
+	tlsConfigName = "starrocks"
+)
+
+const (
+	DefaultTimeout       = time.Minute
+	DefaultMaxRetries    = 3
+	DefaultRetryInterval = 2 * time.Second
+)
+
+// retryableMessages are the error messages returned when FE can not execute the statement for now, e.g. the leader of
+// FE is changing, or the FE the connection belongs to is restarting.
+var retryableMessages = []string{
+	"is not ready",
+	"not leader",
+	"not master",
+	"failed to forward",
+	"connection refused",
+	"connection reset by peer",
+	"broken pipe",
+	"database is closed",
+}
+
+// Options are the options of all the clients, they are set by Configure when the operator starts.
+type Options struct {
+	// Timeout is the timeout of a SQL statement, including connecting to FE and reading the result.
+	Timeout time.Duration
+
+	// TLSMode is the TLS mode to connect to FE, one of disabled, preferred, skip-verify and verify.
+	TLSMode string
+
+	// TLSCAFile is the CA certificate to verify FE when TLSMode is verify. If it is empty, the system CAs are used.
+	TLSCAFile string
+
+	// MaxRetries is the max number of retries when FE can not execute the statement for now.
+	MaxRetries int
+
+	// RetryInterval is the interval between retries.
+	RetryInterval time.Duration
+}
+
+// DefaultOptions returns the default options.
+func DefaultOptions() Options {
+	return Options{
+		Timeout:       DefaultTimeout,
+		TLSMode:       TLSDisabled,
+		MaxRetries:    DefaultMaxRetries,
+		RetryInterval: DefaultRetryInterval,
+	}
+}
+
+var (
+	options = DefaultOptions()
+	// tlsConfig is the value of the tls parameter in DSN.
+	tlsConfig = "false"
+)
+
+// Configure validates and sets the options of all the clients. It should be called before any client is used.
+func Configure(opts Options) error {
+	config := "false"
+	switch opts.TLSMode {
+	case "", TLSDisabled:
+	case TLSPreferred:
+		config = "preferred"
+	case TLSSkipVerify:
+		config = "skip-verify"
+	case TLSVerify:
+		config = "true"
+		if opts.TLSCAFile != "" {
+			pem, err := os.ReadFile(opts.TLSCAFile)
+			if err != nil {
+				return fmt.Errorf("failed to read the CA file of FE: %w", err)
+			}
+			rootCAs := x509.NewCertPool()
+			if !rootCAs.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("no certificate is found in the CA file %s", opts.TLSCAFile)
+			}
+			if err = mysql.RegisterTLSConfig(tlsConfigName, &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}); err != nil {
+				return err
+			}
+			config = tlsConfigName
+		}
+	default:
+		return fmt.Errorf("unknown TLS mode %q, it should be one of %s, %s, %s and %s",
+			opts.TLSMode, TLSDisabled, TLSPreferred, TLSSkipVerify, TLSVerify)
+	}
+	if opts.Timeout <= 0 {
+		return fmt.Errorf("the timeout of SQL statements should be positive, but got %v", opts.Timeout)
+	}
+
+	options, tlsConfig = opts, config
+	return nil
+}
+
+// NewHTTPClient returns the client to call the HTTP API of StarRocks nodes, e.g. /api/update_config of BE and CN. The
+// requests have the same timeout as SQL statements, so that a node which does not respond can not block the
+// reconciliation forever.
+func NewHTTPClient() *http.Client {
+	return &http.Client{Timeout: options.Timeout}
+}
+
+// Client executes SQL statements in a StarRocks cluster by the FE service as root.
+// The connections are cached per FE service, so that they can be reused by the controllers. If the root password
+// is changed, the cached connections are closed and new ones are created.
+type Client struct {
+	RootPassword       string
+	FeServiceName      string
+	FeServiceNamespace string
+	FeServicePort      string
+
+	// Host is the host of a specific FE, e.g. the FQDN of a FE pod. The FE service is used if it is empty.
+	Host string
+}
+
+// ForFrontend returns a client which connects to the FE of the host directly instead of the FE service, e.g. to
+// execute the statements which only take effect on one FE.
+func (c *Client) ForFrontend(host string) *Client {
+	client := *c
+	client.Host = host
+	return &client
+}
+
+// pooledDB is a cached sql.DB, dsn is used to check whether the password or the options are changed.
+type pooledDB struct {
+	dsn string
+	db  *sql.DB
+}
+
+var pool = struct {
+	sync.Mutex
+	dbs map[string]*pooledDB
+}{dbs: map[string]*pooledDB{}}
+
+// address returns the address of FE service, or the address of FE if Host is set.
+func (c *Client) address() string {
+	if c.Host != "" {
+		return net.JoinHostPort(c.Host, c.FeServicePort)
+	}
+	return fmt.Sprintf("%s.%s:%s", c.FeServiceName, c.FeServiceNamespace, c.FeServicePort)
+}
+
+func (c *Client) dsn() string {
+	config := mysql.NewConfig()
+	config.User = "root"
+	config.Passwd = c.RootPassword
+	config.Net = "tcp"
+	config.Addr = c.address()
+	config.Timeout = options.Timeout
+	config.ReadTimeout = options.Timeout
+	config.WriteTimeout = options.Timeout
+	config.TLSConfig = tlsConfig
+	return config.FormatDSN()
+}
+
+// open returns the cached sql.DB of the FE service, db is returned directly if it is not nil, it is used by tests.
+func (c *Client) open(db *sql.DB) (*sql.DB, error) {
+	if db != nil {
+		return db, nil
+	}
+
+	address, dsn := c.address(), c.dsn()
+	pool.Lock()
+	defer pool.Unlock()
+	if pooled, ok := pool.dbs[address]; ok {
+		if pooled.dsn == dsn {
+			return pooled.db, nil
+		}
+		// the root password or the options have been changed.
+		_ = pooled.db.Close()
+		delete(pool.dbs, address)
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQL statements are executed infrequently, so keep a few connections only.
+	db.SetMaxOpenConns(4)
+	db.SetMaxIdleConns(2)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+	pool.dbs[address] = &pooledDB{dsn: dsn, db: db}
+	return db, nil
+}
+
+// ExecuteContext executes a SQL statement. It is retried if FE can not execute it for now.
+func (c *Client) ExecuteContext(ctx context.Context, db *sql.DB, statement string) error {
+	return c.retry(ctx, func() error {
+		conn, err := c.open(db)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+		_, err = conn.ExecContext(ctx, statement)
+		return err
+	})
+}
+
+// Row is a row of the result of a query, the key is the name of column.
+type Row map[string]string
+
+// QueryContext executes a query, and returns all the rows. It is retried if FE can not execute it for now.
+func (c *Client) QueryContext(ctx context.Context, db *sql.DB, query string) ([]Row, error) {
+	var result []Row
+	err := c.retry(ctx, func() error {
+		conn, err := c.open(db)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+		result, err = queryRows(ctx, conn, query)
+		return err
+	})
+	return result, err
+}
+
+func queryRows(ctx context.Context, db *sql.DB, query string) ([]Row, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var result []Row
+	for rows.Next() {
+		// Create a slice of `interface{}` to hold the values dynamically
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err = rows.Scan(valuePtrs...); err != nil {
+			return nil, err
+		}
+		row := make(Row, len(columns))
+		for i, col := range columns {
+			row[col] = toString(values[i])
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// retry calls fn until it succeeds, the error is not retryable, or the max number of retries is reached.
+func (c *Client) retry(ctx context.Context, fn func() error) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	var err error
+	for i := 0; ; i++ {
+		if err = fn(); err == nil || !IsRetryable(err) || i >= options.MaxRetries {
+			return err
+		}
+		logger.Info("FE can not execute the statement for now, retry later", "fe", c.address(), "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(options.RetryInterval):
+		}
+	}
+}
+
+// IsRetryable returns true if the error means FE can not execute the statement for now, e.g. the leader of FE is
+// changing, so the statement may succeed later.
+func IsRetryable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, retryable := range retryableMessages {
+		if strings.Contains(message, retryable) {
+			return true
+		}
+	}
+	return false
+}
+
+// toString converts the value scanned from database to string.
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
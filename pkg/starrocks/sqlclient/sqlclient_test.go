@@ -0,0 +1,235 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setOptions sets the options for a test, and restores them after the test.
+func setOptions(t *testing.T, opts Options) {
+	oldOptions, oldTLSConfig := options, tlsConfig
+	t.Cleanup(func() { options, tlsConfig = oldOptions, oldTLSConfig })
+	require.NoError(t, Configure(opts))
+}
+
+func TestClient_ExecuteContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec("drop warehouse test").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	c := &Client{RootPassword: "root", FeServiceName: "localhost", FeServicePort: "3306"}
+	assert.NoError(t, c.ExecuteContext(context.Background(), db, "drop warehouse test"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClient_ExecuteContext_Retry(t *testing.T) {
+	opts := DefaultOptions()
+	opts.MaxRetries = 2
+	opts.RetryInterval = time.Millisecond
+	setOptions(t, opts)
+
+	tests := []struct {
+		name    string
+		errs    []error
+		wantErr bool
+	}{
+		{
+			name: "succeed after the leader of FE is changed",
+			errs: []error{errors.New("Error 1064 (HY000): The FE is not ready, please wait a moment")},
+		},
+		{
+			name:    "give up after max retries",
+			errs:    []error{mysql.ErrInvalidConn, mysql.ErrInvalidConn, mysql.ErrInvalidConn},
+			wantErr: true,
+		},
+		{
+			name:    "do not retry if the error is not retryable",
+			errs:    []error{&mysql.MySQLError{Number: 1045, Message: "Access denied for user 'root'"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			for _, err := range tt.errs {
+				mock.ExpectExec("SELECT 1").WillReturnError(err)
+			}
+			if !tt.wantErr {
+				mock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+
+			err = (&Client{}).ExecuteContext(context.Background(), db, "SELECT 1")
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestClient_QueryContext(t *testing.T) {
+	opts := DefaultOptions()
+	opts.RetryInterval = time.Millisecond
+	setOptions(t, opts)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("SHOW REPOSITORIES").WillReturnError(errors.New("failed to forward the request to the leader"))
+	mock.ExpectQuery("SHOW REPOSITORIES").WillReturnRows(
+		sqlmock.NewRows([]string{"RepoId", "RepoName", "ErrMsg"}).AddRow([]byte("1"), []byte("repo"), nil).AddRow(2, "repo2", "NULL"))
+
+	rows, err := (&Client{}).QueryContext(context.Background(), db, "SHOW REPOSITORIES")
+	require.NoError(t, err)
+	assert.Equal(t, []Row{
+		{"RepoId": "1", "RepoName": "repo", "ErrMsg": ""},
+		{"RepoId": "2", "RepoName": "repo2", "ErrMsg": "NULL"},
+	}, rows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Timeout = 5 * time.Second
+	setOptions(t, opts)
+	assert.Equal(t, 5*time.Second, NewHTTPClient().Timeout)
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: mysql.ErrInvalidConn, want: true},
+		{err: fmt.Errorf("query failed: %w", mysql.ErrInvalidConn), want: true},
+		{err: errors.New("dial tcp 10.0.0.1:9030: connect: connection refused"), want: true},
+		{err: errors.New("Error 1064 (HY000): current node is not leader"), want: true},
+		{err: &mysql.MySQLError{Number: 1045, Message: "Access denied for user 'root'"}, want: false},
+		{err: errors.New("Error 1064 (HY000): Getting syntax error"), want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsRetryable(tt.err), tt.err.Error())
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0600))
+
+	tests := []struct {
+		name          string
+		opts          func(opts *Options)
+		wantErr       bool
+		wantTLSConfig string
+	}{
+		{
+			name:          "default options",
+			opts:          func(opts *Options) {},
+			wantTLSConfig: "false",
+		},
+		{
+			name:          "skip verify",
+			opts:          func(opts *Options) { opts.TLSMode = TLSSkipVerify },
+			wantTLSConfig: "skip-verify",
+		},
+		{
+			name:          "verify by the system CAs",
+			opts:          func(opts *Options) { opts.TLSMode = TLSVerify },
+			wantTLSConfig: "true",
+		},
+		{
+			name:    "invalid CA file",
+			opts:    func(opts *Options) { opts.TLSMode, opts.TLSCAFile = TLSVerify, caFile },
+			wantErr: true,
+		},
+		{
+			name:    "unknown TLS mode",
+			opts:    func(opts *Options) { opts.TLSMode = "required" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			opts:    func(opts *Options) { opts.Timeout = 0 },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldOptions, oldTLSConfig := options, tlsConfig
+			defer func() { options, tlsConfig = oldOptions, oldTLSConfig }()
+
+			opts := DefaultOptions()
+			tt.opts(&opts)
+			err := Configure(opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTLSConfig, tlsConfig)
+		})
+	}
+}
+
+func TestClient_open(t *testing.T) {
+	c := &Client{RootPassword: "", FeServiceName: "kube-starrocks-fe-service", FeServiceNamespace: "test-open", FeServicePort: "9030"}
+	db1, err := c.open(nil)
+	require.NoError(t, err)
+	db2, err := c.open(nil)
+	require.NoError(t, err)
+	assert.Same(t, db1, db2, "the connections should be reused")
+
+	c.RootPassword = "new-password"
+	db3, err := c.open(nil)
+	require.NoError(t, err)
+	assert.NotSame(t, db1, db3, "the connections should be recreated after the password is changed")
+	assert.Error(t, db1.Ping(), "the old connections should be closed")
+}
+
+func TestClient_dsn(t *testing.T) {
+	setOptions(t, Options{Timeout: 10 * time.Second, TLSMode: TLSPreferred})
+
+	c := &Client{RootPassword: "p@ss", FeServiceName: "kube-starrocks-fe-service", FeServiceNamespace: "default", FeServicePort: "9030"}
+	config, err := mysql.ParseDSN(c.dsn())
+	require.NoError(t, err)
+	assert.Equal(t, "root", config.User)
+	assert.Equal(t, "p@ss", config.Passwd)
+	assert.Equal(t, "kube-starrocks-fe-service.default:9030", config.Addr)
+	assert.Equal(t, 10*time.Second, config.Timeout)
+	assert.Equal(t, 10*time.Second, config.ReadTimeout)
+	assert.Equal(t, "preferred", config.TLSConfig)
+}
+
+func TestClient_ForFrontend(t *testing.T) {
+	c := &Client{FeServiceName: "kube-starrocks-fe-service", FeServiceNamespace: "default", FeServicePort: "9030"}
+	fe := c.ForFrontend("kube-starrocks-fe-1.kube-starrocks-fe-search.default.svc.cluster.local")
+	assert.Equal(t, "kube-starrocks-fe-1.kube-starrocks-fe-search.default.svc.cluster.local:9030", fe.address())
+	assert.Equal(t, "kube-starrocks-fe-service.default:9030", c.address(), "the original client should not be changed")
+}
@@ -18,6 +18,7 @@ package be
 
 import (
 	"context"
+	"net/http"
 	"strconv"
 
 	"github.com/go-logr/logr"
@@ -34,10 +35,12 @@ import (
 	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/statefulset"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
 	subc "github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
 )
@@ -45,11 +48,15 @@ import (
 type BeController struct {
 	Client   client.Client
 	Recorder record.EventRecorder
+
+	// httpClient is used to call the HTTP API of BE, e.g. /api/update_config.
+	httpClient *http.Client
 }
 
 func New(k8sClient client.Client, recorderFor subc.GetEventRecorderForFunc) *BeController {
 	controller := &BeController{
-		Client: k8sClient,
+		Client:     k8sClient,
+		httpClient: sqlclient.NewHTTPClient(),
 	}
 	controller.Recorder = recorderFor(controller.GetControllerName())
 	return controller
@@ -81,7 +88,7 @@ func (be *BeController) SyncCluster(ctx context.Context, src *srapi.StarRocksClu
 	}()
 
 	beSpec := src.Spec.StarRocksBeSpec
-	if err = be.validating(beSpec); err != nil {
+	if err = be.Validating(beSpec); err != nil {
 		return err
 	}
 
@@ -122,13 +129,51 @@ func (be *BeController) SyncCluster(ctx context.Context, src *srapi.StarRocksClu
 	internalService := GenerateInternalService(src, &externalsvc, beConfig, defaultLabels)
 
 	// create be statefulset
-	podTemplateSpec, err := be.buildPodTemplate(src, beConfig)
+	referenceHashes, err := subc.ReferenceHashes(ctx, be.Client, src.Namespace, &beSpec.StarRocksComponentSpec)
+	if err != nil {
+		logger.Error(err, "get the hashes of referenced configMaps and secrets failed")
+		return err
+	}
+	podTemplateSpec, err := be.buildPodTemplate(src, beConfig, referenceHashes)
 	if err != nil {
 		logger.Error(err, "build pod template failed")
 		return err
 	}
+	if src.Status.StarRocksBeStatus == nil {
+		src.Status.StarRocksBeStatus = &srapi.StarRocksBeStatus{
+			StarRocksComponentStatus: srapi.StarRocksComponentStatus{Phase: srapi.ComponentReconciling},
+		}
+	}
+	configHash, err := subc.SyncDynamicConfig(ctx, be.Client, object.NewFromCluster(src), src.Name, beSpec,
+		&src.Status.StarRocksBeStatus.StarRocksComponentStatus, be.dynamicConfigApplier(src))
+	if err != nil {
+		logger.Error(err, "sync dynamic config failed")
+		return err
+	}
+	if configHash != "" {
+		podTemplateSpec.Annotations[srapi.ConfigHashAnnotation] = configHash
+	}
+	if err = subc.SyncConfigMap(ctx, be.Client, object.NewFromCluster(src), src.Name, beSpec, configHash); err != nil {
+		logger.Error(err, "sync be config configMap failed")
+		return err
+	}
 	st := statefulset.MakeStatefulset(object.NewFromCluster(src), beSpec, podTemplateSpec)
 
+	// In shared-nothing mode, the BE nodes should be decommissioned before they are removed. But when the cluster is
+	// suspended, the data is retained in the persistent volumes, and the BE nodes are only stopped.
+	if src.IsComponentSuspended(srapi.DEFAULT_BE) {
+		logger.Info("the cluster is suspended, scale be to zero")
+		st.Spec.Replicas = rutils.GetInt32Pointer(0)
+	} else if !fe.IsRunInSharedDataMode(feConfig) {
+		be.decommissionBackends(ctx, src, &st, nil)
+	}
+
+	if err = subc.ExpandPersistentVolumeClaims(ctx, be.Client, be.Recorder, src, &st,
+		&src.Status.StarRocksBeStatus.StarRocksComponentStatus); err != nil {
+		logger.Error(err, "expand persistent volume claims failed")
+		return err
+	}
+
 	// update the statefulset if feSpec be updated.
 	if err = k8sutils.ApplyStatefulSet(ctx, be.Client, &st, true, rutils.StatefulSetDeepEqual); err != nil {
 		logger.Error(err, "apply statefulset failed")
@@ -148,6 +193,18 @@ func (be *BeController) SyncCluster(ctx context.Context, src *srapi.StarRocksClu
 	return err
 }
 
+// dynamicConfigApplier returns the applier which applies the changed config to the backends of StarRocksCluster, see
+// subc.SyncDynamicConfig.
+func (be *BeController) dynamicConfigApplier(src *srapi.StarRocksCluster) subc.DynamicConfigApplier {
+	return func(ctx context.Context, changes map[string]string) ([]string, error) {
+		sqlClient, err := fe.NewSQLClient(ctx, be.Client, src)
+		if err != nil {
+			return nil, err
+		}
+		return subc.ApplyBackendConfig(ctx, sqlClient, be.httpClient, load.Name(src.Name, src.Spec.StarRocksBeSpec), changes)
+	}
+}
+
 // UpdateClusterStatus update the all resource status about be.
 func (be *BeController) UpdateClusterStatus(ctx context.Context, src *srapi.StarRocksCluster) error {
 	logger := logr.FromContextOrDiscard(ctx).WithName(be.GetControllerName()).WithValues(log.ActionKey, log.ActionUpdateClusterStatus)
@@ -215,9 +272,12 @@ func GenerateInternalService(src *srapi.StarRocksCluster,
 	return searchSvc
 }
 
-// GetBeConfig get the config of BE from configmap.
+// GetBeConfig get the config of BE from spec.config or configmap.
 func (be *BeController) GetBeConfig(ctx context.Context,
 	beSpec *srapi.StarRocksBeSpec, namespace string) (map[string]interface{}, error) {
+	if srconfig.IsManaged(beSpec) {
+		return srconfig.Resolve(beSpec)
+	}
 	return k8sutils.GetConfig(ctx, be.Client, beSpec.ConfigMapInfo,
 		beSpec.ConfigMaps, pod.GetConfigDir(beSpec), "be.conf", namespace)
 }
@@ -226,8 +286,9 @@ func (be *BeController) ClearCluster(ctx context.Context, src *srapi.StarRocksCl
 	logger := logr.FromContextOrDiscard(ctx).WithName(be.GetControllerName()).WithValues(log.ActionKey, log.ActionCluster)
 	ctx = logr.NewContext(ctx, logger)
 
+	// clear the resources when the spec of be is removed, or the StarRocksCluster is being deleted.
 	beSpec := src.Spec.StarRocksBeSpec
-	if beSpec != nil {
+	if beSpec != nil && src.DeletionTimestamp.IsZero() {
 		return nil
 	}
 
@@ -249,11 +310,17 @@ func (be *BeController) ClearCluster(ctx context.Context, src *srapi.StarRocksCl
 		logger.Error(err, "delete external service failed", "externalService", externalServiceName)
 		return err
 	}
+	configMapName := srconfig.ConfigMapName(src.Name, beSpec)
+	if err = k8sutils.DeleteConfigMap(ctx, be.Client, src.Namespace, configMapName); err != nil {
+		logger.Error(err, "delete config configMap failed", "configMap", configMapName)
+		return err
+	}
 
 	return nil
 }
 
-func (be *BeController) validating(beSpec *srapi.StarRocksBeSpec) error {
+// Validating validates the spec of BE, it is also used by the admission webhook.
+func (be *BeController) Validating(beSpec *srapi.StarRocksBeSpec) error {
 	for i := range beSpec.StorageVolumes {
 		if err := beSpec.StorageVolumes[i].Validate(); err != nil {
 			return err
@@ -262,5 +329,5 @@ func (be *BeController) validating(beSpec *srapi.StarRocksBeSpec) error {
 	if err := srapi.ValidUpdateStrategy(beSpec.UpdateStrategy); err != nil {
 		return err
 	}
-	return nil
+	return srconfig.Validate(beSpec)
 }
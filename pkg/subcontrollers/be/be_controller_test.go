@@ -16,6 +16,7 @@ package be_test
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"reflect"
 	"testing"
@@ -37,6 +38,8 @@ import (
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/fakefe"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/be"
 )
 
@@ -180,6 +183,200 @@ func Test_Sync(t *testing.T) {
 	require.Equal(t, asvc.Spec.Selector, st.Spec.Selector.MatchLabels)
 }
 
+func Test_SyncWithConfig(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{},
+			StarRocksBeSpec: &srapi.StarRocksBeSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+						Replicas: rutils.GetInt32Pointer(1),
+						Image:    "test.image",
+					},
+					Config: map[string]string{"webserver_port": "18040", "sys_log_level": "WARN"},
+				},
+			},
+		},
+	}
+
+	ep := corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-fe-service",
+			Namespace: "default",
+		},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "172.0.0.1"}},
+		}},
+	}
+
+	bc := be.New(fake.NewFakeClient(srapi.Scheme, src, &ep), fake.GetEventRecorderFor(nil))
+	require.NoError(t, bc.SyncCluster(context.Background(), src))
+
+	var cm corev1.ConfigMap
+	require.NoError(t, bc.Client.Get(context.Background(),
+		types.NamespacedName{Name: "test-be-config", Namespace: "default"}, &cm))
+	require.Contains(t, cm.Data["be.conf"], "webserver_port = 18040\n")
+	require.Contains(t, cm.Data["be.conf"], "sys_log_level = WARN\n")
+
+	var st appsv1.StatefulSet
+	require.NoError(t, bc.Client.Get(context.Background(),
+		types.NamespacedName{Name: load.Name(src.Name, src.Spec.StarRocksBeSpec), Namespace: "default"}, &st))
+	template := st.Spec.Template
+	require.NotEmpty(t, template.Annotations[srapi.ConfigHashAnnotation])
+	require.Equal(t, "test-be-config", template.Spec.Volumes[len(template.Spec.Volumes)-1].ConfigMap.Name)
+	container := template.Spec.Containers[0]
+	require.Contains(t, container.Env, corev1.EnvVar{Name: "CONFIGMAP_MOUNT_PATH", Value: "/etc/starrocks/be/conf"})
+	require.Equal(t, int32(18040), container.ReadinessProbe.HTTPGet.Port.IntVal)
+
+	// the configMap is deleted when spec.config is removed
+	src.Spec.StarRocksBeSpec.Config = nil
+	require.NoError(t, bc.SyncCluster(context.Background(), src))
+	err := bc.Client.Get(context.Background(), types.NamespacedName{Name: "test-be-config", Namespace: "default"}, &cm)
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func Test_SyncWithDynamicConfig(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{},
+			StarRocksBeSpec: &srapi.StarRocksBeSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+						Replicas: rutils.GetInt32Pointer(1),
+						Image:    "test.image",
+					},
+					Config: map[string]string{"max_compaction_concurrency": "1"},
+				},
+			},
+		},
+	}
+	ep := corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-fe-service",
+			Namespace: "default",
+		},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "172.0.0.1"}},
+		}},
+	}
+
+	// the config of backends is updated by their HTTP API, which is served by the fake FE.
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = fakefe.HTTPClient().Transport
+	defer func() { http.DefaultTransport = defaultTransport }()
+	const fqdn = "test-be-0.test-be-search.default.svc.cluster.local"
+	fakeFE := fakefe.Start(t, "test-fe-service.default")
+	fakeFE.AddBackend(sqlclient.Backend{FQDN: fqdn, HeartbeatPort: "9050"})
+	fakeFE.SetBackendConfig("max_compaction_concurrency", "-1", true)
+	fakeFE.SetBackendConfig("storage_root_path", "${STARROCKS_HOME}/storage", false)
+
+	ctx := context.Background()
+	bc := be.New(fake.NewFakeClient(srapi.Scheme, src, &ep), fake.GetEventRecorderFor(nil))
+	configHash := func() string {
+		var st appsv1.StatefulSet
+		require.NoError(t, bc.Client.Get(ctx, types.NamespacedName{Name: "test-be", Namespace: "default"}, &st))
+		return st.Spec.Template.Annotations[srapi.ConfigHashAnnotation]
+	}
+	require.NoError(t, bc.SyncCluster(ctx, src))
+	originalHash := configHash()
+	require.NotEmpty(t, originalHash)
+
+	// the dynamic config is applied without restarting pods
+	src.Spec.StarRocksBeSpec.Config["max_compaction_concurrency"] = "4"
+	require.NoError(t, bc.SyncCluster(ctx, src))
+	require.Equal(t, originalHash, configHash())
+	require.Equal(t, "4", fakeFE.BackendConfig(fqdn, "max_compaction_concurrency"))
+	require.Equal(t, &srapi.ConfigStatus{HotAppliedKeys: []string{"max_compaction_concurrency"}},
+		src.Status.StarRocksBeStatus.Config)
+	var cm corev1.ConfigMap
+	require.NoError(t, bc.Client.Get(ctx, types.NamespacedName{Name: "test-be-config", Namespace: "default"}, &cm))
+	require.Contains(t, cm.Data["be.conf"], "max_compaction_concurrency = 4\n")
+
+	// the pods are restarted to apply the static config
+	src.Spec.StarRocksBeSpec.Config["storage_root_path"] = "/data"
+	require.NoError(t, bc.SyncCluster(ctx, src))
+	require.NotEqual(t, originalHash, configHash())
+	require.Equal(t, &srapi.ConfigStatus{PendingRestartKeys: []string{"storage_root_path"}},
+		src.Status.StarRocksBeStatus.Config)
+}
+
+func Test_SyncWithReferenceHashes(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{},
+			StarRocksBeSpec: &srapi.StarRocksBeSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+						Replicas:      rutils.GetInt32Pointer(1),
+						Image:         "test.image",
+						ConfigMapInfo: srapi.ConfigMapInfo{ConfigMapName: "be-cm", ResolveKey: "be.conf"},
+					},
+					Secrets: []srapi.SecretReference{
+						{Name: "s3-credential", MountPath: "/etc/s3"},
+						{Name: "static", MountPath: "/etc/static", DisableRollout: true},
+					},
+				},
+			},
+		},
+	}
+	ep := corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-fe-service",
+			Namespace: "default",
+		},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "172.0.0.1"}},
+		}},
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "be-cm", Namespace: "default"},
+		Data:       map[string]string{"be.conf": "be_port = 9060"},
+	}
+	s3Credential := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-credential", Namespace: "default"},
+		Data:       map[string][]byte{"key": []byte("v1")},
+	}
+	static := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "static", Namespace: "default"},
+		Data:       map[string][]byte{"key": []byte("v1")},
+	}
+
+	bc := be.New(fake.NewFakeClient(srapi.Scheme, src, &ep, cm, s3Credential, static), fake.GetEventRecorderFor(nil))
+	getAnnotations := func() map[string]string {
+		require.NoError(t, bc.SyncCluster(context.Background(), src))
+		var st appsv1.StatefulSet
+		require.NoError(t, bc.Client.Get(context.Background(),
+			types.NamespacedName{Name: load.Name(src.Name, src.Spec.StarRocksBeSpec), Namespace: "default"}, &st))
+		return st.Spec.Template.Annotations
+	}
+
+	annotations := getAnnotations()
+	require.NotEmpty(t, annotations["app.starrocks.components/configmap-be-cm"])
+	require.NotEmpty(t, annotations["app.starrocks.components/secret-s3-credential"])
+	require.NotContains(t, annotations, "app.starrocks.components/secret-static")
+
+	// the hash is changed when the content of configMap is changed
+	cm.Data["be.conf"] = "be_port = 9060\nsys_log_level = WARN"
+	require.NoError(t, bc.Client.Update(context.Background(), cm))
+	newAnnotations := getAnnotations()
+	require.NotEqual(t, annotations["app.starrocks.components/configmap-be-cm"],
+		newAnnotations["app.starrocks.components/configmap-be-cm"])
+	require.Equal(t, annotations["app.starrocks.components/secret-s3-credential"],
+		newAnnotations["app.starrocks.components/secret-s3-credential"])
+}
+
 func TestBeController_GetBeConfig(t *testing.T) {
 	type args struct {
 		ctx       context.Context
@@ -0,0 +1,206 @@
+package be
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+// DecommissionRequeueInterval is the interval to check whether the tablets on the decommissioned BE nodes are migrated.
+const DecommissionRequeueInterval = 10 * time.Second
+
+// decommissionBackends makes sure the BE nodes which will be removed by scale-in have been decommissioned, and all
+// of their tablets have been migrated to other BE nodes, before the statefulset is scaled in.
+// In shared-nothing mode, BE nodes store the data, and removing them directly may cause data loss. So the replicas of
+// expectSTS will be kept as the actual replicas until the tablet number of the BE nodes reaches 0.
+// The progress is recorded in the status of BE.
+func (be *BeController) decommissionBackends(ctx context.Context, src *srapi.StarRocksCluster,
+	expectSTS *appsv1.StatefulSet, db *sql.DB) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	if src.Status.StarRocksBeStatus == nil {
+		src.Status.StarRocksBeStatus = &srapi.StarRocksBeStatus{
+			StarRocksComponentStatus: srapi.StarRocksComponentStatus{
+				Phase: srapi.ComponentReconciling,
+			},
+		}
+	}
+	beStatus := src.Status.StarRocksBeStatus
+
+	var actualSTS appsv1.StatefulSet
+	if err := be.Client.Get(ctx, types.NamespacedName{Namespace: expectSTS.Namespace, Name: expectSTS.Name},
+		&actualSTS); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "get be statefulset failed")
+		}
+		beStatus.ScaleIn = nil
+		return
+	}
+
+	expectReplicas := getReplicas(expectSTS)
+	actualReplicas := getReplicas(&actualSTS)
+	if expectReplicas >= actualReplicas {
+		if !IsDecommissioning(src) {
+			beStatus.ScaleIn = nil
+			return
+		}
+		// the replicas are raised again during a scale-in, the BE nodes are kept and their decommission is canceled.
+		sqlClient, backendsByName, err := be.showBackends(ctx, src, db)
+		if err == nil {
+			err = be.cancelDecommission(ctx, src, sqlClient, backendsByName, actualSTS.Name, expectReplicas, db)
+		}
+		if err != nil {
+			beStatus.ScaleIn.Reason = err.Error()
+			return
+		}
+		beStatus.ScaleIn = nil
+		return
+	}
+
+	// keep the replicas until all the removed BE nodes have been decommissioned.
+	expectSTS.Spec.Replicas = &actualReplicas
+	scaleIn := &srapi.BeScaleInStatus{
+		Phase:          srapi.BeScaleInDecommissioning,
+		TargetReplicas: expectReplicas,
+	}
+	beStatus.ScaleIn = scaleIn
+
+	sqlClient, backendsByName, err := be.showBackends(ctx, src, db)
+	if err != nil {
+		scaleIn.Reason = err.Error()
+		return
+	}
+
+	// the target replicas may be raised during a scale-in, e.g. from 5 to 2 and then to 4.
+	if err = be.cancelDecommission(ctx, src, sqlClient, backendsByName, actualSTS.Name, expectReplicas, db); err != nil {
+		scaleIn.Reason = err.Error()
+		return
+	}
+
+	finished := true
+	for index := expectReplicas; index < actualReplicas; index++ {
+		podName := fmt.Sprintf("%s-%d", actualSTS.Name, index)
+		backend, ok := backendsByName[podName]
+		if !ok {
+			// the BE node has been dropped from FE, e.g. FE drops it automatically after decommission.
+			scaleIn.Backends = append(scaleIn.Backends, srapi.DecommissioningBackend{Name: podName, Decommissioned: true})
+			continue
+		}
+
+		scaleIn.Backends = append(scaleIn.Backends, srapi.DecommissioningBackend{
+			Name:           podName,
+			TabletNum:      backend.TabletNum,
+			Decommissioned: backend.SystemDecommissioned && backend.TabletNum == 0,
+		})
+
+		if !backend.SystemDecommissioned {
+			finished = false
+			logger.Info("decommission backend", "backend", backend.FQDN)
+			if err = sqlClient.DecommissionBackend(ctx, db, backend); err != nil {
+				logger.Error(err, "decommission backend failed", "backend", backend.FQDN)
+				scaleIn.Reason = err.Error()
+				return
+			}
+			be.Recorder.Event(src, corev1.EventTypeNormal, "DecommissionBe",
+				fmt.Sprintf("decommission backend %s", backend.FQDN))
+			continue
+		}
+
+		if backend.TabletNum > 0 {
+			finished = false
+			continue
+		}
+
+		// all the tablets have been migrated, it is safe to drop the backend.
+		logger.Info("drop backend", "backend", backend.FQDN)
+		if err = sqlClient.DropBackend(ctx, db, backend); err != nil {
+			logger.Error(err, "drop backend failed", "backend", backend.FQDN)
+			scaleIn.Reason = err.Error()
+			return
+		}
+	}
+
+	if !finished {
+		scaleIn.Reason = "waiting for the tablets on the decommissioned backends to be migrated"
+		return
+	}
+
+	logger.Info("all the removed backends have been decommissioned, scale in the statefulset",
+		"from", actualReplicas, "to", expectReplicas)
+	be.Recorder.Event(src, corev1.EventTypeNormal, "ScaleInBe",
+		fmt.Sprintf("scale in be from %d to %d", actualReplicas, expectReplicas))
+	expectSTS.Spec.Replicas = &expectReplicas
+	scaleIn.Phase = srapi.BeScaleInDone
+	scaleIn.Reason = ""
+}
+
+// IsDecommissioning returns true if the BE nodes removed by scale-in are being decommissioned. FE migrates the tablets
+// without any change of kubernetes resources, so the progress needs to be checked periodically.
+func IsDecommissioning(src *srapi.StarRocksCluster) bool {
+	beStatus := src.Status.StarRocksBeStatus
+	return beStatus != nil && beStatus.ScaleIn != nil && beStatus.ScaleIn.Phase == srapi.BeScaleInDecommissioning
+}
+
+// cancelDecommission cancels the decommission of the BE nodes whose ordinal is smaller than keptReplicas, these
+// BE nodes were removed by a previous scale-in, but are kept by the current replicas.
+func (be *BeController) cancelDecommission(ctx context.Context, src *srapi.StarRocksCluster,
+	sqlClient *sqlclient.Client, backendsByName map[string]sqlclient.Backend, stsName string,
+	keptReplicas int32, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	for index := int32(0); index < keptReplicas; index++ {
+		backend, ok := backendsByName[fmt.Sprintf("%s-%d", stsName, index)]
+		if !ok || !backend.SystemDecommissioned {
+			continue
+		}
+		logger.Info("cancel decommission backend", "backend", backend.FQDN)
+		if err := sqlClient.CancelDecommissionBackend(ctx, db, backend); err != nil {
+			logger.Error(err, "cancel decommission backend failed", "backend", backend.FQDN)
+			return err
+		}
+		be.Recorder.Event(src, corev1.EventTypeNormal, "CancelDecommissionBe",
+			fmt.Sprintf("cancel decommission backend %s", backend.FQDN))
+	}
+	return nil
+}
+
+// showBackends returns the backends in FE keyed by the name of pod.
+func (be *BeController) showBackends(ctx context.Context, src *srapi.StarRocksCluster,
+	db *sql.DB) (*sqlclient.Client, map[string]sqlclient.Backend, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	sqlClient, err := fe.NewSQLClient(ctx, be.Client, src)
+	if err != nil {
+		logger.Error(err, "new SQL client failed")
+		return nil, nil, err
+	}
+	backends, err := sqlClient.ShowBackends(ctx, db)
+	if err != nil {
+		logger.Error(err, "query SHOW BACKENDS failed", "sql", sqlclient.ShowBackendsStatement)
+		return nil, nil, err
+	}
+
+	backendsByName := make(map[string]sqlclient.Backend)
+	for _, backend := range backends {
+		podName := strings.Split(backend.FQDN, ".")[0]
+		backendsByName[podName] = backend
+	}
+	return sqlClient, backendsByName, nil
+}
+
+func getReplicas(sts *appsv1.StatefulSet) int32 {
+	if sts.Spec.Replicas != nil {
+		return *sts.Spec.Replicas
+	}
+	return 1
+}
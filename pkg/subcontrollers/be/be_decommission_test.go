@@ -0,0 +1,250 @@
+package be
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/fakefe"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+func newBeStatefulSet(replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kube-starrocks-be",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: rutils.GetInt32Pointer(replicas),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Env: []corev1.EnvVar{
+								{Name: "FE_SERVICE_NAME", Value: "kube-starrocks-fe-service"},
+								{Name: "FE_QUERY_PORT", Value: "9030"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newCluster returns a StarRocksCluster whose FE service is kube-starrocks-fe-service.
+func newCluster() *srapi.StarRocksCluster {
+	return &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kube-starrocks",
+			Namespace: "default",
+		},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{},
+		},
+	}
+}
+
+func showBackendsRows(rows ...[]string) *sqlmock.Rows {
+	result := sqlmock.NewRows([]string{"BackendId", "IP", "HeartbeatPort", "SystemDecommissioned", "TabletNum"})
+	for _, row := range rows {
+		result.AddRow([]byte(row[0]), []byte(row[1]), []byte("9050"), []byte(row[2]), []byte(row[3]))
+	}
+	return result
+}
+
+func TestBeController_decommissionBackends(t *testing.T) {
+	decommissioning := &srapi.BeScaleInStatus{Phase: srapi.BeScaleInDecommissioning, TargetReplicas: 2}
+	tests := []struct {
+		name           string
+		actualReplicas int32
+		expectReplicas int32
+		scaleIn        *srapi.BeScaleInStatus
+		mockSQL        func(mock sqlmock.Sqlmock)
+		wantReplicas   int32
+		wantScaleIn    *srapi.BeScaleInStatus
+	}{
+		{
+			name:           "no scale-in",
+			actualReplicas: 3,
+			expectReplicas: 3,
+			mockSQL:        func(_ sqlmock.Sqlmock) {},
+			wantReplicas:   3,
+			wantScaleIn:    nil,
+		},
+		{
+			name:           "cancel the decommission when the replicas are raised again",
+			actualReplicas: 3,
+			expectReplicas: 3,
+			scaleIn:        decommissioning,
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(sqlclient.ShowBackendsStatement).WillReturnRows(showBackendsRows(
+					[]string{"1", "kube-starrocks-be-0.kube-starrocks-be-search", "false", "10"},
+					[]string{"2", "kube-starrocks-be-1.kube-starrocks-be-search", "false", "10"},
+					[]string{"3", "kube-starrocks-be-2.kube-starrocks-be-search", "true", "5"},
+				))
+				mock.ExpectExec(`CANCEL DECOMMISSION BACKEND "kube-starrocks-be-2.kube-starrocks-be-search:9050"`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantReplicas: 3,
+			wantScaleIn:  nil,
+		},
+		{
+			name:           "cancel the decommission of the kept backends when the target replicas are raised",
+			actualReplicas: 4,
+			expectReplicas: 3,
+			scaleIn:        decommissioning,
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(sqlclient.ShowBackendsStatement).WillReturnRows(showBackendsRows(
+					[]string{"1", "kube-starrocks-be-0.kube-starrocks-be-search", "false", "10"},
+					[]string{"2", "kube-starrocks-be-1.kube-starrocks-be-search", "false", "10"},
+					[]string{"3", "kube-starrocks-be-2.kube-starrocks-be-search", "true", "5"},
+					[]string{"4", "kube-starrocks-be-3.kube-starrocks-be-search", "true", "5"},
+				))
+				mock.ExpectExec(`CANCEL DECOMMISSION BACKEND "kube-starrocks-be-2.kube-starrocks-be-search:9050"`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantReplicas: 4,
+			wantScaleIn: &srapi.BeScaleInStatus{
+				Phase:          srapi.BeScaleInDecommissioning,
+				TargetReplicas: 3,
+				Reason:         "waiting for the tablets on the decommissioned backends to be migrated",
+				Backends: []srapi.DecommissioningBackend{
+					{Name: "kube-starrocks-be-3", TabletNum: 5},
+				},
+			},
+		},
+		{
+			name:           "decommission the removed backend",
+			actualReplicas: 3,
+			expectReplicas: 2,
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(sqlclient.ShowBackendsStatement).WillReturnRows(showBackendsRows(
+					[]string{"1", "kube-starrocks-be-0.kube-starrocks-be-search", "false", "10"},
+					[]string{"2", "kube-starrocks-be-1.kube-starrocks-be-search", "false", "10"},
+					[]string{"3", "kube-starrocks-be-2.kube-starrocks-be-search", "false", "10"},
+				))
+				mock.ExpectExec(`ALTER SYSTEM DECOMMISSION BACKEND "kube-starrocks-be-2.kube-starrocks-be-search:9050"`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantReplicas: 3,
+			wantScaleIn: &srapi.BeScaleInStatus{
+				Phase:          srapi.BeScaleInDecommissioning,
+				TargetReplicas: 2,
+				Reason:         "waiting for the tablets on the decommissioned backends to be migrated",
+				Backends: []srapi.DecommissioningBackend{
+					{Name: "kube-starrocks-be-2", TabletNum: 10},
+				},
+			},
+		},
+		{
+			name:           "wait for the tablets to be migrated",
+			actualReplicas: 3,
+			expectReplicas: 1,
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(sqlclient.ShowBackendsStatement).WillReturnRows(showBackendsRows(
+					[]string{"1", "kube-starrocks-be-0.kube-starrocks-be-search", "false", "10"},
+					[]string{"2", "kube-starrocks-be-1.kube-starrocks-be-search", "true", "0"},
+					[]string{"3", "kube-starrocks-be-2.kube-starrocks-be-search", "true", "5"},
+				))
+				mock.ExpectExec(`ALTER SYSTEM DROP BACKEND "kube-starrocks-be-1.kube-starrocks-be-search:9050"`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantReplicas: 3,
+			wantScaleIn: &srapi.BeScaleInStatus{
+				Phase:          srapi.BeScaleInDecommissioning,
+				TargetReplicas: 1,
+				Reason:         "waiting for the tablets on the decommissioned backends to be migrated",
+				Backends: []srapi.DecommissioningBackend{
+					{Name: "kube-starrocks-be-1", TabletNum: 0, Decommissioned: true},
+					{Name: "kube-starrocks-be-2", TabletNum: 5},
+				},
+			},
+		},
+		{
+			name:           "all the removed backends are decommissioned",
+			actualReplicas: 3,
+			expectReplicas: 1,
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(sqlclient.ShowBackendsStatement).WillReturnRows(showBackendsRows(
+					[]string{"1", "kube-starrocks-be-0.kube-starrocks-be-search", "false", "10"},
+					[]string{"3", "kube-starrocks-be-2.kube-starrocks-be-search", "true", "0"},
+				))
+				mock.ExpectExec(`ALTER SYSTEM DROP BACKEND "kube-starrocks-be-2.kube-starrocks-be-search:9050"`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantReplicas: 1,
+			wantScaleIn: &srapi.BeScaleInStatus{
+				Phase:          srapi.BeScaleInDone,
+				TargetReplicas: 1,
+				Backends: []srapi.DecommissioningBackend{
+					{Name: "kube-starrocks-be-1", Decommissioned: true},
+					{Name: "kube-starrocks-be-2", TabletNum: 0, Decommissioned: true},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			src := newCluster()
+			if tt.scaleIn != nil {
+				src.Status.StarRocksBeStatus = &srapi.StarRocksBeStatus{ScaleIn: tt.scaleIn.DeepCopy()}
+			}
+			be := New(fake.NewFakeClient(srapi.Scheme, newBeStatefulSet(tt.actualReplicas)), fake.GetEventRecorderFor(nil))
+			expectSTS := newBeStatefulSet(tt.expectReplicas)
+
+			be.decommissionBackends(context.Background(), src, expectSTS, db)
+			assert.Equal(t, tt.wantReplicas, *expectSTS.Spec.Replicas)
+			assert.Equal(t, tt.wantScaleIn, src.Status.StarRocksBeStatus.ScaleIn)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBeController_decommissionBackendsWithFakeFE(t *testing.T) {
+	fakeFE := fakefe.Start(t, "kube-starrocks-fe-service.default")
+	for i := 0; i < 3; i++ {
+		fakeFE.AddBackend(sqlclient.Backend{
+			FQDN:          fmt.Sprintf("kube-starrocks-be-%d.kube-starrocks-be-search", i),
+			HeartbeatPort: "9050",
+			TabletNum:     10,
+		})
+	}
+
+	src := newCluster()
+	be := New(fake.NewFakeClient(srapi.Scheme, newBeStatefulSet(3)), fake.GetEventRecorderFor(nil))
+
+	// the fake FE migrates the tablets of the decommissioned backends after they are shown once, so the scale-in
+	// finishes in a few rounds of reconciliation.
+	for round := 0; round < 5; round++ {
+		expectSTS := newBeStatefulSet(1)
+		be.decommissionBackends(context.Background(), src, expectSTS, nil)
+		if src.Status.StarRocksBeStatus.ScaleIn.Phase == srapi.BeScaleInDone {
+			assert.Equal(t, int32(1), *expectSTS.Spec.Replicas)
+			break
+		}
+		assert.Equal(t, int32(3), *expectSTS.Spec.Replicas)
+	}
+
+	require.Equal(t, srapi.BeScaleInDone, src.Status.StarRocksBeStatus.ScaleIn.Phase)
+	backends := fakeFE.Backends()
+	require.Len(t, backends, 1)
+	assert.Equal(t, "kube-starrocks-be-0.kube-starrocks-be-search", backends[0].FQDN)
+	assert.Equal(t, int64(30), backends[0].TabletNum)
+}
@@ -23,6 +23,7 @@ import (
 	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
 	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
 )
@@ -36,7 +37,8 @@ const (
 )
 
 // buildPodTemplate construct the podTemplate for deploy cn.
-func (be *BeController) buildPodTemplate(src *srapi.StarRocksCluster, config map[string]interface{}) (*corev1.PodTemplateSpec, error) {
+func (be *BeController) buildPodTemplate(src *srapi.StarRocksCluster, config map[string]interface{},
+	referenceHashes map[string]string) (*corev1.PodTemplateSpec, error) {
 	metaName := src.Name + "-" + srapi.DEFAULT_BE
 	beSpec := src.Spec.StarRocksBeSpec
 
@@ -53,8 +55,9 @@ func (be *BeController) buildPodTemplate(src *srapi.StarRocksCluster, config map
 		vols, volumeMounts = pod.MountEmptyDirVolume(vols, volumeMounts, _logName, pod.GetLogDir(beSpec), "")
 	}
 
-	// mount configmap, secrets to pod if needed
-	vols, volumeMounts = pod.MountConfigMapInfo(vols, volumeMounts, beSpec.ConfigMapInfo, _beConfigPath)
+	// mount configmap, secrets to pod if needed, the configMap rendered from spec.config is mounted as configMapInfo.
+	configMapInfo := srconfig.ConfigMapInfo(src.Name, beSpec)
+	vols, volumeMounts = pod.MountConfigMapInfo(vols, volumeMounts, configMapInfo, _beConfigPath)
 	vols, volumeMounts = pod.MountConfigMaps(beSpec, vols, volumeMounts, beSpec.ConfigMaps)
 	vols, volumeMounts = pod.MountSecrets(vols, volumeMounts, beSpec.Secrets)
 	if err := k8sutils.CheckVolumes(vols, volumeMounts); err != nil {
@@ -83,7 +86,7 @@ func (be *BeController) buildPodTemplate(src *srapi.StarRocksCluster, config map
 	if pod.GetStarRocksRootPath(beSpec.BeEnvVars) != pod.GetStarRocksDefaultRootPath() {
 		beContainer.WorkingDir = pod.GetStarRocksRootPath(beSpec.BeEnvVars)
 	}
-	if beSpec.ConfigMapInfo.ConfigMapName != "" && beSpec.ConfigMapInfo.ResolveKey != "" {
+	if configMapInfo.ConfigMapName != "" && configMapInfo.ResolveKey != "" {
 		beContainer.Env = append(beContainer.Env, corev1.EnvVar{
 			Name:  _envBeConfigPath,
 			Value: _beConfigPath,
@@ -92,7 +95,10 @@ func (be *BeController) buildPodTemplate(src *srapi.StarRocksCluster, config map
 
 	podSpec := pod.Spec(beSpec, beContainer, vols)
 
-	annotations := pod.Annotations(beSpec)
+	annotations := pod.Annotations(beSpec, referenceHashes)
+	if srconfig.IsManaged(beSpec) {
+		annotations[srapi.ConfigHashAnnotation] = srconfig.Hash(beSpec)
+	}
 	podSpec.SecurityContext = pod.PodSecurityContext(beSpec)
 	return &corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
@@ -21,11 +21,16 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -39,10 +44,12 @@ import (
 	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/statefulset"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
 	subc "github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
 )
@@ -51,11 +58,14 @@ type CnController struct {
 	k8sClient          client.Client
 	Recorder           record.EventRecorder
 	addEnvForWarehouse bool
+	// httpClient is used to call the HTTP API of FE, e.g. /api/v2/feature.
+	httpClient *http.Client
 }
 
 func New(k8sClient client.Client, recorderFor subc.GetEventRecorderForFunc) *CnController {
 	controller := &CnController{
-		k8sClient: k8sClient,
+		k8sClient:  k8sClient,
+		httpClient: sqlclient.NewHTTPClient(),
 	}
 	controller.Recorder = recorderFor(controller.GetControllerName())
 	return controller
@@ -145,7 +155,20 @@ func (cc *CnController) SyncCluster(ctx context.Context, src *srapi.StarRocksClu
 		return nil
 	}
 
-	err = cc.SyncCnSpec(ctx, object.NewFromCluster(src), src.Spec.StarRocksCnSpec, src.Status.StarRocksCnStatus)
+	cnSpec := src.Spec.StarRocksCnSpec
+	if src.IsComponentSuspended(srapi.DEFAULT_CN) {
+		// delete the HPA while CN is suspended, otherwise it will scale CN up again.
+		logger.Info("the cluster is suspended, scale cn to zero")
+		cnSpec = cnSpec.DeepCopy()
+		cnSpec.Replicas = rutils.GetInt32Pointer(0)
+		cnSpec.AutoScalingPolicy = nil
+	} else if replicas, ok := src.GetSuspendedReplicas(load.Name(src.Name, cnSpec)); ok && cnSpec.Replicas == nil {
+		// the cluster is being resumed, restore the replicas which were scaled by HPA before the cluster is suspended.
+		cnSpec = cnSpec.DeepCopy()
+		cnSpec.Replicas = &replicas
+	}
+
+	err = cc.SyncCnSpec(ctx, object.NewFromCluster(src), cnSpec, src.Status.StarRocksCnStatus)
 	defer func() {
 		// we do not record an event if the error is nil, because this will cause too many events to be recorded.
 		if err != nil {
@@ -164,7 +187,7 @@ func (cc *CnController) SyncCnSpec(ctx context.Context, object object.StarRocksO
 		return err
 	}
 
-	if err := cc.validating(cnSpec); err != nil {
+	if err := cc.Validating(cnSpec); err != nil {
 		return err
 	}
 
@@ -182,13 +205,52 @@ func (cc *CnController) SyncCnSpec(ctx context.Context, object object.StarRocksO
 	cnConfig[rutils.HTTP_PORT] = strconv.FormatInt(int64(rutils.GetPort(feconfig, rutils.HTTP_PORT)), 10)
 
 	// build and deploy statefulset
-	podTemplateSpec, err := cc.buildPodTemplate(ctx, object, cnSpec, cnConfig)
+	referenceHashes, err := subc.ReferenceHashes(ctx, cc.k8sClient, object.Namespace, &cnSpec.StarRocksComponentSpec)
+	if err != nil {
+		logger.Error(err, "get the hashes of referenced configMaps and secrets failed")
+		return err
+	}
+	podTemplateSpec, err := cc.buildPodTemplate(ctx, object, cnSpec, cnConfig, referenceHashes)
 	if err != nil {
 		logger.Error(err, "build pod template failed")
 		return err
 	}
+	var componentStatus *srapi.StarRocksComponentStatus
+	if cnStatus != nil {
+		componentStatus = &cnStatus.StarRocksComponentStatus
+	}
+	configHash, err := subc.SyncDynamicConfig(ctx, cc.k8sClient, object, object.SubResourcePrefixName, cnSpec,
+		componentStatus, cc.dynamicConfigApplier(object.Namespace, object.GetCNStatefulSetName()))
+	if err != nil {
+		logger.Error(err, "sync dynamic config failed")
+		return err
+	}
+	if configHash != "" {
+		podTemplateSpec.Annotations[srapi.ConfigHashAnnotation] = configHash
+	}
+	if err = subc.SyncConfigMap(ctx, cc.k8sClient, object, object.SubResourcePrefixName, cnSpec, configHash); err != nil {
+		logger.Error(err, "sync cn config configMap failed")
+		return err
+	}
 
 	expectSTS := statefulset.MakeStatefulset(object, cnSpec, podTemplateSpec)
+	if cnSpec.AutoScalingPolicy != nil && expectSTS.Spec.Replicas == nil {
+		// The replicas are managed by HPA, keep the actual replicas. Otherwise, the replicas set by the operator
+		// before, e.g. when the cluster is resumed, will be removed by the three-way merge patch and reset to 1.
+		var actualSTS appsv1.StatefulSet
+		err = cc.k8sClient.Get(ctx, types.NamespacedName{Namespace: expectSTS.Namespace, Name: expectSTS.Name}, &actualSTS)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			expectSTS.Spec.Replicas = actualSTS.Spec.Replicas
+		}
+	}
+	if err = subc.ExpandPersistentVolumeClaims(ctx, cc.k8sClient, cc.Recorder, object.Object, &expectSTS,
+		componentStatus); err != nil {
+		logger.Error(err, "expand persistent volume claims failed")
+		return err
+	}
 	if err = k8sutils.ApplyStatefulSet(ctx, cc.k8sClient, &expectSTS, true, rutils.StatefulSetDeepEqual); err != nil {
 		return err
 	}
@@ -230,6 +292,12 @@ func (cc *CnController) SyncCnSpec(ctx context.Context, object object.StarRocksO
 		return nil
 	}
 
+	// keep the compute nodes in FE when CN is scaled to zero, e.g. the cluster is suspended, so that they can rejoin
+	// the cluster after they are scaled back.
+	if expectSTS.Spec.Replicas != nil && *expectSTS.Spec.Replicas == 0 {
+		return nil
+	}
+
 	// get actual statefulset object
 	var actualSTS appsv1.StatefulSet
 	namespacedName := types.NamespacedName{
@@ -344,13 +412,14 @@ func (cc *CnController) ClearWarehouse(ctx context.Context, namespace string, wa
 	ctx = logr.NewContext(ctx, logger)
 
 	cnSTSName := load.Name(object.GetPrefixNameForWarehouse(warehouseName), (*srapi.StarRocksCnSpec)(nil))
-	executor, err := NewSQLExecutor(ctx, cc.k8sClient, namespace, cnSTSName)
-	if err != nil {
-		logger.Error(err, "new SQL executor failed")
+	sqlClient, err := NewSQLClient(ctx, cc.k8sClient, namespace, cnSTSName)
+	if errors.Is(err, ErrStarRocksClusterIsMissing) {
+		// the warehouse is dropped with the StarRocksCluster.
+		logger.Info("StarRocksCluster is not found, skip dropping warehouse", "warehouse", warehouseName)
+	} else if err != nil {
+		logger.Error(err, "new SQL client failed")
 		return err
-	}
-	err = executor.ExecuteDropWarehouse(ctx, nil, warehouseName)
-	if err != nil {
+	} else if err = sqlClient.DropWarehouse(ctx, nil, object.GetWarehouseNameInFE(warehouseName)); err != nil {
 		logger.Error(err, "drop warehouse failed", "warehouse", warehouseName)
 		// we do not return error here, because we want to delete the statefulset anyway.
 	}
@@ -391,10 +460,17 @@ func (cc *CnController) deployAutoScaler(ctx context.Context,
 		Version:         cnSpec.AutoScalingPolicy.Version, // cnSpec.AutoScalingPolicy can not be nil
 		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(object, object.GroupVersionKind())},
 		ScalerPolicy:    &policy,
+		MetricLabels: map[string]string{
+			srapi.StarRocksMetricClusterLabel:   object.ClusterName,
+			srapi.StarRocksMetricWarehouseLabel: warehouseNameInFE(object),
+		},
 	}
 
 	expectHPA := rutils.BuildHPA(hpaParams, "")
 	expectHPA.SetAnnotations(make(map[string]string))
+	// the hash is computed before it is put in the annotations, so it is the same in every reconciliation.
+	expectHash := hash.HashObject(expectHPA)
+	expectHPA.GetAnnotations()[srapi.ComponentResourceHash] = expectHash
 
 	actualHPA := hpaParams.Version.CreateEmptyHPA(k8sutils.KUBE_MAJOR_VERSION, k8sutils.KUBE_MINOR_VERSION)
 	if err := cc.k8sClient.Get(ctx,
@@ -410,22 +486,41 @@ func (cc *CnController) deployAutoScaler(ctx context.Context,
 		return err
 	}
 
-	var expectHash, actualHash string
-	expectHash = hash.HashObject(expectHPA)
-	if v, ok := actualHPA.GetAnnotations()[srapi.ComponentResourceHash]; ok {
-		actualHash = v
-	} else {
-		actualHash = hash.HashObject(actualHPA)
-	}
-
-	if expectHash == actualHash {
+	// the hash annotation is kept when others edit the HPA, so the spec is compared too to revert the drift.
+	actualHash := actualHPA.GetAnnotations()[srapi.ComponentResourceHash]
+	if expectHash == actualHash && !isAutoScalerDrifted(expectHPA, actualHPA) {
 		logger.Info("expectHash == actualHash, no need to update HPA resource")
 		return nil
 	}
-	expectHPA.GetAnnotations()[srapi.ComponentResourceHash] = expectHash
 	return cc.k8sClient.Update(ctx, expectHPA)
 }
 
+// isAutoScalerDrifted returns true if the spec of the actual HPA does not contain the expected spec. The fields not set
+// by operator are ignored, because kubernetes fills default values for them, e.g. spec.metrics.
+func isAutoScalerDrifted(expectHPA, actualHPA client.Object) bool {
+	var expectSpec, actualSpec interface{}
+	switch hpa := expectHPA.(type) {
+	case *autoscalingv1.HorizontalPodAutoscaler:
+		expectSpec = hpa.Spec
+	case *autoscalingv2.HorizontalPodAutoscaler:
+		expectSpec = hpa.Spec
+	case *autoscalingv2beta2.HorizontalPodAutoscaler:
+		expectSpec = hpa.Spec
+	}
+	switch hpa := actualHPA.(type) {
+	case *autoscalingv1.HorizontalPodAutoscaler:
+		actualSpec = hpa.Spec
+	case *autoscalingv2.HorizontalPodAutoscaler:
+		actualSpec = hpa.Spec
+	case *autoscalingv2beta2.HorizontalPodAutoscaler:
+		actualSpec = hpa.Spec
+	}
+	if expectSpec == nil || actualSpec == nil {
+		return false
+	}
+	return !equality.Semantic.DeepDerivative(expectSpec, actualSpec)
+}
+
 // deleteAutoScaler delete the autoscaler.
 func (cc *CnController) deleteAutoScaler(ctx context.Context, object object.StarRocksObject,
 	autoScalerVersion srapi.AutoScalerVersion) error {
@@ -444,7 +539,8 @@ func (cc *CnController) deleteAutoScaler(ctx context.Context, object object.Star
 func (cc *CnController) ClearCluster(ctx context.Context, src *srapi.StarRocksCluster) error {
 	logger := logr.FromContextOrDiscard(ctx)
 
-	if src.Spec.StarRocksCnSpec != nil {
+	// clear the resources when the spec of cn is removed, or the StarRocksCluster is being deleted.
+	if src.Spec.StarRocksCnSpec != nil && src.DeletionTimestamp.IsZero() {
 		return nil
 	}
 
@@ -468,6 +564,11 @@ func (cc *CnController) ClearCluster(ctx context.Context, src *srapi.StarRocksCl
 		logger.Error(err, "delete external service failed")
 		return err
 	}
+	err = k8sutils.DeleteConfigMap(ctx, cc.k8sClient, src.Namespace, srconfig.ConfigMapName(src.Name, cnSpec))
+	if err != nil {
+		logger.Error(err, "delete config configMap failed")
+		return err
+	}
 
 	var version srapi.AutoScalerVersion
 	if src.Status.StarRocksCnStatus != nil {
@@ -483,6 +584,9 @@ func (cc *CnController) ClearCluster(ctx context.Context, src *srapi.StarRocksCl
 
 func (cc *CnController) GetCnConfig(ctx context.Context,
 	cnSpec *srapi.StarRocksCnSpec, namespace string) (map[string]interface{}, error) {
+	if srconfig.IsManaged(cnSpec) {
+		return srconfig.Resolve(cnSpec)
+	}
 	return k8sutils.GetConfig(ctx, cc.k8sClient, cnSpec.ConfigMapInfo,
 		cnSpec.ConfigMaps, pod.GetConfigDir(cnSpec), "cn.conf",
 		namespace)
@@ -501,7 +605,8 @@ func (cc *CnController) mutating(_ *srapi.StarRocksCnSpec) error {
 	return nil
 }
 
-func (cc *CnController) validating(cnSpec *srapi.StarRocksCnSpec) error {
+// Validating validates the spec of CN, it is also used by the admission webhook.
+func (cc *CnController) Validating(cnSpec *srapi.StarRocksCnSpec) error {
 	// validating the auto scaling policy
 	policy := cnSpec.AutoScalingPolicy
 	if policy != nil {
@@ -517,6 +622,10 @@ func (cc *CnController) validating(cnSpec *srapi.StarRocksCnSpec) error {
 		if maxReplicas < minReplicas {
 			return fmt.Errorf("the MaxReplicas must not be smaller than MinReplicas")
 		}
+
+		if len(policy.StarRocksMetrics) != 0 && policy.Version == srapi.AutoScalerV1 {
+			return fmt.Errorf("the StarRocksMetrics are not supported by autoscaling v1")
+		}
 	}
 
 	for i := range cnSpec.StorageVolumes {
@@ -529,7 +638,7 @@ func (cc *CnController) validating(cnSpec *srapi.StarRocksCnSpec) error {
 		return err
 	}
 
-	return nil
+	return srconfig.Validate(cnSpec)
 }
 
 // getStarRocksCluster get the StarRocksCluster object by namespace and name.
@@ -597,24 +706,20 @@ func (cc *CnController) SyncComputeNodesInFE(ctx context.Context, object object.
 	}
 
 	// now, all the new pods have been created, we can check the number of CN from FE
-	executor, err := NewSQLExecutor(ctx, cc.k8sClient, object.Namespace, object.GetCNStatefulSetName())
+	sqlClient, err := NewSQLClient(ctx, cc.k8sClient, object.Namespace, object.GetCNStatefulSetName())
 	if err != nil {
-		logger.Error(err, "new SQL executor failed")
+		logger.Error(err, "new SQL client failed")
 		return err
 	}
-	result, err := executor.QueryShowComputeNodes(ctx, db)
+	result, err := sqlClient.ShowComputeNodes(ctx, db)
 	if err != nil {
-		logger.Error(err, "query SHOW COMPUTE NODES failed", "sql", "SHOW COMPUTE NODES")
+		logger.Error(err, "query SHOW COMPUTE NODES failed", "sql", sqlclient.ShowComputeNodesStatement)
 		return err
 	}
-	warehouseNameInFE := "default_warehouse"
-	if object.IsWarehouseObject {
-		warehouseNameInFE = object.GetWarehouseNameInFE()
-	}
-	computeNodes := result.ComputeNodesByWarehouse[warehouseNameInFE]
+	computeNodes := result.ComputeNodesByWarehouse[warehouseNameInFE(object)]
 	if len(computeNodes) > int(expectReplicas) {
 		for i := len(computeNodes) - 1; i >= int(expectReplicas); i-- {
-			err = executor.ExecuteDropComputeNode(ctx, db, computeNodes[i])
+			err = sqlClient.DropComputeNode(ctx, db, computeNodes[i])
 			if err != nil {
 				logger.Error(err, "drop compute node failed", "computeNode", computeNodes[i])
 				return err
@@ -626,6 +731,14 @@ func (cc *CnController) SyncComputeNodesInFE(ctx context.Context, object object.
 	return nil
 }
 
+// warehouseNameInFE returns the name of warehouse in FE which the CN nodes of object belong to.
+func warehouseNameInFE(object object.StarRocksObject) string {
+	if object.IsWarehouseObject {
+		return object.GetWarehouseNameInFE()
+	}
+	return sqlclient.DefaultWarehouse
+}
+
 func generateInternalService(object object.StarRocksObject, cnSpec *srapi.StarRocksCnSpec,
 	externalService *corev1.Service, cnConfig map[string]interface{}, labels map[string]string) *corev1.Service {
 	searchServiceName := service.SearchServiceName(object.SubResourcePrefixName, cnSpec)
@@ -28,21 +28,23 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
 	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/fakefe"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
 )
 
 func TestMain(m *testing.M) {
@@ -166,7 +168,69 @@ func Test_SyncCluster(t *testing.T) {
 	require.Equal(t, asvc.Spec.Selector, st.Spec.Selector.MatchLabels)
 }
 
-func Test_SyncWarehouse(t *testing.T) {
+func Test_SyncClusterSuspended(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{},
+			StarRocksCnSpec: &srapi.StarRocksCnSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{Image: "test.image"},
+				},
+				AutoScalingPolicy: &srapi.AutoScalingPolicy{MaxReplicas: 10, Version: srapi.AutoScalerV2},
+			},
+		},
+	}
+	ep := corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-fe-service", Namespace: "default"},
+		Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "172.0.0.1"}}}},
+	}
+	cc := New(fake.NewFakeClient(srapi.Scheme, src, &ep), fake.GetEventRecorderFor(nil))
+	stsName := load.Name(src.Name, src.Spec.StarRocksCnSpec)
+	hpaName := cc.generateAutoScalerName(src.Name, src.Spec.StarRocksCnSpec)
+	getSTS := func() *appsv1.StatefulSet {
+		var sts appsv1.StatefulSet
+		require.NoError(t, cc.k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: stsName}, &sts))
+		return &sts
+	}
+	hpaExists := func() bool {
+		hpa := srapi.AutoScalerV2.CreateEmptyHPA(k8sutils.KUBE_MAJOR_VERSION, k8sutils.KUBE_MINOR_VERSION)
+		err := cc.k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: hpaName}, hpa)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	// the cluster is suspended, CN is scaled to zero and the HPA is deleted.
+	src.Status.SuspendStatus = &srapi.SuspendStatus{
+		Phase:               srapi.SuspendPhaseSuspending,
+		SuspendedComponents: []string{srapi.DEFAULT_CN},
+		Replicas:            map[string]int32{stsName: 4},
+	}
+	require.NoError(t, cc.SyncCluster(context.Background(), src))
+	require.Equal(t, int32(0), *getSTS().Spec.Replicas)
+	require.False(t, hpaExists())
+
+	// CN is resumed, the replicas scaled by HPA are restored, and the HPA is created again.
+	src.Status.SuspendStatus.SuspendedComponents = nil
+	src.Status.SuspendStatus.Phase = srapi.SuspendPhaseResuming
+	require.NoError(t, cc.SyncCluster(context.Background(), src))
+	require.Equal(t, int32(4), *getSTS().Spec.Replicas)
+	require.True(t, hpaExists())
+
+	// the cluster has been resumed, the replicas are still managed by HPA.
+	src.Status.SuspendStatus = nil
+	require.NoError(t, cc.SyncCluster(context.Background(), src))
+	require.Equal(t, int32(4), *getSTS().Spec.Replicas)
+}
+
+// newWarehouseObjects returns the objects to sync the warehouse wh1 of the shared-data cluster test.
+func newWarehouseObjects() (*srapi.StarRocksCluster, *corev1.ConfigMap, *srapi.StarRocksWarehouse, *corev1.Endpoints) {
 	src := &srapi.StarRocksCluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test",
@@ -238,7 +302,11 @@ func Test_SyncWarehouse(t *testing.T) {
 			}},
 		}},
 	}
+	return src, feConfigMap, warehouse, ep
+}
 
+func Test_SyncWarehouse(t *testing.T) {
+	src, feConfigMap, warehouse, ep := newWarehouseObjects()
 	cc := New(fake.NewFakeClient(srapi.Scheme, src, feConfigMap, warehouse, ep), fake.GetEventRecorderFor(nil))
 	cc.addEnvForWarehouse = true
 
@@ -278,6 +346,102 @@ func Test_SyncWarehouse(t *testing.T) {
 	require.Equal(t, "wh1-warehouse-cn", sts.Name)
 }
 
+func Test_SyncAndClearWarehouseWithFakeFE(t *testing.T) {
+	src, feConfigMap, warehouse, ep := newWarehouseObjects()
+	feService := service.ExternalServiceName(src.Name, src.Spec.StarRocksFeSpec)
+	fakeFE := fakefe.Start(t, feService+"."+src.Namespace)
+
+	cc := New(fake.NewFakeClient(srapi.Scheme, src, feConfigMap, warehouse, ep), fake.GetEventRecorderFor(nil))
+	cc.httpClient = fakefe.HTTPClient()
+	ctx := context.Background()
+
+	// FE does not support multi-warehouse
+	require.ErrorIs(t, cc.SyncWarehouse(ctx, warehouse), ErrFailedToGetFeFeatureList)
+
+	fakeFE.SetFeatures(fakefe.FeatureMultiWarehouse)
+	require.NoError(t, cc.SyncWarehouse(ctx, warehouse))
+	var sts appsv1.StatefulSet
+	require.NoError(t, cc.k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "wh1-warehouse-cn"}, &sts))
+	require.Contains(t, sts.Spec.Template.Spec.Containers[0].Env,
+		corev1.EnvVar{Name: "KUBE_STARROCKS_MULTI_WAREHOUSE", Value: "wh1"})
+
+	// the CN pods create the warehouse and add themselves to it when they start.
+	fakeFE.AddWarehouse("wh1")
+	for i := 0; i < 3; i++ {
+		fakeFE.AddComputeNode(sqlclient.ComputeNode{
+			FQDN:          fmt.Sprintf("wh1-warehouse-cn-%d.wh1-warehouse-cn-search.default.svc.cluster.local", i),
+			HeartbeatPort: "9050",
+			WarehouseName: "wh1",
+		})
+	}
+
+	require.NoError(t, cc.ClearWarehouse(ctx, "default", "wh1"))
+	assert.Equal(t, []string{fakefe.DefaultWarehouse}, fakeFE.Warehouses())
+	assert.Empty(t, fakeFE.ComputeNodes())
+}
+
+func TestCnController_SyncComputeNodesInFEWithFakeFE(t *testing.T) {
+	fakeFE := fakefe.Start(t, "cluster-fe-service.default")
+	fakeFE.AddWarehouse("wh1")
+	for i := 0; i < 3; i++ {
+		fakeFE.AddComputeNode(sqlclient.ComputeNode{
+			FQDN:          fmt.Sprintf("wh1-warehouse-cn-%d.wh1-warehouse-cn-search.default.svc.cluster.local", i),
+			HeartbeatPort: "9050",
+			WarehouseName: "wh1",
+		})
+	}
+
+	newSTS := func() *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			TypeMeta: metav1.TypeMeta{Kind: rutils.StatefulSetKind, APIVersion: appsv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "wh1-warehouse-cn",
+				Namespace:       "default",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StarRocksCluster", Name: "cluster"}},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: rutils.GetInt32Pointer(1),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Env: []corev1.EnvVar{
+								{Name: "FE_SERVICE_NAME", Value: "fe"},
+								{Name: "FE_QUERY_PORT", Value: "9030"},
+							},
+						}},
+					},
+				},
+			},
+			Status: appsv1.StatefulSetStatus{UpdateRevision: "v2"},
+		}
+	}
+	cluster := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec:       srapi.StarRocksClusterSpec{StarRocksFeSpec: &srapi.StarRocksFeSpec{}},
+	}
+	cc := &CnController{k8sClient: fake.NewFakeClient(srapi.Scheme, newSTS(), cluster)}
+	object := object.StarRocksObject{
+		ObjectMeta:            &metav1.ObjectMeta{Name: "wh1", Namespace: "default"},
+		ClusterName:           "cluster",
+		SubResourcePrefixName: "wh1-warehouse",
+		IsWarehouseObject:     true,
+	}
+	pods := &corev1.PodList{Items: []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "wh1-warehouse-cn-0", Labels: map[string]string{"controller-revision-hash": "v2"}},
+	}}}
+
+	// the compute nodes whose index is not less than the replicas are dropped from FE.
+	require.NoError(t, cc.SyncComputeNodesInFE(context.Background(), object, newSTS(), newSTS(), pods, nil))
+	computeNodes := fakeFE.ComputeNodes()
+	require.Len(t, computeNodes, 1)
+	assert.Equal(t, "wh1-warehouse-cn-0.wh1-warehouse-cn-search.default.svc.cluster.local", computeNodes[0].FQDN)
+	assert.Equal(t, []string{
+		sqlclient.ShowComputeNodesStatement,
+		`ALTER SYSTEM DROP COMPUTE NODE "wh1-warehouse-cn-2.wh1-warehouse-cn-search.default.svc.cluster.local:9050" FROM WAREHOUSE wh1`,
+		`ALTER SYSTEM DROP COMPUTE NODE "wh1-warehouse-cn-1.wh1-warehouse-cn-search.default.svc.cluster.local:9050" FROM WAREHOUSE wh1`,
+	}, fakeFE.Statements())
+}
+
 func TestCnController_UpdateStatus(t *testing.T) {
 	type fields struct {
 		k8sClient client.Client
@@ -388,6 +552,60 @@ func TestCnController_generateAutoScalerName(t *testing.T) {
 	}
 }
 
+func TestCnController_deployAutoScalerRevertsDrift(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+		},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksCnSpec: &srapi.StarRocksCnSpec{
+				AutoScalingPolicy: &srapi.AutoScalingPolicy{
+					Version:     srapi.AutoScalerV2,
+					MinReplicas: rutils.GetInt32Pointer(1),
+					MaxReplicas: 5,
+				},
+			},
+		},
+	}
+	cnSpec := src.Spec.StarRocksCnSpec
+	cc := New(fake.NewFakeClient(srapi.Scheme, src), fake.GetEventRecorderFor(nil))
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "default", Name: cc.generateAutoScalerName(src.Name, cnSpec)}
+
+	require.NoError(t, cc.deployAutoScaler(ctx, object.NewFromCluster(src), cnSpec, *cnSpec.AutoScalingPolicy))
+	var hpa autoscalingv2.HorizontalPodAutoscaler
+	require.NoError(t, cc.k8sClient.Get(ctx, key, &hpa))
+	require.Equal(t, int32(5), hpa.Spec.MaxReplicas)
+	require.NotEmpty(t, hpa.Annotations[srapi.ComponentResourceHash])
+
+	// the HPA is not updated if nothing is changed
+	resourceVersion := hpa.ResourceVersion
+	require.NoError(t, cc.deployAutoScaler(ctx, object.NewFromCluster(src), cnSpec, *cnSpec.AutoScalingPolicy))
+	require.NoError(t, cc.k8sClient.Get(ctx, key, &hpa))
+	require.Equal(t, resourceVersion, hpa.ResourceVersion)
+
+	// edit the HPA and keep the hash annotation, like kubectl edit does
+	hpa.Spec.MaxReplicas = 10
+	require.NoError(t, cc.k8sClient.Update(ctx, &hpa))
+	require.NoError(t, cc.deployAutoScaler(ctx, object.NewFromCluster(src), cnSpec, *cnSpec.AutoScalingPolicy))
+	require.NoError(t, cc.k8sClient.Get(ctx, key, &hpa))
+	require.Equal(t, int32(5), hpa.Spec.MaxReplicas)
+
+	// the fields filled by kubernetes are not a drift
+	hpa.Spec.Metrics = []autoscalingv2.MetricSpec{{Type: autoscalingv2.ResourceMetricSourceType}}
+	require.NoError(t, cc.k8sClient.Update(ctx, &hpa))
+	resourceVersion = hpa.ResourceVersion
+	require.NoError(t, cc.deployAutoScaler(ctx, object.NewFromCluster(src), cnSpec, *cnSpec.AutoScalingPolicy))
+	require.NoError(t, cc.k8sClient.Get(ctx, key, &hpa))
+	require.Equal(t, resourceVersion, hpa.ResourceVersion)
+
+	// the deleted HPA is created again
+	require.NoError(t, cc.k8sClient.Delete(ctx, &hpa))
+	require.NoError(t, cc.deployAutoScaler(ctx, object.NewFromCluster(src), cnSpec, *cnSpec.AutoScalingPolicy))
+	require.NoError(t, cc.k8sClient.Get(ctx, key, &hpa))
+}
+
 func TestCnController_GetCnConfig(t *testing.T) {
 	type args struct {
 		ctx       context.Context
@@ -771,20 +989,16 @@ func TestCnController_SyncComputeNodesInFE(t *testing.T) {
 		{
 			name: "xxx",
 			fields: fields{
-				k8sClient: fake.NewFakeClient(
-					func() *runtime.Scheme {
-						schema := runtime.NewScheme()
-						_ = clientgoscheme.AddToScheme(schema)
-						return schema
-					}(),
+				k8sClient: fake.NewFakeClient(srapi.Scheme,
 					&appsv1.StatefulSet{
 						TypeMeta: metav1.TypeMeta{
 							Kind:       "StatefulSet",
 							APIVersion: appsv1.SchemeGroupVersion.String(),
 						},
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "wh1-warehouse-cn",
-							Namespace: "default",
+							Name:            "wh1-warehouse-cn",
+							Namespace:       "default",
+							OwnerReferences: []metav1.OwnerReference{{Kind: "StarRocksCluster", Name: "cluster"}},
 						},
 						Spec: appsv1.StatefulSetSpec{
 							Template: corev1.PodTemplateSpec{
@@ -811,6 +1025,10 @@ func TestCnController_SyncComputeNodesInFE(t *testing.T) {
 							},
 						},
 					},
+					&srapi.StarRocksCluster{
+						ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+						Spec:       srapi.StarRocksClusterSpec{StarRocksFeSpec: &srapi.StarRocksFeSpec{}},
+					},
 				),
 			},
 			args: args{
@@ -871,7 +1089,7 @@ func TestCnController_SyncComputeNodesInFE(t *testing.T) {
 				db: func() *sql.DB {
 					db, mock, err := sqlmock.New()
 					require.NoError(t, err)
-					mock.ExpectQuery(ShowComputeNodesStatement).WillReturnRows(
+					mock.ExpectQuery(sqlclient.ShowComputeNodesStatement).WillReturnRows(
 						sqlmock.NewRows([]string{"ComputeNodeId", "IP", "WarehouseName"}).AddRow([]byte("1"), []byte("kube-starrocks-fe-0.kube-starrocks-fe-search.default.svc.cluster.local_9010_1751367053833"), []byte("wh1")),
 					)
 					return db
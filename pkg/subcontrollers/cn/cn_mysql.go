@@ -2,43 +2,27 @@ package cn
 
 import (
 	"context"
-	"database/sql"
-	"fmt"
-	"sort"
-	"strings"
 
 	"github.com/go-logr/logr"
-	_ "github.com/go-sql-driver/mysql" // import mysql driver
 	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+	subc "github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
 )
 
-const (
-	ShowComputeNodesStatement = "SHOW COMPUTE NODES"
-)
-
-// SQLExecutor is used to execute sql statements.
-// Component CN needs to connect to mysql and execute sql statements. E.g.: When StarRocksWarehouse is deleted, the
-// related 'DROP WAREHOUSE <name>' statement needs to be executed.
-type SQLExecutor struct {
-	RootPassword       string
-	FeServiceName      string
-	FeServiceNamespace string
-	FeServicePort      string
-}
-
-// NewSQLExecutor creates a SQLExecutor instance. It will get the root password, fe service name, and fe service port
-// from the environment variables of the component CN.
-func NewSQLExecutor(ctx context.Context, k8sClient client.Client, namespace, cnSTSName string) (*SQLExecutor, error) {
-	rootPassword := ""
-	feServiceName := ""
-	feServicePort := ""
-	logger := logr.FromContextOrDiscard(ctx)
-
+// NewSQLClient creates a sqlclient.Client for the component CN. Component CN needs to connect to FE and execute
+// SQL statements, e.g. When StarRocksWarehouse is deleted, the related 'DROP WAREHOUSE <name>' statement needs to be
+// executed. It resolves the StarRocksCluster which the CN statefulset belongs to, and creates the client by
+// fe.NewSQLClient. It returns ErrStarRocksClusterIsMissing if the StarRocksCluster is not found.
+func NewSQLClient(ctx context.Context, k8sClient client.Client, namespace, cnSTSName string) (*sqlclient.Client, error) {
 	var sts appsv1.StatefulSet
 	if err := k8sClient.Get(ctx,
 		types.NamespacedName{
@@ -49,172 +33,87 @@ func NewSQLExecutor(ctx context.Context, k8sClient client.Client, namespace, cnS
 		return nil, err
 	}
 
-	var err error
-	for _, envVar := range sts.Spec.Template.Spec.Containers[0].Env {
-		switch envVar.Name {
-		case "MYSQL_PWD":
-			rootPassword, err = k8sutils.GetEnvVarValue(ctx, k8sClient, namespace, envVar)
-			if err != nil {
-				logger.Error(err, "failed to get MYSQL_PWD from env vars, use the default password: empty string")
-			}
-		case "FE_SERVICE_NAME":
-			feServiceName, err = k8sutils.GetEnvVarValue(ctx, k8sClient, namespace, envVar)
-			if err != nil {
-				logger.Error(err, "failed to get FE_SERVICE_NAME from env vars")
+	src, err := getClusterOfStatefulSet(ctx, k8sClient, &sts)
+	if err != nil {
+		return nil, err
+	} else if src == nil {
+		return nil, ErrStarRocksClusterIsMissing
+	}
+	return fe.NewSQLClient(ctx, k8sClient, src)
+}
+
+// getClusterOfStatefulSet returns the StarRocksCluster which the CN statefulset belongs to. The statefulset is owned by
+// the StarRocksCluster, or by the StarRocksWarehouse which refers to the StarRocksCluster. After the StarRocksWarehouse
+// is deleted, the StarRocksCluster is found by the FE service in the environment variables of CN.
+func getClusterOfStatefulSet(ctx context.Context, k8sClient client.Client,
+	sts *appsv1.StatefulSet) (*srapi.StarRocksCluster, error) {
+	clusterName := ""
+	for _, ref := range sts.OwnerReferences {
+		switch ref.Kind {
+		case object.StarRocksClusterKind:
+			clusterName = ref.Name
+		case object.StarRocksWarehouseKind:
+			var warehouse srapi.StarRocksWarehouse
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: sts.Namespace, Name: ref.Name}, &warehouse)
+			if err != nil && !apierrors.IsNotFound(err) {
 				return nil, err
 			}
-		case "FE_QUERY_PORT":
-			feServicePort, err = k8sutils.GetEnvVarValue(ctx, k8sClient, namespace, envVar)
-			if err != nil {
-				logger.Error(err, "failed to get FE_QUERY_PORT from env vars")
-				return nil, err
+			if err == nil {
+				clusterName = warehouse.Spec.StarRocksCluster
 			}
 		}
 	}
 
-	return &SQLExecutor{
-		RootPassword:       rootPassword,
-		FeServiceName:      feServiceName,
-		FeServiceNamespace: namespace,
-		FeServicePort:      feServicePort,
-	}, nil
-}
-
-// ExecuteContext sql statements. Every time a SQL statement needs to be executed, a new sql.DB instance will be created.
-// This is because SQL statements are executed infrequently.
-func (executor *SQLExecutor) ExecuteContext(ctx context.Context, db *sql.DB, statement string) error {
-	var err error
-	if db == nil {
-		db, err = sql.Open("mysql", fmt.Sprintf("root:%s@tcp(%s.%s:%s)/",
-			executor.RootPassword, executor.FeServiceName, executor.FeServiceNamespace, executor.FeServicePort))
-		if err != nil {
-			return err
+	if clusterName == "" {
+		for _, envVar := range sts.Spec.Template.Spec.Containers[0].Env {
+			if envVar.Name != "FE_SERVICE_NAME" {
+				continue
+			}
+			feServiceName, err := k8sutils.GetEnvVarValue(ctx, k8sClient, sts.Namespace, envVar)
+			if err != nil {
+				logr.FromContextOrDiscard(ctx).Error(err, "failed to get FE_SERVICE_NAME from env vars")
+				return nil, err
+			}
+			return getClusterByFEService(ctx, k8sClient, sts.Namespace, feServiceName)
 		}
-		defer db.Close()
+		return nil, nil
 	}
 
-	_, err = db.ExecContext(ctx, statement)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (executor *SQLExecutor) QueryContext(ctx context.Context, db *sql.DB, statements string) (*sql.Rows, error) {
-	var err error
-	if db == nil {
-		db, err = sql.Open("mysql", fmt.Sprintf("root:%s@tcp(%s.%s:%s)/",
-			executor.RootPassword, executor.FeServiceName, executor.FeServiceNamespace, executor.FeServicePort))
-		if err != nil {
-			return nil, err
+	var src srapi.StarRocksCluster
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: sts.Namespace, Name: clusterName}, &src); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
 		}
-		defer db.Close()
-	}
-
-	rows, err := db.QueryContext(ctx, statements)
-	if err != nil {
 		return nil, err
 	}
-
-	return rows, nil
-}
-
-type ShowComputeNodesResult struct {
-	ComputeNodesByWarehouse map[string][]ComputeNode
-}
-
-type ComputeNode struct {
-	ComputeNodeId string
-	FQDN          string
-	HeartbeatPort string
-	WarehouseName string
-
-	index int // the index is from FQDN, used for sorting
+	return &src, nil
 }
 
-func (executor *SQLExecutor) QueryShowComputeNodes(ctx context.Context, db *sql.DB) (*ShowComputeNodesResult, error) {
-	rows, err := executor.QueryContext(ctx, db, ShowComputeNodesStatement)
-	if err != nil {
+// getClusterByFEService returns the StarRocksCluster whose FE service is feServiceName, it returns nil if the
+// StarRocksCluster is not found, e.g. it has been deleted before the StarRocksWarehouse.
+func getClusterByFEService(ctx context.Context, k8sClient client.Client,
+	namespace, feServiceName string) (*srapi.StarRocksCluster, error) {
+	var clusters srapi.StarRocksClusterList
+	if err := k8sClient.List(ctx, &clusters, client.InNamespace(namespace)); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	// iterate over the rows
-	result := ShowComputeNodesResult{
-		ComputeNodesByWarehouse: make(map[string][]ComputeNode),
-	}
-	for rows.Next() {
-		var columns []string
-		columns, err = rows.Columns()
-		if err != nil {
-			return nil, err
-		}
-
-		// Create a slice of `interface{}` to hold the values dynamically
-		// Note: all data types of fields are sql.RawBytes([]byte)
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
-		for i := range values {
-			valuePtrs[i] = &values[i]
+	for i := range clusters.Items {
+		src := &clusters.Items[i]
+		if service.ExternalServiceName(src.Name, src.Spec.StarRocksFeSpec) == feServiceName {
+			return src, nil
 		}
+	}
+	return nil, nil
+}
 
-		// Scan the row into the slice
-		err = rows.Scan(valuePtrs...)
+// dynamicConfigApplier returns the applier which applies the changed config to the compute nodes of the statefulset,
+// see subc.SyncDynamicConfig.
+func (cc *CnController) dynamicConfigApplier(namespace, cnSTSName string) subc.DynamicConfigApplier {
+	return func(ctx context.Context, changes map[string]string) ([]string, error) {
+		sqlClient, err := NewSQLClient(ctx, cc.k8sClient, namespace, cnSTSName)
 		if err != nil {
 			return nil, err
 		}
-
-		// Map the values to specific fields based on column names
-		computeNode := ComputeNode{}
-		for i, col := range columns {
-			switch col {
-			case "ComputeNodeId":
-				computeNode.ComputeNodeId = string(values[i].([]byte))
-			case "IP":
-				computeNode.FQDN = string(values[i].([]byte))
-				firstPart := strings.Split(computeNode.FQDN, ".")[0]
-				parts := strings.Split(firstPart, "-")
-				indexStr := parts[len(parts)-1]
-				var index int
-				_, err = fmt.Sscanf(indexStr, "%d", &index)
-				if err != nil {
-					return nil, fmt.Errorf("failed to parse index from FQDN %s: %v", computeNode.FQDN, err)
-				}
-				computeNode.index = index
-			case "HeartbeatPort":
-				computeNode.HeartbeatPort = string(values[i].([]byte))
-			case "WarehouseName":
-				computeNode.WarehouseName = string(values[i].([]byte))
-			}
-		}
-		result.ComputeNodesByWarehouse[computeNode.WarehouseName] = append(result.ComputeNodesByWarehouse[computeNode.WarehouseName], computeNode)
-	}
-	if err = rows.Err(); err != nil {
-		return nil, err
+		return subc.ApplyComputeNodeConfig(ctx, sqlClient, cc.httpClient, cnSTSName, changes)
 	}
-
-	// the FQDN looks like: kube-starrocks-cn-2.kube-starrocks-cn-search.default.svc.cluster.local,
-	// kube-starrocks-cn-1.kube-starrocks-cn-search.default.svc.cluster.local,
-	// kube-starrocks-cn-10.kube-starrocks-cn-search.default.svc.cluster.local
-	// kube-starrocks-cn-10 should be after kube-starrocks-cn-2 when sorting.
-	for _, computeNodes := range result.ComputeNodesByWarehouse {
-		sort.Slice(computeNodes, func(i, j int) bool {
-			return computeNodes[i].index < computeNodes[j].index
-		})
-	}
-
-	return &result, nil
-}
-
-// ExecuteDropComputeNode executes the SQL statement to drop a compute node from a warehouse.
-func (executor *SQLExecutor) ExecuteDropComputeNode(ctx context.Context, db *sql.DB, cn ComputeNode) error {
-	dropStatement := fmt.Sprintf("ALTER SYSTEM DROP COMPUTE NODE \"%v:%v\" FROM WAREHOUSE %v", cn.FQDN, cn.HeartbeatPort, cn.WarehouseName)
-	return executor.ExecuteContext(ctx, db, dropStatement)
-}
-
-// ExecuteDropWarehouse executes the SQL statement to drop a warehouse.
-func (executor *SQLExecutor) ExecuteDropWarehouse(ctx context.Context, db *sql.DB, warehouseName string) error {
-	warehouseNameInFE := object.GetWarehouseNameInFE(warehouseName)
-	return executor.ExecuteContext(ctx, db, fmt.Sprintf("DROP WAREHOUSE %s", warehouseNameInFE))
 }
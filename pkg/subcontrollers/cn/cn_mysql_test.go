@@ -2,25 +2,65 @@ package cn
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
-	"reflect"
 	"testing"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
 )
 
-func TestNewSQLExecutor(t *testing.T) {
+func TestNewSQLClient(t *testing.T) {
+	newCNStatefulSet := func() *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "StatefulSet",
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-sts",
+				Namespace: "default",
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Env: []corev1.EnvVar{
+									{
+										Name:  "FE_SERVICE_NAME",
+										Value: "kube-starrocks-fe-service",
+									},
+									{
+										Name:  "FE_QUERY_PORT",
+										Value: "9030",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	newCluster := func(rootPasswordSecretRef *corev1.SecretKeySelector) *srapi.StarRocksCluster {
+		return &srapi.StarRocksCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+			Spec: srapi.StarRocksClusterSpec{
+				StarRocksFeSpec: &srapi.StarRocksFeSpec{
+					FeEnvVars: []corev1.EnvVar{{Name: "MYSQL_PWD", Value: "123456"}},
+				},
+				RootPasswordSecretRef: rootPasswordSecretRef,
+			},
+		}
+	}
+
 	type args struct {
 		ctx       context.Context
 		k8sClient client.Client
@@ -30,329 +70,98 @@ func TestNewSQLExecutor(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		want    *SQLExecutor
+		want    *sqlclient.Client
 		wantErr assert.ErrorAssertionFunc
 	}{
 		{
-			name: "test NewSQLExecutor",
+			name: "test NewSQLClient",
 			args: args{
-				ctx: context.Background(),
-				k8sClient: fake.NewFakeClient(
-					func() *runtime.Scheme {
-						schema := runtime.NewScheme()
-						_ = clientgoscheme.AddToScheme(schema)
-						return schema
-					}(),
-					&appsv1.StatefulSet{
-						TypeMeta: metav1.TypeMeta{
-							Kind:       "StatefulSet",
-							APIVersion: appsv1.SchemeGroupVersion.String(),
-						},
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      "my-sts",
-							Namespace: "default",
-							Annotations: map[string]string{
-								"test": "test",
-							},
-						},
-						Spec: appsv1.StatefulSetSpec{
-							Template: corev1.PodTemplateSpec{
-								Spec: corev1.PodSpec{
-									Containers: []corev1.Container{
-										{
-											Env: []corev1.EnvVar{
-												{
-													Name:  "MYSQL_PWD",
-													Value: "123456",
-												},
-												{
-													Name:  "FE_SERVICE_NAME",
-													Value: "fe",
-												},
-												{
-													Name:  "FE_QUERY_PORT",
-													Value: "9030",
-												},
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				),
+				ctx:       context.Background(),
+				k8sClient: fake.NewFakeClient(srapi.Scheme, newCNStatefulSet(), newCluster(nil)),
 				namespace: "default",
 				name:      "my-sts",
 			},
-			want: &SQLExecutor{
+			want: &sqlclient.Client{
 				RootPassword:       "123456",
-				FeServiceName:      "fe",
+				FeServiceName:      "kube-starrocks-fe-service",
 				FeServiceNamespace: "default",
 				FeServicePort:      "9030",
 			},
 			wantErr: assert.NoError,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewSQLExecutor(tt.args.ctx, tt.args.k8sClient, tt.args.namespace, tt.args.name)
-			if !tt.wantErr(t, err, fmt.Sprintf("NewSQLExecutor(%v, %v, %v, %v)", tt.args.ctx, tt.args.k8sClient, tt.args.namespace, tt.args.name)) {
-				return
-			}
-			assert.Equalf(t, tt.want, got, "NewSQLExecutor(%v, %v, %v, %v)", tt.args.ctx, tt.args.k8sClient, tt.args.namespace, tt.args.name)
-		})
-	}
-}
-
-func TestSQLExecutor_Execute(t *testing.T) {
-	type fields struct {
-		RootPassword  string
-		FeServiceName string
-		FeServicePort string
-	}
-	type args struct {
-		ctx        context.Context
-		statements string
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr assert.ErrorAssertionFunc
-	}{
 		{
-			name: "test ExecuteContext",
-			fields: fields{
-				RootPassword:  "root",
-				FeServiceName: "localhost",
-				FeServicePort: "3306",
-			},
+			name: "test NewSQLClient with the root password applied by operator",
 			args: args{
-				ctx:        context.Background(),
-				statements: "drop warehouse test",
+				ctx: context.Background(),
+				k8sClient: fake.NewFakeClient(srapi.Scheme, newCNStatefulSet(),
+					newCluster(&corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "rootcredential"},
+						Key:                  "password",
+					}),
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks-root-password", Namespace: "default"},
+						Data:       map[string][]byte{"password": []byte("654321")},
+					},
+				),
+				namespace: "default",
+				name:      "my-sts",
+			},
+			want: &sqlclient.Client{
+				RootPassword:       "654321",
+				FeServiceName:      "kube-starrocks-fe-service",
+				FeServiceNamespace: "default",
+				FeServicePort:      "9030",
 			},
 			wantErr: assert.NoError,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			executor := &SQLExecutor{
-				RootPassword:  tt.fields.RootPassword,
-				FeServiceName: tt.fields.FeServiceName,
-				FeServicePort: tt.fields.FeServicePort,
-			}
-
-			// create mock db
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-
-			// set expected behavior on mock db
-			mock.ExpectExec(tt.args.statements).
-				WillReturnResult(sqlmock.NewResult(1, 1))
-
-			err = executor.ExecuteContext(tt.args.ctx, db, tt.args.statements)
-			tt.wantErr(t, err, fmt.Sprintf("ExecuteContext(%v, %v)", tt.args.ctx, tt.args.statements))
-		})
-	}
-}
-
-func TestSQLExecutor_QueryShowComputeNodes(t *testing.T) {
-	type fields struct {
-		RootPassword       string
-		FeServiceName      string
-		FeServiceNamespace string
-		FeServicePort      string
-	}
-	type args struct {
-		ctx  context.Context
-		db   *sql.DB
-		rows *sqlmock.Rows
-	}
-
-	tests := []struct {
-		name     string
-		fields   fields
-		args     args
-		wantErr  assert.ErrorAssertionFunc
-		wantData *ShowComputeNodesResult
-	}{
 		{
-			name: "test ExecuteContext",
-			fields: fields{
-				RootPassword:  "",
-				FeServiceName: "localhost",
-				FeServicePort: "9030",
-			},
+			name: "test NewSQLClient with the StarRocksWarehouse which owns the statefulset",
 			args: args{
 				ctx: context.Background(),
-				rows: sqlmock.NewRows([]string{"ComputeNodeId", "IP", "WarehouseName"}).
-					AddRow([]byte("id"), []byte("cn-1"), []byte("wh1")).
-					AddRow([]byte("id"), []byte("cn-0"), []byte("wh1")).
-					AddRow([]byte("id"), []byte("cn-20"), []byte("wh1")).
-					AddRow([]byte("id"), []byte("cn-10"), []byte("wh1")).
-					AddRow([]byte("id"), []byte("cn-15"), []byte("wh1")).
-					AddRow([]byte("id"), []byte("cn-3"), []byte("wh1")).
-					AddRow([]byte("id"), []byte("cn-1"), []byte("wh2")).
-					AddRow([]byte("id"), []byte("cn-0"), []byte("wh2")).
-					AddRow([]byte("id"), []byte("cn-20"), []byte("wh2")).
-					AddRow([]byte("id"), []byte("cn-10"), []byte("wh2")).
-					AddRow([]byte("id"), []byte("cn-15"), []byte("wh2")).
-					AddRow([]byte("id"), []byte("cn-3"), []byte("wh2")),
-			},
-			wantErr: assert.NoError,
-			wantData: &ShowComputeNodesResult{
-				ComputeNodesByWarehouse: map[string][]ComputeNode{
-					"wh1": {
-						{ComputeNodeId: "id", FQDN: "cn-0", WarehouseName: "wh1", index: 0},
-						{ComputeNodeId: "id", FQDN: "cn-1", WarehouseName: "wh1", index: 1},
-						{ComputeNodeId: "id", FQDN: "cn-3", WarehouseName: "wh1", index: 3},
-						{ComputeNodeId: "id", FQDN: "cn-10", WarehouseName: "wh1", index: 10},
-						{ComputeNodeId: "id", FQDN: "cn-15", WarehouseName: "wh1", index: 15},
-						{ComputeNodeId: "id", FQDN: "cn-20", WarehouseName: "wh1", index: 20},
-					},
-					"wh2": {
-						{ComputeNodeId: "id", FQDN: "cn-0", WarehouseName: "wh2", index: 0},
-						{ComputeNodeId: "id", FQDN: "cn-1", WarehouseName: "wh2", index: 1},
-						{ComputeNodeId: "id", FQDN: "cn-3", WarehouseName: "wh2", index: 3},
-						{ComputeNodeId: "id", FQDN: "cn-10", WarehouseName: "wh2", index: 10},
-						{ComputeNodeId: "id", FQDN: "cn-15", WarehouseName: "wh2", index: 15},
-						{ComputeNodeId: "id", FQDN: "cn-20", WarehouseName: "wh2", index: 20},
+				k8sClient: fake.NewFakeClient(srapi.Scheme, newCluster(nil),
+					func() *appsv1.StatefulSet {
+						sts := newCNStatefulSet()
+						sts.Spec.Template.Spec.Containers[0].Env = nil
+						sts.OwnerReferences = []metav1.OwnerReference{{Kind: "StarRocksWarehouse", Name: "wh1"}}
+						return sts
+					}(),
+					&srapi.StarRocksWarehouse{
+						ObjectMeta: metav1.ObjectMeta{Name: "wh1", Namespace: "default"},
+						Spec:       srapi.StarRocksWarehouseSpec{StarRocksCluster: "kube-starrocks"},
 					},
-				},
+				),
+				namespace: "default",
+				name:      "my-sts",
 			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			executor := &SQLExecutor{
-				RootPassword:       tt.fields.RootPassword,
-				FeServiceName:      tt.fields.FeServiceName,
-				FeServiceNamespace: tt.fields.FeServiceNamespace,
-				FeServicePort:      tt.fields.FeServicePort,
-			}
-
-			// set expected behavior on mock db
-			// create mock db
-			db, mock, err := sqlmock.New()
-			require.NoError(t, err)
-			defer db.Close()
-			mock.ExpectQuery(ShowComputeNodesStatement).WillReturnRows(tt.args.rows)
-
-			result, err := executor.QueryShowComputeNodes(tt.args.ctx, db)
-			tt.wantErr(t, err, fmt.Sprintf("ShowComputeNodes(%v, %v)", tt.args.ctx, tt.args.db))
-			assert.True(t, reflect.DeepEqual(tt.wantData.ComputeNodesByWarehouse, result.ComputeNodesByWarehouse))
-		})
-	}
-}
-
-func TestSQLExecutor_ExecuteDropComputeNode(t *testing.T) {
-	type fields struct {
-		RootPassword       string
-		FeServiceName      string
-		FeServiceNamespace string
-		FeServicePort      string
-	}
-	type args struct {
-		ctx context.Context
-		db  *sql.DB
-		cn  ComputeNode
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr assert.ErrorAssertionFunc
-	}{
-		{
-			name: "test ExecuteDropComputeNode",
-			fields: fields{
-				RootPassword:       "root",
-				FeServiceName:      "fe-search",
+			want: &sqlclient.Client{
+				RootPassword:       "123456",
+				FeServiceName:      "kube-starrocks-fe-service",
 				FeServiceNamespace: "default",
 				FeServicePort:      "9030",
 			},
-			args: args{
-				ctx: context.Background(),
-				db: func() *sql.DB {
-					db, mock, err := sqlmock.New()
-					require.NoError(t, err)
-					statement := fmt.Sprintf("ALTER SYSTEM DROP COMPUTE NODE \"%v:%v\" FROM WAREHOUSE %v", "fqdn", "9010", "wh1")
-					mock.ExpectExec(statement).WillReturnResult(sqlmock.NewResult(1, 1))
-					return db
-				}(),
-				cn: ComputeNode{
-					ComputeNodeId: "id",
-					FQDN:          "fqdn",
-					HeartbeatPort: "9010",
-					WarehouseName: "wh1",
-				},
-			},
 			wantErr: assert.NoError,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			executor := &SQLExecutor{
-				RootPassword:       tt.fields.RootPassword,
-				FeServiceName:      tt.fields.FeServiceName,
-				FeServiceNamespace: tt.fields.FeServiceNamespace,
-				FeServicePort:      tt.fields.FeServicePort,
-			}
-			tt.wantErr(t, executor.ExecuteDropComputeNode(tt.args.ctx, tt.args.db, tt.args.cn), fmt.Sprintf("ExecuteDropComputeNode(%v, %v, %v)", tt.args.ctx, tt.args.db, tt.args.cn))
-		})
-	}
-}
-
-func TestSQLExecutor_ExecuteDropWarehouse(t *testing.T) {
-	type fields struct {
-		RootPassword       string
-		FeServiceName      string
-		FeServiceNamespace string
-		FeServicePort      string
-	}
-	type args struct {
-		ctx           context.Context
-		db            *sql.DB
-		warehouseName string
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr assert.ErrorAssertionFunc
-	}{
 		{
-			name: "test ExecuteDropComputeNode",
-			fields: fields{
-				RootPassword:       "root",
-				FeServiceName:      "fe-search",
-				FeServiceNamespace: "default",
-				FeServicePort:      "9030",
-			},
+			name: "test NewSQLClient without StarRocksCluster",
 			args: args{
-				ctx: context.Background(),
-				db: func() *sql.DB {
-					db, mock, err := sqlmock.New()
-					require.NoError(t, err)
-					mock.ExpectExec("DROP WAREHOUSE wh1").
-						WillReturnResult(sqlmock.NewResult(1, 1))
-					return db
-				}(),
-				warehouseName: "wh1",
+				ctx:       context.Background(),
+				k8sClient: fake.NewFakeClient(srapi.Scheme, newCNStatefulSet()),
+				namespace: "default",
+				name:      "my-sts",
+			},
+			want: nil,
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, ErrStarRocksClusterIsMissing, i...)
 			},
-			wantErr: assert.NoError,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			executor := &SQLExecutor{
-				RootPassword:       tt.fields.RootPassword,
-				FeServiceName:      tt.fields.FeServiceName,
-				FeServiceNamespace: tt.fields.FeServiceNamespace,
-				FeServicePort:      tt.fields.FeServicePort,
+			got, err := NewSQLClient(tt.args.ctx, tt.args.k8sClient, tt.args.namespace, tt.args.name)
+			if !tt.wantErr(t, err, fmt.Sprintf("NewSQLClient(%v, %v, %v, %v)", tt.args.ctx, tt.args.k8sClient, tt.args.namespace, tt.args.name)) {
+				return
 			}
-			tt.wantErr(t, executor.ExecuteDropWarehouse(tt.args.ctx, tt.args.db, tt.args.warehouseName), fmt.Sprintf("ExecuteDropWarehouse(%v, %v, %v)", tt.args.ctx, tt.args.db, tt.args.warehouseName))
+			assert.Equalf(t, tt.want, got, "NewSQLClient(%v, %v, %v, %v)", tt.args.ctx, tt.args.k8sClient, tt.args.namespace, tt.args.name)
 		})
 	}
 }
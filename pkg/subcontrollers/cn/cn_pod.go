@@ -30,6 +30,7 @@ import (
 	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
 	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
 	srobject "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
@@ -43,15 +44,16 @@ const (
 
 // buildPodTemplate construct the podTemplate for deploy cn.
 func (cc *CnController) buildPodTemplate(ctx context.Context, object srobject.StarRocksObject,
-	cnSpec *srapi.StarRocksCnSpec, config map[string]interface{}) (*corev1.PodTemplateSpec, error) {
+	cnSpec *srapi.StarRocksCnSpec, config map[string]interface{}, referenceHashes map[string]string) (*corev1.PodTemplateSpec, error) {
 	vols, volumeMounts := pod.MountStorageVolumes(cnSpec)
 
 	if !k8sutils.HasVolume(vols, _logName) && !k8sutils.HasMountPath(volumeMounts, pod.GetLogDir(cnSpec)) {
 		vols, volumeMounts = pod.MountEmptyDirVolume(vols, volumeMounts, _logName, pod.GetLogDir(cnSpec), "")
 	}
 
-	// mount configmap, secrets to pod if needed
-	vols, volumeMounts = pod.MountConfigMapInfo(vols, volumeMounts, cnSpec.ConfigMapInfo, _cnConfigPath)
+	// mount configmap, secrets to pod if needed, the configMap rendered from spec.config is mounted as configMapInfo.
+	configMapInfo := srconfig.ConfigMapInfo(object.SubResourcePrefixName, cnSpec)
+	vols, volumeMounts = pod.MountConfigMapInfo(vols, volumeMounts, configMapInfo, _cnConfigPath)
 	vols, volumeMounts = pod.MountConfigMaps(cnSpec, vols, volumeMounts, cnSpec.ConfigMaps)
 	vols, volumeMounts = pod.MountSecrets(vols, volumeMounts, cnSpec.Secrets)
 	if err := k8sutils.CheckVolumes(vols, volumeMounts); err != nil {
@@ -94,7 +96,7 @@ func (cc *CnController) buildPodTemplate(ctx context.Context, object srobject.St
 		cnContainer.WorkingDir = pod.GetStarRocksRootPath(cnSpec.CnEnvVars)
 	}
 
-	if cnSpec.ConfigMapInfo.ConfigMapName != "" && cnSpec.ConfigMapInfo.ResolveKey != "" {
+	if configMapInfo.ConfigMapName != "" && configMapInfo.ResolveKey != "" {
 		cnContainer.Env = append(cnContainer.Env, corev1.EnvVar{
 			Name:  _envCnConfigPath,
 			Value: _cnConfigPath,
@@ -102,7 +104,10 @@ func (cc *CnController) buildPodTemplate(ctx context.Context, object srobject.St
 	}
 
 	podSpec := pod.Spec(cnSpec, cnContainer, vols)
-	annotations := pod.Annotations(cnSpec)
+	annotations := pod.Annotations(cnSpec, referenceHashes)
+	if srconfig.IsManaged(cnSpec) {
+		annotations[srapi.ConfigHashAnnotation] = srconfig.Hash(cnSpec)
+	}
 	podSpec.SecurityContext = pod.PodSecurityContext(cnSpec)
 	return &corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
@@ -138,8 +143,7 @@ func (cc *CnController) addWarehouseEnv(ctx context.Context, url string) bool {
 		logger.Error(err, "failed to create request")
 		return false
 	}
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := cc.httpClient.Do(req)
 	if err != nil {
 		logger.Error(err, "failed to get features information from FE")
 		return false
@@ -45,7 +45,7 @@ func TestCnController_addWarehouseEnv1(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// create a mock server
-			cc := &CnController{}
+			cc := &CnController{httpClient: &http.Client{}}
 			server := httptest.NewServer(http.HandlerFunc(tt.args.ServerFunc))
 			defer server.Close()
 
@@ -0,0 +1,395 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subcontrollers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
+	srobject "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+// DynamicConfigApplier applies the changed config to the running processes of a component, and returns the keys
+// which are applied and verified. The other keys take effect after the pods are restarted.
+type DynamicConfigApplier func(ctx context.Context, changes map[string]string) ([]string, error)
+
+// SyncDynamicConfig returns the config hash which should be added to the annotations of pods and the configMap, see
+// srapi.ConfigHashAnnotation. When spec.config is changed, the changed keys are applied by apply without restarting
+// the pods, and the hash is changed to restart the pods only if some keys can not be applied, e.g. the static ones
+// or the removed ones. The result is recorded in status if it is not nil.
+// The config and the hash applied last time are read from the configMap, so it must be called before SyncConfigMap.
+func SyncDynamicConfig(ctx context.Context, k8sClient client.Client, object srobject.StarRocksObject, prefix string,
+	spec srapi.SpecInterface, status *srapi.StarRocksComponentStatus, apply DynamicConfigApplier) (string, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	if !srconfig.IsManaged(spec) {
+		if status != nil {
+			status.Config = nil
+		}
+		return "", nil
+	}
+
+	desiredHash := srconfig.Hash(spec)
+	var configMap corev1.ConfigMap
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: object.Namespace, Name: srconfig.ConfigMapName(prefix, spec)},
+		&configMap)
+	if apierrors.IsNotFound(err) {
+		return desiredHash, nil
+	} else if err != nil {
+		return "", err
+	}
+	var sts appsv1.StatefulSet
+	err = k8sClient.Get(ctx, types.NamespacedName{Namespace: object.Namespace, Name: load.Name(prefix, spec)}, &sts)
+	if apierrors.IsNotFound(err) {
+		return desiredHash, nil
+	} else if err != nil {
+		return "", err
+	}
+	currentHash := configMap.Annotations[srapi.ConfigHashAnnotation]
+	if currentHash == "" {
+		// the configMap is created by an older version of operator.
+		currentHash = sts.Spec.Template.Annotations[srapi.ConfigHashAnnotation]
+	}
+	if currentHash == "" {
+		// the pods do not use the config rendered by the operator yet.
+		return desiredHash, nil
+	}
+
+	changes, removed := diffConfig(srconfig.Parse(configMap.Data[srconfig.FileName(spec)]), srconfig.Merge(spec))
+	if len(changes) == 0 && len(removed) == 0 {
+		if status != nil && status.Config != nil && isRolledOut(&sts, currentHash) {
+			status.Config.PendingRestartKeys = nil
+		}
+		return currentHash, nil
+	}
+
+	var applied []string
+	if len(changes) > 0 {
+		if applied, err = apply(ctx, changes); err != nil {
+			// e.g. FE is not available, the pods are restarted to apply the config.
+			logger.Info("failed to apply the config without restarting pods", "error", err.Error())
+			applied = nil
+		}
+	}
+	pending := removed
+	for key := range changes {
+		if !contains(applied, key) {
+			pending = append(pending, key)
+		}
+	}
+	logger.Info("config is changed", "hotAppliedKeys", applied, "pendingRestartKeys", pending)
+
+	hash := currentHash
+	if len(pending) > 0 {
+		hash = desiredHash
+	}
+	if status != nil {
+		if status.Config != nil {
+			pending = append(pending, status.Config.PendingRestartKeys...)
+		}
+		status.Config = &srapi.ConfigStatus{HotAppliedKeys: applied, PendingRestartKeys: uniqueSorted(pending)}
+	}
+	return hash, nil
+}
+
+// ConfigHash returns the config hash applied last time, which is stored in the annotations of the configMap
+// rendered from spec.config. It is empty if the configMap does not exist.
+func ConfigHash(ctx context.Context, k8sClient client.Client, namespace, configMapName string) (string, error) {
+	var configMap corev1.ConfigMap
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: configMapName}, &configMap); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	return configMap.Annotations[srapi.ConfigHashAnnotation], nil
+}
+
+// diffConfig returns the keys whose values are added or changed, and the keys which are removed.
+func diffConfig(applied, desired map[string]string) (map[string]string, []string) {
+	changes := make(map[string]string)
+	for key, value := range desired {
+		if old, ok := applied[key]; !ok || old != value {
+			changes[key] = value
+		}
+	}
+	var removed []string
+	for key := range applied {
+		if _, ok := desired[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	return changes, removed
+}
+
+// isRolledOut returns true if the pods of the statefulset use the config hash, and all of them are updated to the
+// latest revision.
+func isRolledOut(sts *appsv1.StatefulSet, configHash string) bool {
+	return sts.Spec.Template.Annotations[srapi.ConfigHashAnnotation] == configHash &&
+		sts.Status.ObservedGeneration >= sts.Generation && sts.Status.UpdateRevision == sts.Status.CurrentRevision &&
+		sts.Status.UpdatedReplicas == sts.Status.Replicas
+}
+
+// ApplyFrontendConfig applies the changed config to all the alive frontends, including the observers, by ADMIN SET
+// FRONTEND CONFIG. Only the mutable keys are applied, and they are verified by ADMIN SHOW FRONTEND CONFIG.
+func ApplyFrontendConfig(ctx context.Context, sqlClient *sqlclient.Client, changes map[string]string) ([]string, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	frontends, err := sqlClient.ShowFrontends(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, key := range sortedKeys(changes) {
+		config, err := sqlClient.ShowFrontendConfig(ctx, nil, key)
+		if err != nil {
+			logger.Info("failed to get the config of FE", "key", key, "error", err.Error())
+			continue
+		} else if config == nil || !config.Mutable {
+			continue
+		}
+		if err = setFrontendConfig(ctx, sqlClient, frontends, key, unquote(changes[key])); err != nil {
+			logger.Info("failed to apply the config of FE", "key", key, "error", err.Error())
+			continue
+		}
+		applied = append(applied, key)
+	}
+	return applied, nil
+}
+
+func setFrontendConfig(ctx context.Context, sqlClient *sqlclient.Client, frontends []sqlclient.Frontend,
+	key, value string) error {
+	for _, frontend := range frontends {
+		if !frontend.Alive {
+			// the config is loaded from the configMap when it is restarted.
+			continue
+		}
+		client := sqlClient.ForFrontend(frontend.FQDN)
+		if err := client.SetFrontendConfig(ctx, nil, key, value); err != nil {
+			return err
+		}
+		config, err := client.ShowFrontendConfig(ctx, nil, key)
+		if err != nil {
+			return err
+		} else if config == nil || !sameValue(config.Value, value) {
+			return fmt.Errorf("the config %s of FE %s is not changed to %s", key, frontend.FQDN, value)
+		}
+	}
+	return nil
+}
+
+// configNode is a backend or compute node whose config is updated by its HTTP API.
+type configNode struct {
+	id       string
+	fqdn     string
+	httpPort string
+}
+
+// ApplyBackendConfig applies the changed config to the backends of the statefulset, see applyNodeConfig.
+func ApplyBackendConfig(ctx context.Context, sqlClient *sqlclient.Client, httpClient *http.Client, stsName string,
+	changes map[string]string) ([]string, error) {
+	backends, err := sqlClient.ShowBackends(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []configNode
+	for _, backend := range backends {
+		if isPodOfStatefulSet(backend.FQDN, stsName) {
+			nodes = append(nodes, configNode{id: backend.BackendId, fqdn: backend.FQDN, httpPort: backend.HttpPort})
+		}
+	}
+	return applyNodeConfig(ctx, sqlClient, httpClient, nodes, changes)
+}
+
+// ApplyComputeNodeConfig applies the changed config to the compute nodes of the statefulset, see applyNodeConfig.
+func ApplyComputeNodeConfig(ctx context.Context, sqlClient *sqlclient.Client, httpClient *http.Client, stsName string,
+	changes map[string]string) ([]string, error) {
+	result, err := sqlClient.ShowComputeNodes(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []configNode
+	for _, computeNodes := range result.ComputeNodesByWarehouse {
+		for _, computeNode := range computeNodes {
+			if isPodOfStatefulSet(computeNode.FQDN, stsName) {
+				nodes = append(nodes, configNode{id: computeNode.ComputeNodeId, fqdn: computeNode.FQDN,
+					httpPort: computeNode.HttpPort})
+			}
+		}
+	}
+	return applyNodeConfig(ctx, sqlClient, httpClient, nodes, changes)
+}
+
+// applyNodeConfig applies the changed config to the nodes by /api/update_config. Only the mutable keys reported by
+// information_schema.be_configs are applied, and they are verified by information_schema.be_configs again.
+func applyNodeConfig(ctx context.Context, sqlClient *sqlclient.Client, httpClient *http.Client, nodes []configNode,
+	changes map[string]string) ([]string, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	var keys []string
+	for _, key := range sortedKeys(changes) {
+		if sqlclient.IsConfigKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	configs, err := sqlClient.ShowBackendConfigs(ctx, nil, keys)
+	if err != nil {
+		return nil, err
+	}
+	mutable := make(map[string]bool)
+	for _, config := range configs {
+		mutable[config.Name] = mutable[config.Name] || config.Mutable
+	}
+
+	var updated []string
+	for _, key := range keys {
+		if !mutable[key] {
+			continue
+		}
+		if err = updateNodeConfig(ctx, httpClient, sqlClient.RootPassword, nodes, key, unquote(changes[key])); err != nil {
+			logger.Info("failed to apply the config of BE/CN", "key", key, "error", err.Error())
+			continue
+		}
+		updated = append(updated, key)
+	}
+	if len(updated) == 0 {
+		return nil, nil
+	}
+
+	configs, err = sqlClient.ShowBackendConfigs(ctx, nil, updated)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	for _, config := range configs {
+		values[config.BackendId+"/"+config.Name] = config.Value
+	}
+	var applied []string
+	for _, key := range updated {
+		verified := true
+		for _, node := range nodes {
+			if value, ok := values[node.id+"/"+key]; !ok || !sameValue(value, unquote(changes[key])) {
+				logger.Info("the config of BE/CN is not changed", "key", key, "node", node.fqdn, "value", value)
+				verified = false
+				break
+			}
+		}
+		if verified {
+			applied = append(applied, key)
+		}
+	}
+	return applied, nil
+}
+
+// updateNodeConfig calls /api/update_config of every node, the response looks like: {"status": "OK", "msg": ""}.
+func updateNodeConfig(ctx context.Context, httpClient *http.Client, rootPassword string, nodes []configNode,
+	key, value string) error {
+	for _, node := range nodes {
+		endpoint := fmt.Sprintf("http://%s/api/update_config?%s", net.JoinHostPort(node.fqdn, node.httpPort),
+			url.Values{key: []string{value}}.Encode())
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth("root", rootPassword)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		var result struct {
+			Status string `json:"status"`
+			Msg    string `json:"msg"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode the response of %s: %v", endpoint, err)
+		} else if result.Status != "OK" {
+			return fmt.Errorf("failed to update the config of %s: %s", node.fqdn, result.Msg)
+		}
+	}
+	return nil
+}
+
+// isPodOfStatefulSet returns true if the FQDN belongs to a pod of the statefulset, the FQDN looks like:
+// kube-starrocks-be-1.kube-starrocks-be-search.default.svc.cluster.local
+func isPodOfStatefulSet(fqdn, stsName string) bool {
+	podName := strings.Split(fqdn, ".")[0]
+	if !strings.HasPrefix(podName, stsName+"-") {
+		return false
+	}
+	_, err := strconv.ParseInt(strings.TrimPrefix(podName, stsName+"-"), 10, 32)
+	return err == nil
+}
+
+// unquote removes the quotes of the value in the config file, e.g. "a b".
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// sameValue compares the values case-insensitively, because StarRocks reports the booleans in lower case.
+func sameValue(actual, expected string) bool {
+	return strings.EqualFold(strings.TrimSpace(actual), strings.TrimSpace(expected))
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func uniqueSorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Strings(values)
+	result := values[:1]
+	for _, value := range values[1:] {
+		if value != result[len(result)-1] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
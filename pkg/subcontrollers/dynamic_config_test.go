@@ -0,0 +1,199 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subcontrollers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
+	srobject "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/fakefe"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+func TestSyncDynamicConfig(t *testing.T) {
+	ctx := context.Background()
+	cluster := &srapi.StarRocksCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	object := srobject.NewFromCluster(cluster)
+	spec := &srapi.StarRocksBeSpec{}
+	spec.Config = map[string]string{"max_compaction_concurrency": "1", "storage_root_path": "/opt/starrocks/be/storage"}
+	status := &srapi.StarRocksComponentStatus{}
+
+	applyAll := func(_ context.Context, changes map[string]string) ([]string, error) {
+		return sortedKeys(changes), nil
+	}
+	applyNone := func(_ context.Context, _ map[string]string) ([]string, error) {
+		return nil, nil
+	}
+	applyFailed := func(_ context.Context, _ map[string]string) ([]string, error) {
+		return nil, errors.New("FE is not available")
+	}
+
+	// the config is applied by restarting pods when the configMap does not exist.
+	k8sClient := fake.NewClientBuilder().Build()
+	hash, err := SyncDynamicConfig(ctx, k8sClient, object, "test", spec, status, applyAll)
+	require.NoError(t, err)
+	require.Equal(t, srconfig.Hash(spec), hash)
+
+	// deploy the configMap and the statefulset like the controllers.
+	originalHash := hash
+	require.NoError(t, SyncConfigMap(ctx, k8sClient, object, "test", spec, hash))
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-be", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{srapi.ConfigHashAnnotation: hash}},
+		}},
+	}
+	require.NoError(t, k8sClient.Create(ctx, sts))
+	sync := func(apply DynamicConfigApplier) string {
+		hash, err := SyncDynamicConfig(ctx, k8sClient, object, "test", spec, status, apply)
+		require.NoError(t, err)
+		require.NoError(t, SyncConfigMap(ctx, k8sClient, object, "test", spec, hash))
+		return hash
+	}
+
+	// the dynamic keys are applied without restarting pods.
+	spec.Config["max_compaction_concurrency"] = "4"
+	assert.Equal(t, originalHash, sync(applyAll))
+	assert.Equal(t, &srapi.ConfigStatus{HotAppliedKeys: []string{"max_compaction_concurrency"}}, status.Config)
+	assert.Equal(t, originalHash, sync(applyAll), "the hash should be kept if the config is not changed")
+
+	// the pods are restarted if some keys can not be applied.
+	spec.Config["storage_root_path"] = "/data"
+	hash = sync(applyNone)
+	assert.Equal(t, srconfig.Hash(spec), hash)
+	assert.Equal(t, &srapi.ConfigStatus{PendingRestartKeys: []string{"storage_root_path"}}, status.Config)
+
+	// the pending keys are kept until the pods are restarted.
+	delete(spec.Config, "max_compaction_concurrency")
+	assert.Equal(t, srconfig.Hash(spec), sync(applyAll))
+	assert.Equal(t, &srapi.ConfigStatus{PendingRestartKeys: []string{"max_compaction_concurrency", "storage_root_path"}},
+		status.Config)
+	spec.Config["sys_log_level"] = "WARN"
+	hash = sync(applyFailed)
+	assert.Equal(t, srconfig.Hash(spec), hash)
+	assert.Equal(t, []string{"max_compaction_concurrency", "storage_root_path", "sys_log_level"},
+		status.Config.PendingRestartKeys)
+
+	sts.Spec.Template.Annotations[srapi.ConfigHashAnnotation] = hash
+	require.NoError(t, k8sClient.Update(ctx, sts))
+	assert.Equal(t, hash, sync(applyAll))
+	assert.Empty(t, status.Config.PendingRestartKeys, "the pods have been restarted")
+
+	// the status is removed when spec.config is removed.
+	spec.Config = nil
+	hash, err = SyncDynamicConfig(ctx, k8sClient, object, "test", spec, status, applyAll)
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+	assert.Nil(t, status.Config)
+}
+
+func TestConfigHash(t *testing.T) {
+	ctx := context.Background()
+	cluster := &srapi.StarRocksCluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	spec := &srapi.StarRocksFeSpec{}
+	spec.Config = map[string]string{"sys_log_level": "WARN"}
+	k8sClient := fake.NewClientBuilder().Build()
+
+	hash, err := ConfigHash(ctx, k8sClient, "default", "test-fe-config")
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+
+	require.NoError(t, SyncConfigMap(ctx, k8sClient, srobject.NewFromCluster(cluster), "test", spec, "12345"))
+	hash, err = ConfigHash(ctx, k8sClient, "default", "test-fe-config")
+	require.NoError(t, err)
+	assert.Equal(t, "12345", hash)
+
+	var configMap corev1.ConfigMap
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "test-fe-config"}, &configMap))
+	assert.Equal(t, "12345", configMap.Annotations[srapi.ConfigHashAnnotation])
+}
+
+func TestApplyFrontendConfig(t *testing.T) {
+	const host = "dynamic-config-fe-service.default"
+	fe := fakefe.Start(t, host)
+	fe.AddFrontend(sqlclient.Frontend{FQDN: "test-fe-0.test-fe-search.default.svc.cluster.local", EditLogPort: "9010",
+		Role: sqlclient.FrontendRoleLeader, Alive: true})
+	fe.AddFrontend(sqlclient.Frontend{FQDN: "test-fe-1.test-fe-search.default.svc.cluster.local", EditLogPort: "9010",
+		Role: sqlclient.FrontendRoleFollower})
+	fe.SetFrontendConfig("max_routine_load_task_num_per_be", "16", true)
+	fe.SetFrontendConfig("http_port", "8030", false)
+	sqlClient := &sqlclient.Client{FeServiceName: "dynamic-config-fe-service", FeServiceNamespace: "default", FeServicePort: "9030"}
+
+	applied, err := ApplyFrontendConfig(context.Background(), sqlClient, map[string]string{
+		"max_routine_load_task_num_per_be": "32",
+		"http_port":                        "8031",
+		"unknown_key":                      "1",
+		"invalid key":                      "1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"max_routine_load_task_num_per_be"}, applied)
+	assert.Equal(t, "32", fe.FrontendConfig("max_routine_load_task_num_per_be"))
+	assert.Equal(t, "8030", fe.FrontendConfig("http_port"))
+	// the config is set in every alive FE.
+	assert.Equal(t, 1, countStatements(fe.Statements(), `ADMIN SET FRONTEND CONFIG ("max_routine_load_task_num_per_be" = "32")`))
+}
+
+func TestApplyBackendConfig(t *testing.T) {
+	const host = "dynamic-config-be-fe-service.default"
+	fe := fakefe.Start(t, host)
+	fe.AddBackend(sqlclient.Backend{FQDN: "test-be-0.test-be-search.default.svc.cluster.local", HeartbeatPort: "9050"})
+	fe.AddBackend(sqlclient.Backend{FQDN: "test-be-1.test-be-search.default.svc.cluster.local", HeartbeatPort: "9050"})
+	fe.AddComputeNode(sqlclient.ComputeNode{FQDN: "test-cn-0.test-cn-search.default.svc.cluster.local", HeartbeatPort: "9050"})
+	fe.SetBackendConfig("max_compaction_concurrency", "-1", true)
+	fe.SetBackendConfig("be_port", "9060", false)
+	sqlClient := &sqlclient.Client{FeServiceName: "dynamic-config-be-fe-service", FeServiceNamespace: "default", FeServicePort: "9030"}
+
+	applied, err := ApplyBackendConfig(context.Background(), sqlClient, fakefe.HTTPClient(), "test-be", map[string]string{
+		"max_compaction_concurrency": "4",
+		"be_port":                    "9061",
+		"unknown_key":                "1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"max_compaction_concurrency"}, applied)
+	assert.Equal(t, "4", fe.BackendConfig("test-be-0.test-be-search.default.svc.cluster.local", "max_compaction_concurrency"))
+	assert.Equal(t, "4", fe.BackendConfig("test-be-1.test-be-search.default.svc.cluster.local", "max_compaction_concurrency"))
+	assert.Equal(t, "-1", fe.BackendConfig("test-cn-0.test-cn-search.default.svc.cluster.local", "max_compaction_concurrency"),
+		"the compute nodes should not be changed")
+
+	applied, err = ApplyComputeNodeConfig(context.Background(), sqlClient, fakefe.HTTPClient(), "test-cn",
+		map[string]string{"max_compaction_concurrency": "2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"max_compaction_concurrency"}, applied)
+	assert.Equal(t, "2", fe.BackendConfig("test-cn-0.test-cn-search.default.svc.cluster.local", "max_compaction_concurrency"))
+}
+
+func countStatements(statements []string, statement string) int {
+	count := 0
+	for _, s := range statements {
+		if s == statement {
+			count++
+		}
+	}
+	return count
+}
@@ -18,6 +18,8 @@ package fe
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
@@ -33,6 +35,7 @@ import (
 	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
@@ -43,6 +46,10 @@ import (
 type FeController struct {
 	Client   client.Client
 	Recorder record.EventRecorder
+
+	// QueryFrontends is used by the leader-aware rolling update. If it is nil, the FE pods are updated in the default
+	// order of statefulset.
+	QueryFrontends FrontendsQuerier
 }
 
 // New construct a FeController.
@@ -102,12 +109,37 @@ func (fc *FeController) SyncCluster(ctx context.Context, src *srapi.StarRocksClu
 			AppProtocol: func() *string { mysql := "mysql"; return &mysql }(),
 		},
 	}, defaultLabels)
+	leaderServiceName := service.LeaderServiceName(src.Name)
+	leaderService := service.MakeLeaderService(leaderServiceName, &svc, defaultLabels)
 
-	podTemplateSpec, err := fc.buildPodTemplate(src, feConfig)
+	referenceHashes, err := subcontrollers.ReferenceHashes(ctx, fc.Client, src.Namespace, &feSpec.StarRocksComponentSpec)
+	if err != nil {
+		logger.Error(err, "get the hashes of referenced configMaps and secrets failed")
+		return err
+	}
+	podTemplateSpec, err := buildPodTemplate(object, feSpec, feConfig, referenceHashes)
 	if err != nil {
 		logger.Error(err, "build pod template failed")
 		return err
 	}
+	if src.Status.StarRocksFeStatus == nil {
+		src.Status.StarRocksFeStatus = &srapi.StarRocksFeStatus{
+			StarRocksComponentStatus: srapi.StarRocksComponentStatus{Phase: srapi.ComponentReconciling},
+		}
+	}
+	configHash, err := subcontrollers.SyncDynamicConfig(ctx, fc.Client, object, src.Name, feSpec,
+		&src.Status.StarRocksFeStatus.StarRocksComponentStatus, dynamicConfigApplier(fc.Client, src))
+	if err != nil {
+		logger.Error(err, "sync dynamic config failed")
+		return err
+	}
+	if configHash != "" {
+		podTemplateSpec.Annotations[srapi.ConfigHashAnnotation] = configHash
+	}
+	if err = subcontrollers.SyncConfigMap(ctx, fc.Client, object, src.Name, feSpec, configHash); err != nil {
+		logger.Error(err, "sync fe config configMap failed")
+		return err
+	}
 	expectSts := statefulset.MakeStatefulset(object, feSpec, podTemplateSpec)
 
 	drSpec := src.Spec.DisasterRecovery
@@ -115,15 +147,30 @@ func (fc *FeController) SyncCluster(ctx context.Context, src *srapi.StarRocksClu
 	shouldEnterDRMode, queryPort := ShouldEnterDisasterRecoveryMode(drSpec, drStatus, feConfig)
 	if shouldEnterDRMode {
 		logger.Info("should enter disaster recovery mode")
-		if drStatus == nil {
-			drStatus = srapi.NewDisasterRecoveryStatus(drSpec.Generation)
-			src.Status.DisasterRecoveryStatus = drStatus
-		}
-		if err = EnterDisasterRecoveryMode(ctx, fc.Client, src, &expectSts, queryPort); err != nil {
+		if err = EnterDisasterRecoveryMode(ctx, fc.Client, fc.Recorder, src, &expectSts, queryPort); err != nil {
 			logger.Error(err, "enter disaster recovery mode failed")
 			return err
 		}
+		// FE is deployed as normal in the same round once the disaster recovery is finished, so that BE and CN are
+		// synced again without waiting for another event.
+		shouldEnterDRMode = !src.Status.DisasterRecoveryStatus.Phase.IsFinished()
+	}
+	if shouldEnterDRMode {
 		logger.Info("deploy statefulset", "statefulset", expectSts)
+	} else if src.IsComponentSuspended(srapi.DEFAULT_FE) {
+		// FE is the last component to be suspended, no FE pod needs to be updated.
+		logger.Info("the cluster is suspended, scale fe to zero")
+		expectSts.Spec.Replicas = rutils.GetInt32Pointer(0)
+		setRollingUpdateStatus(src, nil)
+	} else if err = fc.leaderAwareRollingUpdate(ctx, src, &expectSts); err != nil {
+		logger.Error(err, "leader-aware rolling update failed")
+		return err
+	}
+
+	if err = subcontrollers.ExpandPersistentVolumeClaims(ctx, fc.Client, fc.Recorder, src, &expectSts,
+		&src.Status.StarRocksFeStatus.StarRocksComponentStatus); err != nil {
+		logger.Error(err, "expand persistent volume claims failed")
+		return err
 	}
 
 	if err = k8sutils.ApplyStatefulSet(ctx, fc.Client, &expectSts, shouldEnterDRMode, rutils.StatefulSetDeepEqual); err != nil {
@@ -142,6 +189,23 @@ func (fc *FeController) SyncCluster(ctx context.Context, src *srapi.StarRocksClu
 		return err
 	}
 
+	if feSpec.EnableLeaderService {
+		if err = k8sutils.ApplyService(ctx, fc.Client, leaderService, rutils.ServiceDeepEqual); err != nil {
+			logger.Error(err, "deploy leader service failed", "leaderService", leaderService)
+			return err
+		}
+	} else if err = k8sutils.DeleteService(ctx, fc.Client, src.Namespace, leaderServiceName); err != nil {
+		logger.Error(err, "delete leader service failed", "leaderServiceName", leaderServiceName)
+		return err
+	}
+
+	if !shouldEnterDRMode {
+		if err = fc.syncFrontendRoles(ctx, src); err != nil {
+			logger.Error(err, "sync the roles of fe pods failed")
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -214,10 +278,21 @@ func (fc *FeController) ClearCluster(ctx context.Context, src *srapi.StarRocksCl
 		logger.Error(err, "delete external service failed", "externalServiceName", externalServiceName)
 		return err
 	}
+	leaderServiceName := service.LeaderServiceName(src.Name)
+	if err = k8sutils.DeleteService(ctx, fc.Client, src.Namespace, leaderServiceName); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "delete leader service failed", "leaderServiceName", leaderServiceName)
+		return err
+	}
+	configMapName := srconfig.ConfigMapName(src.Name, feSpec)
+	if err = k8sutils.DeleteConfigMap(ctx, fc.Client, src.Namespace, configMapName); err != nil {
+		logger.Error(err, "delete config configMap failed", "configMapName", configMapName)
+		return err
+	}
 
 	return nil
 }
 
+// Validating validates the spec of FE, it is also used by the admission webhook.
 func (fc *FeController) Validating(feSpec *srapi.StarRocksFeSpec) error {
 	for i := range feSpec.StorageVolumes {
 		if err := feSpec.StorageVolumes[i].Validate(); err != nil {
@@ -227,7 +302,17 @@ func (fc *FeController) Validating(feSpec *srapi.StarRocksFeSpec) error {
 	if err := srapi.ValidUpdateStrategy(feSpec.UpdateStrategy); err != nil {
 		return err
 	}
-	return nil
+	groupNames := make(map[string]bool)
+	for _, group := range feSpec.ObserverGroups {
+		if group.Name == "" {
+			return errors.New("the name of observer group is empty")
+		}
+		if groupNames[group.Name] {
+			return fmt.Errorf("the name of observer group %s is duplicated", group.Name)
+		}
+		groupNames[group.Name] = true
+	}
+	return srconfig.Validate(feSpec)
 }
 
 // CheckFEReady check the fe cluster is ok.
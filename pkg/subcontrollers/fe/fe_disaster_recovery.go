@@ -3,6 +3,8 @@ package fe
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,14 +12,79 @@ import (
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
 	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
 )
 
+const (
+	clusterSnapshotConfFile   = "cluster_snapshot.yaml"
+	clusterSnapshotVolumeName = "cluster-snapshot"
+
+	// DefaultSnapshotStorageVolumeName is the name of storage volume used when it is not specified.
+	DefaultSnapshotStorageVolumeName = "builtin_storage_volume"
+)
+
+// snapshotCredentialKeys maps the keys in the credentials secret to the properties of storage volume.
+var snapshotCredentialKeys = map[string]map[string]string{
+	"S3": {
+		"access_key": "aws.s3.access_key",
+		"secret_key": "aws.s3.secret_key",
+	},
+	"HDFS": {
+		"username": "username",
+		"password": "password",
+	},
+	"AZBLOB": {
+		"shared_key": "azure.blob.shared_key",
+	},
+	"ADLS2": {
+		"shared_key": "azure.adls2.shared_key",
+	},
+	"GS": {
+		"service_account_email":          "gcp.gcs.service_account_email",
+		"service_account_private_key_id": "gcp.gcs.service_account_private_key_id",
+		"service_account_private_key":    "gcp.gcs.service_account_private_key",
+	},
+}
+
+// snapshotEndpointKeys maps the type of storage volume to the property keys of endpoint and region.
+var snapshotEndpointKeys = map[string][2]string{
+	"S3":     {"aws.s3.endpoint", "aws.s3.region"},
+	"AZBLOB": {"azure.blob.endpoint", ""},
+	"ADLS2":  {"azure.adls2.endpoint", ""},
+	"GS":     {"gcp.gcs.endpoint", ""},
+}
+
+type clusterSnapshotConf struct {
+	ClusterSnapshot struct {
+		ClusterSnapshotPath string `json:"cluster_snapshot_path"`
+		StorageVolumeName   string `json:"storage_volume_name"`
+	} `json:"cluster_snapshot"`
+	StorageVolumes []storageVolumeConf `json:"storage_volumes"`
+}
+
+type storageVolumeConf struct {
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	Location   string            `json:"location"`
+	Properties []storageVolumeKV `json:"properties,omitempty"`
+}
+
+type storageVolumeKV struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
 func ShouldEnterDisasterRecoveryMode(drSpec *v1.DisasterRecovery,
 	drStatus *v1.DisasterRecoveryStatus, feConfig map[string]interface{}) (bool, int32) {
 	if !IsRunInSharedDataMode(feConfig) {
@@ -39,14 +106,17 @@ func ShouldEnterDisasterRecoveryMode(drSpec *v1.DisasterRecovery,
 	}
 
 	og := drStatus.ObservedGeneration
-	if drSpec.Generation > og || (drSpec.Generation == og && drStatus.Phase != v1.DRPhaseDone) {
+	if drSpec.Generation > og || (drSpec.Generation == og && !drStatus.Phase.IsFinished()) {
 		return true, rutils.GetPort(feConfig, rutils.QUERY_PORT)
 	}
 
 	return false, 0
 }
 
-func EnterDisasterRecoveryMode(ctx context.Context, k8sClient client.Client,
+// EnterDisasterRecoveryMode moves the disaster recovery forward and rewrites the statefulset of FE if the disaster
+// recovery is not finished. The phase transitions are: todo -> doing -> done, and todo/doing -> failed/aborted when it
+// is timed out or aborted by user. Once it is finished, the statefulset is left as normal.
+func EnterDisasterRecoveryMode(ctx context.Context, k8sClient client.Client, recorder record.EventRecorder,
 	src *v1.StarRocksCluster, sts *appsv1.StatefulSet, queryPort int32) error {
 	feSpec := src.Spec.StarRocksFeSpec
 	drSpec := src.Spec.DisasterRecovery
@@ -55,13 +125,38 @@ func EnterDisasterRecoveryMode(ctx context.Context, k8sClient client.Client,
 
 	logger.Info("enter disaster recovery mode")
 	if drStatus == nil || drSpec.Generation > drStatus.ObservedGeneration {
-		drStatus = v1.NewDisasterRecoveryStatus(drSpec.Generation)
+		newStatus := v1.NewDisasterRecoveryStatus(drSpec.Generation)
+		if drStatus != nil {
+			if !drStatus.Phase.IsFinished() {
+				finishDisasterRecovery(recorder, src, drStatus, v1.DRPhaseAborted,
+					fmt.Sprintf("replaced by generation %d", drSpec.Generation))
+			}
+			newStatus.History = drStatus.History
+		}
+		drStatus = newStatus
 		src.Status.DisasterRecoveryStatus = drStatus
+		recorder.Eventf(src, corev1.EventTypeNormal, "DisasterRecoveryRequested",
+			"disaster recovery of generation %d is requested", drSpec.Generation)
+	}
+
+	if drSpec.Abort {
+		finishDisasterRecovery(recorder, src, drStatus, v1.DRPhaseAborted, "disaster recovery is aborted by user")
+		return nil
+	}
+	if drSpec.TimeoutSeconds > 0 && time.Now().Unix()-drStatus.StartTimestamp > drSpec.TimeoutSeconds {
+		finishDisasterRecovery(recorder, src, drStatus, v1.DRPhaseFailed,
+			fmt.Sprintf("disaster recovery is not done in %d seconds, last reason: %s", drSpec.TimeoutSeconds, drStatus.Reason))
+		return nil
 	}
 
 	switch drStatus.Phase {
 	case v1.DRPhaseTodo:
-		if !hasClusterSnapshotConf(feSpec.ConfigMaps) {
+		if drSpec.ClusterSnapshot != nil {
+			if err := applyClusterSnapshotConf(ctx, k8sClient, src); err != nil {
+				drStatus.Reason = err.Error()
+				return err
+			}
+		} else if !hasClusterSnapshotConf(feSpec.ConfigMaps) {
 			drStatus.Phase = v1.DRPhaseTodo
 			reason := "cluster_snapshot.yaml is not mounted"
 			drStatus.Reason = reason
@@ -69,22 +164,165 @@ func EnterDisasterRecoveryMode(ctx context.Context, k8sClient client.Client,
 		}
 		// rewrite the statefulset
 		rewriteStatefulSetForDisasterRecovery(sts, drSpec.Generation, queryPort)
+		mountClusterSnapshotConf(sts, src)
 		drStatus.Phase = v1.DRPhaseDoing
 		drStatus.Reason = "has changed to pod template for disaster recovery"
+		recorder.Eventf(src, corev1.EventTypeNormal, "DisasterRecoveryStarted",
+			"disaster recovery of generation %d is started", drSpec.Generation)
 	case v1.DRPhaseDoing:
 		// check whether the pod is ready
-		rewriteStatefulSetForDisasterRecovery(sts, drSpec.Generation, queryPort)
-		if !CheckFEReadyInDisasterRecovery(ctx, k8sClient, src.Namespace, src.Name, drSpec.Generation) {
-			drStatus.Reason = "disaster recovery is in progress"
-		} else {
-			drStatus.Phase = v1.DRPhaseDone
-			drStatus.Reason = "disaster recovery is done"
-			drStatus.EndTimestamp = time.Now().Unix()
+		if CheckFEReadyInDisasterRecovery(ctx, k8sClient, src.Namespace, src.Name, drSpec.Generation) {
+			finishDisasterRecovery(recorder, src, drStatus, v1.DRPhaseDone, "disaster recovery is done")
+			return nil
 		}
+		rewriteStatefulSetForDisasterRecovery(sts, drSpec.Generation, queryPort)
+		mountClusterSnapshotConf(sts, src)
+		drStatus.Reason = "disaster recovery is in progress"
 	}
 	return nil
 }
 
+// finishDisasterRecovery sets the final phase of disaster recovery and records an event.
+func finishDisasterRecovery(recorder record.EventRecorder, src *v1.StarRocksCluster,
+	drStatus *v1.DisasterRecoveryStatus, phase v1.DRPhase, reason string) {
+	drStatus.Finish(phase, reason)
+	switch phase {
+	case v1.DRPhaseDone:
+		recorder.Event(src, corev1.EventTypeNormal, "DisasterRecoverySucceeded", reason)
+	case v1.DRPhaseFailed:
+		recorder.Event(src, corev1.EventTypeWarning, "DisasterRecoveryFailed", reason)
+	case v1.DRPhaseAborted:
+		recorder.Event(src, corev1.EventTypeWarning, "DisasterRecoveryAborted", reason)
+	}
+}
+
+// ClusterSnapshotSecretName returns the name of secret which contains the rendered cluster_snapshot.yaml.
+func ClusterSnapshotSecretName(clusterName string) string {
+	return clusterName + "-fe-cluster-snapshot"
+}
+
+// RenderClusterSnapshotConf validates the cluster snapshot and renders the content of cluster_snapshot.yaml.
+// credentials is the data of the credentials secret, it can be nil if no secret is referenced.
+func RenderClusterSnapshotConf(snapshot *v1.ClusterSnapshot, credentials map[string][]byte) ([]byte, error) {
+	volume := &snapshot.StorageVolume
+	if snapshot.Path == "" {
+		return nil, errors.New("clusterSnapshot.path is required")
+	}
+	if volume.Type == "" || volume.Location == "" {
+		return nil, errors.New("clusterSnapshot.storageVolume.type and clusterSnapshot.storageVolume.location are required")
+	}
+	credentialKeys, ok := snapshotCredentialKeys[volume.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported storage volume type %q", volume.Type)
+	}
+	if !strings.HasPrefix(snapshot.Path, strings.TrimSuffix(volume.Location, "/")+"/") {
+		return nil, fmt.Errorf("cluster snapshot path %q is not under the location %q of storage volume",
+			snapshot.Path, volume.Location)
+	}
+
+	properties := map[string]string{}
+	if keys, ok := snapshotEndpointKeys[volume.Type]; ok {
+		if volume.Endpoint != "" {
+			properties[keys[0]] = volume.Endpoint
+		}
+		if volume.Region != "" && keys[1] != "" {
+			properties[keys[1]] = volume.Region
+		}
+	}
+	if volume.CredentialsSecretRef != nil {
+		found := false
+		for key, property := range credentialKeys {
+			if value, ok := credentials[key]; ok {
+				properties[property] = string(value)
+				found = true
+			}
+		}
+		if !found {
+			keys := make([]string, 0, len(credentialKeys))
+			for key := range credentialKeys {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			return nil, fmt.Errorf("secret %s does not contain any of the keys %s",
+				volume.CredentialsSecretRef.Name, strings.Join(keys, ", "))
+		}
+		if volume.Type == "S3" {
+			properties["aws.s3.use_aws_sdk_default_behavior"] = "false"
+			properties["aws.s3.use_instance_profile"] = "false"
+		}
+	}
+	for key, value := range volume.Properties {
+		properties[key] = value
+	}
+
+	name := volume.Name
+	if name == "" {
+		name = DefaultSnapshotStorageVolumeName
+	}
+	conf := clusterSnapshotConf{}
+	conf.ClusterSnapshot.ClusterSnapshotPath = snapshot.Path
+	conf.ClusterSnapshot.StorageVolumeName = name
+	storageVolume := storageVolumeConf{Name: name, Type: volume.Type, Location: volume.Location}
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		storageVolume.Properties = append(storageVolume.Properties, storageVolumeKV{Key: key, Value: properties[key]})
+	}
+	conf.StorageVolumes = []storageVolumeConf{storageVolume}
+	return yaml.Marshal(conf)
+}
+
+// applyClusterSnapshotConf renders cluster_snapshot.yaml and saves it into a secret owned by the cluster, because the
+// file may contain the credentials of the storage volume.
+func applyClusterSnapshotConf(ctx context.Context, k8sClient client.Client, src *v1.StarRocksCluster) error {
+	snapshot := src.Spec.DisasterRecovery.ClusterSnapshot
+	var credentials map[string][]byte
+	if ref := snapshot.StorageVolume.CredentialsSecretRef; ref != nil {
+		var secret corev1.Secret
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: src.Namespace, Name: ref.Name}, &secret); err != nil {
+			return fmt.Errorf("failed to get credentials secret %s: %w", ref.Name, err)
+		}
+		credentials = secret.Data
+	}
+
+	data, err := RenderClusterSnapshotConf(snapshot, credentials)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ClusterSnapshotSecretName(src.Name),
+			Namespace:       src.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(src, src.GroupVersionKind())},
+		},
+		Data: map[string][]byte{clusterSnapshotConfFile: data},
+	}
+	return k8sutils.ApplySecret(ctx, k8sClient, secret)
+}
+
+// mountClusterSnapshotConf mounts the rendered cluster_snapshot.yaml into the config directory of FE.
+func mountClusterSnapshotConf(sts *appsv1.StatefulSet, src *v1.StarRocksCluster) {
+	if src.Spec.DisasterRecovery.ClusterSnapshot == nil {
+		return
+	}
+	podSpec := &sts.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: clusterSnapshotVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: ClusterSnapshotSecretName(src.Name)},
+		},
+	})
+	feContainer := &podSpec.Containers[0]
+	feContainer.VolumeMounts = append(feContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      clusterSnapshotVolumeName,
+		MountPath: pod.GetConfigDir(src.Spec.StarRocksFeSpec) + "/" + clusterSnapshotConfFile,
+		SubPath:   clusterSnapshotConfFile,
+	})
+}
+
 func hasClusterSnapshotConf(configMaps []v1.ConfigMapReference) bool {
 	// check all the mount paths, to make sure cluster_snapshot.yaml is mounted
 	hasConf := false
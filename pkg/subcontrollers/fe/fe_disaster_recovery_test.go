@@ -3,11 +3,15 @@ package fe
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
@@ -502,3 +506,314 @@ func TestCheckFEReadyInDisasterRecovery(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderClusterSnapshotConf(t *testing.T) {
+	snapshot := func() *v1.ClusterSnapshot {
+		return &v1.ClusterSnapshot{
+			Path: "s3://bucket/data/meta/image/automated_cluster_snapshot_1",
+			StorageVolume: v1.SnapshotStorageVolume{
+				Type:                 "S3",
+				Location:             "s3://bucket/data",
+				Endpoint:             "https://s3.us-west-2.amazonaws.com",
+				Region:               "us-west-2",
+				CredentialsSecretRef: &corev1.LocalObjectReference{Name: "s3-credentials"},
+			},
+		}
+	}
+
+	t.Run("render S3 storage volume", func(t *testing.T) {
+		data, err := RenderClusterSnapshotConf(snapshot(), map[string][]byte{
+			"access_key": []byte("ak"),
+			"secret_key": []byte("sk"),
+		})
+		if err != nil {
+			t.Fatalf("RenderClusterSnapshotConf() error = %v", err)
+		}
+		want := `cluster_snapshot:
+  cluster_snapshot_path: s3://bucket/data/meta/image/automated_cluster_snapshot_1
+  storage_volume_name: builtin_storage_volume
+storage_volumes:
+- location: s3://bucket/data
+  name: builtin_storage_volume
+  properties:
+  - key: aws.s3.access_key
+    value: ak
+  - key: aws.s3.endpoint
+    value: https://s3.us-west-2.amazonaws.com
+  - key: aws.s3.region
+    value: us-west-2
+  - key: aws.s3.secret_key
+    value: sk
+  - key: aws.s3.use_aws_sdk_default_behavior
+    value: "false"
+  - key: aws.s3.use_instance_profile
+    value: "false"
+  type: S3
+`
+		if string(data) != want {
+			t.Errorf("RenderClusterSnapshotConf() got:\n%s\nwant:\n%s", data, want)
+		}
+	})
+
+	t.Run("properties override generated ones", func(t *testing.T) {
+		s := snapshot()
+		s.StorageVolume.CredentialsSecretRef = nil
+		s.StorageVolume.Endpoint = ""
+		s.StorageVolume.Properties = map[string]string{"aws.s3.region": "us-east-1"}
+		data, err := RenderClusterSnapshotConf(s, nil)
+		if err != nil {
+			t.Fatalf("RenderClusterSnapshotConf() error = %v", err)
+		}
+		if !strings.Contains(string(data), "value: us-east-1") || strings.Contains(string(data), "us-west-2") {
+			t.Errorf("RenderClusterSnapshotConf() got:\n%s", data)
+		}
+	})
+
+	invalid := []struct {
+		name        string
+		mutate      func(s *v1.ClusterSnapshot)
+		credentials map[string][]byte
+	}{
+		{name: "path is empty", mutate: func(s *v1.ClusterSnapshot) { s.Path = "" }},
+		{name: "location is empty", mutate: func(s *v1.ClusterSnapshot) { s.StorageVolume.Location = "" }},
+		{name: "unsupported type", mutate: func(s *v1.ClusterSnapshot) { s.StorageVolume.Type = "OSS" }},
+		{name: "path is not under location", mutate: func(s *v1.ClusterSnapshot) { s.Path = "s3://other/meta" }},
+		{name: "secret has no credentials", mutate: func(s *v1.ClusterSnapshot) {}, credentials: map[string][]byte{}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			s := snapshot()
+			tt.mutate(s)
+			if _, err := RenderClusterSnapshotConf(s, tt.credentials); err == nil {
+				t.Errorf("RenderClusterSnapshotConf() expected an error")
+			}
+		})
+	}
+}
+
+func TestEnterDisasterRecoveryModeWithClusterSnapshot(t *testing.T) {
+	src := &v1.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+		Spec: v1.StarRocksClusterSpec{
+			StarRocksFeSpec: &v1.StarRocksFeSpec{},
+			DisasterRecovery: &v1.DisasterRecovery{
+				Enabled:    true,
+				Generation: 1,
+				ClusterSnapshot: &v1.ClusterSnapshot{
+					Path: "s3://bucket/data/meta/image/automated_cluster_snapshot_1",
+					StorageVolume: v1.SnapshotStorageVolume{
+						Type:                 "S3",
+						Location:             "s3://bucket/data",
+						CredentialsSecretRef: &corev1.LocalObjectReference{Name: "s3-credentials"},
+					},
+				},
+			},
+		},
+	}
+	newSts := func() *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: v1.DEFAULT_FE}}},
+				},
+			},
+		}
+	}
+
+	// the credentials secret does not exist
+	k8sClient := fake.NewFakeClient(v1.Scheme)
+	sts := newSts()
+	if err := EnterDisasterRecoveryMode(context.Background(), k8sClient, record.NewFakeRecorder(10), src, sts, 9030); err == nil {
+		t.Fatalf("EnterDisasterRecoveryMode() expected an error when the credentials secret is missing")
+	}
+	if src.Status.DisasterRecoveryStatus.Phase != v1.DRPhaseTodo {
+		t.Errorf("phase = %v, want %v", src.Status.DisasterRecoveryStatus.Phase, v1.DRPhaseTodo)
+	}
+
+	k8sClient = fake.NewFakeClient(v1.Scheme, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"access_key": []byte("ak"), "secret_key": []byte("sk")},
+	})
+	sts = newSts()
+	if err := EnterDisasterRecoveryMode(context.Background(), k8sClient, record.NewFakeRecorder(10), src, sts, 9030); err != nil {
+		t.Fatalf("EnterDisasterRecoveryMode() error = %v", err)
+	}
+	if src.Status.DisasterRecoveryStatus.Phase != v1.DRPhaseDoing {
+		t.Errorf("phase = %v, want %v", src.Status.DisasterRecoveryStatus.Phase, v1.DRPhaseDoing)
+	}
+
+	var secret corev1.Secret
+	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default",
+		Name: ClusterSnapshotSecretName(src.Name)}, &secret); err != nil {
+		t.Fatalf("get cluster snapshot secret error = %v", err)
+	}
+	if !strings.Contains(string(secret.Data["cluster_snapshot.yaml"]), "value: ak") {
+		t.Errorf("unexpected cluster_snapshot.yaml: %s", secret.Data["cluster_snapshot.yaml"])
+	}
+
+	mounts := sts.Spec.Template.Spec.Containers[0].VolumeMounts
+	if len(mounts) != 1 || mounts[0].MountPath != "/opt/starrocks/fe/conf/cluster_snapshot.yaml" ||
+		mounts[0].SubPath != "cluster_snapshot.yaml" {
+		t.Errorf("unexpected volume mounts: %v", mounts)
+	}
+	volumes := sts.Spec.Template.Spec.Volumes
+	if len(volumes) != 1 || volumes[0].Secret == nil ||
+		volumes[0].Secret.SecretName != ClusterSnapshotSecretName(src.Name) {
+		t.Errorf("unexpected volumes: %v", volumes)
+	}
+}
+
+func TestEnterDisasterRecoveryModeLifecycle(t *testing.T) {
+	newCluster := func(drSpec *v1.DisasterRecovery, drStatus *v1.DisasterRecoveryStatus) *v1.StarRocksCluster {
+		return &v1.StarRocksCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+			Spec: v1.StarRocksClusterSpec{
+				StarRocksFeSpec: &v1.StarRocksFeSpec{
+					StarRocksComponentSpec: v1.StarRocksComponentSpec{
+						ConfigMaps: []v1.ConfigMapReference{
+							{Name: "cluster-snapshot", SubPath: "cluster_snapshot.yaml"},
+						},
+					},
+				},
+				DisasterRecovery: drSpec,
+			},
+			Status: v1.StarRocksClusterStatus{DisasterRecoveryStatus: drStatus},
+		}
+	}
+	newSts := func() *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: v1.DEFAULT_FE}}},
+				},
+			},
+		}
+	}
+	readyPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "kube-starrocks-fe-0",
+			Labels: map[string]string{
+				v1.ComponentLabelKey: v1.DEFAULT_FE,
+				v1.OwnerReference:    "kube-starrocks-fe",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: v1.DEFAULT_FE,
+				Env:  []corev1.EnvVar{{Name: "RESTORE_CLUSTER_GENERATION", Value: "1"}},
+			}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: v1.DEFAULT_FE, Ready: true}},
+		},
+	}
+	doing := func() *v1.DisasterRecoveryStatus {
+		return &v1.DisasterRecoveryStatus{
+			Phase:              v1.DRPhaseDoing,
+			StartTimestamp:     time.Now().Add(-time.Hour).Unix(),
+			ObservedGeneration: 1,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		src           *v1.StarRocksCluster
+		objects       []runtime.Object
+		wantPhase     v1.DRPhase
+		wantRewritten bool
+		wantEvent     string
+		wantHistory   []v1.DRPhase
+	}{
+		{
+			name:          "disaster recovery is in progress",
+			src:           newCluster(&v1.DisasterRecovery{Enabled: true, Generation: 1, TimeoutSeconds: 7200}, doing()),
+			wantPhase:     v1.DRPhaseDoing,
+			wantRewritten: true,
+		},
+		{
+			name:        "disaster recovery is timed out",
+			src:         newCluster(&v1.DisasterRecovery{Enabled: true, Generation: 1, TimeoutSeconds: 60}, doing()),
+			wantPhase:   v1.DRPhaseFailed,
+			wantEvent:   "Warning DisasterRecoveryFailed",
+			wantHistory: []v1.DRPhase{v1.DRPhaseFailed},
+		},
+		{
+			name:        "disaster recovery is aborted",
+			src:         newCluster(&v1.DisasterRecovery{Enabled: true, Generation: 1, Abort: true}, doing()),
+			wantPhase:   v1.DRPhaseAborted,
+			wantEvent:   "Warning DisasterRecoveryAborted",
+			wantHistory: []v1.DRPhase{v1.DRPhaseAborted},
+		},
+		{
+			name:        "disaster recovery is done",
+			src:         newCluster(&v1.DisasterRecovery{Enabled: true, Generation: 1}, doing()),
+			objects:     []runtime.Object{readyPod},
+			wantPhase:   v1.DRPhaseDone,
+			wantEvent:   "Normal DisasterRecoverySucceeded",
+			wantHistory: []v1.DRPhase{v1.DRPhaseDone},
+		},
+		{
+			name:          "disaster recovery is replaced by a newer generation",
+			src:           newCluster(&v1.DisasterRecovery{Enabled: true, Generation: 2}, doing()),
+			wantPhase:     v1.DRPhaseDoing,
+			wantRewritten: true,
+			wantEvent:     "Warning DisasterRecoveryAborted",
+			wantHistory:   []v1.DRPhase{v1.DRPhaseAborted},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			sts := newSts()
+			err := EnterDisasterRecoveryMode(context.Background(), fake.NewFakeClient(v1.Scheme, tt.objects...),
+				recorder, tt.src, sts, 9030)
+			if err != nil {
+				t.Fatalf("EnterDisasterRecoveryMode() error = %v", err)
+			}
+			drStatus := tt.src.Status.DisasterRecoveryStatus
+			if drStatus.Phase != tt.wantPhase {
+				t.Errorf("phase = %v, want %v", drStatus.Phase, tt.wantPhase)
+			}
+			if rewritten := sts.Spec.Replicas != nil; rewritten != tt.wantRewritten {
+				t.Errorf("statefulset rewritten = %v, want %v", rewritten, tt.wantRewritten)
+			}
+			var phases []v1.DRPhase
+			for _, record := range drStatus.History {
+				phases = append(phases, record.Phase)
+			}
+			if !reflect.DeepEqual(phases, tt.wantHistory) {
+				t.Errorf("history = %v, want %v", phases, tt.wantHistory)
+			}
+			if tt.wantEvent != "" {
+				found := false
+				for len(recorder.Events) > 0 {
+					if strings.HasPrefix(<-recorder.Events, tt.wantEvent) {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("event %q is not recorded", tt.wantEvent)
+				}
+			}
+		})
+	}
+}
+
+func TestDisasterRecoveryStatusFinish(t *testing.T) {
+	drStatus := v1.NewDisasterRecoveryStatus(v1.MaxDisasterRecoveryHistory + 1)
+	for i := 1; i <= v1.MaxDisasterRecoveryHistory; i++ {
+		drStatus.History = append(drStatus.History, v1.DisasterRecoveryRecord{Generation: int64(i), Phase: v1.DRPhaseDone})
+	}
+	drStatus.Finish(v1.DRPhaseFailed, "timed out")
+	if len(drStatus.History) != v1.MaxDisasterRecoveryHistory {
+		t.Fatalf("len(history) = %d, want %d", len(drStatus.History), v1.MaxDisasterRecoveryHistory)
+	}
+	if drStatus.History[0].Generation != 2 {
+		t.Errorf("the oldest record should be removed, got generation %d", drStatus.History[0].Generation)
+	}
+	last := drStatus.History[len(drStatus.History)-1]
+	if last.Generation != v1.MaxDisasterRecoveryHistory+1 || last.Phase != v1.DRPhaseFailed || last.Reason != "timed out" {
+		t.Errorf("unexpected last record %+v", last)
+	}
+}
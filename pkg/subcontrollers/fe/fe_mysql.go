@@ -0,0 +1,90 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers"
+)
+
+// NewSQLClient creates a sqlclient.Client which connects to the FE service of StarRocksCluster as root.
+func NewSQLClient(ctx context.Context, k8sClient client.Client, src *srapi.StarRocksCluster) (*sqlclient.Client, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	feSpec := src.Spec.StarRocksFeSpec
+	if feSpec == nil {
+		return nil, fmt.Errorf("the spec of fe is not found in StarRocksCluster %s/%s", src.Namespace, src.Name)
+	}
+
+	feConfig, err := GetFEConfig(ctx, k8sClient, feSpec, src.Namespace)
+	if err != nil {
+		logger.Error(err, "failed to get fe config")
+		return nil, err
+	}
+
+	rootPassword, err := GetRootPassword(ctx, k8sClient, src)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlclient.Client{
+		RootPassword:       rootPassword,
+		FeServiceName:      service.ExternalServiceName(src.Name, feSpec),
+		FeServiceNamespace: src.Namespace,
+		FeServicePort:      fmt.Sprintf("%d", rutils.GetPort(feConfig, rutils.QUERY_PORT)),
+	}, nil
+}
+
+// FrontendsOfStatefulSet returns the frontends whose pods belong to the statefulset, the key is the ordinal of pod.
+// The FQDN of a frontend looks like: kube-starrocks-fe-1.kube-starrocks-fe-search.default.svc.cluster.local
+func FrontendsOfStatefulSet(frontends []sqlclient.Frontend, stsName string) map[int32]sqlclient.Frontend {
+	result := make(map[int32]sqlclient.Frontend)
+	for _, frontend := range frontends {
+		podName := strings.Split(frontend.FQDN, ".")[0]
+		if !strings.HasPrefix(podName, stsName+"-") {
+			continue
+		}
+		ordinal, err := strconv.ParseInt(strings.TrimPrefix(podName, stsName+"-"), 10, 32)
+		if err != nil {
+			continue
+		}
+		result[int32(ordinal)] = frontend
+	}
+	return result
+}
+
+// dynamicConfigApplier returns the applier which applies the changed config to all the frontends of StarRocksCluster,
+// see subcontrollers.SyncDynamicConfig.
+func dynamicConfigApplier(k8sClient client.Client, src *srapi.StarRocksCluster) subcontrollers.DynamicConfigApplier {
+	return func(ctx context.Context, changes map[string]string) ([]string, error) {
+		sqlClient, err := NewSQLClient(ctx, k8sClient, src)
+		if err != nil {
+			return nil, err
+		}
+		return subcontrollers.ApplyFrontendConfig(ctx, sqlClient, changes)
+	}
+}
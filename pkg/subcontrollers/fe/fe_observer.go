@@ -0,0 +1,74 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	srobject "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/statefulset"
+)
+
+// observerRole is the value of the environment variable FE_ROLE for observers.
+const observerRole = "OBSERVER"
+
+// ObserverGroupSpec merges the observer group into a copy of feSpec, the result is used to render the statefulset and
+// services of the group.
+func ObserverGroupSpec(feSpec *srapi.StarRocksFeSpec, group *srapi.StarRocksFeObserverGroup) *srapi.StarRocksFeSpec {
+	spec := feSpec.DeepCopy()
+	spec.ObserverGroups = nil
+	// The service of FE may use node ports or a load balancer, which can not be shared with the observers. The
+	// observers of a group are exposed by a ClusterIP service.
+	spec.Service = nil
+
+	spec.Replicas = group.Replicas
+	if group.Limits != nil || group.Requests != nil || group.Claims != nil {
+		spec.ResourceRequirements = *group.ResourceRequirements.DeepCopy()
+	}
+	if group.NodeSelector != nil {
+		spec.NodeSelector = group.NodeSelector
+	}
+	if group.Affinity != nil {
+		spec.Affinity = group.Affinity
+	}
+	if group.Tolerations != nil {
+		spec.Tolerations = group.Tolerations
+	}
+	if group.TopologySpreadConstraints != nil {
+		spec.TopologySpreadConstraints = group.TopologySpreadConstraints
+	}
+	return spec
+}
+
+// BuildObserverStatefulSet builds the statefulset for a group of FE observers.
+// The observers are started by the entrypoint of FE, or the command and args of FE if they are set. The operator adds
+// the observers by `ALTER SYSTEM ADD OBSERVER` before they are started, so the entrypoint finds the pod in the
+// frontends, and starts it with the FE service as the helper instead of adding it as a follower. The environment
+// variable FE_ROLE=OBSERVER is added to tell a custom entrypoint that the pod is an observer.
+func BuildObserverStatefulSet(object srobject.StarRocksObject, observerSpec *srapi.StarRocksFeSpec,
+	config map[string]interface{}, referenceHashes map[string]string) (appsv1.StatefulSet, error) {
+	podTemplateSpec, err := buildPodTemplate(object, observerSpec, config, referenceHashes)
+	if err != nil {
+		return appsv1.StatefulSet{}, err
+	}
+
+	container := &podTemplateSpec.Spec.Containers[0]
+	container.Env = append(container.Env, corev1.EnvVar{Name: srapi.FE_ROLE, Value: observerRole})
+	return statefulset.MakeStatefulset(object, observerSpec, podTemplateSpec), nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+)
+
+func TestObserverGroupSpec(t *testing.T) {
+	feSpec := &srapi.StarRocksFeSpec{
+		StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+			StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+				Replicas: rutils.GetInt32Pointer(3),
+				ResourceRequirements: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+				},
+				NodeSelector: map[string]string{"node": "fe"},
+				Service:      &srapi.StarRocksService{Type: corev1.ServiceTypeLoadBalancer},
+			},
+			Command: []string{"/bin/bash"},
+		},
+		ObserverGroups: []srapi.StarRocksFeObserverGroup{{Name: "read"}},
+	}
+	affinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "node-role/read", Operator: corev1.NodeSelectorOpExists},
+				}}},
+			},
+		},
+	}
+	group := &srapi.StarRocksFeObserverGroup{
+		Name:     "read",
+		Replicas: rutils.GetInt32Pointer(2),
+		ResourceRequirements: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+		},
+		Affinity: affinity,
+	}
+
+	spec := ObserverGroupSpec(feSpec, group)
+	assert.Equal(t, int32(2), *spec.Replicas)
+	assert.Equal(t, resource.MustParse("8"), spec.Requests[corev1.ResourceCPU])
+	assert.Equal(t, map[string]string{"node": "fe"}, spec.NodeSelector)
+	assert.Equal(t, affinity, spec.Affinity)
+	assert.Nil(t, spec.Service)
+	assert.Equal(t, []string{"/bin/bash"}, spec.Command)
+	assert.Nil(t, spec.ObserverGroups)
+	// feSpec is not changed
+	assert.Equal(t, int32(3), *feSpec.Replicas)
+	assert.NotNil(t, feSpec.Service)
+}
+
+func TestBuildObserverStatefulSet(t *testing.T) {
+	src := &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+	}
+	spec := ObserverGroupSpec(&srapi.StarRocksFeSpec{}, &srapi.StarRocksFeObserverGroup{Name: "read"})
+	sts, err := BuildObserverStatefulSet(object.NewFromObserverGroup(src, "read"), spec, map[string]interface{}{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "kube-starrocks-read-observer-fe", sts.Name)
+	assert.Equal(t, "kube-starrocks-read-observer-fe-search", sts.Spec.ServiceName)
+	assert.Equal(t, "kube-starrocks-read-observer-fe", sts.Spec.Template.Labels[srapi.OwnerReference])
+
+	container := sts.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"/opt/starrocks/fe_entrypoint.sh"}, container.Command)
+	assert.Equal(t, []string{"$(FE_SERVICE_NAME)"}, container.Args)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: srapi.FE_ROLE, Value: "OBSERVER"})
+	for _, env := range container.Env {
+		if env.Name == srapi.FE_SERVICE_NAME {
+			assert.Equal(t, "kube-starrocks-fe-service.default", env.Value)
+		}
+	}
+
+	// the command and args of FE are used by the observers
+	spec.Command = []string{"/custom_entrypoint.sh"}
+	spec.Args = []string{"--observer"}
+	sts, err = BuildObserverStatefulSet(object.NewFromObserverGroup(src, "read"), spec, map[string]interface{}{}, nil)
+	require.NoError(t, err)
+	container = sts.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"/custom_entrypoint.sh"}, container.Command)
+	assert.Equal(t, []string{"--observer"}, container.Args)
+}
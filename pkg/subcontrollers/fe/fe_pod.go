@@ -23,6 +23,8 @@ import (
 	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
 	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
+	srobject "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
 )
@@ -34,10 +36,11 @@ const (
 	_envFeConfigMountPath = "CONFIGMAP_MOUNT_PATH"
 )
 
-// buildPodTemplate construct the podTemplate for deploy fe.
-func (fc *FeController) buildPodTemplate(src *srapi.StarRocksCluster, config map[string]interface{}) (*corev1.PodTemplateSpec, error) {
-	metaName := src.Name + "-" + srapi.DEFAULT_FE
-	feSpec := src.Spec.StarRocksFeSpec
+// buildPodTemplate construct the podTemplate for deploy fe. referenceHashes are the hashes of the configMaps and
+// secrets referenced by feSpec, see subcontrollers.ReferenceHashes.
+func buildPodTemplate(object srobject.StarRocksObject, feSpec *srapi.StarRocksFeSpec,
+	config map[string]interface{}, referenceHashes map[string]string) (*corev1.PodTemplateSpec, error) {
+	metaName := object.SubResourcePrefixName + "-" + srapi.DEFAULT_FE
 
 	vols, volMounts := pod.MountStorageVolumes(feSpec)
 	// add default volume about log, meta if not configure.
@@ -48,16 +51,17 @@ func (fc *FeController) buildPodTemplate(src *srapi.StarRocksCluster, config map
 		vols, volMounts = pod.MountEmptyDirVolume(vols, volMounts, _logName, pod.GetLogDir(feSpec), "")
 	}
 
-	// mount configmap, secrets to pod if needed
-	vols, volMounts = pod.MountConfigMapInfo(vols, volMounts, feSpec.ConfigMapInfo, _feConfigMountPath)
+	// mount configmap, secrets to pod if needed, the configMap rendered from spec.config is mounted as configMapInfo.
+	configMapInfo := srconfig.ConfigMapInfo(object.ClusterName, feSpec)
+	vols, volMounts = pod.MountConfigMapInfo(vols, volMounts, configMapInfo, _feConfigMountPath)
 	vols, volMounts = pod.MountConfigMaps(feSpec, vols, volMounts, feSpec.ConfigMaps)
 	vols, volMounts = pod.MountSecrets(vols, volMounts, feSpec.Secrets)
 	if err := k8sutils.CheckVolumes(vols, volMounts); err != nil {
 		return nil, err
 	}
 
-	feExternalServiceName := service.ExternalServiceName(src.Name, feSpec)
-	envs := pod.Envs(src.Spec.StarRocksFeSpec, config, feExternalServiceName, src.Namespace, feSpec.FeEnvVars)
+	feExternalServiceName := service.ExternalServiceName(object.ClusterName, feSpec)
+	envs := pod.Envs(feSpec, config, feExternalServiceName, object.Namespace, feSpec.FeEnvVars)
 	httpPort := rutils.GetPort(config, rutils.HTTP_PORT)
 	feContainer := corev1.Container{
 		Name:            srapi.DEFAULT_FE,
@@ -79,7 +83,7 @@ func (fc *FeController) buildPodTemplate(src *srapi.StarRocksCluster, config map
 		feContainer.WorkingDir = pod.GetStarRocksRootPath(feSpec.FeEnvVars)
 	}
 
-	if feSpec.ConfigMapInfo.ConfigMapName != "" && feSpec.ConfigMapInfo.ResolveKey != "" {
+	if configMapInfo.ConfigMapName != "" && configMapInfo.ResolveKey != "" {
 		feContainer.Env = append(feContainer.Env, corev1.EnvVar{
 			Name:  _envFeConfigMountPath,
 			Value: _feConfigMountPath,
@@ -87,14 +91,17 @@ func (fc *FeController) buildPodTemplate(src *srapi.StarRocksCluster, config map
 	}
 
 	podSpec := pod.Spec(feSpec, feContainer, vols)
-	annotations := pod.Annotations(feSpec)
+	annotations := pod.Annotations(feSpec, referenceHashes)
+	if srconfig.IsManaged(feSpec) {
+		annotations[srapi.ConfigHashAnnotation] = srconfig.Hash(feSpec)
+	}
 	podSpec.SecurityContext = pod.PodSecurityContext(feSpec)
 	return &corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        metaName,
-			Namespace:   src.Namespace,
+			Namespace:   object.Namespace,
 			Annotations: annotations,
-			Labels:      pod.Labels(src.Name, src.Spec.StarRocksFeSpec),
+			Labels:      pod.Labels(object.SubResourcePrefixName, feSpec),
 		},
 		Spec: podSpec,
 	}, nil
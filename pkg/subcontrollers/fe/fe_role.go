@@ -0,0 +1,88 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+)
+
+// syncFrontendRoles labels the FE pods, including the followers and the observers, with their live roles in
+// SHOW FRONTENDS. The label is removed if the FE is not alive, so that the leader service never selects a dead
+// leader. It does nothing if FE is not ready, the labels will be synced in the next reconciliation.
+func (fc *FeController) syncFrontendRoles(ctx context.Context, src *srapi.StarRocksCluster) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	if fc.QueryFrontends == nil || !CheckFEReady(ctx, fc.Client, src.Namespace, src.Name) {
+		return nil
+	}
+	frontends, err := fc.QueryFrontends(ctx, src)
+	if err != nil {
+		logger.Error(err, "query SHOW FRONTENDS failed, skip syncing the roles of fe pods")
+		return nil
+	}
+	roles := make(map[string]string)
+	for _, frontend := range frontends {
+		if frontend.Alive {
+			podName := strings.Split(frontend.FQDN, ".")[0]
+			roles[podName] = strings.ToLower(frontend.Role)
+		}
+	}
+
+	feSpec := src.Spec.StarRocksFeSpec
+	owners := map[string]bool{load.Name(src.Name, feSpec): true}
+	for _, group := range feSpec.ObserverGroups {
+		owners[load.Name(object.GetPrefixNameForObserverGroup(src.Name, group.Name), feSpec)] = true
+	}
+	var podList corev1.PodList
+	if err = fc.Client.List(ctx, &podList, client.InNamespace(src.Namespace),
+		client.MatchingLabels{srapi.ComponentLabelKey: srapi.DEFAULT_FE}); err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !owners[pod.Labels[srapi.OwnerReference]] {
+			continue
+		}
+		role := roles[pod.Name]
+		if pod.Labels[srapi.FeRoleLabelKey] == role {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		if role == "" {
+			delete(pod.Labels, srapi.FeRoleLabelKey)
+		} else {
+			pod.Labels[srapi.FeRoleLabelKey] = role
+		}
+		logger.Info("update the role label of fe pod", "pod", pod.Name, "role", role)
+		if err = fc.Client.Patch(ctx, pod, patch); err != nil {
+			return err
+		}
+		if role == srapi.FeRoleLeader {
+			fc.Recorder.Event(src, corev1.EventTypeNormal, "FeLeaderChanged", fmt.Sprintf("the FE leader is %s", pod.Name))
+		}
+	}
+	return nil
+}
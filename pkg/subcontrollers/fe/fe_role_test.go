@@ -0,0 +1,117 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+func newRolePod(name, owner, role string) *corev1.Pod {
+	labels := map[string]string{srapi.ComponentLabelKey: srapi.DEFAULT_FE, srapi.OwnerReference: owner}
+	if role != "" {
+		labels[srapi.FeRoleLabelKey] = role
+	}
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels}}
+}
+
+func newReadyFeEndpoints() *corev1.Endpoints {
+	return &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks-fe-service", Namespace: "default"},
+		Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "127.0.0.1"}}}},
+	}
+}
+
+func TestFeController_syncFrontendRoles(t *testing.T) {
+	src := newRollingUpdateCluster(nil)
+	src.Spec.StarRocksFeSpec.ObserverGroups = []srapi.StarRocksFeObserverGroup{{Name: "read"}}
+	objects := []runtime.Object{
+		src, newReadyFeEndpoints(),
+		newRolePod("kube-starrocks-fe-0", "kube-starrocks-fe", ""),
+		newRolePod("kube-starrocks-fe-1", "kube-starrocks-fe", srapi.FeRoleLeader),
+		newRolePod("kube-starrocks-fe-2", "kube-starrocks-fe", srapi.FeRoleFollower),
+		newRolePod("kube-starrocks-read-observer-fe-0", "kube-starrocks-read-observer-fe", ""),
+		newRolePod("other-fe-0", "other-fe", srapi.FeRoleLeader),
+	}
+	fc := New(fake.NewFakeClient(srapi.Scheme, objects...), fake.GetEventRecorderFor(nil))
+	fc.QueryFrontends = func(_ context.Context, _ *srapi.StarRocksCluster) ([]sqlclient.Frontend, error) {
+		frontends := newFrontends(0, 2)
+		return append(frontends, sqlclient.Frontend{
+			FQDN:  "kube-starrocks-read-observer-fe-0.kube-starrocks-read-observer-fe-search.default.svc.cluster.local",
+			Role:  sqlclient.FrontendRoleObserver,
+			Alive: true,
+		}), nil
+	}
+
+	require.NoError(t, fc.syncFrontendRoles(context.Background(), src))
+	for name, want := range map[string]string{
+		"kube-starrocks-fe-0":               srapi.FeRoleLeader,
+		"kube-starrocks-fe-1":               srapi.FeRoleFollower,
+		"kube-starrocks-fe-2":               "",
+		"kube-starrocks-read-observer-fe-0": srapi.FeRoleObserver,
+		"other-fe-0":                        srapi.FeRoleLeader,
+	} {
+		var pod corev1.Pod
+		require.NoError(t, fc.Client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, &pod))
+		require.Equal(t, want, pod.Labels[srapi.FeRoleLabelKey], name)
+	}
+}
+
+func TestFeController_syncFrontendRoles_QueryFailed(t *testing.T) {
+	src := newRollingUpdateCluster(nil)
+	fc := New(fake.NewFakeClient(srapi.Scheme, src, newReadyFeEndpoints(),
+		newRolePod("kube-starrocks-fe-0", "kube-starrocks-fe", srapi.FeRoleLeader)), fake.GetEventRecorderFor(nil))
+	fc.QueryFrontends = func(_ context.Context, _ *srapi.StarRocksCluster) ([]sqlclient.Frontend, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	// the labels are kept if SHOW FRONTENDS failed.
+	require.NoError(t, fc.syncFrontendRoles(context.Background(), src))
+	var pod corev1.Pod
+	require.NoError(t, fc.Client.Get(context.Background(),
+		types.NamespacedName{Namespace: "default", Name: "kube-starrocks-fe-0"}, &pod))
+	require.Equal(t, srapi.FeRoleLeader, pod.Labels[srapi.FeRoleLabelKey])
+}
+
+func TestFeController_SyncCluster_LeaderService(t *testing.T) {
+	src := newRollingUpdateCluster(nil)
+	src.Spec.StarRocksFeSpec.EnableLeaderService = true
+	fc := New(fake.NewFakeClient(srapi.Scheme, src), fake.GetEventRecorderFor(nil))
+
+	require.NoError(t, fc.SyncCluster(context.Background(), src))
+	var svc corev1.Service
+	key := types.NamespacedName{Namespace: "default", Name: "kube-starrocks-fe-leader"}
+	require.NoError(t, fc.Client.Get(context.Background(), key, &svc))
+	require.Equal(t, srapi.FeRoleLeader, svc.Spec.Selector[srapi.FeRoleLabelKey])
+	require.Equal(t, "kube-starrocks-fe", svc.Spec.Selector[srapi.OwnerReference])
+
+	src.Spec.StarRocksFeSpec.EnableLeaderService = false
+	require.NoError(t, fc.SyncCluster(context.Background(), src))
+	require.True(t, apierrors.IsNotFound(fc.Client.Get(context.Background(), key, &svc)))
+}
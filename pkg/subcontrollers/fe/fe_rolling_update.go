@@ -0,0 +1,234 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+// FrontendsQuerier executes SHOW FRONTENDS in the cluster. It is injected by the package controllers, so that the
+// FE controller can be tested without a running FE.
+type FrontendsQuerier func(ctx context.Context, src *srapi.StarRocksCluster) ([]sqlclient.Frontend, error)
+
+// leaderAwareRollingUpdate updates the FE pods one by one, and updates the FE leader last.
+// When the pod template is changed, the update strategy of the expected FE statefulset is changed to OnDelete, so
+// the statefulset controller does not update any pod by itself. The operator finds the leader by SHOW FRONTENDS, and
+// deletes the outdated followers one by one, from the largest ordinal to the smallest. A pod is deleted only after the
+// updated pods are ready and alive in SHOW FRONTENDS, and the leader is deleted after all the followers are updated.
+// When all the pods are updated, the update strategy in spec is restored. The pods whose ordinals are smaller than
+// the partition set by the user in updateStrategy are not updated.
+func (fc *FeController) leaderAwareRollingUpdate(ctx context.Context, src *srapi.StarRocksCluster,
+	expect *appsv1.StatefulSet) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	feSpec := src.Spec.StarRocksFeSpec
+	policy := feSpec.RollingUpdatePolicy
+	if policy == nil {
+		policy = &srapi.StarRocksFeRollingUpdatePolicy{}
+	}
+	if fc.QueryFrontends == nil || policy.DisableLeaderAware ||
+		expect.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType ||
+		expect.Spec.UpdateStrategy.RollingUpdate == nil {
+		setRollingUpdateStatus(src, nil)
+		return nil
+	}
+
+	var actual appsv1.StatefulSet
+	if err := fc.Client.Get(ctx, types.NamespacedName{Namespace: expect.Namespace, Name: expect.Name}, &actual); err != nil {
+		if apierrors.IsNotFound(err) {
+			setRollingUpdateStatus(src, nil)
+			return nil
+		}
+		return err
+	}
+
+	var minPartition int32
+	if partition := expect.Spec.UpdateStrategy.RollingUpdate.Partition; partition != nil {
+		minPartition = *partition
+	}
+	replicas := int32(1)
+	if expect.Spec.Replicas != nil {
+		replicas = *expect.Spec.Replicas
+	}
+	// the update strategy in spec is shared with the StarRocksCluster, replace it instead of modifying it.
+	onDelete := func(status *srapi.StarRocksFeRollingUpdateStatus) {
+		expect.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+		setRollingUpdateStatus(src, status)
+	}
+
+	if statefulSetSpecChanged(expect, &actual) {
+		// the statefulset controller computes the new revision after the statefulset is applied, and no pod is
+		// updated until the operator deletes it.
+		logger.Info("fe statefulset is changed, start leader-aware rolling update")
+		onDelete(&srapi.StarRocksFeRollingUpdateStatus{})
+		return nil
+	}
+	if actual.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType {
+		setRollingUpdateStatus(src, nil)
+		return nil
+	}
+	// the revisions in status are stale before the statefulset controller observes the latest spec.
+	if actual.Status.ObservedGeneration < actual.Generation {
+		onDelete(&srapi.StarRocksFeRollingUpdateStatus{})
+		return nil
+	}
+
+	pods, err := fc.getPods(ctx, &actual)
+	if err != nil {
+		return err
+	}
+	var outdated []int32
+	for ordinal := replicas - 1; ordinal >= minPartition; ordinal-- {
+		if pod := pods[ordinal]; pod != nil && pod.Labels[appsv1.StatefulSetRevisionLabel] != actual.Status.UpdateRevision {
+			outdated = append(outdated, ordinal)
+		}
+	}
+	if len(outdated) == 0 && allPodsExist(pods, replicas) {
+		// restore the update strategy, and the statefulset controller marks the rolling update as complete.
+		logger.Info("all the fe pods are updated, finish leader-aware rolling update")
+		setRollingUpdateStatus(src, nil)
+		return nil
+	}
+
+	frontends, err := fc.QueryFrontends(ctx, src)
+	if err != nil {
+		// keep the pods, and the rolling update will be continued in the next reconciliation.
+		logger.Error(err, "query SHOW FRONTENDS failed, hold the rolling update of fe")
+		onDelete(&srapi.StarRocksFeRollingUpdateStatus{})
+		return nil
+	}
+	frontendsByOrdinal := FrontendsOfStatefulSet(frontends, actual.Name)
+	leaderOrdinal := int32(-1)
+	leader := ""
+	for ordinal, frontend := range frontendsByOrdinal {
+		if frontend.Role == sqlclient.FrontendRoleLeader {
+			leaderOrdinal, leader = ordinal, podName(&actual, ordinal)
+		}
+	}
+
+	// wait for the updated pods to be ready and alive, a deleted pod is recreated with the latest revision.
+	for ordinal := replicas - 1; ordinal >= 0; ordinal-- {
+		pod := pods[ordinal]
+		if pod != nil && pod.Labels[appsv1.StatefulSetRevisionLabel] != actual.Status.UpdateRevision {
+			continue
+		}
+		if !isPodUpdatedAndAlive(pod, &actual, frontendsByOrdinal[ordinal]) {
+			logger.Info("wait for the updated fe to rejoin the cluster", "pod", podName(&actual, ordinal))
+			onDelete(&srapi.StarRocksFeRollingUpdateStatus{Updating: podName(&actual, ordinal), Leader: leader})
+			return nil
+		}
+	}
+	// update the followers first, and the leader last.
+	next := outdated[0]
+	for _, ordinal := range outdated {
+		if ordinal != leaderOrdinal {
+			next = ordinal
+			break
+		}
+	}
+	nextPod := pods[next]
+	logger.Info("delete the outdated fe pod to update it", "pod", nextPod.Name, "leader", leader)
+	if err = fc.Client.Delete(ctx, nextPod); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if next == leaderOrdinal {
+		fc.Recorder.Event(src, corev1.EventTypeNormal, "UpdateFeLeader",
+			fmt.Sprintf("delete the FE leader %s to update it after the followers are updated", nextPod.Name))
+	}
+	onDelete(&srapi.StarRocksFeRollingUpdateStatus{Updating: nextPod.Name, Leader: leader})
+	return nil
+}
+
+// statefulSetSpecChanged returns true if the expected statefulset is different from the actual one, without
+// considering the update strategy set by the rolling update.
+func statefulSetSpecChanged(expect, actual *appsv1.StatefulSet) bool {
+	sts := expect.DeepCopy()
+	sts.Spec.UpdateStrategy = *actual.Spec.UpdateStrategy.DeepCopy()
+	// keep the same as k8sutils.ApplyStatefulSet
+	if actual.Spec.PodManagementPolicy == appsv1.OrderedReadyPodManagement {
+		sts.Spec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	}
+	sts.Spec.ServiceName = actual.Spec.ServiceName
+	_, equal := rutils.StatefulSetDeepEqual(sts, actual)
+	return !equal
+}
+
+// allPodsExist returns true if the pods of all the ordinals exist.
+func allPodsExist(pods map[int32]*corev1.Pod, replicas int32) bool {
+	for ordinal := int32(0); ordinal < replicas; ordinal++ {
+		if pods[ordinal] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// getPods returns the pods of the statefulset, the key is the ordinal of pod.
+func (fc *FeController) getPods(ctx context.Context, sts *appsv1.StatefulSet) (map[int32]*corev1.Pod, error) {
+	var podList corev1.PodList
+	if err := fc.Client.List(ctx, &podList, client.InNamespace(sts.Namespace),
+		client.MatchingLabels(sts.Spec.Selector.MatchLabels)); err != nil {
+		return nil, err
+	}
+	pods := make(map[int32]*corev1.Pod)
+	for i := range podList.Items {
+		var ordinal int32
+		if _, err := fmt.Sscanf(podList.Items[i].Name, sts.Name+"-%d", &ordinal); err != nil ||
+			podList.Items[i].Name != podName(sts, ordinal) {
+			continue
+		}
+		pods[ordinal] = &podList.Items[i]
+	}
+	return pods, nil
+}
+
+// isPodUpdatedAndAlive returns true if the pod is updated to the latest revision, it is ready, and the FE in it
+// has rejoined the cluster.
+func isPodUpdatedAndAlive(pod *corev1.Pod, sts *appsv1.StatefulSet, frontend sqlclient.Frontend) bool {
+	return pod != nil && pod.DeletionTimestamp == nil &&
+		pod.Labels[appsv1.StatefulSetRevisionLabel] == sts.Status.UpdateRevision &&
+		k8sutils.PodIsReady(&pod.Status) && frontend.Alive
+}
+
+func podName(sts *appsv1.StatefulSet, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", sts.Name, ordinal)
+}
+
+// setRollingUpdateStatus records the progress of the leader-aware rolling update in the status of FE.
+func setRollingUpdateStatus(src *srapi.StarRocksCluster, status *srapi.StarRocksFeRollingUpdateStatus) {
+	if src.Status.StarRocksFeStatus == nil {
+		if status == nil {
+			return
+		}
+		src.Status.StarRocksFeStatus = &srapi.StarRocksFeStatus{
+			StarRocksComponentStatus: srapi.StarRocksComponentStatus{Phase: srapi.ComponentReconciling},
+		}
+	}
+	src.Status.StarRocksFeStatus.RollingUpdate = status
+}
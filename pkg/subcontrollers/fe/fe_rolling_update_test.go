@@ -0,0 +1,318 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+const (
+	oldRevision = "kube-starrocks-fe-old"
+	newRevision = "kube-starrocks-fe-new"
+)
+
+func newRollingUpdateCluster(policy *srapi.StarRocksFeRollingUpdatePolicy) *srapi.StarRocksCluster {
+	return &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{Replicas: rutils.GetInt32Pointer(3)},
+				},
+				RollingUpdatePolicy: policy,
+			},
+		},
+	}
+}
+
+func newExpectStatefulSet(image string) *appsv1.StatefulSet {
+	labels := map[string]string{"app.kubernetes.io/component": "fe"}
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks-fe", Namespace: "default", Annotations: map[string]string{}},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: rutils.GetInt32Pointer(3),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "fe", Image: image}}},
+			},
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: rutils.GetInt32Pointer(0)},
+			},
+		},
+	}
+}
+
+// newActualStatefulSet returns a statefulset which is being updated from oldRevision to newRevision by the operator.
+func newActualStatefulSet() *appsv1.StatefulSet {
+	sts := newExpectStatefulSet("fe:3.3")
+	sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+	sts.Status = appsv1.StatefulSetStatus{CurrentRevision: oldRevision, UpdateRevision: newRevision}
+	return sts
+}
+
+func newFePod(ordinal int32, revision string, ready bool) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("kube-starrocks-fe-%d", ordinal),
+			Namespace: "default",
+			Labels: map[string]string{
+				"app.kubernetes.io/component":   "fe",
+				appsv1.StatefulSetRevisionLabel: revision,
+			},
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "fe", Ready: ready}}},
+	}
+}
+
+func newFrontends(leader int32, notAlive ...int32) []sqlclient.Frontend {
+	var frontends []sqlclient.Frontend
+	for i := int32(0); i < 3; i++ {
+		frontend := sqlclient.Frontend{
+			FQDN:  fmt.Sprintf("kube-starrocks-fe-%d.kube-starrocks-fe-search.default.svc.cluster.local", i),
+			Role:  sqlclient.FrontendRoleFollower,
+			Alive: true,
+		}
+		if i == leader {
+			frontend.Role = sqlclient.FrontendRoleLeader
+		}
+		for _, ordinal := range notAlive {
+			if ordinal == i {
+				frontend.Alive = false
+			}
+		}
+		frontends = append(frontends, frontend)
+	}
+	return frontends
+}
+
+func newFePods(revisions ...string) []runtime.Object {
+	var pods []runtime.Object
+	for i, revision := range revisions {
+		pods = append(pods, newFePod(int32(i), revision, true))
+	}
+	return pods
+}
+
+func TestFeController_leaderAwareRollingUpdate(t *testing.T) {
+	onDelete := appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+	rollingUpdate := func(partition int32) appsv1.StatefulSetUpdateStrategy {
+		return appsv1.StatefulSetUpdateStrategy{
+			Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+		}
+	}
+	tests := []struct {
+		name         string
+		policy       *srapi.StarRocksFeRollingUpdatePolicy
+		expect       *appsv1.StatefulSet
+		actual       *appsv1.StatefulSet
+		pods         []runtime.Object
+		frontends    []sqlclient.Frontend
+		queryErr     error
+		wantStrategy appsv1.StatefulSetUpdateStrategy
+		wantStatus   *srapi.StarRocksFeRollingUpdateStatus
+		wantDeleted  string
+	}{
+		{
+			name:         "the statefulset does not exist",
+			expect:       newExpectStatefulSet("fe:3.3"),
+			wantStrategy: rollingUpdate(0),
+		},
+		{
+			name:         "update the pods by the operator when the pod template is changed",
+			expect:       newExpectStatefulSet("fe:3.4"),
+			actual:       newExpectStatefulSet("fe:3.3"),
+			wantStrategy: onDelete,
+			wantStatus:   &srapi.StarRocksFeRollingUpdateStatus{},
+		},
+		{
+			name:         "leader-aware rolling update is disabled",
+			policy:       &srapi.StarRocksFeRollingUpdatePolicy{DisableLeaderAware: true},
+			expect:       newExpectStatefulSet("fe:3.4"),
+			actual:       newExpectStatefulSet("fe:3.3"),
+			wantStrategy: rollingUpdate(0),
+		},
+		{
+			name:         "the statefulset is not updated by the operator",
+			expect:       newExpectStatefulSet("fe:3.3"),
+			actual:       newExpectStatefulSet("fe:3.3"),
+			wantStrategy: rollingUpdate(0),
+		},
+		{
+			name:   "wait for the updated fe to be alive",
+			expect: newExpectStatefulSet("fe:3.3"),
+			actual: newActualStatefulSet(),
+			pods: []runtime.Object{newFePod(0, oldRevision, true), newFePod(1, oldRevision, true),
+				newFePod(2, newRevision, true)},
+			frontends:    newFrontends(0, 2),
+			wantStrategy: onDelete,
+			wantStatus:   &srapi.StarRocksFeRollingUpdateStatus{Updating: "kube-starrocks-fe-2", Leader: "kube-starrocks-fe-0"},
+		},
+		{
+			name:   "wait for the updated fe to be ready",
+			expect: newExpectStatefulSet("fe:3.3"),
+			actual: newActualStatefulSet(),
+			pods: []runtime.Object{newFePod(0, oldRevision, true), newFePod(1, oldRevision, true),
+				newFePod(2, newRevision, false)},
+			frontends:    newFrontends(0),
+			wantStrategy: onDelete,
+			wantStatus:   &srapi.StarRocksFeRollingUpdateStatus{Updating: "kube-starrocks-fe-2", Leader: "kube-starrocks-fe-0"},
+		},
+		{
+			name:         "wait for the deleted fe to be recreated",
+			expect:       newExpectStatefulSet("fe:3.3"),
+			actual:       newActualStatefulSet(),
+			pods:         []runtime.Object{newFePod(0, oldRevision, true), newFePod(1, oldRevision, true)},
+			frontends:    newFrontends(0),
+			wantStrategy: onDelete,
+			wantStatus:   &srapi.StarRocksFeRollingUpdateStatus{Updating: "kube-starrocks-fe-2", Leader: "kube-starrocks-fe-0"},
+		},
+		{
+			name:         "hold the rolling update if SHOW FRONTENDS failed",
+			expect:       newExpectStatefulSet("fe:3.3"),
+			actual:       newActualStatefulSet(),
+			pods:         newFePods(oldRevision, oldRevision, oldRevision),
+			queryErr:     errors.New("connection refused"),
+			wantStrategy: onDelete,
+			wantStatus:   &srapi.StarRocksFeRollingUpdateStatus{},
+		},
+		{
+			name:         "update the follower with the largest ordinal first",
+			expect:       newExpectStatefulSet("fe:3.3"),
+			actual:       newActualStatefulSet(),
+			pods:         newFePods(oldRevision, oldRevision, oldRevision),
+			frontends:    newFrontends(0),
+			wantStrategy: onDelete,
+			wantStatus:   &srapi.StarRocksFeRollingUpdateStatus{Updating: "kube-starrocks-fe-2", Leader: "kube-starrocks-fe-0"},
+			wantDeleted:  "kube-starrocks-fe-2",
+		},
+		{
+			name:         "skip the leader with the largest ordinal",
+			expect:       newExpectStatefulSet("fe:3.3"),
+			actual:       newActualStatefulSet(),
+			pods:         newFePods(oldRevision, oldRevision, oldRevision),
+			frontends:    newFrontends(2),
+			wantStrategy: onDelete,
+			wantStatus:   &srapi.StarRocksFeRollingUpdateStatus{Updating: "kube-starrocks-fe-1", Leader: "kube-starrocks-fe-2"},
+			wantDeleted:  "kube-starrocks-fe-1",
+		},
+		{
+			name:         "update the followers with smaller ordinals before the leader",
+			expect:       newExpectStatefulSet("fe:3.3"),
+			actual:       newActualStatefulSet(),
+			pods:         newFePods(oldRevision, oldRevision, newRevision),
+			frontends:    newFrontends(1),
+			wantStrategy: onDelete,
+			wantStatus:   &srapi.StarRocksFeRollingUpdateStatus{Updating: "kube-starrocks-fe-0", Leader: "kube-starrocks-fe-1"},
+			wantDeleted:  "kube-starrocks-fe-0",
+		},
+		{
+			name:         "update the leader last",
+			expect:       newExpectStatefulSet("fe:3.3"),
+			actual:       newActualStatefulSet(),
+			pods:         newFePods(newRevision, oldRevision, newRevision),
+			frontends:    newFrontends(1),
+			wantStrategy: onDelete,
+			wantStatus:   &srapi.StarRocksFeRollingUpdateStatus{Updating: "kube-starrocks-fe-1", Leader: "kube-starrocks-fe-1"},
+			wantDeleted:  "kube-starrocks-fe-1",
+		},
+		{
+			name: "the pods whose ordinals are smaller than the partition are not updated",
+			expect: func() *appsv1.StatefulSet {
+				sts := newExpectStatefulSet("fe:3.3")
+				sts.Spec.UpdateStrategy = rollingUpdate(1)
+				return sts
+			}(),
+			actual:       newActualStatefulSet(),
+			pods:         newFePods(oldRevision, newRevision, newRevision),
+			frontends:    newFrontends(0),
+			wantStrategy: rollingUpdate(1),
+		},
+		{
+			name:         "the rolling update is finished",
+			expect:       newExpectStatefulSet("fe:3.3"),
+			actual:       newActualStatefulSet(),
+			pods:         newFePods(newRevision, newRevision, newRevision),
+			wantStrategy: rollingUpdate(0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newRollingUpdateCluster(tt.policy)
+			objects := append([]runtime.Object{src}, tt.pods...)
+			if tt.actual != nil {
+				objects = append(objects, tt.actual)
+			}
+			fc := New(fake.NewFakeClient(srapi.Scheme, objects...), fake.GetEventRecorderFor(nil))
+			fc.QueryFrontends = func(_ context.Context, _ *srapi.StarRocksCluster) ([]sqlclient.Frontend, error) {
+				return tt.frontends, tt.queryErr
+			}
+
+			require.NoError(t, fc.leaderAwareRollingUpdate(context.Background(), src, tt.expect))
+			require.Equal(t, tt.wantStrategy, tt.expect.Spec.UpdateStrategy)
+			if tt.wantStatus == nil {
+				require.True(t, src.Status.StarRocksFeStatus == nil || src.Status.StarRocksFeStatus.RollingUpdate == nil)
+			} else {
+				require.Equal(t, tt.wantStatus, src.Status.StarRocksFeStatus.RollingUpdate)
+			}
+
+			var pods corev1.PodList
+			require.NoError(t, fc.Client.List(context.Background(), &pods))
+			wantPodNum := len(tt.pods)
+			if tt.wantDeleted != "" {
+				wantPodNum--
+				var pod corev1.Pod
+				err := fc.Client.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: tt.wantDeleted}, &pod)
+				require.True(t, apierrors.IsNotFound(err))
+			}
+			require.Len(t, pods.Items, wantPodNum)
+		})
+	}
+}
+
+func TestFeController_leaderAwareRollingUpdate_KeepSpec(t *testing.T) {
+	src := newRollingUpdateCluster(nil)
+	expect := newExpectStatefulSet("fe:3.4")
+	rollingUpdate := expect.Spec.UpdateStrategy.RollingUpdate
+	fc := New(fake.NewFakeClient(srapi.Scheme, src, newExpectStatefulSet("fe:3.3")), fake.GetEventRecorderFor(nil))
+	fc.QueryFrontends = func(_ context.Context, _ *srapi.StarRocksCluster) ([]sqlclient.Frontend, error) {
+		return nil, nil
+	}
+
+	require.NoError(t, fc.leaderAwareRollingUpdate(context.Background(), src, expect))
+	require.Equal(t, appsv1.OnDeleteStatefulSetStrategyType, expect.Spec.UpdateStrategy.Type)
+	// the update strategy shared with the spec of StarRocksCluster is not changed.
+	require.Equal(t, int32(0), *rollingUpdate.Partition)
+}
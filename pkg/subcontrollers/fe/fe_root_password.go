@@ -0,0 +1,87 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fe
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+)
+
+const (
+	// RootPasswordKey is the key of the password in the secret which records the applied root password.
+	RootPasswordKey = "password"
+
+	rootPasswordEnvName = "MYSQL_PWD"
+)
+
+// RootPasswordSecretName returns the name of secret which records the root password applied by the operator.
+func RootPasswordSecretName(clusterName string) string {
+	return clusterName + "-root-password"
+}
+
+// GetRootPassword returns the password of the root user, all the SQL statements executed by the operator use it.
+//  1. If spec.rootPasswordSecretRef is set and the operator has applied the password, the applied one is returned.
+//     It is not read from spec.rootPasswordSecretRef directly, because the password in StarRocks is not changed yet
+//     when the secret is changed.
+//  2. Otherwise, the password is from the environment variable MYSQL_PWD of FE, it is empty if it is not set.
+func GetRootPassword(ctx context.Context, k8sClient client.Client, src *v1.StarRocksCluster) (string, error) {
+	if src.Spec.RootPasswordSecretRef != nil {
+		var secret corev1.Secret
+		err := k8sClient.Get(ctx, types.NamespacedName{Namespace: src.Namespace, Name: RootPasswordSecretName(src.Name)}, &secret)
+		if err == nil {
+			return string(secret.Data[RootPasswordKey]), nil
+		} else if !apierrors.IsNotFound(err) {
+			return "", err
+		}
+	}
+
+	if src.Spec.StarRocksFeSpec == nil {
+		return "", nil
+	}
+	for _, envVar := range src.Spec.StarRocksFeSpec.FeEnvVars {
+		if envVar.Name == rootPasswordEnvName {
+			password, err := k8sutils.GetEnvVarValue(ctx, k8sClient, src.Namespace, envVar)
+			if err != nil {
+				logr.FromContextOrDiscard(ctx).Error(err, "failed to get MYSQL_PWD from env vars, use the default password: empty string")
+			}
+			return password, nil
+		}
+	}
+	return "", nil
+}
+
+// SaveRootPassword records the root password which has been applied to StarRocks.
+func SaveRootPassword(ctx context.Context, k8sClient client.Client, src *v1.StarRocksCluster, password string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            RootPasswordSecretName(src.Name),
+			Namespace:       src.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(src, src.GroupVersionKind())},
+		},
+		Data: map[string][]byte{RootPasswordKey: []byte(password)},
+	}
+	return k8sutils.ApplySecret(ctx, k8sClient, secret)
+}
@@ -8,13 +8,17 @@ import (
 
 	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
 )
 
-// GetFEConfig get the fe config from configMap.
+// GetFEConfig get the fe config from spec.config or configMap.
 // It is not a method of FeController, but BE/CN controller also need to get the config from configMap.
 func GetFEConfig(ctx context.Context, client client.Client,
 	feSpec *srapi.StarRocksFeSpec, namespace string) (map[string]interface{}, error) {
+	if srconfig.IsManaged(feSpec) {
+		return srconfig.Resolve(feSpec)
+	}
 	return k8sutils.GetConfig(ctx, client, feSpec.ConfigMapInfo,
 		feSpec.ConfigMaps, pod.GetConfigDir(feSpec), "fe.conf",
 		namespace)
@@ -0,0 +1,402 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feobserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cmdconfig "github.com/StarRocks/starrocks-kubernetes-operator/cmd/config"
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/log"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/load"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/service"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+// FeObserverController manages the groups of FE observers. Every group is deployed as a separate statefulset, the
+// observers are added to the cluster by `ALTER SYSTEM ADD OBSERVER` before they are started, and are dropped by
+// `ALTER SYSTEM DROP OBSERVER` after they are removed by scale-in.
+type FeObserverController struct {
+	k8sClient client.Client
+	Recorder  record.EventRecorder
+}
+
+var _ subcontrollers.ClusterSubController = &FeObserverController{}
+
+// New construct a FeObserverController.
+func New(k8sClient client.Client, recorderFor subcontrollers.GetEventRecorderForFunc) *FeObserverController {
+	controller := &FeObserverController{
+		k8sClient: k8sClient,
+	}
+	controller.Recorder = recorderFor(controller.GetControllerName())
+	return controller
+}
+
+func (controller *FeObserverController) GetControllerName() string {
+	return "feObserverController"
+}
+
+// SyncCluster syncs the observer groups of starRocksCluster to statefulsets and services.
+func (controller *FeObserverController) SyncCluster(ctx context.Context, src *srapi.StarRocksCluster) error {
+	logger := logr.FromContextOrDiscard(ctx).WithName(controller.GetControllerName()).WithValues(log.ActionKey, log.ActionSyncCluster)
+	ctx = logr.NewContext(ctx, logger)
+	if src.Spec.StarRocksFeSpec == nil {
+		logger.Info("src.Spec.StarRocksFeSpec == nil, skip sync fe observers")
+		return nil
+	}
+
+	err := controller.syncObserverGroups(ctx, src, nil)
+	if err != nil {
+		controller.Recorder.Event(src, corev1.EventTypeWarning, "SyncFeObserverFailed", err.Error())
+	}
+	return err
+}
+
+// syncObserverGroups syncs all the observer groups, and removes the groups which are deleted from the spec.
+// db is used to inject a mocked database in tests, it should be nil in other cases.
+func (controller *FeObserverController) syncObserverGroups(ctx context.Context, src *srapi.StarRocksCluster, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	feSpec := src.Spec.StarRocksFeSpec
+
+	expectGroups := make(map[string]bool)
+	for _, group := range feSpec.ObserverGroups {
+		expectGroups[group.Name] = true
+	}
+	var removedGroups []string
+	for _, groupStatus := range src.Status.StarRocksFeObserverStatus {
+		if !expectGroups[groupStatus.Name] {
+			removedGroups = append(removedGroups, groupStatus.Name)
+		}
+	}
+	if len(feSpec.ObserverGroups) == 0 && len(removedGroups) == 0 {
+		return nil
+	}
+
+	// The metadata of FE is being recovered, the observers will be added after the disaster recovery is done.
+	if drStatus := src.Status.DisasterRecoveryStatus; drStatus != nil && !drStatus.Phase.IsFinished() {
+		logger.Info("disaster recovery is in progress, skip sync fe observers")
+		return nil
+	}
+	if !fe.CheckFEReady(ctx, controller.k8sClient, src.Namespace, src.Name) {
+		logger.Info("FE is not ready, skip sync fe observers")
+		return nil
+	}
+
+	feConfig, err := fe.GetFEConfig(ctx, controller.k8sClient, feSpec, src.Namespace)
+	if err != nil {
+		logger.Error(err, "get fe config failed", "ConfigMapInfo", feSpec.ConfigMapInfo)
+		return err
+	}
+	sqlClient, err := fe.NewSQLClient(ctx, controller.k8sClient, src)
+	if err != nil {
+		logger.Error(err, "new SQL client failed")
+		return err
+	}
+	frontends, err := sqlClient.ShowFrontends(ctx, db)
+	if err != nil {
+		logger.Error(err, "query SHOW FRONTENDS failed", "sql", sqlclient.ShowFrontendsStatement)
+		return err
+	}
+
+	for _, groupName := range removedGroups {
+		if err = controller.removeObserverGroup(ctx, src, groupName, frontends, sqlClient, db); err != nil {
+			logger.Error(err, "remove observer group failed", "group", groupName)
+			return err
+		}
+	}
+
+	for i := range feSpec.ObserverGroups {
+		group := &feSpec.ObserverGroups[i]
+		if err = controller.syncObserverGroup(ctx, src, group, feConfig, frontends, sqlClient, db); err != nil {
+			logger.Error(err, "sync observer group failed", "group", group.Name)
+			return err
+		}
+	}
+	return nil
+}
+
+// syncObserverGroup adds the observers of the group, deploys the statefulset and services, and drops the observers
+// which are removed by scale-in.
+func (controller *FeObserverController) syncObserverGroup(ctx context.Context, src *srapi.StarRocksCluster,
+	group *srapi.StarRocksFeObserverGroup, feConfig map[string]interface{}, frontends []sqlclient.Frontend,
+	sqlClient *sqlclient.Client, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx).WithValues("group", group.Name)
+
+	object := object.NewFromObserverGroup(src, group.Name)
+	observerSpec := fe.ObserverGroupSpec(src.Spec.StarRocksFeSpec, group)
+	referenceHashes, err := subcontrollers.ReferenceHashes(ctx, controller.k8sClient, src.Namespace,
+		&observerSpec.StarRocksComponentSpec)
+	if err != nil {
+		logger.Error(err, "get the hashes of referenced configMaps and secrets failed")
+		return err
+	}
+	expectSTS, err := fe.BuildObserverStatefulSet(object, observerSpec, feConfig, referenceHashes)
+	if err != nil {
+		logger.Error(err, "build observer statefulset failed")
+		return err
+	}
+	if srconfig.IsManaged(observerSpec) {
+		// the observers share the config of FE, and the changed config is applied to them together with the other
+		// frontends, so they are restarted only if FE is restarted, see subcontrollers.SyncDynamicConfig.
+		var configHash string
+		configHash, err = subcontrollers.ConfigHash(ctx, controller.k8sClient, src.Namespace,
+			srconfig.ConfigMapName(src.Name, observerSpec))
+		if err != nil {
+			logger.Error(err, "get the config hash of fe failed")
+			return err
+		}
+		if configHash != "" {
+			expectSTS.Spec.Template.Annotations[srapi.ConfigHashAnnotation] = configHash
+		}
+	}
+
+	svc := rutils.BuildExternalService(object, observerSpec, feConfig,
+		load.Selector(object.SubResourcePrefixName, observerSpec), load.Labels(object.SubResourcePrefixName, observerSpec))
+	searchService := service.MakeSearchService(service.SearchServiceName(object.SubResourcePrefixName, observerSpec), &svc,
+		[]corev1.ServicePort{
+			{
+				Name:        "query-port",
+				Port:        rutils.GetPort(feConfig, rutils.QUERY_PORT),
+				TargetPort:  intstr.FromInt(int(rutils.GetPort(feConfig, rutils.QUERY_PORT))),
+				AppProtocol: func() *string { mysql := "mysql"; return &mysql }(),
+			},
+		}, load.Labels(object.SubResourcePrefixName, observerSpec))
+	if err = k8sutils.ApplyService(ctx, controller.k8sClient, searchService, rutils.ServiceDeepEqual); err != nil {
+		logger.Error(err, "deploy observer search service failed", "searchService", searchService.Name)
+		return err
+	}
+	if err = k8sutils.ApplyService(ctx, controller.k8sClient, &svc, rutils.ServiceDeepEqual); err != nil {
+		logger.Error(err, "deploy observer external service failed", "externalService", svc.Name)
+		return err
+	}
+
+	replicas := int32(1)
+	if expectSTS.Spec.Replicas != nil {
+		replicas = *expectSTS.Spec.Replicas
+	}
+	groupStatus := getOrCreateGroupStatus(src, group.Name)
+	groupStatus.Observers = nil
+
+	// the observers must be added before they are started, otherwise they can not join the cluster by the helper.
+	observers := observersOfStatefulSet(frontends, expectSTS.Name)
+	for ordinal := int32(0); ordinal < replicas; ordinal++ {
+		fqdn := observerFQDN(&expectSTS, ordinal)
+		if _, ok := observers[ordinal]; !ok {
+			logger.Info("add observer", "observer", fqdn)
+			if err = sqlClient.AddObserver(ctx, db, fqdn, rutils.GetPort(feConfig, rutils.EDIT_LOG_PORT)); err != nil {
+				logger.Error(err, "add observer failed", "observer", fqdn)
+				return err
+			}
+			controller.Recorder.Event(src, corev1.EventTypeNormal, "AddFeObserver", fmt.Sprintf("add observer %s", fqdn))
+		}
+		groupStatus.Observers = append(groupStatus.Observers, fqdn)
+	}
+
+	// the observers are kept in FE while the cluster is suspended, so that they can rejoin after they are resumed.
+	if src.IsComponentSuspended(srapi.FeObserverComponent) {
+		logger.Info("the cluster is suspended, scale observer group to zero")
+		expectSTS.Spec.Replicas = rutils.GetInt32Pointer(0)
+	}
+	if err = subcontrollers.ExpandPersistentVolumeClaims(ctx, controller.k8sClient, controller.Recorder, src,
+		&expectSTS, &groupStatus.StarRocksComponentStatus); err != nil {
+		logger.Error(err, "expand persistent volume claims failed")
+		return err
+	}
+	if err = k8sutils.ApplyStatefulSet(ctx, controller.k8sClient, &expectSTS, true, rutils.StatefulSetDeepEqual); err != nil {
+		logger.Error(err, "deploy observer statefulset failed")
+		return err
+	}
+
+	return controller.dropObservers(ctx, src, observers, replicas, sqlClient, db)
+}
+
+// removeObserverGroup drops all the observers of a group which is deleted from the spec, and deletes its statefulset
+// and services.
+func (controller *FeObserverController) removeObserverGroup(ctx context.Context, src *srapi.StarRocksCluster,
+	groupName string, frontends []sqlclient.Frontend, sqlClient *sqlclient.Client, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx).WithValues("group", groupName)
+	logger.Info("remove observer group")
+
+	prefixName := object.GetPrefixNameForObserverGroup(src.Name, groupName)
+	feSpec := src.Spec.StarRocksFeSpec
+	if err := controller.deleteObserverGroupResources(ctx, src.Namespace, prefixName); err != nil {
+		return err
+	}
+	observers := observersOfStatefulSet(frontends, load.Name(prefixName, feSpec))
+	if err := controller.dropObservers(ctx, src, observers, 0, sqlClient, db); err != nil {
+		return err
+	}
+
+	var statuses []srapi.StarRocksFeObserverGroupStatus
+	for _, groupStatus := range src.Status.StarRocksFeObserverStatus {
+		if groupStatus.Name != groupName {
+			statuses = append(statuses, groupStatus)
+		}
+	}
+	src.Status.StarRocksFeObserverStatus = statuses
+	return nil
+}
+
+// dropObservers drops the observers whose ordinal is not less than replicas.
+func (controller *FeObserverController) dropObservers(ctx context.Context, src *srapi.StarRocksCluster,
+	observers map[int32]sqlclient.Frontend, replicas int32, sqlClient *sqlclient.Client, db *sql.DB) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	ordinals := make([]int32, 0, len(observers))
+	for ordinal := range observers {
+		if ordinal >= replicas {
+			ordinals = append(ordinals, ordinal)
+		}
+	}
+	sort.Slice(ordinals, func(i, j int) bool { return ordinals[i] > ordinals[j] })
+
+	for _, ordinal := range ordinals {
+		observer := observers[ordinal]
+		logger.Info("drop observer", "observer", observer.FQDN)
+		if err := sqlClient.DropObserver(ctx, db, observer); err != nil {
+			logger.Error(err, "drop observer failed", "observer", observer.FQDN)
+			return err
+		}
+		controller.Recorder.Event(src, corev1.EventTypeNormal, "DropFeObserver", fmt.Sprintf("drop observer %s", observer.FQDN))
+	}
+	return nil
+}
+
+// UpdateClusterStatus updates the status of every observer group.
+func (controller *FeObserverController) UpdateClusterStatus(ctx context.Context, src *srapi.StarRocksCluster) error {
+	feSpec := src.Spec.StarRocksFeSpec
+	if feSpec == nil {
+		src.Status.StarRocksFeObserverStatus = nil
+		return nil
+	}
+
+	var statuses []srapi.StarRocksFeObserverGroupStatus
+	expectGroups := make(map[string]bool)
+	for i := range feSpec.ObserverGroups {
+		group := &feSpec.ObserverGroups[i]
+		expectGroups[group.Name] = true
+		groupStatus := getOrCreateGroupStatus(src, group.Name).DeepCopy()
+
+		prefixName := object.GetPrefixNameForObserverGroup(src.Name, group.Name)
+		statefulSetName := load.Name(prefixName, feSpec)
+		groupStatus.ServiceName = service.ExternalServiceName(prefixName, feSpec)
+		groupStatus.ResourceNames = rutils.MergeSlices(groupStatus.ResourceNames, []string{statefulSetName})
+
+		var sts appsv1.StatefulSet
+		err := controller.k8sClient.Get(ctx, types.NamespacedName{Namespace: src.Namespace, Name: statefulSetName}, &sts)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if apierrors.IsNotFound(err) {
+			// the statefulset is created after FE is ready.
+			groupStatus.Phase = srapi.ComponentReconciling
+			groupStatus.Reason = "waiting for FE to be ready"
+		} else if err = subcontrollers.UpdateStatus(&groupStatus.StarRocksComponentStatus, controller.k8sClient,
+			src.Namespace, statefulSetName, pod.Labels(prefixName, feSpec), subcontrollers.StatefulSetLoadType); err != nil {
+			return err
+		}
+		statuses = append(statuses, *groupStatus)
+	}
+	// the groups deleted from the spec are kept until their observers are dropped by SyncCluster.
+	for _, groupStatus := range src.Status.StarRocksFeObserverStatus {
+		if !expectGroups[groupStatus.Name] {
+			statuses = append(statuses, groupStatus)
+		}
+	}
+	src.Status.StarRocksFeObserverStatus = statuses
+	return nil
+}
+
+// ClearCluster clears the resources of observer groups when the starRocksCluster is being deleted.
+func (controller *FeObserverController) ClearCluster(ctx context.Context, src *srapi.StarRocksCluster) error {
+	logger := logr.FromContextOrDiscard(ctx).WithName(controller.GetControllerName()).WithValues(log.ActionKey, log.ActionCluster)
+	ctx = logr.NewContext(ctx, logger)
+
+	if src.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	for _, groupStatus := range src.Status.StarRocksFeObserverStatus {
+		prefixName := object.GetPrefixNameForObserverGroup(src.Name, groupStatus.Name)
+		if err := controller.deleteObserverGroupResources(ctx, src.Namespace, prefixName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (controller *FeObserverController) deleteObserverGroupResources(ctx context.Context, namespace string, prefixName string) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	feSpec := (*srapi.StarRocksFeSpec)(nil)
+
+	statefulSetName := load.Name(prefixName, feSpec)
+	if err := k8sutils.DeleteStatefulset(ctx, controller.k8sClient, namespace, statefulSetName); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "delete statefulset failed", "statefulsetName", statefulSetName)
+		return err
+	}
+	searchServiceName := service.SearchServiceName(prefixName, feSpec)
+	if err := k8sutils.DeleteService(ctx, controller.k8sClient, namespace, searchServiceName); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "delete search service failed", "searchServiceName", searchServiceName)
+		return err
+	}
+	externalServiceName := service.ExternalServiceName(prefixName, feSpec)
+	if err := k8sutils.DeleteService(ctx, controller.k8sClient, namespace, externalServiceName); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "delete external service failed", "externalServiceName", externalServiceName)
+		return err
+	}
+	return nil
+}
+
+// getOrCreateGroupStatus returns the status of the observer group, it is created if it does not exist.
+func getOrCreateGroupStatus(src *srapi.StarRocksCluster, groupName string) *srapi.StarRocksFeObserverGroupStatus {
+	for i := range src.Status.StarRocksFeObserverStatus {
+		if src.Status.StarRocksFeObserverStatus[i].Name == groupName {
+			return &src.Status.StarRocksFeObserverStatus[i]
+		}
+	}
+	src.Status.StarRocksFeObserverStatus = append(src.Status.StarRocksFeObserverStatus, srapi.StarRocksFeObserverGroupStatus{
+		Name: groupName,
+		StarRocksComponentStatus: srapi.StarRocksComponentStatus{
+			Phase: srapi.ComponentReconciling,
+		},
+	})
+	return &src.Status.StarRocksFeObserverStatus[len(src.Status.StarRocksFeObserverStatus)-1]
+}
+
+// observerFQDN returns the FQDN of the observer with the given ordinal, the observer is added to the cluster by it.
+func observerFQDN(sts *appsv1.StatefulSet, ordinal int32) string {
+	return fmt.Sprintf("%s-%d.%s.%s.%s", sts.Name, ordinal, sts.Spec.ServiceName, sts.Namespace,
+		cmdconfig.GetServiceDomainSuffix())
+}
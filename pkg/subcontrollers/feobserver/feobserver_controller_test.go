@@ -0,0 +1,219 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feobserver
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmdconfig "github.com/StarRocks/starrocks-kubernetes-operator/cmd/config"
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+func TestMain(m *testing.M) {
+	srapi.Register()
+	cmdconfig.DNSDomainSuffix = "cluster.local"
+	os.Exit(m.Run())
+}
+
+func newCluster(groups ...srapi.StarRocksFeObserverGroup) *srapi.StarRocksCluster {
+	return &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kube-starrocks",
+			Namespace: "default",
+		},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+						Image:    "starrocks/fe-ubuntu:latest",
+						Replicas: rutils.GetInt32Pointer(3),
+					},
+				},
+				ObserverGroups: groups,
+			},
+		},
+	}
+}
+
+// readyFeObjects returns the objects which make FE ready, and make the SQL executor could be created.
+func readyFeObjects() []runtime.Object {
+	return []runtime.Object{
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks-fe", Namespace: "default"},
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "fe"}}},
+				},
+			},
+		},
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks-fe-service", Namespace: "default"},
+			Subsets: []corev1.EndpointSubset{
+				{Addresses: []corev1.EndpointAddress{{IP: "127.0.0.1"}}},
+			},
+		},
+	}
+}
+
+func showFrontendsRows(observers ...string) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"Name", "IP", "EditLogPort", "Role", "Alive"}).
+		AddRow([]byte("fe-0"), []byte("kube-starrocks-fe-0.kube-starrocks-fe-search.default.svc.cluster.local"),
+			[]byte("9010"), []byte("LEADER"), []byte("true"))
+	for _, observer := range observers {
+		rows.AddRow([]byte(observer), []byte(observer), []byte("9010"), []byte(sqlclient.FrontendRoleObserver), []byte("true"))
+	}
+	return rows
+}
+
+func TestFeObserverController_syncObserverGroups(t *testing.T) {
+	observer0 := "kube-starrocks-read-observer-fe-0.kube-starrocks-read-observer-fe-search.default.svc.cluster.local"
+	observer1 := "kube-starrocks-read-observer-fe-1.kube-starrocks-read-observer-fe-search.default.svc.cluster.local"
+	oldObserver := "kube-starrocks-old-observer-fe-0.kube-starrocks-old-observer-fe-search.default.svc.cluster.local"
+
+	tests := []struct {
+		name          string
+		src           *srapi.StarRocksCluster
+		mockSQL       func(mock sqlmock.Sqlmock)
+		wantReplicas  int32
+		wantObservers []string
+		wantGroups    []string
+	}{
+		{
+			name: "add observers of a new group",
+			src:  newCluster(srapi.StarRocksFeObserverGroup{Name: "read", Replicas: rutils.GetInt32Pointer(2)}),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(sqlclient.ShowFrontendsStatement).WillReturnRows(showFrontendsRows())
+				mock.ExpectExec(`ALTER SYSTEM ADD OBSERVER "` + observer0 + `:9010"`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`ALTER SYSTEM ADD OBSERVER "` + observer1 + `:9010"`).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantReplicas:  2,
+			wantObservers: []string{observer0, observer1},
+			wantGroups:    []string{"read"},
+		},
+		{
+			name: "drop observers removed by scale-in",
+			src:  newCluster(srapi.StarRocksFeObserverGroup{Name: "read", Replicas: rutils.GetInt32Pointer(1)}),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(sqlclient.ShowFrontendsStatement).WillReturnRows(showFrontendsRows(observer0, observer1))
+				mock.ExpectExec(`ALTER SYSTEM DROP OBSERVER "` + observer1 + `:9010"`).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantReplicas:  1,
+			wantObservers: []string{observer0},
+			wantGroups:    []string{"read"},
+		},
+		{
+			name: "drop observers of a removed group",
+			src: func() *srapi.StarRocksCluster {
+				src := newCluster(srapi.StarRocksFeObserverGroup{Name: "read", Replicas: rutils.GetInt32Pointer(1)})
+				src.Status.StarRocksFeObserverStatus = []srapi.StarRocksFeObserverGroupStatus{{Name: "old"}}
+				return src
+			}(),
+			mockSQL: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(sqlclient.ShowFrontendsStatement).WillReturnRows(showFrontendsRows(observer0, oldObserver))
+				mock.ExpectExec(`ALTER SYSTEM DROP OBSERVER "` + oldObserver + `:9010"`).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantReplicas:  1,
+			wantObservers: []string{observer0},
+			wantGroups:    []string{"read"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+			tt.mockSQL(mock)
+
+			k8sClient := fake.NewFakeClient(srapi.Scheme, readyFeObjects()...)
+			controller := New(k8sClient, fake.GetEventRecorderFor(nil))
+			require.NoError(t, controller.syncObserverGroups(context.Background(), tt.src, db))
+			require.NoError(t, mock.ExpectationsWereMet())
+
+			var sts appsv1.StatefulSet
+			require.NoError(t, k8sClient.Get(context.Background(),
+				types.NamespacedName{Namespace: "default", Name: "kube-starrocks-read-observer-fe"}, &sts))
+			assert.Equal(t, tt.wantReplicas, *sts.Spec.Replicas)
+			assert.Equal(t, "kube-starrocks-read-observer-fe-search", sts.Spec.ServiceName)
+			assert.Equal(t, "kube-starrocks-read-observer-fe", sts.Spec.Selector.MatchLabels[srapi.OwnerReference])
+			assert.Equal(t, []string{"/opt/starrocks/fe_entrypoint.sh"}, sts.Spec.Template.Spec.Containers[0].Command)
+			assert.Contains(t, sts.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: srapi.FE_ROLE, Value: "OBSERVER"})
+
+			var svc corev1.Service
+			require.NoError(t, k8sClient.Get(context.Background(),
+				types.NamespacedName{Namespace: "default", Name: "kube-starrocks-read-observer-fe-service"}, &svc))
+			assert.Equal(t, corev1.ServiceTypeClusterIP, svc.Spec.Type)
+			require.NoError(t, k8sClient.Get(context.Background(),
+				types.NamespacedName{Namespace: "default", Name: "kube-starrocks-read-observer-fe-search"}, &svc))
+
+			var groups []string
+			for _, groupStatus := range tt.src.Status.StarRocksFeObserverStatus {
+				groups = append(groups, groupStatus.Name)
+			}
+			assert.Equal(t, tt.wantGroups, groups)
+			assert.Equal(t, tt.wantObservers, tt.src.Status.StarRocksFeObserverStatus[0].Observers)
+		})
+	}
+}
+
+func TestFeObserverController_syncObserverGroups_FeNotReady(t *testing.T) {
+	k8sClient := fake.NewFakeClient(srapi.Scheme)
+	controller := New(k8sClient, fake.GetEventRecorderFor(nil))
+	src := newCluster(srapi.StarRocksFeObserverGroup{Name: "read"})
+	// no SQL statements are expected, the nil database will panic if any SQL statement is executed.
+	require.NoError(t, controller.syncObserverGroups(context.Background(), src, nil))
+
+	var sts appsv1.StatefulSet
+	err := k8sClient.Get(context.Background(),
+		types.NamespacedName{Namespace: "default", Name: "kube-starrocks-read-observer-fe"}, &sts)
+	assert.True(t, err != nil)
+}
+
+func TestFeObserverController_UpdateClusterStatus(t *testing.T) {
+	k8sClient := fake.NewFakeClient(srapi.Scheme)
+	controller := New(k8sClient, fake.GetEventRecorderFor(nil))
+	src := newCluster(srapi.StarRocksFeObserverGroup{Name: "read"})
+	require.NoError(t, controller.UpdateClusterStatus(context.Background(), src))
+	require.Len(t, src.Status.StarRocksFeObserverStatus, 1)
+	groupStatus := src.Status.StarRocksFeObserverStatus[0]
+	assert.Equal(t, "read", groupStatus.Name)
+	assert.Equal(t, srapi.ComponentReconciling, groupStatus.Phase)
+	assert.Equal(t, "kube-starrocks-read-observer-fe-service", groupStatus.ServiceName)
+	assert.Equal(t, []string{"kube-starrocks-read-observer-fe"}, groupStatus.ResourceNames)
+
+	// the status of a deleted group is kept until its observers are dropped.
+	src.Spec.StarRocksFeSpec.ObserverGroups = nil
+	require.NoError(t, controller.UpdateClusterStatus(context.Background(), src))
+	assert.Len(t, src.Status.StarRocksFeObserverStatus, 1)
+
+	src.Spec.StarRocksFeSpec = nil
+	require.NoError(t, controller.UpdateClusterStatus(context.Background(), src))
+	assert.Nil(t, src.Status.StarRocksFeObserverStatus)
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feobserver
+
+import (
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+// observersOfStatefulSet returns the observers whose pods belong to the statefulset, the key is the ordinal of pod.
+// The FQDN of an observer looks like: kube-starrocks-read-observer-fe-1.kube-starrocks-read-observer-fe-search.default.svc.cluster.local
+func observersOfStatefulSet(frontends []sqlclient.Frontend, stsName string) map[int32]sqlclient.Frontend {
+	observers := make(map[int32]sqlclient.Frontend)
+	for ordinal, frontend := range fe.FrontendsOfStatefulSet(frontends, stsName) {
+		if frontend.Role == sqlclient.FrontendRoleObserver {
+			observers[ordinal] = frontend
+		}
+	}
+	return observers
+}
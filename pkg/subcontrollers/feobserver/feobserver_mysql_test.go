@@ -0,0 +1,37 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feobserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/starrocks/sqlclient"
+)
+
+func Test_observersOfStatefulSet(t *testing.T) {
+	frontends := []sqlclient.Frontend{
+		{FQDN: "kube-starrocks-fe-0.kube-starrocks-fe-search", Role: "LEADER"},
+		{FQDN: "kube-starrocks-read-observer-fe-0.kube-starrocks-read-observer-fe-search", Role: sqlclient.FrontendRoleObserver},
+		{FQDN: "kube-starrocks-read-observer-fe-10.kube-starrocks-read-observer-fe-search", Role: sqlclient.FrontendRoleObserver},
+		{FQDN: "kube-starrocks-read2-observer-fe-1.kube-starrocks-read2-observer-fe-search", Role: sqlclient.FrontendRoleObserver},
+		{FQDN: "kube-starrocks-read-observer-fe-x.kube-starrocks-read-observer-fe-search", Role: sqlclient.FrontendRoleObserver},
+	}
+	got := observersOfStatefulSet(frontends, "kube-starrocks-read-observer-fe")
+	assert.Equal(t, map[int32]sqlclient.Frontend{0: frontends[1], 10: frontends[2]}, got)
+}
@@ -28,6 +28,10 @@ func (controller *FeProxyController) SyncConfigMap(ctx context.Context, src *sra
 
 	feSearchServiceName := service.SearchServiceName(src.Name, feSpec)
 	feExternalServiceName := service.ExternalServiceName(src.Name, feSpec)
+	if feSpec.EnableLeaderService {
+		// send the requests to the leader directly, e.g. stream load will not be redirected by the followers.
+		feExternalServiceName = service.LeaderServiceName(src.Name)
+	}
 	proxyPass := fmt.Sprintf("http://%s.%s.%s:%d", feExternalServiceName, src.GetNamespace(), cmdconfig.GetServiceDomainSuffix(), httpPort)
 
 	resolver := feProxySpec.Resolver
@@ -98,6 +98,10 @@ func (controller *FeProxyController) SyncCluster(ctx context.Context, src *srapi
 
 	podTemplate := controller.buildPodTemplate(src)
 	expectDeployment := deployment.MakeDeployment(src, feProxySpec, podTemplate)
+	if src.IsComponentSuspended(srapi.DEFAULT_FE_PROXY) {
+		logger.Info("the cluster is suspended, scale fe proxy to zero")
+		expectDeployment.Spec.Replicas = rutils.GetInt32Pointer(0)
+	}
 	err = k8sutils.ApplyDeployment(ctx, controller.k8sClient, expectDeployment)
 	if err != nil {
 		logger.Error(err, "sync fe proxy deployment failed", "StarRocksCluster", src)
@@ -166,7 +170,8 @@ func (controller *FeProxyController) ClearCluster(ctx context.Context, src *srap
 		WithValues(log.ActionKey, log.ActionCluster)
 	ctx = logr.NewContext(ctx, logger)
 
-	if src.Spec.StarRocksFeProxySpec != nil {
+	// clear the resources when the spec of fe proxy is removed, or the StarRocksCluster is being deleted.
+	if src.Spec.StarRocksFeProxySpec != nil && src.DeletionTimestamp.IsZero() {
 		return nil
 	}
 
@@ -20,15 +20,22 @@ package subcontrollers
 
 import (
 	"context"
+	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/hash"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils"
+	srconfig "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/config"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/deployment"
+	srobject "github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/object"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/pod"
 	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/statefulset"
 )
@@ -61,6 +68,28 @@ type WarehouseSubController interface {
 	UpdateWarehouseStatus(ctx context.Context, warehouse *srapi.StarRocksWarehouse) error
 }
 
+// ExpandPersistentVolumeClaims expands the persistent volume claims of the statefulset after the storage size is
+// increased, and records the progress of every pod in the status of the component. If the expansion is skipped, e.g.
+// the storage size is decreased, a warning event is recorded for the object.
+func ExpandPersistentVolumeClaims(ctx context.Context, k8sClient client.Client, recorder record.EventRecorder,
+	object runtime.Object, expect *appsv1.StatefulSet, status *srapi.StarRocksComponentStatus) error {
+	expansion, err := k8sutils.ExpandPersistentVolumeClaims(ctx, k8sClient, expect)
+	if status != nil {
+		status.VolumeExpansion = expansion
+	}
+	if err != nil || expansion == nil || recorder == nil || object == nil {
+		return err
+	}
+	for _, pod := range expansion.Pods {
+		if pod.Phase == srapi.VolumeExpansionFailed {
+			recorder.Event(object, corev1.EventTypeWarning, "VolumeExpansionSkipped",
+				fmt.Sprintf("statefulset %s: %s", expect.Name, pod.Reason))
+			break
+		}
+	}
+	return nil
+}
+
 type LoadType string
 
 const (
@@ -128,3 +157,69 @@ func UpdateStatus(componentStatus *srapi.StarRocksComponentStatus, k8sClient cli
 	}
 	return nil
 }
+
+// SyncConfigMap applies the configMap which stores the config file rendered from spec.config, and deletes it when
+// spec.config is removed. The config hash returned by SyncDynamicConfig is stored in its annotations.
+func SyncConfigMap(ctx context.Context, k8sClient client.Client, object srobject.StarRocksObject,
+	prefix string, spec srapi.SpecInterface, configHash string) error {
+	if !srconfig.IsManaged(spec) {
+		return k8sutils.DeleteConfigMap(ctx, k8sClient, object.Namespace, srconfig.ConfigMapName(prefix, spec))
+	}
+	configMap := srconfig.MakeConfigMap(object, prefix, spec)
+	configMap.Annotations = map[string]string{srapi.ConfigHashAnnotation: configHash}
+	return k8sutils.ApplyConfigMap(ctx, k8sClient, configMap)
+}
+
+// ReferencedConfigMaps returns the names of configMaps referenced by spec, except the ones whose rollout is disabled.
+func ReferencedConfigMaps(spec *srapi.StarRocksComponentSpec) []string {
+	var names []string
+	if info := spec.ConfigMapInfo; info.ConfigMapName != "" && !info.DisableRollout {
+		names = append(names, info.ConfigMapName)
+	}
+	for _, reference := range spec.ConfigMaps {
+		if !reference.DisableRollout {
+			names = append(names, reference.Name)
+		}
+	}
+	return names
+}
+
+// ReferencedSecrets returns the names of secrets referenced by spec, except the ones whose rollout is disabled.
+func ReferencedSecrets(spec *srapi.StarRocksComponentSpec) []string {
+	var names []string
+	for _, reference := range spec.Secrets {
+		if !reference.DisableRollout {
+			names = append(names, reference.Name)
+		}
+	}
+	return names
+}
+
+// ReferenceHashes returns the hashes of the content of configMaps and secrets referenced by spec, keyed by the
+// annotations of pods. The pods are rolled out by the update strategy when a referenced object is changed. The
+// objects which do not exist are skipped, and the pods are rolled out after they are created.
+func ReferenceHashes(ctx context.Context, k8sClient client.Client, namespace string,
+	spec *srapi.StarRocksComponentSpec) (map[string]string, error) {
+	hashes := make(map[string]string)
+	for _, name := range ReferencedConfigMaps(spec) {
+		var configMap corev1.ConfigMap
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &configMap); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		hashes[pod.ConfigMapHashAnnotation(name)] = hash.HashObject([]interface{}{configMap.Data, configMap.BinaryData})
+	}
+	for _, name := range ReferencedSecrets(spec) {
+		var secret corev1.Secret
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		hashes[pod.SecretHashAnnotation(name)] = hash.HashObject(secret.Data)
+	}
+	return hashes, nil
+}
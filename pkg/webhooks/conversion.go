@@ -0,0 +1,99 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	srapiv2 "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v2"
+)
+
+// ConvertibleCRDs are the CRDs which have multiple versions, and their objects are converted by the conversion webhook.
+var ConvertibleCRDs = []string{"starrocksclusters.starrocks.com", "starrockswarehouses.starrocks.com"}
+
+// SetupConversion configures the conversion webhook of StarRocks CRDs, and serves the v2 version of them.
+// The v2 version is not served in the CRD manifests, because the objects of v2 can not be converted without the
+// conversion webhook. The CA bundle is read from the ca.crt file in the certificate directory of webhook server.
+func SetupConversion(ctx context.Context, mgr ctrl.Manager, service types.NamespacedName, certDir string) error {
+	caBundle, err := os.ReadFile(filepath.Join(certDir, "ca.crt"))
+	if err != nil {
+		return fmt.Errorf("failed to read the CA bundle of webhook server: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err = apiextensionsv1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	k8sClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	return ensureCRDConversion(ctx, k8sClient, service, caBundle)
+}
+
+func ensureCRDConversion(ctx context.Context, k8sClient client.Client, service types.NamespacedName, caBundle []byte) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	path := "/convert"
+	for _, name := range ConvertibleCRDs {
+		var crd apiextensionsv1.CustomResourceDefinition
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: name}, &crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				// StarRocksWarehouse CRD is optional.
+				logger.Info("CRD is not found, skip setting up conversion webhook", "crd", name)
+				continue
+			}
+			return err
+		}
+
+		patch := client.MergeFrom(crd.DeepCopy())
+		crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+			Strategy: apiextensionsv1.WebhookConverter,
+			Webhook: &apiextensionsv1.WebhookConversion{
+				ClientConfig: &apiextensionsv1.WebhookClientConfig{
+					Service: &apiextensionsv1.ServiceReference{
+						Namespace: service.Namespace,
+						Name:      service.Name,
+						Path:      &path,
+					},
+					CABundle: caBundle,
+				},
+				ConversionReviewVersions: []string{"v1"},
+			},
+		}
+		for i := range crd.Spec.Versions {
+			if crd.Spec.Versions[i].Name == srapiv2.GroupVersion.Version {
+				crd.Spec.Versions[i].Served = true
+			}
+		}
+		if err := k8sClient.Patch(ctx, &crd, patch); err != nil {
+			return err
+		}
+		logger.Info("set up conversion webhook", "crd", name)
+	}
+	return nil
+}
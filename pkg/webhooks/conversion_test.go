@@ -0,0 +1,59 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+)
+
+func TestEnsureCRDConversion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "starrocksclusters.starrocks.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v2", Served: false},
+			},
+		},
+	}
+	// the CRD of StarRocksWarehouse is not installed.
+	k8sClient := fake.NewFakeClient(scheme, crd)
+
+	service := types.NamespacedName{Namespace: "starrocks", Name: "kube-starrocks-webhook-service"}
+	require.NoError(t, ensureCRDConversion(context.Background(), k8sClient, service, []byte("ca")))
+
+	var actual apiextensionsv1.CustomResourceDefinition
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: crd.Name}, &actual))
+	require.Equal(t, apiextensionsv1.WebhookConverter, actual.Spec.Conversion.Strategy)
+	clientConfig := actual.Spec.Conversion.Webhook.ClientConfig
+	require.Equal(t, "kube-starrocks-webhook-service", clientConfig.Service.Name)
+	require.Equal(t, "/convert", *clientConfig.Service.Path)
+	require.Equal(t, []byte("ca"), clientConfig.CABundle)
+	require.True(t, actual.Spec.Versions[0].Served)
+	require.True(t, actual.Spec.Versions[1].Served)
+}
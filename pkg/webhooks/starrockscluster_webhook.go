@@ -0,0 +1,144 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/be"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/cn"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+// +kubebuilder:webhook:path=/mutate-starrocks-com-v1-starrockscluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=starrocks.com,resources=starrocksclusters,verbs=create;update,versions=v1,name=mstarrockscluster.starrocks.com,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-starrocks-com-v1-starrockscluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=starrocks.com,resources=starrocksclusters,verbs=create;update,versions=v1,name=vstarrockscluster.starrocks.com,admissionReviewVersions=v1
+
+// StarRocksClusterWebhook defaults and validates StarRocksCluster objects.
+type StarRocksClusterWebhook struct {
+	FeController *fe.FeController
+	BeController *be.BeController
+	CnController *cn.CnController
+}
+
+var _ admission.CustomDefaulter = &StarRocksClusterWebhook{}
+var _ admission.CustomValidator = &StarRocksClusterWebhook{}
+
+// Default implements admission.CustomDefaulter.
+func (w *StarRocksClusterWebhook) Default(_ context.Context, obj runtime.Object) error {
+	src, ok := obj.(*srapi.StarRocksCluster)
+	if !ok {
+		return fmt.Errorf("expected a StarRocksCluster but got a %T", obj)
+	}
+	if src.Spec.StarRocksFeSpec != nil {
+		defaultComponentSpec(&src.Spec.StarRocksFeSpec.StarRocksComponentSpec)
+	}
+	if src.Spec.StarRocksBeSpec != nil {
+		defaultComponentSpec(&src.Spec.StarRocksBeSpec.StarRocksComponentSpec)
+	}
+	if src.Spec.StarRocksCnSpec != nil {
+		defaultComponentSpec(&src.Spec.StarRocksCnSpec.StarRocksComponentSpec)
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.CustomValidator.
+func (w *StarRocksClusterWebhook) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	src, ok := obj.(*srapi.StarRocksCluster)
+	if !ok {
+		return fmt.Errorf("expected a StarRocksCluster but got a %T", obj)
+	}
+	return w.validate(src)
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (w *StarRocksClusterWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	oldSrc, ok := oldObj.(*srapi.StarRocksCluster)
+	if !ok {
+		return fmt.Errorf("expected a StarRocksCluster but got a %T", oldObj)
+	}
+	src, ok := newObj.(*srapi.StarRocksCluster)
+	if !ok {
+		return fmt.Errorf("expected a StarRocksCluster but got a %T", newObj)
+	}
+	// The operator removes the finalizer when the cluster is being deleted, do not block it by an invalid spec.
+	if !src.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if err := w.validate(src); err != nil {
+		return err
+	}
+	return validateClusterImmutableFields(oldSrc, src)
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (w *StarRocksClusterWebhook) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (w *StarRocksClusterWebhook) validate(src *srapi.StarRocksCluster) error {
+	if src.Spec.StarRocksFeSpec != nil {
+		if err := w.FeController.Validating(src.Spec.StarRocksFeSpec); err != nil {
+			return fmt.Errorf("starRocksFeSpec: %w", err)
+		}
+	}
+	if src.Spec.StarRocksBeSpec != nil {
+		if err := w.BeController.Validating(src.Spec.StarRocksBeSpec); err != nil {
+			return fmt.Errorf("starRocksBeSpec: %w", err)
+		}
+	}
+	if src.Spec.StarRocksCnSpec != nil {
+		if err := w.CnController.Validating(src.Spec.StarRocksCnSpec); err != nil {
+			return fmt.Errorf("starRocksCnSpec: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateClusterImmutableFields checks the fields which can not be changed after the statefulsets are created.
+func validateClusterImmutableFields(oldSrc, src *srapi.StarRocksCluster) error {
+	oldFeSpec, feSpec := oldSrc.Spec.StarRocksFeSpec, src.Spec.StarRocksFeSpec
+	if oldFeSpec != nil && feSpec != nil {
+		if err := validateStorageVolumes("starRocksFeSpec", oldFeSpec.StorageVolumes, feSpec.StorageVolumes); err != nil {
+			return err
+		}
+		// FE followers can not be scaled to 1, because the metadata of the single FE would lose the quorum.
+		oldReplicas, replicas := oldFeSpec.GetReplicas(), feSpec.GetReplicas()
+		if oldReplicas != nil && *oldReplicas > 1 && (replicas == nil || *replicas == 1) {
+			return fmt.Errorf("starRocksFeSpec: the replicas of FE can not be scaled to 1 from %d", *oldReplicas)
+		}
+	}
+
+	oldBeSpec, beSpec := oldSrc.Spec.StarRocksBeSpec, src.Spec.StarRocksBeSpec
+	if oldBeSpec != nil && beSpec != nil {
+		if err := validateStorageVolumes("starRocksBeSpec", oldBeSpec.StorageVolumes, beSpec.StorageVolumes); err != nil {
+			return err
+		}
+	}
+
+	oldCnSpec, cnSpec := oldSrc.Spec.StarRocksCnSpec, src.Spec.StarRocksCnSpec
+	if oldCnSpec != nil && cnSpec != nil {
+		if err := validateStorageVolumes("starRocksCnSpec", oldCnSpec.StorageVolumes, cnSpec.StorageVolumes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	rutils "github.com/StarRocks/starrocks-kubernetes-operator/pkg/common/resource_utils"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/be"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/cn"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+func newClusterWebhook() *StarRocksClusterWebhook {
+	k8sClient := fake.NewFakeClient(srapi.Scheme)
+	return &StarRocksClusterWebhook{
+		FeController: fe.New(k8sClient, fake.GetEventRecorderFor(nil)),
+		BeController: be.New(k8sClient, fake.GetEventRecorderFor(nil)),
+		CnController: cn.New(k8sClient, fake.GetEventRecorderFor(nil)),
+	}
+}
+
+func newCluster(feReplicas int32, storageVolumes ...srapi.StorageVolume) *srapi.StarRocksCluster {
+	return &srapi.StarRocksCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-starrocks", Namespace: "default"},
+		Spec: srapi.StarRocksClusterSpec{
+			StarRocksFeSpec: &srapi.StarRocksFeSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{
+						Replicas:       &feReplicas,
+						StorageVolumes: storageVolumes,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestStarRocksClusterWebhook_Default(t *testing.T) {
+	src := newCluster(3)
+	src.Spec.StarRocksBeSpec = &srapi.StarRocksBeSpec{}
+	var gracePeriod int64 = 30
+	src.Spec.StarRocksBeSpec.TerminationGracePeriodSeconds = &gracePeriod
+
+	require.NoError(t, newClusterWebhook().Default(context.Background(), src))
+	feSpec := src.Spec.StarRocksFeSpec
+	require.Equal(t, appsv1.RollingUpdateStatefulSetStrategyType, feSpec.UpdateStrategy.Type)
+	require.Equal(t, int32(0), *feSpec.UpdateStrategy.RollingUpdate.Partition)
+	require.Equal(t, int64(120), *feSpec.TerminationGracePeriodSeconds)
+	require.Equal(t, int64(30), *src.Spec.StarRocksBeSpec.TerminationGracePeriodSeconds)
+	require.Nil(t, src.Spec.StarRocksCnSpec)
+}
+
+func TestStarRocksClusterWebhook_ValidateCreate(t *testing.T) {
+	hostPath := srapi.HostPath
+	zero := intstr.FromInt(0)
+	minReplicas := int32(3)
+	tests := []struct {
+		name    string
+		modify  func(src *srapi.StarRocksCluster)
+		wantErr bool
+	}{
+		{
+			name:   "valid cluster",
+			modify: func(src *srapi.StarRocksCluster) {},
+		},
+		{
+			name: "hostPath is required",
+			modify: func(src *srapi.StarRocksCluster) {
+				src.Spec.StarRocksFeSpec.StorageVolumes = []srapi.StorageVolume{
+					{Name: "fe-meta", StorageClassName: &hostPath, MountPath: "/opt/starrocks/fe/meta"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated observer groups",
+			modify: func(src *srapi.StarRocksCluster) {
+				src.Spec.StarRocksFeSpec.ObserverGroups = []srapi.StarRocksFeObserverGroup{{Name: "read"}, {Name: "read"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "maxUnavailable of be is 0",
+			modify: func(src *srapi.StarRocksCluster) {
+				src.Spec.StarRocksBeSpec = &srapi.StarRocksBeSpec{}
+				src.Spec.StarRocksBeSpec.UpdateStrategy = &appsv1.StatefulSetUpdateStrategy{
+					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{MaxUnavailable: &zero},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "max replicas of cn is smaller than min replicas",
+			modify: func(src *srapi.StarRocksCluster) {
+				src.Spec.StarRocksCnSpec = &srapi.StarRocksCnSpec{
+					AutoScalingPolicy: &srapi.AutoScalingPolicy{MinReplicas: &minReplicas, MaxReplicas: 2},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "starrocks metrics with autoscaling v1",
+			modify: func(src *srapi.StarRocksCluster) {
+				src.Spec.StarRocksCnSpec = &srapi.StarRocksCnSpec{
+					AutoScalingPolicy: &srapi.AutoScalingPolicy{
+						Version:          srapi.AutoScalerV1,
+						MaxReplicas:      3,
+						StarRocksMetrics: []srapi.StarRocksMetric{{Name: srapi.PendingQueries, Target: resource.MustParse("2")}},
+					},
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newCluster(3)
+			tt.modify(src)
+			err := newClusterWebhook().ValidateCreate(context.Background(), src)
+			require.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestStarRocksClusterWebhook_ValidateUpdate(t *testing.T) {
+	feMeta := srapi.StorageVolume{Name: "fe-meta", MountPath: "/opt/starrocks/fe/meta"}
+	feLog := srapi.StorageVolume{Name: "fe-log", MountPath: "/opt/starrocks/fe/log"}
+	tests := []struct {
+		name    string
+		oldSrc  *srapi.StarRocksCluster
+		newSrc  *srapi.StarRocksCluster
+		wantErr bool
+	}{
+		{
+			name:   "scale out fe",
+			oldSrc: newCluster(3, feMeta),
+			newSrc: newCluster(5, feMeta),
+		},
+		{
+			name:    "scale fe to 1",
+			oldSrc:  newCluster(3, feMeta),
+			newSrc:  newCluster(1, feMeta),
+			wantErr: true,
+		},
+		{
+			name:    "add a storage volume",
+			oldSrc:  newCluster(3, feMeta),
+			newSrc:  newCluster(3, feMeta, feLog),
+			wantErr: true,
+		},
+		{
+			name:   "add an emptyDir storage volume",
+			oldSrc: newCluster(3, feMeta),
+			newSrc: newCluster(3, feMeta, srapi.StorageVolume{Name: "fe-tmp", MountPath: "/tmp",
+				StorageClassName: rutils.GetStringPointer(srapi.EmptyDir)}),
+		},
+		{
+			name:    "rename a storage volume",
+			oldSrc:  newCluster(3, feMeta),
+			newSrc:  newCluster(3, srapi.StorageVolume{Name: "meta", MountPath: "/opt/starrocks/fe/meta"}),
+			wantErr: true,
+		},
+		{
+			name:   "change the mount path of a storage volume",
+			oldSrc: newCluster(3, feMeta),
+			newSrc: newCluster(3, srapi.StorageVolume{Name: "fe-meta", MountPath: "/data/meta"}),
+		},
+		{
+			name: "increase the storage size of a storage volume",
+			oldSrc: newCluster(3, srapi.StorageVolume{Name: "fe-meta", MountPath: "/opt/starrocks/fe/meta",
+				StorageSize: "10Gi"}),
+			newSrc: newCluster(3, srapi.StorageVolume{Name: "fe-meta", MountPath: "/opt/starrocks/fe/meta",
+				StorageSize: "20Gi"}),
+		},
+		{
+			name: "decrease the storage size of a storage volume",
+			oldSrc: newCluster(3, srapi.StorageVolume{Name: "fe-meta", MountPath: "/opt/starrocks/fe/meta",
+				StorageSize: "20Gi"}),
+			newSrc: newCluster(3, srapi.StorageVolume{Name: "fe-meta", MountPath: "/opt/starrocks/fe/meta",
+				StorageSize: "10Gi"}),
+			wantErr: true,
+		},
+		{
+			name:   "add fe spec to a cluster without fe",
+			oldSrc: &srapi.StarRocksCluster{},
+			newSrc: newCluster(1, feMeta),
+		},
+		{
+			name:   "the cluster is being deleted",
+			oldSrc: newCluster(3, feMeta),
+			newSrc: func() *srapi.StarRocksCluster {
+				src := newCluster(1)
+				now := metav1.Now()
+				src.DeletionTimestamp = &now
+				return src
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newClusterWebhook().ValidateUpdate(context.Background(), tt.oldSrc, tt.newSrc)
+			require.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestStarRocksClusterWebhook_WrongType(t *testing.T) {
+	w := newClusterWebhook()
+	require.Error(t, w.Default(context.Background(), &corev1.Pod{}))
+	require.Error(t, w.ValidateCreate(context.Background(), &corev1.Pod{}))
+	require.Error(t, w.ValidateUpdate(context.Background(), &corev1.Pod{}, &corev1.Pod{}))
+}
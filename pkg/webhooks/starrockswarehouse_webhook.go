@@ -0,0 +1,103 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/cn"
+)
+
+// +kubebuilder:webhook:path=/mutate-starrocks-com-v1-starrockswarehouse,mutating=true,failurePolicy=fail,sideEffects=None,groups=starrocks.com,resources=starrockswarehouses,verbs=create;update,versions=v1,name=mstarrockswarehouse.starrocks.com,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-starrocks-com-v1-starrockswarehouse,mutating=false,failurePolicy=fail,sideEffects=None,groups=starrocks.com,resources=starrockswarehouses,verbs=create;update,versions=v1,name=vstarrockswarehouse.starrocks.com,admissionReviewVersions=v1
+
+// StarRocksWarehouseWebhook defaults and validates StarRocksWarehouse objects.
+type StarRocksWarehouseWebhook struct {
+	CnController *cn.CnController
+}
+
+var _ admission.CustomDefaulter = &StarRocksWarehouseWebhook{}
+var _ admission.CustomValidator = &StarRocksWarehouseWebhook{}
+
+// Default implements admission.CustomDefaulter.
+func (w *StarRocksWarehouseWebhook) Default(_ context.Context, obj runtime.Object) error {
+	warehouse, ok := obj.(*srapi.StarRocksWarehouse)
+	if !ok {
+		return fmt.Errorf("expected a StarRocksWarehouse but got a %T", obj)
+	}
+	if warehouse.Spec.Template != nil {
+		defaultComponentSpec(&warehouse.Spec.Template.StarRocksComponentSpec)
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.CustomValidator.
+func (w *StarRocksWarehouseWebhook) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	warehouse, ok := obj.(*srapi.StarRocksWarehouse)
+	if !ok {
+		return fmt.Errorf("expected a StarRocksWarehouse but got a %T", obj)
+	}
+	return w.validate(warehouse)
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (w *StarRocksWarehouseWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	oldWarehouse, ok := oldObj.(*srapi.StarRocksWarehouse)
+	if !ok {
+		return fmt.Errorf("expected a StarRocksWarehouse but got a %T", oldObj)
+	}
+	warehouse, ok := newObj.(*srapi.StarRocksWarehouse)
+	if !ok {
+		return fmt.Errorf("expected a StarRocksWarehouse but got a %T", newObj)
+	}
+	if !warehouse.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if err := w.validate(warehouse); err != nil {
+		return err
+	}
+
+	if oldWarehouse.Spec.StarRocksCluster != warehouse.Spec.StarRocksCluster {
+		return fmt.Errorf("starRocksCluster: the cluster of warehouse can not be changed from %s to %s",
+			oldWarehouse.Spec.StarRocksCluster, warehouse.Spec.StarRocksCluster)
+	}
+	if oldWarehouse.Spec.Template != nil && warehouse.Spec.Template != nil {
+		return validateStorageVolumes("template",
+			oldWarehouse.Spec.Template.StorageVolumes, warehouse.Spec.Template.StorageVolumes)
+	}
+	return nil
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (w *StarRocksWarehouseWebhook) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (w *StarRocksWarehouseWebhook) validate(warehouse *srapi.StarRocksWarehouse) error {
+	if warehouse.Spec.Template == nil {
+		return nil
+	}
+	if err := w.CnController.Validating(warehouse.Spec.Template.ToCnSpec()); err != nil {
+		return fmt.Errorf("template: %w", err)
+	}
+	return nil
+}
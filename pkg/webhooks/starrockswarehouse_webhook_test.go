@@ -0,0 +1,75 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/fake"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/cn"
+)
+
+func TestMain(m *testing.M) {
+	srapi.Register()
+	os.Exit(m.Run())
+}
+
+func newWarehouseWebhook() *StarRocksWarehouseWebhook {
+	return &StarRocksWarehouseWebhook{
+		CnController: cn.New(fake.NewFakeClient(srapi.Scheme), fake.GetEventRecorderFor(nil)),
+	}
+}
+
+func newWarehouse(cluster string, minReplicas int32, storageVolumes ...srapi.StorageVolume) *srapi.StarRocksWarehouse {
+	return &srapi.StarRocksWarehouse{
+		ObjectMeta: metav1.ObjectMeta{Name: "wh1", Namespace: "default"},
+		Spec: srapi.StarRocksWarehouseSpec{
+			StarRocksCluster: cluster,
+			Template: &srapi.WarehouseComponentSpec{
+				StarRocksComponentSpec: srapi.StarRocksComponentSpec{
+					StarRocksLoadSpec: srapi.StarRocksLoadSpec{StorageVolumes: storageVolumes},
+				},
+				AutoScalingPolicy: &srapi.AutoScalingPolicy{MinReplicas: &minReplicas, MaxReplicas: 3},
+			},
+		},
+	}
+}
+
+func TestStarRocksWarehouseWebhook_Default(t *testing.T) {
+	warehouse := newWarehouse("kube-starrocks", 1)
+	require.NoError(t, newWarehouseWebhook().Default(context.Background(), warehouse))
+	require.NotNil(t, warehouse.Spec.Template.UpdateStrategy)
+	require.Equal(t, int64(120), *warehouse.Spec.Template.TerminationGracePeriodSeconds)
+}
+
+func TestStarRocksWarehouseWebhook_Validate(t *testing.T) {
+	cache := srapi.StorageVolume{Name: "cn-cache", MountPath: "/opt/starrocks/cn/storage"}
+	w := newWarehouseWebhook()
+	require.NoError(t, w.ValidateCreate(context.Background(), newWarehouse("kube-starrocks", 1, cache)))
+	require.Error(t, w.ValidateCreate(context.Background(), newWarehouse("kube-starrocks", 0, cache)))
+
+	oldWarehouse := newWarehouse("kube-starrocks", 1, cache)
+	require.NoError(t, w.ValidateUpdate(context.Background(), oldWarehouse, newWarehouse("kube-starrocks", 2, cache)))
+	require.Error(t, w.ValidateUpdate(context.Background(), oldWarehouse, newWarehouse("other", 1, cache)))
+	require.Error(t, w.ValidateUpdate(context.Background(), oldWarehouse, newWarehouse("kube-starrocks", 1)))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021-present, StarRocks Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhooks contains the admission webhooks of StarRocks CRDs. The webhooks reuse the validating functions of
+// subcontrollers, so a bad spec is rejected by the API server instead of being reported as a failed phase later.
+package webhooks
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	srapi "github.com/StarRocks/starrocks-kubernetes-operator/pkg/apis/starrocks/v1"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/k8sutils/templates/statefulset"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/be"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/cn"
+	"github.com/StarRocks/starrocks-kubernetes-operator/pkg/subcontrollers/fe"
+)
+
+// SetupWebhooks registers the defaulting and validating webhooks of StarRocksCluster and StarRocksWarehouse.
+func SetupWebhooks(mgr ctrl.Manager) error {
+	clusterWebhook := &StarRocksClusterWebhook{
+		FeController: fe.New(mgr.GetClient(), mgr.GetEventRecorderFor),
+		BeController: be.New(mgr.GetClient(), mgr.GetEventRecorderFor),
+		CnController: cn.New(mgr.GetClient(), mgr.GetEventRecorderFor),
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&srapi.StarRocksCluster{}).
+		WithDefaulter(clusterWebhook).
+		WithValidator(clusterWebhook).
+		Complete(); err != nil {
+		return err
+	}
+
+	warehouseWebhook := &StarRocksWarehouseWebhook{
+		CnController: cn.New(mgr.GetClient(), mgr.GetEventRecorderFor),
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&srapi.StarRocksWarehouse{}).
+		WithDefaulter(warehouseWebhook).
+		WithValidator(warehouseWebhook).
+		Complete()
+}
+
+// defaultComponentSpec sets the default values of the fields which have a default value in the getter functions.
+// The statefulset rendered from the spec is not changed, because the getter functions return the same values.
+func defaultComponentSpec(spec *srapi.StarRocksComponentSpec) {
+	if spec.UpdateStrategy == nil {
+		spec.UpdateStrategy = spec.GetUpdateStrategy()
+	}
+	if spec.TerminationGracePeriodSeconds == nil {
+		spec.TerminationGracePeriodSeconds = spec.GetTerminationGracePeriodSeconds()
+	}
+}
+
+// validateStorageVolumes checks the storage volumes which are rendered into the volumeClaimTemplates of statefulset are
+// not added, removed or renamed, and their storage sizes are not decreased. The volumeClaimTemplates can not be updated
+// except that the persistent volume claims are expanded by the operator. The emptyDir, hostPath and zero-size storage
+// volumes are in the pod template, so they can be changed freely.
+func validateStorageVolumes(component string, oldVolumes, newVolumes []srapi.StorageVolume) error {
+	oldSizes := make(map[string]string, len(oldVolumes))
+	for i := range oldVolumes {
+		if statefulset.IsClaimTemplate(oldVolumes[i]) {
+			oldSizes[oldVolumes[i].Name] = oldVolumes[i].StorageSize
+		}
+	}
+	newNames := make(map[string]bool, len(newVolumes))
+	for i := range newVolumes {
+		if statefulset.IsClaimTemplate(newVolumes[i]) {
+			newNames[newVolumes[i].Name] = true
+		}
+	}
+	for name := range oldSizes {
+		if !newNames[name] {
+			return fmt.Errorf("%s: storage volume %s can not be removed or renamed", component, name)
+		}
+	}
+	for i := range newVolumes {
+		if !newNames[newVolumes[i].Name] {
+			continue
+		}
+		oldSize, ok := oldSizes[newVolumes[i].Name]
+		if !ok {
+			return fmt.Errorf("%s: storage volume %s can not be added to an existing component", component, newVolumes[i].Name)
+		}
+		if err := validateStorageSize(component, &newVolumes[i], oldSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateStorageSize checks the storage size of the storage volume is not decreased from oldSize.
+func validateStorageSize(component string, volume *srapi.StorageVolume, oldSize string) error {
+	if volume.StorageSize == "" || oldSize == "" {
+		return nil
+	}
+	size, err := resource.ParseQuantity(volume.StorageSize)
+	if err != nil {
+		return fmt.Errorf("%s: invalid storage size %s of storage volume %s: %w", component, volume.StorageSize,
+			volume.Name, err)
+	}
+	previous, err := resource.ParseQuantity(oldSize)
+	if err != nil {
+		return nil
+	}
+	if size.Cmp(previous) < 0 {
+		return fmt.Errorf("%s: the storage size of storage volume %s can not be decreased from %s to %s", component,
+			volume.Name, oldSize, volume.StorageSize)
+	}
+	return nil
+}